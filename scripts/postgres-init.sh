@@ -170,4 +170,20 @@ ADD CONSTRAINT set_states_displayname_constrainte UNIQUE (version, app_ref_id, v
 
 alter table set_states owner to illa_builder;
 
+-- action_resume_tokens, the last position of resumable actions, action_kind 1 for app action and 2 for flow action
+create table if not exists action_resume_tokens (
+    id                      bigserial                       not null primary key,
+    team_id                 bigint                          not null,
+    action_kind             smallint                        not null,
+    action_id               bigint                          not null,
+    resume_token            text                            not null,
+    created_at              timestamp                       not null,
+    updated_at              timestamp                       not null
+);
+
+ALTER TABLE action_resume_tokens DROP CONSTRAINT IF EXISTS action_resume_tokens_action_constrainte,
+ADD CONSTRAINT action_resume_tokens_action_constrainte UNIQUE (team_id, action_kind, action_id);
+
+alter table action_resume_tokens owner to illa_builder;
+
 EOF
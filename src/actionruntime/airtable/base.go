@@ -86,6 +86,9 @@ func (a *Connector) ListRecords() (common.RuntimeResult, error) {
 	if listConfig.Offset != "" {
 		listReqBody["offset"] = listConfig.Offset
 	}
+	if listConfig.AllPages {
+		return a.listAllRecords(listReqBody, listConfig.MaxRecords)
+	}
 
 	// call `List Records` method
 	restyClient := resty.New()
@@ -182,6 +185,9 @@ func (a *Connector) CreateRecords() (common.RuntimeResult, error) {
 	if err := validate.Struct(createConfig); err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
+	if len(createConfig.Records) > MAX_RECORDS_PER_REQUEST {
+		return a.writeRecordsInChunks(http.MethodPost, createConfig.Records)
+	}
 
 	// build `Create Records` request body
 	createReqBody := make(map[string]interface{}, 1)
@@ -242,6 +248,9 @@ func (a *Connector) UpdateMultipleRecords() (common.RuntimeResult, error) {
 	if err := validate.Struct(bulkUpdateConfig); err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
+	if len(bulkUpdateConfig.Records) > MAX_RECORDS_PER_REQUEST {
+		return a.writeRecordsInChunks(http.MethodPatch, bulkUpdateConfig.Records)
+	}
 
 	// build `Update Multiple Records` request body
 	bulkUpdateReqBody := make(map[string]interface{}, 1)
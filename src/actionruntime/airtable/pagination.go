@@ -0,0 +1,206 @@
+// Copyright 2023 Illa Soft, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package airtable
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+)
+
+const (
+	// airtable allows 5 requests per second for each base
+	REQUEST_INTERVAL = 200 * time.Millisecond
+)
+
+// the runs of the same base share the limiter in one instance, so the concurrent runs do not exceed the rate limit together
+var baseRequestLimiter = newRequestLimiter(REQUEST_INTERVAL)
+
+// newRestyClient creates the client of each run, it is replaced to send the requests to a fake airtable in tests.
+var newRestyClient = resty.New
+
+// requestLimiter schedules the requests of each key one interval apart.
+type requestLimiter struct {
+	mutex         sync.Mutex
+	interval      time.Duration
+	nextRequestAt map[string]time.Time
+}
+
+func newRequestLimiter(interval time.Duration) *requestLimiter {
+	return &requestLimiter{
+		interval:      interval,
+		nextRequestAt: make(map[string]time.Time),
+	}
+}
+
+// reserve returns the time when the request of key can be sent, the keys idle for a whole interval are removed.
+func (l *requestLimiter) reserve(key string) time.Time {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	now := time.Now()
+	for idleKey, nextRequestAt := range l.nextRequestAt {
+		if nextRequestAt.Before(now) {
+			delete(l.nextRequestAt, idleKey)
+		}
+	}
+	requestAt, ok := l.nextRequestAt[key]
+	if !ok {
+		requestAt = now
+	}
+	l.nextRequestAt[key] = requestAt.Add(l.interval)
+	return requestAt
+}
+
+func (l *requestLimiter) wait(key string) {
+	time.Sleep(time.Until(l.reserve(key)))
+}
+
+// requestPacer paces the requests of one run by the limiter of the base.
+type requestPacer struct {
+	restyClient *resty.Client
+	resource    *Resource
+	limiter     *requestLimiter
+	limiterKey  string
+}
+
+func newRequestPacer(resource *Resource, baseID string) *requestPacer {
+	return &requestPacer{
+		restyClient: newRestyClient(),
+		resource:    resource,
+		limiter:     baseRequestLimiter,
+		limiterKey:  baseID,
+	}
+}
+
+func (p *requestPacer) newRequest() *resty.Request {
+	req := p.restyClient.R().SetHeader("Content-Type", "application/json")
+	if p.resource.AuthenticationType == API_KEY_AUTHENTICATION {
+		req.SetAuthToken(p.resource.AuthenticationConfig[API_KEY_AUTHENTICATION])
+	} else if p.resource.AuthenticationType == PERSONAL_TOKEN_AUTHENTICATION {
+		req.SetAuthToken(p.resource.AuthenticationConfig[TOKEN_AUTHENTICATION])
+	}
+	return req
+}
+
+// do sends the request built by buildRequest when the limiter allows. The request rejected by rate limit is not retried,
+// since airtable rejects all the requests in the following 30 seconds, the caller returns what it got instead.
+func (p *requestPacer) do(buildRequest func(req *resty.Request) (*resty.Response, error)) (*resty.Response, error) {
+	p.limiter.wait(p.limiterKey)
+	return buildRequest(p.newRequest())
+}
+
+// exportFailedResult keeps the same result of failed request as the single request methods.
+func exportFailedResult(resp *resty.Response, errRun error, extra map[string]interface{}) common.RuntimeResult {
+	if errRun != nil {
+		return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{{"message": "Request to Airtable failed: " + errRun.Error()}}}
+	}
+	respMap, errParseError := parseAirtableResponse(resp.String())
+	if errParseError != nil {
+		return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{{"message": "Parse Airtable response error: " + errParseError.Error()}}}
+	}
+	respMap["status"] = resp.Status()
+	for key, value := range extra {
+		respMap[key] = value
+	}
+	return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{respMap}}
+}
+
+// listAllRecords follows the offset until all records fetched or the max records reached.
+// The offset is returned when there are remaining records, and also with the fetched records when a page failed, e.g. rate limited.
+func (a *Connector) listAllRecords(listReqBody map[string]interface{}, maxRecords int) (common.RuntimeResult, error) {
+	if maxRecords <= 0 {
+		maxRecords = DEFAULT_ALL_PAGES_MAX_RECORDS
+	}
+	listReqBody["maxRecords"] = maxRecords
+
+	pacer := newRequestPacer(&a.Resource, a.Action.BaseConfig.BaseID)
+	records := make([]interface{}, 0)
+	offset, _ := listReqBody["offset"].(string)
+	for {
+		resp, errRun := pacer.do(func(req *resty.Request) (*resty.Response, error) {
+			return req.SetBody(listReqBody).
+				SetPathParams(map[string]string{
+					"baseId":    a.Action.BaseConfig.BaseID,
+					"tableName": a.Action.BaseConfig.TableName,
+				}).
+				Post(AIRTABLE_API + "/listRecords")
+		})
+		if errRun != nil || resp.StatusCode() != http.StatusOK {
+			extra := map[string]interface{}{"records": records}
+			if offset != "" {
+				extra["offset"] = offset
+			}
+			return exportFailedResult(resp, errRun, extra), nil
+		}
+		respMap, errParseResp := parseAirtableResponse(resp.String())
+		if errParseResp != nil {
+			return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{{"message": "Parse Airtable response error: " + errParseResp.Error()}}}, nil
+		}
+		if pageRecords, ok := respMap["records"].([]interface{}); ok {
+			records = append(records, pageRecords...)
+		}
+		offset, _ = respMap["offset"].(string)
+		if offset == "" || len(records) >= maxRecords {
+			break
+		}
+		listReqBody["offset"] = offset
+	}
+	if len(records) > maxRecords {
+		records = records[:maxRecords]
+	}
+
+	result := map[string]interface{}{"records": records}
+	if offset != "" {
+		result["offset"] = offset
+	}
+	return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{result}}, nil
+}
+
+// writeRecordsInChunks sends the records in chunks of 10, which is the limit of airtable.
+// The written records are returned even when a chunk failed.
+func (a *Connector) writeRecordsInChunks(method string, records []map[string]interface{}) (common.RuntimeResult, error) {
+	pacer := newRequestPacer(&a.Resource, a.Action.BaseConfig.BaseID)
+	writtenRecords := make([]interface{}, 0, len(records))
+	for start := 0; start < len(records); start += MAX_RECORDS_PER_REQUEST {
+		end := start + MAX_RECORDS_PER_REQUEST
+		if end > len(records) {
+			end = len(records)
+		}
+		reqBody := map[string]interface{}{"records": records[start:end]}
+		resp, errRun := pacer.do(func(req *resty.Request) (*resty.Response, error) {
+			return req.SetBody(reqBody).
+				SetPathParams(map[string]string{
+					"baseId":    a.Action.BaseConfig.BaseID,
+					"tableName": a.Action.BaseConfig.TableName,
+				}).
+				Execute(method, AIRTABLE_API)
+		})
+		if errRun != nil || resp.StatusCode() != http.StatusOK {
+			return exportFailedResult(resp, errRun, map[string]interface{}{"records": writtenRecords}), nil
+		}
+		respMap, errParseResp := parseAirtableResponse(resp.String())
+		if errParseResp != nil {
+			return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{{"message": "Parse Airtable response error: " + errParseResp.Error()}}}, nil
+		}
+		if chunkRecords, ok := respMap["records"].([]interface{}); ok {
+			writtenRecords = append(writtenRecords, chunkRecords...)
+		}
+	}
+
+	return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{{"records": writtenRecords}}}, nil
+}
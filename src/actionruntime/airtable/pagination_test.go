@@ -0,0 +1,201 @@
+package airtable
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// redirectTransport sends the requests of airtable api to the fake server
+type redirectTransport struct {
+	serverURL *url.URL
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.serverURL.Scheme
+	req.URL.Host = t.serverURL.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newFakeAirtable(t *testing.T, handler http.HandlerFunc) {
+	server := httptest.NewServer(handler)
+	serverURL, _ := url.Parse(server.URL)
+	newRestyClient = func() *resty.Client {
+		return resty.New().SetTransport(&redirectTransport{serverURL: serverURL})
+	}
+	baseRequestLimiter = newRequestLimiter(0)
+	t.Cleanup(func() {
+		server.Close()
+		newRestyClient = resty.New
+		baseRequestLimiter = newRequestLimiter(REQUEST_INTERVAL)
+	})
+}
+
+func newTestConnector() *Connector {
+	return &Connector{
+		Resource: Resource{
+			AuthenticationType:   PERSONAL_TOKEN_AUTHENTICATION,
+			AuthenticationConfig: map[string]string{TOKEN_AUTHENTICATION: "token"},
+		},
+		Action: Action{BaseConfig: BaseConfig{BaseID: "appTest", TableName: "tblTest"}},
+	}
+}
+
+// listRecordsHandler serves the records in pages of 10, the page of rejectedOffset is rejected by rate limit
+func listRecordsHandler(t *testing.T, totalRecords int, rejectedOffset string, offsets *[]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v0/appTest/tblTest/listRecords", r.URL.Path)
+		var reqBody map[string]interface{}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		offset, _ := reqBody["offset"].(string)
+		*offsets = append(*offsets, offset)
+		if offset != "" && offset == rejectedOffset {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"errors":[{"error":"RATE_LIMIT_REACHED"}]}`))
+			return
+		}
+
+		start, _ := strconv.Atoi(offset)
+		records := make([]map[string]interface{}, 0)
+		for i := start; i < totalRecords && i < start+MAX_RECORDS_PER_REQUEST; i++ {
+			records = append(records, map[string]interface{}{"id": fmt.Sprintf("rec%d", i)})
+		}
+		respBody := map[string]interface{}{"records": records}
+		if start+MAX_RECORDS_PER_REQUEST < totalRecords {
+			respBody["offset"] = strconv.Itoa(start + MAX_RECORDS_PER_REQUEST)
+		}
+		json.NewEncoder(w).Encode(respBody)
+	}
+}
+
+func TestRequestLimiterSpacesRequestsOfSameKey(t *testing.T) {
+	limiter := newRequestLimiter(time.Second)
+	first := limiter.reserve("appA")
+	second := limiter.reserve("appA")
+	other := limiter.reserve("appB")
+
+	assert.Equal(t, time.Second, second.Sub(first))
+	assert.True(t, other.Before(second))
+}
+
+func TestRequestLimiterRemovesIdleKeys(t *testing.T) {
+	limiter := newRequestLimiter(time.Millisecond)
+	limiter.reserve("appA")
+	time.Sleep(2 * time.Millisecond)
+	limiter.reserve("appB")
+
+	assert.Equal(t, 1, len(limiter.nextRequestAt))
+}
+
+func TestListAllRecordsFollowsOffsets(t *testing.T) {
+	offsets := make([]string, 0)
+	newFakeAirtable(t, listRecordsHandler(t, 25, "", &offsets))
+
+	result, err := newTestConnector().listAllRecords(map[string]interface{}{}, 0)
+	assert.Nil(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, []string{"", "10", "20"}, offsets)
+	assert.Len(t, result.Rows[0]["records"], 25)
+	assert.NotContains(t, result.Rows[0], "offset")
+}
+
+func TestListAllRecordsTruncatesToMaxRecords(t *testing.T) {
+	offsets := make([]string, 0)
+	newFakeAirtable(t, listRecordsHandler(t, 25, "", &offsets))
+
+	result, err := newTestConnector().listAllRecords(map[string]interface{}{}, 15)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"", "10"}, offsets)
+	records := result.Rows[0]["records"].([]interface{})
+	assert.Len(t, records, 15)
+	assert.Equal(t, "rec14", records[14].(map[string]interface{})["id"])
+	assert.Equal(t, "20", result.Rows[0]["offset"])
+}
+
+func TestListAllRecordsReturnsPartialResultWhenRateLimited(t *testing.T) {
+	offsets := make([]string, 0)
+	newFakeAirtable(t, listRecordsHandler(t, 25, "10", &offsets))
+
+	result, err := newTestConnector().listAllRecords(map[string]interface{}{}, 0)
+	assert.Nil(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, []string{"", "10"}, offsets)
+	assert.Equal(t, "429 Too Many Requests", result.Rows[0]["status"])
+	assert.Len(t, result.Rows[0]["records"], 10)
+	assert.Equal(t, "10", result.Rows[0]["offset"])
+	assert.NotNil(t, result.Rows[0]["errors"])
+}
+
+func TestWriteRecordsInChunks(t *testing.T) {
+	chunkSizes := make([]int, 0)
+	newFakeAirtable(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/v0/appTest/tblTest", r.URL.Path)
+		var reqBody struct {
+			Records []map[string]interface{} `json:"records"`
+		}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		chunkSizes = append(chunkSizes, len(reqBody.Records))
+		json.NewEncoder(w).Encode(reqBody)
+	})
+
+	records := make([]map[string]interface{}, 0)
+	for i := 0; i < 23; i++ {
+		records = append(records, map[string]interface{}{"id": fmt.Sprintf("rec%d", i)})
+	}
+	result, err := newTestConnector().writeRecordsInChunks(http.MethodPatch, records)
+	assert.Nil(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, []int{10, 10, 3}, chunkSizes)
+	assert.Len(t, result.Rows[0]["records"], 23)
+}
+
+func TestWriteRecordsInChunksKeepsWrittenRecordsWhenChunkFailed(t *testing.T) {
+	requests := 0
+	newFakeAirtable(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 2 {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"error":{"type":"INVALID_RECORDS"}}`))
+			return
+		}
+		var reqBody map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&reqBody)
+		json.NewEncoder(w).Encode(reqBody)
+	})
+
+	records := make([]map[string]interface{}, 25)
+	for i := range records {
+		records[i] = map[string]interface{}{"fields": map[string]interface{}{"index": i}}
+	}
+	result, err := newTestConnector().writeRecordsInChunks(http.MethodPost, records)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, "422 Unprocessable Entity", result.Rows[0]["status"])
+	assert.Len(t, result.Rows[0]["records"], 10)
+}
+
+func TestGetMetaInfoReportsMetadataErrorInSchema(t *testing.T) {
+	newFakeAirtable(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"error":{"type":"INVALID_PERMISSIONS_OR_MODEL_NOT_FOUND"}}`))
+	})
+
+	result, err := newTestConnector().GetMetaInfo(map[string]interface{}{
+		"authenticationType":   PERSONAL_TOKEN_AUTHENTICATION,
+		"authenticationConfig": map[string]string{TOKEN_AUTHENTICATION: "token"},
+	})
+	assert.Nil(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, []baseSchema{}, result.Schema["bases"])
+	assert.Contains(t, result.Schema["error"], "403 Forbidden")
+}
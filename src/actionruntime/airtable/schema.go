@@ -0,0 +1,116 @@
+// Copyright 2023 Illa Soft, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package airtable
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-resty/resty/v2"
+)
+
+type baseSchema struct {
+	ID     string        `json:"id"`
+	Name   string        `json:"name"`
+	Tables []tableSchema `json:"tables,omitempty"`
+}
+
+type tableSchema struct {
+	ID             string        `json:"id"`
+	Name           string        `json:"name"`
+	PrimaryFieldID string        `json:"primaryFieldId"`
+	Fields         []fieldSchema `json:"fields"`
+	Views          []viewSchema  `json:"views"`
+}
+
+type fieldSchema struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type viewSchema struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// listBasesSchema lists the bases which the token can access by metadata API, the tables and fields are only fetched for
+// the bases in use, which are configured in resource, since airtable limits the requests of each base.
+// The token requires `schema.bases:read` scope.
+func (a *Connector) listBasesSchema(baseIDs []string) ([]baseSchema, error) {
+	pacer := newRequestPacer(&a.Resource, AIRTABLE_META_BASES_API)
+	bases := make([]baseSchema, 0)
+	offset := ""
+	for {
+		resp, errRun := pacer.do(func(req *resty.Request) (*resty.Response, error) {
+			if offset != "" {
+				req.SetQueryParam("offset", offset)
+			}
+			return req.Get(AIRTABLE_META_BASES_API)
+		})
+		if err := checkMetaResponse(resp, errRun); err != nil {
+			return nil, err
+		}
+		var basesResp struct {
+			Bases  []baseSchema `json:"bases"`
+			Offset string       `json:"offset"`
+		}
+		if err := json.Unmarshal(resp.Body(), &basesResp); err != nil {
+			return nil, err
+		}
+		bases = append(bases, basesResp.Bases...)
+		offset = basesResp.Offset
+		if offset == "" {
+			break
+		}
+	}
+
+	basesInUse := make(map[string]bool, len(baseIDs))
+	for _, baseID := range baseIDs {
+		basesInUse[baseID] = true
+	}
+	for i := range bases {
+		if !basesInUse[bases[i].ID] {
+			continue
+		}
+		tablePacer := newRequestPacer(&a.Resource, bases[i].ID)
+		resp, errRun := tablePacer.do(func(req *resty.Request) (*resty.Response, error) {
+			return req.SetPathParam("baseId", bases[i].ID).Get(AIRTABLE_META_TABLES_API)
+		})
+		if err := checkMetaResponse(resp, errRun); err != nil {
+			return nil, err
+		}
+		var tablesResp struct {
+			Tables []tableSchema `json:"tables"`
+		}
+		if err := json.Unmarshal(resp.Body(), &tablesResp); err != nil {
+			return nil, err
+		}
+		bases[i].Tables = tablesResp.Tables
+	}
+	return bases, nil
+}
+
+func checkMetaResponse(resp *resty.Response, errRun error) error {
+	if errRun != nil {
+		return errors.New("request to airtable failed: " + errRun.Error())
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return errors.New("request to airtable metadata api failed: " + resp.Status() + " " + resp.String())
+	}
+	return nil
+}
@@ -74,7 +74,24 @@ func (a *Connector) TestConnection(resourceOptions map[string]interface{}) (comm
 }
 
 func (a *Connector) GetMetaInfo(resourceOptions map[string]interface{}) (common.MetaInfoResult, error) {
-	return common.MetaInfoResult{Success: true}, nil
+	// format resource options
+	if err := mapstructure.Decode(resourceOptions, &a.Resource); err != nil {
+		return common.MetaInfoResult{Success: false}, err
+	}
+
+	// get tables and fields of bases, the token without metadata scope still works for records, so the error is only reported in schema
+	bases, err := a.listBasesSchema(a.Resource.BaseIDs)
+	if err != nil {
+		return common.MetaInfoResult{
+			Success: true,
+			Schema:  map[string]interface{}{"bases": []baseSchema{}, "error": err.Error()},
+		}, nil
+	}
+
+	return common.MetaInfoResult{
+		Success: true,
+		Schema:  map[string]interface{}{"bases": bases},
+	}, nil
 }
 
 func (a *Connector) Run(resourceOptions map[string]interface{}, actionOptions map[string]interface{}, rawActionOptions map[string]interface{}) (common.RuntimeResult, error) {
@@ -15,7 +15,9 @@
 package airtable
 
 const (
-	AIRTABLE_API = "https://api.airtable.com/v0/{baseId}/{tableName}"
+	AIRTABLE_API             = "https://api.airtable.com/v0/{baseId}/{tableName}"
+	AIRTABLE_META_BASES_API  = "https://api.airtable.com/v0/meta/bases"
+	AIRTABLE_META_TABLES_API = "https://api.airtable.com/v0/meta/bases/{baseId}/tables"
 
 	PERSONAL_TOKEN_AUTHENTICATION = "personalToken"
 	TOKEN_AUTHENTICATION          = "token"
@@ -31,11 +33,16 @@ const (
 
 	JSON_CELL_FORMAT   = "json"
 	STRING_CELL_FORMAT = "string"
+
+	MAX_RECORDS_PER_REQUEST       = 10
+	DEFAULT_ALL_PAGES_MAX_RECORDS = 10000
 )
 
 type Resource struct {
 	AuthenticationType   string            `mapstructure:"authenticationType" validate:"oneof=personalToken apiKey"`
 	AuthenticationConfig map[string]string `mapstructure:"authenticationConfig" validate:"required"`
+	// the bases in use, whose tables and fields are listed in meta info
+	BaseIDs []string `mapstructure:"baseIds"`
 }
 
 type Action struct {
@@ -60,6 +67,7 @@ type ListConfig struct {
 	TimeZone        string       `mapstructure:"timeZone"`
 	UserLocale      string       `mapstructure:"userLocale"`
 	Offset          string       `mapstructure:"offset"`
+	AllPages        bool         `mapstructure:"allPages"`
 }
 
 type SortObject struct {
@@ -71,12 +79,13 @@ type GetConfig struct {
 	RecordID string `mapstructure:"recordId" validate:"required"`
 }
 
+// the records more than 10 are sent in chunks
 type CreateConfig struct {
-	Records []map[string]interface{} `mapstructure:"records" validate:"required,gt=0,lt=11"`
+	Records []map[string]interface{} `mapstructure:"records" validate:"required,gt=0,lt=1001"`
 }
 
 type BulkUpdateConfig struct {
-	Records []map[string]interface{} `mapstructure:"records" validate:"required,gt=0,lt=11"`
+	Records []map[string]interface{} `mapstructure:"records" validate:"required,gt=0,lt=1001"`
 }
 
 type UpdateConfig struct {
@@ -18,13 +18,14 @@ import (
 	"strings"
 
 	"github.com/illacloud/appwrite-sdk-go/appwrite"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
 	"github.com/mitchellh/mapstructure"
 )
 
-func (a *Connector) getClientWithOpts(Opts map[string]interface{}) (*appwrite.Databases, error) {
+func (a *Connector) getAppwriteClient(Opts map[string]interface{}) (appwrite.Client, error) {
 	// format resource options
 	if err := mapstructure.Decode(Opts, &a.Resource); err != nil {
-		return nil, err
+		return appwrite.Client{}, err
 	}
 
 	// create appwrite client
@@ -33,6 +34,15 @@ func (a *Connector) getClientWithOpts(Opts map[string]interface{}) (*appwrite.Da
 	client.SetProject(a.Resource.ProjectID)
 	client.SetKey(a.Resource.APIKey)
 
+	return client, nil
+}
+
+func (a *Connector) getClientWithOpts(Opts map[string]interface{}) (*appwrite.Databases, error) {
+	client, err := a.getAppwriteClient(Opts)
+	if err != nil {
+		return nil, err
+	}
+
 	// create appwrite database service
 	database := appwrite.NewDatabases(client)
 
@@ -48,3 +58,13 @@ func modifyMapKeysWithPattern(in map[string]interface{}, pattern string, replace
 		}
 	}
 }
+
+func buildFailedResult(message string, result string) common.RuntimeResult {
+	return common.RuntimeResult{Success: false,
+		Rows: []map[string]interface{}{0: {
+			"message": message,
+			"success": false,
+			"result":  result,
+		}},
+	}
+}
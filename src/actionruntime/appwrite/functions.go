@@ -0,0 +1,78 @@
+// Copyright 2023 Illa Soft, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appwrite
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/illacloud/appwrite-sdk-go/appwrite"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/mitchellh/mapstructure"
+)
+
+type FunctionExecutor struct {
+	client *appwrite.Functions
+	action Action
+}
+
+// ExecuteFunction creates an execution of function, the execution is returned before the function finished in async mode.
+func (f *FunctionExecutor) ExecuteFunction() (common.RuntimeResult, error) {
+	var executeFunctionOpts ExecuteFunctionOpts
+	if err := mapstructure.Decode(f.action.Opts, &executeFunctionOpts); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// validate opts
+	if executeFunctionOpts.FunctionID == "" {
+		return common.RuntimeResult{}, errors.New("functionID is required")
+	}
+
+	// the payload is passed to function in string
+	payload := ""
+	switch payloadAsserted := executeFunctionOpts.Payload.(type) {
+	case nil:
+	case string:
+		payload = payloadAsserted
+	default:
+		payloadInBytes, err := json.Marshal(payloadAsserted)
+		if err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		payload = string(payloadInBytes)
+	}
+
+	executeRes, err := f.client.CreateExecution(executeFunctionOpts.FunctionID, payload, executeFunctionOpts.Async)
+	if err != nil {
+		return buildFailedResult(err.Error(), ""), nil
+	}
+	if executeRes == nil {
+		return buildFailedResult("An error occurred while executing the function.", "Unknown error"), nil
+	}
+	if executeRes.StatusCode != 201 {
+		return buildFailedResult("An error occurred while executing the function.", executeRes.Result), nil
+	}
+
+	res := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(executeRes.Result), &res); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	modifyMapKeysWithPattern(res, "$", "")
+
+	return common.RuntimeResult{
+		Success: true,
+		Rows:    []map[string]interface{}{0: res},
+	}, nil
+}
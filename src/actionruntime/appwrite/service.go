@@ -19,6 +19,7 @@ import (
 	"errors"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/illacloud/appwrite-sdk-go/appwrite"
 	"github.com/illacloud/builder-backend/src/actionruntime/common"
 	"github.com/mitchellh/mapstructure"
 )
@@ -107,7 +108,7 @@ func (a *Connector) GetMetaInfo(resourceOptions map[string]interface{}) (common.
 		return common.MetaInfoResult{Success: false}, errors.New("invalid response")
 	}
 
-	res := make([]map[string]string, 0)
+	res := make([]map[string]interface{}, 0)
 	for _, collection := range collectionsAsserted {
 		collectionAsserted, collectionAssertPass := collection.(map[string]interface{})
 		if !collectionAssertPass {
@@ -121,7 +122,11 @@ func (a *Connector) GetMetaInfo(resourceOptions map[string]interface{}) (common.
 		if !collectionIDAssertPass {
 			continue
 		}
-		res = append(res, map[string]string{"id": collectionIDString})
+		attributes, errInGetAttributes := exportCollectionAttributes(db, a.Resource.DatabaseID, collectionIDString, collectionAsserted)
+		if errInGetAttributes != nil {
+			return common.MetaInfoResult{Success: false}, errInGetAttributes
+		}
+		res = append(res, map[string]interface{}{"id": collectionIDString, "name": collectionAsserted["name"], "attributes": attributes})
 	}
 
 	return common.MetaInfoResult{
@@ -131,8 +136,8 @@ func (a *Connector) GetMetaInfo(resourceOptions map[string]interface{}) (common.
 }
 
 func (a *Connector) Run(resourceOptions map[string]interface{}, actionOptions map[string]interface{}, rawActionOptions map[string]interface{}) (common.RuntimeResult, error) {
-	// get appwrite database client
-	db, err := a.getClientWithOpts(resourceOptions)
+	// get appwrite client
+	client, err := a.getAppwriteClient(resourceOptions)
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
@@ -143,7 +148,9 @@ func (a *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 	}
 
 	var result common.RuntimeResult
-	executor := ActionExecutor{client: db, action: a.Action, database: a.Resource.DatabaseID}
+	executor := ActionExecutor{client: appwrite.NewDatabases(client), action: a.Action, database: a.Resource.DatabaseID}
+	storageExecutor := StorageExecutor{client: appwrite.NewStorage(client), resource: a.Resource, action: a.Action}
+	functionExecutor := FunctionExecutor{client: appwrite.NewFunctions(client), action: a.Action}
 	switch a.Action.Method {
 	case LIST_METHOD:
 		result, err = executor.ListDocs()
@@ -155,6 +162,52 @@ func (a *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 		result, err = executor.UpdateDoc()
 	case DELETE_METHOD:
 		result, err = executor.DeleteDoc()
+	case LIST_FILES_METHOD:
+		result, err = storageExecutor.ListFiles()
+	case UPLOAD_FILE_METHOD:
+		result, err = storageExecutor.UploadFile()
+	case DOWNLOAD_FILE_METHOD:
+		result, err = storageExecutor.DownloadFile()
+	case DELETE_FILE_METHOD:
+		result, err = storageExecutor.DeleteFile()
+	case FILE_PREVIEW_URL_METHOD:
+		result, err = storageExecutor.FilePreviewURL()
+	case EXECUTE_FUNCTION_METHOD:
+		result, err = functionExecutor.ExecuteFunction()
 	}
 	return result, err
 }
+
+// exportCollectionAttributes returns the attributes in collection, or lists them when the collection does not carry them.
+func exportCollectionAttributes(db *appwrite.Databases, databaseID string, collectionID string, collection map[string]interface{}) ([]map[string]interface{}, error) {
+	rawAttributes, hit := collection["attributes"].([]interface{})
+	if !hit {
+		attributesRes, err := db.ListAttributes(databaseID, collectionID)
+		if err != nil {
+			return nil, err
+		}
+		if attributesRes.StatusCode != 200 {
+			return nil, errors.New(attributesRes.Result)
+		}
+		var jsonResp map[string]interface{}
+		if err := json.Unmarshal([]byte(attributesRes.Result), &jsonResp); err != nil {
+			return nil, errors.New("invalid response")
+		}
+		rawAttributes, _ = jsonResp["attributes"].([]interface{})
+	}
+
+	attributes := make([]map[string]interface{}, 0, len(rawAttributes))
+	for _, rawAttribute := range rawAttributes {
+		attribute, attributeAssertPass := rawAttribute.(map[string]interface{})
+		if !attributeAssertPass {
+			continue
+		}
+		attributes = append(attributes, map[string]interface{}{
+			"key":      attribute["key"],
+			"type":     attribute["type"],
+			"required": attribute["required"],
+			"array":    attribute["array"],
+		})
+	}
+	return attributes, nil
+}
@@ -0,0 +1,313 @@
+// Copyright 2023 Illa Soft, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appwrite
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/illacloud/appwrite-sdk-go/appwrite"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	FILES_API      = "/storage/buckets/{bucketId}/files"
+	FILE_API       = "/storage/buckets/{bucketId}/files/{fileId}"
+	HEADER_KEY     = "X-Appwrite-Key"
+	HEADER_PROJECT = "X-Appwrite-Project"
+	HEADER_ID      = "X-Appwrite-ID"
+
+	// appwrite requires the files larger than 5MB to be uploaded in chunks of 5MB
+	UPLOAD_CHUNK_SIZE = 5 * 1024 * 1024
+	// the downloaded file is returned in memory, so the larger files should be read by preview url
+	MAX_DOWNLOAD_FILE_SIZE = 20 * 1024 * 1024
+)
+
+// StorageExecutor runs the storage bucket methods. The upload and download are sent directly,
+// since the sdk reads and writes the file content from local file system.
+type StorageExecutor struct {
+	client   *appwrite.Storage
+	resource Resource
+	action   Action
+}
+
+func (s *StorageExecutor) newRequest() *resty.Request {
+	return resty.New().R().
+		SetHeader(HEADER_PROJECT, s.resource.ProjectID).
+		SetHeader(HEADER_KEY, s.resource.APIKey)
+}
+
+func (s *StorageExecutor) buildFileURL(bucketID string, fileID string) string {
+	r := strings.NewReplacer("{bucketId}", url.PathEscape(bucketID), "{fileId}", url.PathEscape(fileID))
+	return strings.TrimSuffix(s.resource.Host, "/") + r.Replace(FILE_API)
+}
+
+func (s *StorageExecutor) ListFiles() (common.RuntimeResult, error) {
+	var listFilesOpts ListFilesOpts
+	if err := mapstructure.Decode(s.action.Opts, &listFilesOpts); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// validate opts
+	if listFilesOpts.BucketID == "" {
+		return common.RuntimeResult{}, errors.New("bucketID is required")
+	}
+
+	// build queries
+	queriesArray := make([]interface{}, 0)
+	if listFilesOpts.Limit > 0 {
+		queriesArray = append(queriesArray, fmt.Sprintf("limit(%d)", listFilesOpts.Limit))
+	}
+	if listFilesOpts.Offset > 0 {
+		queriesArray = append(queriesArray, fmt.Sprintf("offset(%d)", listFilesOpts.Offset))
+	}
+
+	listRes, err := s.client.ListFiles(listFilesOpts.BucketID, queriesArray, listFilesOpts.Search)
+	if err != nil {
+		return buildFailedResult(err.Error(), ""), nil
+	}
+	if listRes == nil {
+		return buildFailedResult("An error occurred while listing the files.", "Unknown error"), nil
+	}
+	if listRes.StatusCode != 200 {
+		return buildFailedResult("An error occurred while listing the files.", listRes.Result), nil
+	}
+
+	res := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(listRes.Result), &res); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	if vs, ok := res["files"].([]interface{}); ok {
+		for _, v := range vs {
+			if m, ok := v.(map[string]interface{}); ok {
+				modifyMapKeysWithPattern(m, "$", "")
+			}
+		}
+	}
+
+	return common.RuntimeResult{
+		Success: true,
+		Rows:    []map[string]interface{}{0: res},
+	}, nil
+}
+
+func (s *StorageExecutor) UploadFile() (common.RuntimeResult, error) {
+	var uploadFileOpts UploadFileOpts
+	if err := mapstructure.Decode(s.action.Opts, &uploadFileOpts); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// validate opts
+	if uploadFileOpts.BucketID == "" {
+		return common.RuntimeResult{}, errors.New("bucketID is required")
+	}
+	if uploadFileOpts.FileName == "" {
+		return common.RuntimeResult{}, errors.New("fileName is required")
+	}
+	data, err := base64.StdEncoding.DecodeString(uploadFileOpts.Data)
+	if err != nil {
+		return common.RuntimeResult{}, errors.New("data should be base64 encoded")
+	}
+	if uploadFileOpts.FileID == "" {
+		uploadFileOpts.FileID = UNIQUE_ID
+	}
+	if uploadFileOpts.ContentType == "" {
+		uploadFileOpts.ContentType = http.DetectContentType(data)
+	}
+
+	// upload file
+	r := strings.NewReplacer("{bucketId}", url.PathEscape(uploadFileOpts.BucketID))
+	uploadRes, err := s.uploadFileInChunks(strings.TrimSuffix(s.resource.Host, "/")+r.Replace(FILES_API), uploadFileOpts, data)
+	if err != nil {
+		return buildFailedResult(err.Error(), ""), nil
+	}
+	if uploadRes.StatusCode() != http.StatusCreated && uploadRes.StatusCode() != http.StatusOK {
+		return buildFailedResult("An error occurred while uploading the file.", uploadRes.String()), nil
+	}
+
+	return common.RuntimeResult{
+		Success: true,
+		Rows: []map[string]interface{}{0: {
+			"message": "File uploaded successfully.",
+			"success": true,
+			"result":  uploadRes.String(),
+		}},
+	}, nil
+}
+
+// uploadFileInChunks sends the file in one request when it is not larger than the chunk size, otherwise in chunks with
+// Content-Range. The chunks after the first one are sent with the id of file, which is generated by appwrite for unique().
+// The response of the last sent chunk is returned.
+func (s *StorageExecutor) uploadFileInChunks(uploadURL string, uploadFileOpts UploadFileOpts, data []byte) (*resty.Response, error) {
+	fileID := uploadFileOpts.FileID
+	for start := 0; ; start += UPLOAD_CHUNK_SIZE {
+		end := start + UPLOAD_CHUNK_SIZE
+		if end > len(data) {
+			end = len(data)
+		}
+		uploadReq := s.newRequest().
+			SetMultipartField("file", uploadFileOpts.FileName, uploadFileOpts.ContentType, bytes.NewReader(data[start:end])).
+			SetFormDataFromValues(url.Values{"fileId": []string{fileID}, "permissions[]": uploadFileOpts.Permissions})
+		if len(data) > UPLOAD_CHUNK_SIZE {
+			uploadReq.SetHeader("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end-1, len(data)))
+		}
+		if start > 0 {
+			uploadReq.SetHeader(HEADER_ID, fileID)
+		}
+		uploadRes, err := uploadReq.Post(uploadURL)
+		if err != nil || (uploadRes.StatusCode() != http.StatusCreated && uploadRes.StatusCode() != http.StatusOK) || end == len(data) {
+			return uploadRes, err
+		}
+		if start == 0 {
+			var file struct {
+				ID string `json:"$id"`
+			}
+			if errInUnmarshal := json.Unmarshal(uploadRes.Body(), &file); errInUnmarshal != nil || file.ID == "" {
+				return nil, errors.New("the id of uploading file is not returned by appwrite")
+			}
+			fileID = file.ID
+		}
+	}
+}
+
+// DownloadFile returns the file content in base64, the file larger than MAX_DOWNLOAD_FILE_SIZE is rejected.
+func (s *StorageExecutor) DownloadFile() (common.RuntimeResult, error) {
+	var fileOpts FileOpts
+	if err := mapstructure.Decode(s.action.Opts, &fileOpts); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// validate opts
+	if err := fileOpts.validate(); err != nil {
+		return common.RuntimeResult{}, err
+	}
+
+	// the body is read by limited reader, so the file larger than the limit is not kept in memory
+	downloadRes, err := s.newRequest().SetDoNotParseResponse(true).Get(s.buildFileURL(fileOpts.BucketID, fileOpts.FileID) + "/download")
+	if err != nil {
+		return buildFailedResult(err.Error(), ""), nil
+	}
+	defer downloadRes.RawBody().Close()
+	if downloadRes.RawResponse.ContentLength > MAX_DOWNLOAD_FILE_SIZE {
+		return buildFailedResult("An error occurred while downloading the file.", fmt.Sprintf("the file is larger than %d bytes", MAX_DOWNLOAD_FILE_SIZE)), nil
+	}
+	body, err := io.ReadAll(io.LimitReader(downloadRes.RawBody(), MAX_DOWNLOAD_FILE_SIZE+1))
+	if err != nil {
+		return buildFailedResult(err.Error(), ""), nil
+	}
+	if downloadRes.StatusCode() != http.StatusOK {
+		return buildFailedResult("An error occurred while downloading the file.", string(body)), nil
+	}
+	if len(body) > MAX_DOWNLOAD_FILE_SIZE {
+		return buildFailedResult("An error occurred while downloading the file.", fmt.Sprintf("the file is larger than %d bytes", MAX_DOWNLOAD_FILE_SIZE)), nil
+	}
+
+	return common.RuntimeResult{
+		Success: true,
+		Rows: []map[string]interface{}{0: {
+			"fileID":      fileOpts.FileID,
+			"contentType": downloadRes.Header().Get("Content-Type"),
+			"size":        len(body),
+			"data":        base64.StdEncoding.EncodeToString(body),
+		}},
+	}, nil
+}
+
+func (s *StorageExecutor) DeleteFile() (common.RuntimeResult, error) {
+	var fileOpts FileOpts
+	if err := mapstructure.Decode(s.action.Opts, &fileOpts); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// validate opts
+	if err := fileOpts.validate(); err != nil {
+		return common.RuntimeResult{}, err
+	}
+
+	deleteRes, err := s.client.DeleteFile(fileOpts.BucketID, fileOpts.FileID)
+	if err != nil {
+		return buildFailedResult(err.Error(), ""), nil
+	}
+	if deleteRes == nil {
+		return buildFailedResult("An error occurred while deleting the file.", "Unknown error"), nil
+	}
+	if deleteRes.StatusCode != 204 {
+		return buildFailedResult("An error occurred while deleting the file.", deleteRes.Result), nil
+	}
+
+	return common.RuntimeResult{
+		Success: true,
+		Rows: []map[string]interface{}{0: {
+			"message": "File deleted successfully.",
+			"success": true,
+			"result":  deleteRes.Result,
+		}},
+	}, nil
+}
+
+// FilePreviewURL builds the preview url, the api key is not included so the url only works for the files can be read publicly.
+func (s *StorageExecutor) FilePreviewURL() (common.RuntimeResult, error) {
+	var filePreviewOpts FilePreviewOpts
+	if err := mapstructure.Decode(s.action.Opts, &filePreviewOpts); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// validate opts
+	fileOpts := FileOpts{BucketID: filePreviewOpts.BucketID, FileID: filePreviewOpts.FileID}
+	if err := fileOpts.validate(); err != nil {
+		return common.RuntimeResult{}, err
+	}
+
+	query := url.Values{}
+	query.Set("project", s.resource.ProjectID)
+	if filePreviewOpts.Width > 0 {
+		query.Set("width", strconv.Itoa(filePreviewOpts.Width))
+	}
+	if filePreviewOpts.Height > 0 {
+		query.Set("height", strconv.Itoa(filePreviewOpts.Height))
+	}
+	if filePreviewOpts.Quality > 0 {
+		query.Set("quality", strconv.Itoa(filePreviewOpts.Quality))
+	}
+	if filePreviewOpts.Output != "" {
+		query.Set("output", filePreviewOpts.Output)
+	}
+	previewURL := s.buildFileURL(filePreviewOpts.BucketID, filePreviewOpts.FileID) + "/preview?" + query.Encode()
+
+	return common.RuntimeResult{
+		Success: true,
+		Rows:    []map[string]interface{}{0: {"url": previewURL}},
+	}, nil
+}
+
+func (o *FileOpts) validate() error {
+	if o.BucketID == "" {
+		return errors.New("bucketID is required")
+	}
+	if o.FileID == "" {
+		return errors.New("fileID is required")
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package appwrite
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUploadFileInChunks(t *testing.T) {
+	var mutex sync.Mutex
+	contentRanges := make([]string, 0)
+	ids := make([]string, 0)
+	uploaded := make([]byte, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		file, _, err := r.FormFile("file")
+		assert.Nil(t, err)
+		chunk, _ := io.ReadAll(file)
+		uploaded = append(uploaded, chunk...)
+		contentRanges = append(contentRanges, r.Header.Get("Content-Range"))
+		ids = append(ids, r.Header.Get(HEADER_ID)+"|"+r.FormValue("fileId"))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"$id":"file1"}`))
+	}))
+	defer server.Close()
+
+	data := bytes.Repeat([]byte("a"), 2*UPLOAD_CHUNK_SIZE+1)
+	executor := &StorageExecutor{
+		resource: Resource{Host: server.URL},
+		action: Action{Opts: map[string]interface{}{
+			"bucketID": "bucket1",
+			"fileName": "a.txt",
+			"data":     base64.StdEncoding.EncodeToString(data),
+		}},
+	}
+	result, err := executor.UploadFile()
+	assert.Nil(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, []string{
+		"bytes 0-5242879/10485761",
+		"bytes 5242880-10485759/10485761",
+		"bytes 10485760-10485760/10485761",
+	}, contentRanges)
+	assert.Equal(t, []string{"|" + UNIQUE_ID, "file1|file1", "file1|file1"}, ids)
+	assert.Equal(t, data, uploaded)
+}
+
+func TestUploadSmallFileWithoutContentRange(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "", r.Header.Get("Content-Range"))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"$id":"file1"}`))
+	}))
+	defer server.Close()
+
+	executor := &StorageExecutor{
+		resource: Resource{Host: server.URL},
+		action: Action{Opts: map[string]interface{}{
+			"bucketID": "bucket1",
+			"fileName": "a.txt",
+			"data":     base64.StdEncoding.EncodeToString([]byte("hello")),
+		}},
+	}
+	result, err := executor.UploadFile()
+	assert.Nil(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, 1, requests)
+}
+
+func TestDownloadFileRejectsLargeFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/storage/buckets/bucket1/files/large/download" {
+			// chunked response without content length
+			w.(http.Flusher).Flush()
+			w.Write(bytes.Repeat([]byte("a"), MAX_DOWNLOAD_FILE_SIZE+1))
+			return
+		}
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	executor := &StorageExecutor{
+		resource: Resource{Host: server.URL},
+		action:   Action{Opts: map[string]interface{}{"bucketID": "bucket1", "fileID": "large"}},
+	}
+	result, err := executor.DownloadFile()
+	assert.Nil(t, err)
+	assert.False(t, result.Success)
+
+	executor.action.Opts["fileID"] = "small"
+	result, err = executor.DownloadFile()
+	assert.Nil(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("hello")), result.Rows[0]["data"])
+}
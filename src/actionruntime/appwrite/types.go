@@ -20,6 +20,15 @@ const (
 	GET_METHOD    = "get"
 	UPDATE_METHOD = "update"
 	DELETE_METHOD = "delete"
+
+	LIST_FILES_METHOD       = "listFiles"
+	UPLOAD_FILE_METHOD      = "uploadFile"
+	DOWNLOAD_FILE_METHOD    = "downloadFile"
+	DELETE_FILE_METHOD      = "deleteFile"
+	FILE_PREVIEW_URL_METHOD = "filePreviewURL"
+	EXECUTE_FUNCTION_METHOD = "executeFunction"
+
+	UNIQUE_ID = "unique()"
 )
 
 type Resource struct {
@@ -30,7 +39,7 @@ type Resource struct {
 }
 
 type Action struct {
-	Method string                 `validate:"required,oneof=list create get update delete"`
+	Method string                 `validate:"required,oneof=list create get update delete listFiles uploadFile downloadFile deleteFile filePreviewURL executeFunction"`
 	Opts   map[string]interface{} `validate:"required"`
 }
 
@@ -62,3 +71,39 @@ type WithDataOpts struct {
 	DocumentID   string
 	Data         map[string]interface{}
 }
+
+type ListFilesOpts struct {
+	BucketID string
+	Search   string
+	Limit    int
+	Offset   int
+}
+
+type FileOpts struct {
+	BucketID string
+	FileID   string
+}
+
+type UploadFileOpts struct {
+	BucketID    string
+	FileID      string
+	FileName    string
+	ContentType string
+	Data        string
+	Permissions []string
+}
+
+type FilePreviewOpts struct {
+	BucketID string
+	FileID   string
+	Width    int
+	Height   int
+	Quality  int
+	Output   string
+}
+
+type ExecuteFunctionOpts struct {
+	FunctionID string
+	Payload    interface{}
+	Async      bool
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -32,6 +33,26 @@ const (
 )
 
 func (c *Connector) getConnectionWithOptions(resourceOptions map[string]interface{}) (*sql.DB, error) {
+	opts, err := c.exportConnectionOptions(resourceOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	db := clickhouse.OpenDB(opts)
+
+	return db, nil
+}
+
+// getNativeConnectionWithOptions returns the native protocol connection, which supports batch insert.
+func (c *Connector) getNativeConnectionWithOptions(resourceOptions map[string]interface{}) (driver.Conn, error) {
+	opts, err := c.exportConnectionOptions(resourceOptions)
+	if err != nil {
+		return nil, err
+	}
+	return clickhouse.Open(opts)
+}
+
+func (c *Connector) exportConnectionOptions(resourceOptions map[string]interface{}) (*clickhouse.Options, error) {
 	if err := mapstructure.Decode(resourceOptions, &c.ResourceOpts); err != nil {
 		return nil, err
 	}
@@ -68,9 +89,7 @@ func (c *Connector) getConnectionWithOptions(resourceOptions map[string]interfac
 		opts.TLS = t
 	}
 
-	db := clickhouse.OpenDB(&opts)
-
-	return db, nil
+	return &opts, nil
 }
 
 func tablesInfo(db *sql.DB, dbName string) []string {
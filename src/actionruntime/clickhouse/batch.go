@@ -0,0 +1,299 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	decimalType = reflect.TypeOf(decimal.Decimal{})
+)
+
+// recordGroup is the records with same key set, they are inserted by one batch so the missed columns are filled by column default.
+type recordGroup struct {
+	columns []string
+	indexes []int
+	// values are the converted records in order of indexes
+	values [][]interface{}
+}
+
+// batchInsert streams the records to table by native protocol batch, the record values are converted to the column types,
+// since the json numbers are all float64 and the driver does not convert them.
+// The records are grouped by key set, and all records are converted before sending any batch. Then the batches are prepared and
+// sent one by one, since every prepared batch holds a connection of pool until sent.
+func (c *Connector) batchInsert(conn driver.Conn, progress *queryProgress) (common.RuntimeResult, error) {
+	groups := groupRecordsByColumns(c.ActionOpts.Records)
+	if len(groups) == 0 {
+		return common.RuntimeResult{Success: false}, errors.New("missing records to insert")
+	}
+	table := quoteTableName(c.ActionOpts.Table)
+	ctx := progress.exportContext(c.ActionOpts.ExportSettings())
+
+	// convert records
+	for _, group := range groups {
+		// fetch column types
+		rows, err := conn.Query(ctx, fmt.Sprintf("SELECT %s FROM %s LIMIT 0", group.exportColumnList(), table))
+		if err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		columnTypes := rows.ColumnTypes()
+		rows.Close()
+
+		group.values = make([][]interface{}, 0, len(group.indexes))
+		for _, i := range group.indexes {
+			record := c.ActionOpts.Records[i]
+			values := make([]interface{}, 0, len(group.columns))
+			for j, column := range group.columns {
+				value, errInConvert := convertValue(record[column], columnTypes[j].ScanType())
+				if errInConvert != nil {
+					return common.RuntimeResult{Success: false}, fmt.Errorf("convert column %s of record %d failed: %w", column, i, errInConvert)
+				}
+				values = append(values, value)
+			}
+			group.values = append(group.values, values)
+		}
+	}
+
+	// send batches
+	insertedRows := 0
+	for _, group := range groups {
+		if err := sendRecordGroup(ctx, conn, table, group); err != nil {
+			return common.RuntimeResult{Success: false}, fmt.Errorf("inserted %d rows before failed: %w", insertedRows, err)
+		}
+		insertedRows += len(group.values)
+	}
+
+	extra := map[string]interface{}{
+		"message": fmt.Sprintf("Inserted %d rows.", insertedRows),
+	}
+	progress.exportToExtra(extra)
+	return common.RuntimeResult{
+		Success: true,
+		Rows:    []map[string]interface{}{},
+		Extra:   extra,
+	}, nil
+}
+
+// sendRecordGroup inserts the converted records of group by one batch, the batch is aborted when appending failed.
+func sendRecordGroup(ctx context.Context, conn driver.Conn, table string, group *recordGroup) error {
+	batch, err := conn.PrepareBatch(ctx, fmt.Sprintf("INSERT INTO %s (%s)", table, group.exportColumnList()))
+	if err != nil {
+		return err
+	}
+	for serial, values := range group.values {
+		if err := batch.Append(values...); err != nil {
+			batch.Abort()
+			return fmt.Errorf("append record %d failed: %w", group.indexes[serial], err)
+		}
+	}
+	return batch.Send()
+}
+
+func (group *recordGroup) exportColumnList() string {
+	quotedColumns := make([]string, 0, len(group.columns))
+	for _, column := range group.columns {
+		quotedColumns = append(quotedColumns, quoteIdentifier(column))
+	}
+	return strings.Join(quotedColumns, ", ")
+}
+
+// groupRecordsByColumns groups the records by sorted key set in order of first appearance,
+// the column missed in record is not inserted so the column default is used.
+func groupRecordsByColumns(records []map[string]interface{}) []*recordGroup {
+	groups := make([]*recordGroup, 0)
+	groupByColumns := make(map[string]*recordGroup)
+	for i, record := range records {
+		if len(record) == 0 {
+			continue
+		}
+		columns := make([]string, 0, len(record))
+		for column := range record {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		// the NUL character is not allowed in clickhouse identifier
+		key := strings.Join(columns, "\x00")
+		group, hit := groupByColumns[key]
+		if !hit {
+			group = &recordGroup{columns: columns}
+			groupByColumns[key] = group
+			groups = append(groups, group)
+		}
+		group.indexes = append(group.indexes, i)
+	}
+	return groups
+}
+
+func quoteIdentifier(identifier string) string {
+	return "`" + strings.ReplaceAll(identifier, "`", "``") + "`"
+}
+
+func quoteTableName(table string) string {
+	parts := strings.SplitN(table, ".", 2)
+	for i, part := range parts {
+		parts[i] = quoteIdentifier(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+func convertValue(value interface{}, scanType reflect.Type) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	for scanType.Kind() == reflect.Ptr {
+		scanType = scanType.Elem()
+	}
+	rv := reflect.ValueOf(value)
+	switch {
+	case scanType == timeType:
+		switch v := value.(type) {
+		case float64:
+			return time.Unix(int64(v), 0), nil
+		case string:
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t, nil
+			}
+		}
+	case scanType == decimalType:
+		switch v := value.(type) {
+		case float64:
+			return decimal.NewFromFloat(v), nil
+		case string:
+			return decimal.NewFromString(v)
+		}
+	case scanType.Kind() == reflect.String:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case []interface{}, map[string]interface{}:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			return string(b), nil
+		default:
+			return fmt.Sprint(v), nil
+		}
+	case scanType.Kind() == reflect.Slice && scanType.Elem().Kind() != reflect.Uint8:
+		items, ok := value.([]interface{})
+		if !ok {
+			break
+		}
+		slice := reflect.MakeSlice(scanType, len(items), len(items))
+		for i, item := range items {
+			if err := setConvertedValue(slice.Index(i), item); err != nil {
+				return nil, err
+			}
+		}
+		return slice.Interface(), nil
+	case scanType.Kind() == reflect.Map:
+		entries, ok := value.(map[string]interface{})
+		if !ok {
+			break
+		}
+		m := reflect.MakeMapWithSize(scanType, len(entries))
+		for key, entry := range entries {
+			k := reflect.New(scanType.Key()).Elem()
+			if err := setConvertedValue(k, key); err != nil {
+				return nil, err
+			}
+			v := reflect.New(scanType.Elem()).Elem()
+			if err := setConvertedValue(v, entry); err != nil {
+				return nil, err
+			}
+			m.SetMapIndex(k, v)
+		}
+		return m.Interface(), nil
+	case isNumericKind(scanType.Kind()) && isNumericKind(rv.Kind()):
+		return rv.Convert(scanType).Interface(), nil
+	case isNumericKind(scanType.Kind()) && rv.Kind() == reflect.String:
+		return parseNumber(value.(string), scanType)
+	}
+	// leave the value to driver, like uuid, ip and enum in string
+	return value, nil
+}
+
+// setConvertedValue converts the value and sets it to target, the pointer target is allocated for nullable element.
+func setConvertedValue(target reflect.Value, value interface{}) error {
+	converted, err := convertValue(value, target.Type())
+	if err != nil {
+		return err
+	}
+	if converted == nil {
+		return nil
+	}
+	cv := reflect.ValueOf(converted)
+	if target.Kind() == reflect.Ptr {
+		ptr := reflect.New(target.Type().Elem())
+		if !cv.Type().AssignableTo(ptr.Elem().Type()) {
+			return fmt.Errorf("can not convert %T to %s", value, target.Type())
+		}
+		ptr.Elem().Set(cv)
+		target.Set(ptr)
+		return nil
+	}
+	if !cv.Type().AssignableTo(target.Type()) {
+		return fmt.Errorf("can not convert %T to %s", value, target.Type())
+	}
+	target.Set(cv)
+	return nil
+}
+
+func isNumericKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func parseNumber(value string, scanType reflect.Type) (interface{}, error) {
+	switch scanType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, scanType.Bits())
+		if err != nil {
+			return nil, err
+		}
+		return reflect.ValueOf(n).Convert(scanType).Interface(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, scanType.Bits())
+		if err != nil {
+			return nil, err
+		}
+		return reflect.ValueOf(n).Convert(scanType).Interface(), nil
+	default:
+		n, err := strconv.ParseFloat(value, scanType.Bits())
+		if err != nil {
+			return nil, err
+		}
+		return reflect.ValueOf(n).Convert(scanType).Interface(), nil
+	}
+}
@@ -0,0 +1,199 @@
+package clickhouse
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupRecordsByColumns(t *testing.T) {
+	groups := groupRecordsByColumns([]map[string]interface{}{
+		{"id": 1.0, "name": "alice"},
+		{"id": 2.0},
+		{"name": "carol", "id": 3.0},
+		{},
+		{"id": 5.0, "name": nil},
+	})
+	assert.Equal(t, 2, len(groups))
+	assert.Equal(t, []string{"id", "name"}, groups[0].columns)
+	// the explicit null is inserted as null, only the missed column uses default
+	assert.Equal(t, []int{0, 2, 4}, groups[0].indexes)
+	assert.Equal(t, []string{"id"}, groups[1].columns)
+	assert.Equal(t, []int{1}, groups[1].indexes)
+
+	assert.Equal(t, 0, len(groupRecordsByColumns([]map[string]interface{}{{}})))
+}
+
+func TestConvertNumberValue(t *testing.T) {
+	value, err := convertValue(42.0, reflect.TypeOf(uint8(0)))
+	assert.Nil(t, err)
+	assert.Equal(t, uint8(42), value)
+
+	// nullable column
+	value, err = convertValue(float64(1<<40), reflect.TypeOf((*int64)(nil)))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1<<40), value)
+
+	value, err = convertValue("18446744073709551615", reflect.TypeOf(uint64(0)))
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(18446744073709551615), value)
+
+	value, err = convertValue("1.5", reflect.TypeOf(float32(0)))
+	assert.Nil(t, err)
+	assert.Equal(t, float32(1.5), value)
+
+	_, err = convertValue("not a number", reflect.TypeOf(int32(0)))
+	assert.NotNil(t, err)
+
+	value, err = convertValue(nil, reflect.TypeOf(int32(0)))
+	assert.Nil(t, err)
+	assert.Nil(t, value)
+}
+
+func TestConvertTimeAndDecimalValue(t *testing.T) {
+	value, err := convertValue(1700000000.0, timeType)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Unix(1700000000, 0), value)
+
+	value, err = convertValue("2023-11-14T22:13:20Z", timeType)
+	assert.Nil(t, err)
+	assert.True(t, time.Unix(1700000000, 0).Equal(value.(time.Time)))
+
+	value, err = convertValue("12.345", decimalType)
+	assert.Nil(t, err)
+	assert.True(t, decimal.RequireFromString("12.345").Equal(value.(decimal.Decimal)))
+
+	value, err = convertValue(0.5, decimalType)
+	assert.Nil(t, err)
+	assert.True(t, decimal.NewFromFloat(0.5).Equal(value.(decimal.Decimal)))
+}
+
+func TestConvertStringValue(t *testing.T) {
+	value, err := convertValue(map[string]interface{}{"k": "v"}, reflect.TypeOf(""))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"k":"v"}`, value)
+
+	value, err = convertValue(12.0, reflect.TypeOf(""))
+	assert.Nil(t, err)
+	assert.Equal(t, "12", value)
+
+	// uuid is left to driver
+	value, err = convertValue("5d4c8b8e-6a47-4a4c-9bd8-8e1b3a3c1f00", reflect.TypeOf([16]byte{}))
+	assert.Nil(t, err)
+	assert.Equal(t, "5d4c8b8e-6a47-4a4c-9bd8-8e1b3a3c1f00", value)
+}
+
+func TestConvertCompositeValue(t *testing.T) {
+	value, err := convertValue([]interface{}{1.0, 2.0, nil}, reflect.TypeOf([]*int16{}))
+	assert.Nil(t, err)
+	items := value.([]*int16)
+	assert.Equal(t, 3, len(items))
+	assert.Equal(t, int16(1), *items[0])
+	assert.Equal(t, int16(2), *items[1])
+	assert.Nil(t, items[2])
+
+	value, err = convertValue(map[string]interface{}{"a": 1.0, "b": "2"}, reflect.TypeOf(map[string]uint64{}))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]uint64{"a": 1, "b": 2}, value)
+
+	value, err = convertValue([]interface{}{[]interface{}{"x"}, []interface{}{}}, reflect.TypeOf([][]string{}))
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"x"}, {}}, value)
+
+	_, err = convertValue([]interface{}{"x"}, reflect.TypeOf([]time.Time{}))
+	assert.NotNil(t, err)
+}
+
+func TestQuoteTableName(t *testing.T) {
+	assert.Equal(t, "`db`.`events`", quoteTableName("db.events"))
+	assert.Equal(t, "`ev``ents`", quoteTableName("ev`ents"))
+}
+
+// fakeConn answers the column types of query and counts the batches holding connections.
+type fakeConn struct {
+	driver.Conn
+	columnTypes    map[string]reflect.Type
+	openBatches    int
+	maxOpenBatches int
+	sentRows       int
+}
+
+type fakeRows struct {
+	driver.Rows
+	columnTypes []driver.ColumnType
+}
+
+type fakeColumnType struct {
+	driver.ColumnType
+	scanType reflect.Type
+}
+
+type fakeBatch struct {
+	driver.Batch
+	conn *fakeConn
+	rows int
+}
+
+func (c *fakeConn) Query(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+	columnList := strings.TrimPrefix(strings.SplitN(query, " FROM ", 2)[0], "SELECT ")
+	columnTypes := make([]driver.ColumnType, 0)
+	for _, column := range strings.Split(columnList, ", ") {
+		columnTypes = append(columnTypes, &fakeColumnType{scanType: c.columnTypes[strings.Trim(column, "`")]})
+	}
+	return &fakeRows{columnTypes: columnTypes}, nil
+}
+
+func (c *fakeConn) PrepareBatch(ctx context.Context, query string, opts ...driver.PrepareBatchOption) (driver.Batch, error) {
+	c.openBatches++
+	if c.openBatches > c.maxOpenBatches {
+		c.maxOpenBatches = c.openBatches
+	}
+	return &fakeBatch{conn: c}, nil
+}
+
+func (r *fakeRows) ColumnTypes() []driver.ColumnType { return r.columnTypes }
+func (r *fakeRows) Close() error                     { return nil }
+func (t *fakeColumnType) ScanType() reflect.Type     { return t.scanType }
+func (b *fakeBatch) Append(v ...any) error           { b.rows++; return nil }
+func (b *fakeBatch) Abort() error                    { b.conn.openBatches--; return nil }
+func (b *fakeBatch) Send() error {
+	b.conn.openBatches--
+	b.conn.sentRows += b.rows
+	return nil
+}
+
+func TestBatchInsertSendsGroupsOneByOne(t *testing.T) {
+	conn := &fakeConn{columnTypes: map[string]reflect.Type{"id": reflect.TypeOf(uint32(0)), "a": reflect.TypeOf(""), "b": reflect.TypeOf(""), "c": reflect.TypeOf("")}}
+	connector := &Connector{ActionOpts: Action{Table: "events", Records: []map[string]interface{}{
+		{"id": 1.0},
+		{"id": 2.0, "a": "x"},
+		{"id": 3.0, "b": "y"},
+		{"id": 4.0, "c": "z"},
+		{"id": 5.0, "a": "x"},
+	}}}
+	result, err := connector.batchInsert(conn, newQueryProgress())
+	assert.Nil(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, 5, conn.sentRows)
+	assert.Equal(t, 1, conn.maxOpenBatches)
+	assert.Equal(t, 0, conn.openBatches)
+}
+
+func TestBatchInsertConvertsAllGroupsBeforeSending(t *testing.T) {
+	conn := &fakeConn{columnTypes: map[string]reflect.Type{"id": reflect.TypeOf(uint32(0)), "a": reflect.TypeOf(int32(0))}}
+	connector := &Connector{ActionOpts: Action{Table: "events", Records: []map[string]interface{}{
+		{"id": 1.0},
+		{"id": 2.0, "a": "not a number"},
+	}}}
+	result, err := connector.batchInsert(conn, newQueryProgress())
+	assert.NotNil(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, 0, conn.maxOpenBatches)
+	assert.Equal(t, 0, conn.sentRows)
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+)
+
+var (
+	exceptionLineRegexp     = regexp.MustCompile(`\(line (\d+), col \d+\)`)
+	exceptionPositionRegexp = regexp.MustCompile(`failed at position (\d+)`)
+)
+
+func (c *Connector) ClassifyError(err error) *common.ConnectorError {
+	var exception *clickhouse.Exception
+	if !errors.As(err, &exception) {
+		return common.ClassifyNetworkError(err)
+	}
+	var connectorError *common.ConnectorError
+	switch exception.Code {
+	case 62:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_SYNTAX, err)
+	case 192, 516:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_AUTH, err)
+	case 497:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_PERMISSION, err)
+	case 47, 60, 81:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_NOT_FOUND, err)
+	case 159, 209:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_TIMEOUT, err).SetRetryable(true)
+	case 210:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_CONNECTION, err).SetRetryable(true)
+	default:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_UNKNOWN, err)
+	}
+	connectorError.SetVendorCode(strconv.Itoa(int(exception.Code))).SetMessage(exception.Message)
+
+	// syntax error message looks like "Syntax error: failed at position 15 ('FORM') (line 1, col 15): ..."
+	if matched := exceptionLineRegexp.FindStringSubmatch(exception.Message); len(matched) == 2 {
+		lineNumber, _ := strconv.Atoi(matched[1])
+		connectorError.SetLineNumber(lineNumber)
+	}
+	if matched := exceptionPositionRegexp.FindStringSubmatch(exception.Message); len(matched) == 2 {
+		position, _ := strconv.Atoi(matched[1])
+		connectorError.SetPosition(position)
+	}
+	return connectorError
+}
@@ -0,0 +1,57 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouse
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+// queryProgress accumulates the progress packets sent by server during query, the packets carry increments.
+type queryProgress struct {
+	rowsRead     uint64
+	bytesRead    uint64
+	rowsWritten  uint64
+	bytesWritten uint64
+	startedAt    time.Time
+}
+
+func newQueryProgress() *queryProgress {
+	return &queryProgress{
+		startedAt: time.Now(),
+	}
+}
+
+func (p *queryProgress) update(progress *clickhouse.Progress) {
+	atomic.AddUint64(&p.rowsRead, progress.Rows)
+	atomic.AddUint64(&p.bytesRead, progress.Bytes)
+	atomic.AddUint64(&p.rowsWritten, progress.WroteRows)
+	atomic.AddUint64(&p.bytesWritten, progress.WroteBytes)
+}
+
+func (p *queryProgress) exportContext(settings clickhouse.Settings) context.Context {
+	return clickhouse.Context(context.Background(), clickhouse.WithSettings(settings), clickhouse.WithProgress(p.update))
+}
+
+func (p *queryProgress) exportToExtra(extra map[string]interface{}) {
+	extra["rowsRead"] = atomic.LoadUint64(&p.rowsRead)
+	extra["bytesRead"] = atomic.LoadUint64(&p.bytesRead)
+	extra["rowsWritten"] = atomic.LoadUint64(&p.rowsWritten)
+	extra["bytesWritten"] = atomic.LoadUint64(&p.bytesWritten)
+	extra["elapsedMs"] = time.Since(p.startedAt).Milliseconds()
+}
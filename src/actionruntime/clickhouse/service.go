@@ -29,6 +29,23 @@ import (
 type Connector struct {
 	ResourceOpts Resource
 	ActionOpts   Action
+	resultStream common.ResultStream
+}
+
+func (c *Connector) SetResultStream(stream common.ResultStream) {
+	c.resultStream = stream
+}
+
+func (c *Connector) IsReadOnlyAction(actionOptions map[string]interface{}) bool {
+	mode, _ := actionOptions["mode"].(string)
+	query, _ := actionOptions["query"].(string)
+	return common.IsReadOnlySQLQuery(mode, query)
+}
+
+func (c *Connector) IsWriteAction(actionOptions map[string]interface{}) bool {
+	mode, _ := actionOptions["mode"].(string)
+	query, _ := actionOptions["query"].(string)
+	return common.IsWriteSQLQuery(mode, query)
 }
 
 func (c *Connector) ValidateResourceOptions(resourceOptions map[string]interface{}) (common.ValidateResult, error) {
@@ -99,6 +116,22 @@ func (c *Connector) GetMetaInfo(resourceOptions map[string]interface{}) (common.
 }
 
 func (c *Connector) Run(resourceOptions map[string]interface{}, actionOptions map[string]interface{}, rawActionOptions map[string]interface{}) (common.RuntimeResult, error) {
+	// format query
+	if err := mapstructure.Decode(actionOptions, &c.ActionOpts); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	progress := newQueryProgress()
+
+	// insert records by native batch in gui mode
+	if c.ActionOpts.IsGUIMode() {
+		conn, err := c.getNativeConnectionWithOptions(resourceOptions)
+		if err != nil {
+			return common.RuntimeResult{Success: false}, errors.New("failed to get clickhouse connection")
+		}
+		defer conn.Close()
+		return c.batchInsert(conn, progress)
+	}
+
 	// get clickhouse connection
 	db, err := c.getConnectionWithOptions(resourceOptions)
 	if err != nil {
@@ -106,11 +139,6 @@ func (c *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 	}
 	defer db.Close()
 
-	// format query
-	if err := mapstructure.Decode(actionOptions, &c.ActionOpts); err != nil {
-		return common.RuntimeResult{Success: false}, err
-	}
-
 	// set context field
 	errInSetRawQuery := c.ActionOpts.SetRawQueryAndContext(rawActionOptions)
 	if errInSetRawQuery != nil {
@@ -138,44 +166,27 @@ func (c *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 		return common.RuntimeResult{Success: false}, err
 	}
 
+	// the query settings and progress are passed by context
+	ctx := progress.exportContext(c.ActionOpts.ExportSettings())
+	if !c.ActionOpts.IsSafeMode() {
+		sqlArgs = nil
+	}
+
 	// fetch data
-	if isSelectQuery && c.ActionOpts.IsSafeMode() {
-		rows, err := db.Query(escapedSQL, sqlArgs...)
-		if err != nil {
-			return queryResult, err
-		}
-		mapRes, err := common.RetrieveToMap(rows)
-		if err != nil {
-			return queryResult, err
-		}
-		defer rows.Close()
-		queryResult.Success = true
-		queryResult.Rows = mapRes
-	} else if isSelectQuery && !c.ActionOpts.IsSafeMode() {
-		rows, err := db.Query(escapedSQL)
+	if isSelectQuery {
+		rows, err := db.QueryContext(ctx, escapedSQL, sqlArgs...)
 		if err != nil {
 			return queryResult, err
 		}
-		mapRes, err := common.RetrieveToMap(rows)
+		mapRes, err := common.RetrieveToMapOrStream(rows, c.resultStream)
 		if err != nil {
 			return queryResult, err
 		}
 		defer rows.Close()
 		queryResult.Success = true
 		queryResult.Rows = mapRes
-	} else if !isSelectQuery && c.ActionOpts.IsSafeMode() { // update, insert, delete data
-		execResult, err := db.Exec(escapedSQL, sqlArgs...)
-		if err != nil {
-			return queryResult, err
-		}
-		affectedRows, err := execResult.RowsAffected()
-		if err != nil {
-			return queryResult, err
-		}
-		queryResult.Success = true
-		queryResult.Extra["message"] = fmt.Sprintf("Affeted %d rows.", affectedRows)
-	} else if !isSelectQuery && !c.ActionOpts.IsSafeMode() { // update, insert, delete data
-		execResult, err := db.Exec(escapedSQL)
+	} else { // update, insert, delete data
+		execResult, err := db.ExecContext(ctx, escapedSQL, sqlArgs...)
 		if err != nil {
 			return queryResult, err
 		}
@@ -186,6 +197,7 @@ func (c *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 		queryResult.Success = true
 		queryResult.Extra["message"] = fmt.Sprintf("Affeted %d rows.", affectedRows)
 	}
+	progress.exportToExtra(queryResult.Extra)
 
 	return queryResult, nil
 }
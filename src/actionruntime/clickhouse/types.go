@@ -16,7 +16,9 @@ package clickhouse
 
 import (
 	"errors"
+	"math"
 
+	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/illacloud/builder-backend/src/actionruntime/common"
 )
 
@@ -25,6 +27,11 @@ const (
 	FIELD_QUERY   = "query"
 )
 
+const (
+	SETTING_ASYNC_INSERT          = "async_insert"
+	SETTING_WAIT_FOR_ASYNC_INSERT = "wait_for_async_insert"
+)
+
 type Resource struct {
 	Host         string `validate:"required"`
 	Port         int    `validate:"gt=0"`
@@ -42,17 +49,58 @@ type SSLOptions struct {
 	ClientCert string
 }
 
+// Action in gui mode inserts the records into table by native batch, the sql and sql-safe mode run the raw query.
 type Action struct {
-	Query    string
-	Mode     string `validate:"required,oneof=gui sql sql-safe"`
-	RawQuery string
-	Context  map[string]interface{}
+	Query              string
+	Mode               string                   `validate:"required,oneof=gui sql sql-safe"`
+	Table              string                   `validate:"required_if=Mode gui"`
+	Records            []map[string]interface{} `validate:"required_if=Mode gui"`
+	AsyncInsert        bool
+	WaitForAsyncInsert bool
+	Settings           map[string]interface{}
+	RawQuery           string
+	Context            map[string]interface{}
 }
 
 func (q *Action) IsSafeMode() bool {
 	return q.Mode == common.MODE_SQL_SAFE
 }
 
+func (q *Action) IsGUIMode() bool {
+	return q.Mode == common.MODE_GUI
+}
+
+// ExportSettings returns the query settings, the integral json numbers are sent as integer since clickhouse rejects "1e+10" like values.
+func (q *Action) ExportSettings() clickhouse.Settings {
+	settings := clickhouse.Settings{}
+	for key, value := range q.Settings {
+		switch v := value.(type) {
+		case float64:
+			if v == math.Trunc(v) {
+				settings[key] = int64(v)
+			} else {
+				settings[key] = v
+			}
+		case bool:
+			if v {
+				settings[key] = 1
+			} else {
+				settings[key] = 0
+			}
+		default:
+			settings[key] = v
+		}
+	}
+	if q.IsGUIMode() && q.AsyncInsert {
+		settings[SETTING_ASYNC_INSERT] = 1
+		settings[SETTING_WAIT_FOR_ASYNC_INSERT] = 0
+		if q.WaitForAsyncInsert {
+			settings[SETTING_WAIT_FOR_ASYNC_INSERT] = 1
+		}
+	}
+	return settings
+}
+
 func (q *Action) SetRawQueryAndContext(rawTemplate map[string]interface{}) error {
 	queryRaw, hit := rawTemplate[FIELD_QUERY]
 	if !hit {
@@ -0,0 +1,35 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportSettings(t *testing.T) {
+	action := &Action{
+		Mode: "sql",
+		Settings: map[string]interface{}{
+			"max_execution_time":     1e10,
+			"max_memory_usage_rate":  0.5,
+			"readonly":               true,
+			"use_uncompressed_cache": false,
+			"insert_quorum":          "auto",
+		},
+		AsyncInsert: true,
+	}
+	assert.Equal(t, clickhouse.Settings{
+		"max_execution_time":     int64(1e10),
+		"max_memory_usage_rate":  0.5,
+		"readonly":               1,
+		"use_uncompressed_cache": 0,
+		"insert_quorum":          "auto",
+	}, action.ExportSettings())
+
+	// the async insert is only applied to gui mode
+	action = &Action{Mode: "gui", AsyncInsert: true}
+	assert.Equal(t, clickhouse.Settings{SETTING_ASYNC_INSERT: 1, SETTING_WAIT_FOR_ASYNC_INSERT: 0}, action.ExportSettings())
+	action.WaitForAsyncInsert = true
+	assert.Equal(t, clickhouse.Settings{SETTING_ASYNC_INSERT: 1, SETTING_WAIT_FOR_ASYNC_INSERT: 1}, action.ExportSettings())
+}
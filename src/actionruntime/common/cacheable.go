@@ -0,0 +1,60 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"strings"
+
+	parser_sql "github.com/illacloud/builder-backend/src/utils/parser/sql"
+)
+
+// CacheableDataConnector is implemented by the connectors which can tell whether an action only reads data or writes data.
+// The results of read only actions can be cached, write actions will invalidate the cached results of the same resource.
+// An action can be neither, like an empty query or a status polling, it is not cached and invalidates nothing.
+type CacheableDataConnector interface {
+	IsReadOnlyAction(actionOptions map[string]interface{}) bool
+	IsWriteAction(actionOptions map[string]interface{}) bool
+}
+
+// IsReadOnlySQLQuery reports whether a sql action only reads data, only select query in sql mode is read only.
+func IsReadOnlySQLQuery(mode string, query string) bool {
+	if mode != MODE_SQL && mode != MODE_SQL_SAFE {
+		return false
+	}
+	if strings.TrimSpace(query) == "" {
+		return false
+	}
+	isSelectQuery, errInParse := parser_sql.IsSelectSQL(parser_sql.NewLexer(query))
+	if errInParse != nil {
+		return false
+	}
+	return isSelectQuery
+}
+
+// IsWriteSQLQuery reports whether a sql action may write data. The gui and procedure mode actions and the sql mode
+// queries which are not select (or can not be parsed) are treated as writes, empty queries are not.
+func IsWriteSQLQuery(mode string, query string) bool {
+	if mode != MODE_SQL && mode != MODE_SQL_SAFE {
+		return mode == MODE_GUI || mode == MODE_PROCEDURE
+	}
+	if strings.TrimSpace(query) == "" {
+		return false
+	}
+	isSelectQuery, errInParse := parser_sql.IsSelectSQL(parser_sql.NewLexer(query))
+	if errInParse != nil {
+		return true
+	}
+	return !isSelectQuery
+}
@@ -0,0 +1,26 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsReadOnlySQLQuery(t *testing.T) {
+	assert.True(t, IsReadOnlySQLQuery(MODE_SQL, "select * from users"))
+	assert.True(t, IsReadOnlySQLQuery(MODE_SQL_SAFE, "SELECT id FROM users WHERE id = $1"))
+	assert.False(t, IsReadOnlySQLQuery(MODE_SQL, "update users set name = 'a'"))
+	assert.False(t, IsReadOnlySQLQuery(MODE_SQL, "  "))
+	assert.False(t, IsReadOnlySQLQuery(MODE_GUI, "select * from users"))
+	assert.False(t, IsReadOnlySQLQuery(MODE_PROCEDURE, ""))
+}
+
+func TestIsWriteSQLQuery(t *testing.T) {
+	assert.True(t, IsWriteSQLQuery(MODE_SQL, "update users set name = 'a'"))
+	assert.True(t, IsWriteSQLQuery(MODE_SQL_SAFE, "delete from users where id = $1"))
+	assert.True(t, IsWriteSQLQuery(MODE_GUI, ""))
+	assert.True(t, IsWriteSQLQuery(MODE_PROCEDURE, ""))
+	assert.False(t, IsWriteSQLQuery(MODE_SQL, "select * from users"))
+	assert.False(t, IsWriteSQLQuery(MODE_SQL, ""))
+	assert.False(t, IsWriteSQLQuery("", "update users set name = 'a'"))
+}
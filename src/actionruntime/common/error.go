@@ -0,0 +1,192 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+)
+
+const (
+	CONNECTOR_ERROR_CATEGORY_SYNTAX     = "syntax"
+	CONNECTOR_ERROR_CATEGORY_AUTH       = "auth"
+	CONNECTOR_ERROR_CATEGORY_CONNECTION = "connection"
+	CONNECTOR_ERROR_CATEGORY_TIMEOUT    = "timeout"
+	CONNECTOR_ERROR_CATEGORY_PERMISSION = "permission"
+	CONNECTOR_ERROR_CATEGORY_CONSTRAINT = "constraint"
+	CONNECTOR_ERROR_CATEGORY_NOT_FOUND  = "not-found"
+	CONNECTOR_ERROR_CATEGORY_UNKNOWN    = "unknown"
+)
+
+// ConnectorError is the normalized error returned by connectors, it will be feedback to client as "errorData".
+// The Error() method keeps the original error message, so the error message is the same as before.
+type ConnectorError struct {
+	Category   string `json:"category"`
+	VendorCode string `json:"vendorCode,omitempty"`
+	LineNumber int    `json:"lineNumber,omitempty"`
+	Position   int    `json:"position,omitempty"`
+	Retryable  bool   `json:"retryable"`
+	Message    string `json:"message"`
+	err        error
+	// feedbackMessage replaces the "errorMessage" of the feedback, some connectors responded a fixed message before
+	feedbackMessage string
+}
+
+func NewConnectorError(category string, err error) *ConnectorError {
+	return &ConnectorError{
+		Category: category,
+		Message:  err.Error(),
+		err:      err,
+	}
+}
+
+func (e *ConnectorError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ConnectorError) Unwrap() error {
+	return e.err
+}
+
+func (e *ConnectorError) SetVendorCode(vendorCode string) *ConnectorError {
+	e.VendorCode = vendorCode
+	return e
+}
+
+func (e *ConnectorError) SetLineNumber(lineNumber int) *ConnectorError {
+	e.LineNumber = lineNumber
+	return e
+}
+
+func (e *ConnectorError) SetPosition(position int) *ConnectorError {
+	e.Position = position
+	return e
+}
+
+func (e *ConnectorError) SetRetryable(retryable bool) *ConnectorError {
+	e.Retryable = retryable
+	return e
+}
+
+func (e *ConnectorError) SetMessage(message string) *ConnectorError {
+	e.Message = message
+	return e
+}
+
+func (e *ConnectorError) SetFeedbackMessage(feedbackMessage string) *ConnectorError {
+	e.feedbackMessage = feedbackMessage
+	return e
+}
+
+// ExportFeedbackMessage returns the feedback message if connector set, or the given default message
+func (e *ConnectorError) ExportFeedbackMessage(defaultMessage string) string {
+	if e.feedbackMessage != "" {
+		return e.feedbackMessage
+	}
+	return defaultMessage
+}
+
+func AsConnectorError(err error) (*ConnectorError, bool) {
+	var connectorError *ConnectorError
+	if errors.As(err, &connectorError) {
+		return connectorError, true
+	}
+	return nil, false
+}
+
+// ErrorClassifiableDataConnector is implemented by the connectors which can convert the driver errors into ConnectorError
+type ErrorClassifiableDataConnector interface {
+	ClassifyError(err error) *ConnectorError
+}
+
+// NormalizeRunError converts the error returned by connector Run() method into ConnectorError if the connector supports.
+func NormalizeRunError(connector DataConnector, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, isConnectorError := AsConnectorError(err); isConnectorError {
+		return err
+	}
+	classifier, isClassifiable := connector.(ErrorClassifiableDataConnector)
+	if !isClassifiable {
+		return err
+	}
+	if connectorError := classifier.ClassifyError(err); connectorError != nil {
+		return connectorError
+	}
+	return err
+}
+
+// ClassifyNetworkError detects the timeout and connection errors which are not vendor specific, returns nil if not hit.
+func ClassifyNetworkError(err error) *ConnectorError {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return NewConnectorError(CONNECTOR_ERROR_CATEGORY_TIMEOUT, err).SetRetryable(true)
+	}
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	if errors.As(err, &opErr) || errors.As(err, &dnsErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return NewConnectorError(CONNECTOR_ERROR_CATEGORY_CONNECTION, err).SetRetryable(true)
+	}
+	errorMessage := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(errorMessage, "timeout") || strings.Contains(errorMessage, "deadline exceeded"):
+		return NewConnectorError(CONNECTOR_ERROR_CATEGORY_TIMEOUT, err).SetRetryable(true)
+	case strings.Contains(errorMessage, "connection refused") || strings.Contains(errorMessage, "connection reset") || strings.Contains(errorMessage, "broken pipe"):
+		return NewConnectorError(CONNECTOR_ERROR_CATEGORY_CONNECTION, err).SetRetryable(true)
+	}
+	return nil
+}
+
+// ClassifyHTTPStatusCode classifies the error responded by HTTP based connectors
+func ClassifyHTTPStatusCode(statusCode int, err error) *ConnectorError {
+	var connectorError *ConnectorError
+	switch {
+	case statusCode == 400:
+		connectorError = NewConnectorError(CONNECTOR_ERROR_CATEGORY_SYNTAX, err)
+	case statusCode == 401:
+		connectorError = NewConnectorError(CONNECTOR_ERROR_CATEGORY_AUTH, err)
+	case statusCode == 403:
+		connectorError = NewConnectorError(CONNECTOR_ERROR_CATEGORY_PERMISSION, err)
+	case statusCode == 404:
+		connectorError = NewConnectorError(CONNECTOR_ERROR_CATEGORY_NOT_FOUND, err)
+	case statusCode == 409 || statusCode == 412 || statusCode == 422:
+		connectorError = NewConnectorError(CONNECTOR_ERROR_CATEGORY_CONSTRAINT, err)
+	case statusCode == 408 || statusCode == 504:
+		connectorError = NewConnectorError(CONNECTOR_ERROR_CATEGORY_TIMEOUT, err).SetRetryable(true)
+	case statusCode == 429 || statusCode == 502 || statusCode == 503:
+		connectorError = NewConnectorError(CONNECTOR_ERROR_CATEGORY_CONNECTION, err).SetRetryable(true)
+	default:
+		connectorError = NewConnectorError(CONNECTOR_ERROR_CATEGORY_UNKNOWN, err)
+	}
+	return connectorError
+}
+
+// LineNumberOfPosition returns the 1 based line number of the 1 based character position in query
+func LineNumberOfPosition(query string, position int) int {
+	if position <= 0 {
+		return 0
+	}
+	runes := []rune(query)
+	if position > len(runes) {
+		position = len(runes)
+	}
+	return strings.Count(string(runes[:position]), "\n") + 1
+}
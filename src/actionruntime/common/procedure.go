@@ -0,0 +1,281 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	PROCEDURE_KIND_PROCEDURE = "procedure"
+	PROCEDURE_KIND_FUNCTION  = "function"
+
+	PROCEDURE_PARAMETER_DIRECTION_IN    = "in"
+	PROCEDURE_PARAMETER_DIRECTION_OUT   = "out"
+	PROCEDURE_PARAMETER_DIRECTION_INOUT = "inout"
+
+	PROCEDURE_PARAMETER_TYPE_STRING   = "string"
+	PROCEDURE_PARAMETER_TYPE_INT      = "int"
+	PROCEDURE_PARAMETER_TYPE_FLOAT    = "float"
+	PROCEDURE_PARAMETER_TYPE_BOOL     = "bool"
+	PROCEDURE_PARAMETER_TYPE_DATETIME = "datetime"
+	PROCEDURE_PARAMETER_TYPE_CURSOR   = "cursor"
+
+	DEFAULT_PROCEDURE_PARAMETER_SIZE = 4000
+
+	PROCEDURE_RESULT_FIELD_OUT_PARAMETERS = "outParameters"
+	PROCEDURE_RESULT_FIELD_CURSORS        = "cursors"
+	PROCEDURE_RESULT_FIELD_RESULT_SETS    = "resultSets"
+	PROCEDURE_RESULT_FIELD_RETURN_VALUE   = "returnValue"
+)
+
+// procedure names are concatenated into the statement, so only plain (optionally schema or package qualified) identifiers are accepted
+var procedureNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$#]*(\.[A-Za-z_][A-Za-z0-9_$#]*){0,2}$`)
+
+type Procedure struct {
+	Name       string                `mapstructure:"name"`
+	Kind       string                `mapstructure:"kind"`
+	ReturnType string                `mapstructure:"returnType"`
+	Parameters []*ProcedureParameter `mapstructure:"parameters"`
+}
+
+type ProcedureParameter struct {
+	Name      string      `mapstructure:"name"`
+	Direction string      `mapstructure:"direction"`
+	Type      string      `mapstructure:"type"`
+	Size      int         `mapstructure:"size"`
+	Value     interface{} `mapstructure:"value"`
+}
+
+func (procedure *Procedure) Validate() error {
+	if !procedureNameRegexp.MatchString(procedure.Name) {
+		return fmt.Errorf("invalid procedure name \"%s\"", procedure.Name)
+	}
+	if procedure.Kind != "" && procedure.Kind != PROCEDURE_KIND_PROCEDURE && procedure.Kind != PROCEDURE_KIND_FUNCTION {
+		return fmt.Errorf("unsupported procedure kind \"%s\"", procedure.Kind)
+	}
+	for serial, param := range procedure.Parameters {
+		if param == nil {
+			return fmt.Errorf("procedure parameter %d is empty", serial)
+		}
+		switch param.Direction {
+		case PROCEDURE_PARAMETER_DIRECTION_IN, PROCEDURE_PARAMETER_DIRECTION_OUT, PROCEDURE_PARAMETER_DIRECTION_INOUT:
+		default:
+			return fmt.Errorf("unsupported direction \"%s\" of procedure parameter %d", param.Direction, serial)
+		}
+		if param.IsOutput() && param.Name == "" {
+			return fmt.Errorf("procedure output parameter %d must have a name", serial)
+		}
+		if param.IsCursor() && param.Direction != PROCEDURE_PARAMETER_DIRECTION_OUT {
+			return fmt.Errorf("procedure cursor parameter \"%s\" must be an output parameter", param.Name)
+		}
+	}
+	return nil
+}
+
+func (procedure *Procedure) IsFunction() bool {
+	return procedure.Kind == PROCEDURE_KIND_FUNCTION
+}
+
+func (procedure *Procedure) ExportReturnParameter() *ProcedureParameter {
+	return &ProcedureParameter{
+		Name:      PROCEDURE_RESULT_FIELD_RETURN_VALUE,
+		Direction: PROCEDURE_PARAMETER_DIRECTION_OUT,
+		Type:      procedure.ReturnType,
+	}
+}
+
+func (param *ProcedureParameter) IsInput() bool {
+	return param.Direction == PROCEDURE_PARAMETER_DIRECTION_IN || param.Direction == PROCEDURE_PARAMETER_DIRECTION_INOUT
+}
+
+func (param *ProcedureParameter) IsOutput() bool {
+	return param.Direction == PROCEDURE_PARAMETER_DIRECTION_OUT || param.Direction == PROCEDURE_PARAMETER_DIRECTION_INOUT
+}
+
+func (param *ProcedureParameter) IsInOut() bool {
+	return param.Direction == PROCEDURE_PARAMETER_DIRECTION_INOUT
+}
+
+func (param *ProcedureParameter) IsCursor() bool {
+	return param.Type == PROCEDURE_PARAMETER_TYPE_CURSOR
+}
+
+func (param *ProcedureParameter) ExportSize() int {
+	if param.Size <= 0 {
+		return DEFAULT_PROCEDURE_PARAMETER_SIZE
+	}
+	return param.Size
+}
+
+// ExportTypedValue converts the JSON decoded value to the go type described by param.Type
+func (param *ProcedureParameter) ExportTypedValue() (interface{}, error) {
+	if param.Value == nil {
+		return nil, nil
+	}
+	switch param.Type {
+	case PROCEDURE_PARAMETER_TYPE_INT:
+		switch value := param.Value.(type) {
+		case float64:
+			return int64(value), nil
+		case int:
+			return int64(value), nil
+		case int64:
+			return value, nil
+		case string:
+			return strconv.ParseInt(value, 10, 64)
+		}
+	case PROCEDURE_PARAMETER_TYPE_FLOAT:
+		switch value := param.Value.(type) {
+		case float64:
+			return value, nil
+		case int:
+			return float64(value), nil
+		case int64:
+			return float64(value), nil
+		case string:
+			return strconv.ParseFloat(value, 64)
+		}
+	case PROCEDURE_PARAMETER_TYPE_BOOL:
+		switch value := param.Value.(type) {
+		case bool:
+			return value, nil
+		case string:
+			return strconv.ParseBool(value)
+		}
+	case PROCEDURE_PARAMETER_TYPE_DATETIME:
+		switch value := param.Value.(type) {
+		case time.Time:
+			return value, nil
+		case string:
+			return time.Parse(time.RFC3339, value)
+		}
+	default:
+		switch value := param.Value.(type) {
+		case string:
+			return value, nil
+		default:
+			return fmt.Sprintf("%v", value), nil
+		}
+	}
+	return nil, fmt.Errorf("procedure parameter \"%s\" value %v can not convert to type %s", param.Name, param.Value, param.Type)
+}
+
+// NewOutputDest returns a pointer which the driver can write the output value into, the inout parameter dest carries its input value
+func (param *ProcedureParameter) NewOutputDest() (interface{}, error) {
+	typedValue, errInConvert := param.ExportTypedValue()
+	if errInConvert != nil {
+		return nil, errInConvert
+	}
+	if !param.IsInOut() {
+		typedValue = nil
+	}
+	switch param.Type {
+	case PROCEDURE_PARAMETER_TYPE_INT:
+		dest, _ := typedValue.(int64)
+		return &dest, nil
+	case PROCEDURE_PARAMETER_TYPE_FLOAT:
+		dest, _ := typedValue.(float64)
+		return &dest, nil
+	case PROCEDURE_PARAMETER_TYPE_BOOL:
+		dest, _ := typedValue.(bool)
+		return &dest, nil
+	case PROCEDURE_PARAMETER_TYPE_DATETIME:
+		dest, _ := typedValue.(time.Time)
+		return &dest, nil
+	case PROCEDURE_PARAMETER_TYPE_CURSOR:
+		return nil, errors.New("cursor parameter dest should be allocated by driver")
+	default:
+		dest, _ := typedValue.(string)
+		return &dest, nil
+	}
+}
+
+func ExportOutputDestValue(dest interface{}) interface{} {
+	switch value := dest.(type) {
+	case *int64:
+		return *value
+	case *float64:
+		return *value
+	case *bool:
+		return *value
+	case *time.Time:
+		return *value
+	case *string:
+		return *value
+	case []byte:
+		return string(value)
+	default:
+		return value
+	}
+}
+
+type ProcedureResult struct {
+	ResultSets    [][]map[string]interface{}
+	OutParameters map[string]interface{}
+	Cursors       map[string][]map[string]interface{}
+	ReturnValue   interface{}
+	cursorNames   []string
+}
+
+func NewProcedureResult() *ProcedureResult {
+	return &ProcedureResult{
+		ResultSets:    make([][]map[string]interface{}, 0),
+		OutParameters: make(map[string]interface{}),
+		Cursors:       make(map[string][]map[string]interface{}),
+		cursorNames:   make([]string, 0),
+	}
+}
+
+func (procedureResult *ProcedureResult) AppendResultSet(resultSet []map[string]interface{}) {
+	procedureResult.ResultSets = append(procedureResult.ResultSets, resultSet)
+}
+
+func (procedureResult *ProcedureResult) SetOutParameter(name string, value interface{}) {
+	procedureResult.OutParameters[name] = value
+}
+
+func (procedureResult *ProcedureResult) SetCursor(name string, rows []map[string]interface{}) {
+	procedureResult.Cursors[name] = rows
+	procedureResult.cursorNames = append(procedureResult.cursorNames, name)
+}
+
+func (procedureResult *ProcedureResult) SetReturnValue(value interface{}) {
+	procedureResult.ReturnValue = value
+}
+
+// ExportRuntimeResult puts the first result set (or the first cursor when no result set returned) in Rows, and all of the details in Extra
+func (procedureResult *ProcedureResult) ExportRuntimeResult() RuntimeResult {
+	rows := make([]map[string]interface{}, 0)
+	if len(procedureResult.ResultSets) > 0 {
+		rows = procedureResult.ResultSets[0]
+	} else if len(procedureResult.cursorNames) > 0 {
+		rows = procedureResult.Cursors[procedureResult.cursorNames[0]]
+	}
+	return RuntimeResult{
+		Success: true,
+		Rows:    rows,
+		Extra: map[string]interface{}{
+			PROCEDURE_RESULT_FIELD_OUT_PARAMETERS: procedureResult.OutParameters,
+			PROCEDURE_RESULT_FIELD_CURSORS:        procedureResult.Cursors,
+			PROCEDURE_RESULT_FIELD_RESULT_SETS:    procedureResult.ResultSets,
+			PROCEDURE_RESULT_FIELD_RETURN_VALUE:   procedureResult.ReturnValue,
+		},
+	}
+}
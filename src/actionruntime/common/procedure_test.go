@@ -0,0 +1,47 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcedureValidate(t *testing.T) {
+	assert.Nil(t, (&Procedure{Name: "sales.get_orders"}).Validate())
+	assert.NotNil(t, (&Procedure{Name: "get_orders; DROP TABLE users"}).Validate())
+	assert.NotNil(t, (&Procedure{Name: "get_orders", Kind: "trigger"}).Validate())
+	assert.NotNil(t, (&Procedure{Name: "get_orders", Parameters: []*ProcedureParameter{{Name: "id", Direction: "both"}}}).Validate())
+	assert.NotNil(t, (&Procedure{Name: "get_orders", Parameters: []*ProcedureParameter{{Direction: PROCEDURE_PARAMETER_DIRECTION_OUT}}}).Validate())
+	assert.NotNil(t, (&Procedure{Name: "get_orders", Parameters: []*ProcedureParameter{{Name: "c", Direction: PROCEDURE_PARAMETER_DIRECTION_IN, Type: PROCEDURE_PARAMETER_TYPE_CURSOR}}}).Validate())
+}
+
+func TestProcedureParameterExportTypedValue(t *testing.T) {
+	value, err := (&ProcedureParameter{Type: PROCEDURE_PARAMETER_TYPE_INT, Value: float64(42)}).ExportTypedValue()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42), value)
+
+	value, err = (&ProcedureParameter{Type: PROCEDURE_PARAMETER_TYPE_FLOAT, Value: "1.5"}).ExportTypedValue()
+	assert.Nil(t, err)
+	assert.Equal(t, 1.5, value)
+
+	value, err = (&ProcedureParameter{Type: PROCEDURE_PARAMETER_TYPE_DATETIME, Value: "2023-01-02T03:04:05Z"}).ExportTypedValue()
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC), value)
+
+	value, err = (&ProcedureParameter{Value: float64(7)}).ExportTypedValue()
+	assert.Nil(t, err)
+	assert.Equal(t, "7", value)
+
+	_, err = (&ProcedureParameter{Type: PROCEDURE_PARAMETER_TYPE_BOOL, Value: float64(1)}).ExportTypedValue()
+	assert.NotNil(t, err)
+}
+
+func TestProcedureResultExportRuntimeResult(t *testing.T) {
+	procedureResult := NewProcedureResult()
+	procedureResult.SetCursor("orders", []map[string]interface{}{{"id": 1}})
+	procedureResult.SetReturnValue(int32(0))
+	result := procedureResult.ExportRuntimeResult()
+	assert.Equal(t, []map[string]interface{}{{"id": 1}}, result.Rows)
+	assert.Equal(t, int32(0), result.Extra[PROCEDURE_RESULT_FIELD_RETURN_VALUE])
+}
@@ -0,0 +1,176 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/illacloud/builder-backend/src/utils/jsonschema"
+	"github.com/illacloud/builder-backend/src/utils/resourcelist"
+)
+
+// ConnectorCapability describes which features the connector supports, it is exposed to frontend by resource types API.
+// The MetaInfoCache is opted in by the connectors which fetch meta info slowly (like sampling documents),
+// their meta info will be cached and can be refreshed by "?refresh=true".
+type ConnectorCapability struct {
+	MetaInfo       bool `json:"metaInfo"`
+	MetaInfoCache  bool `json:"metaInfoCache"`
+	TestConnection bool `json:"testConnection"`
+	Streaming      bool `json:"streaming"`
+	GUIMode        bool `json:"guiMode"`
+}
+
+type ConnectorAlias struct {
+	Name string `json:"name"`
+	ID   int    `json:"id"`
+}
+
+// ConnectorDescriptor is registered by every connector package in init(),
+// the aliases are the resource types which share the same connector, like supabasedb to postgresql.
+// The schemas are derived from the structs which resource options and action template decoded into,
+// they are nil when the connector does not decode them.
+type ConnectorDescriptor struct {
+	Name                  string               `json:"name"`
+	ID                    int                  `json:"id"`
+	Aliases               []*ConnectorAlias    `json:"aliases,omitempty"`
+	Capability            ConnectorCapability  `json:"capability"`
+	ResourceOptionsSchema *jsonschema.Schema   `json:"-"`
+	ActionTemplateSchema  *jsonschema.Schema   `json:"-"`
+	Build                 func() DataConnector `json:"-"`
+}
+
+func NewConnectorAlias(name string) *ConnectorAlias {
+	return &ConnectorAlias{
+		Name: name,
+		ID:   resourcelist.GetResourceNameMappedID(name),
+	}
+}
+
+type connectorRegistry struct {
+	mutex       sync.RWMutex
+	descriptors map[int]*ConnectorDescriptor // resource type id or alias id => descriptor
+}
+
+var registry = &connectorRegistry{
+	descriptors: make(map[int]*ConnectorDescriptor),
+}
+
+// RegisterConnector panics when the resource type id has been registered, like database/sql.Register does.
+func RegisterConnector(descriptor *ConnectorDescriptor) {
+	if err := TryRegisterConnector(descriptor); err != nil {
+		panic(err)
+	}
+}
+
+// TryRegisterConnector registers the connector which discovered at runtime.
+func TryRegisterConnector(descriptor *ConnectorDescriptor) error {
+	if descriptor == nil || descriptor.Build == nil {
+		return errors.New("register connector failed: missing connector builder")
+	}
+	// streaming capability is detected from the connector itself
+	_, descriptor.Capability.Streaming = descriptor.Build().(StreamableDataConnector)
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	ids := []int{descriptor.ID}
+	for _, alias := range descriptor.Aliases {
+		ids = append(ids, alias.ID)
+	}
+	for _, id := range ids {
+		if registered, hit := registry.descriptors[id]; hit {
+			return fmt.Errorf("register connector %s failed: resource type %d has been registered by %s", descriptor.Name, id, registered.Name)
+		}
+	}
+	for _, id := range ids {
+		registry.descriptors[id] = descriptor
+	}
+	return nil
+}
+
+func UnregisterConnector(id int) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	descriptor, hit := registry.descriptors[id]
+	if !hit {
+		return
+	}
+	delete(registry.descriptors, descriptor.ID)
+	for _, alias := range descriptor.Aliases {
+		delete(registry.descriptors, alias.ID)
+	}
+}
+
+func RetrieveConnectorDescriptor(id int) (*ConnectorDescriptor, bool) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	descriptor, hit := registry.descriptors[id]
+	return descriptor, hit
+}
+
+// RetrieveAllConnectorDescriptors returns the registered connectors ordered by resource type id, aliases are not listed separately.
+func RetrieveAllConnectorDescriptors() []*ConnectorDescriptor {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	descriptors := make([]*ConnectorDescriptor, 0, len(registry.descriptors))
+	for id, descriptor := range registry.descriptors {
+		if id == descriptor.ID {
+			descriptors = append(descriptors, descriptor)
+		}
+	}
+	sort.Slice(descriptors, func(i, j int) bool {
+		return descriptors[i].ID < descriptors[j].ID
+	})
+	return descriptors
+}
+
+func BuildConnector(id int) (DataConnector, error) {
+	descriptor, hit := RetrieveConnectorDescriptor(id)
+	if !hit {
+		return nil, errors.New("invalid ActionType: unsupported type " + resourceTypeName(id))
+	}
+	return descriptor.Build(), nil
+}
+
+// ValidateResourceOptions validates the resource options against the json schema registered by connector first,
+// then the connector decodes and validates them itself. So the connectors do not need to validate the schema again.
+func ValidateResourceOptions(id int, connector DataConnector, resourceOptions map[string]interface{}) (ValidateResult, error) {
+	if descriptor, hit := RetrieveConnectorDescriptor(id); hit {
+		if err := jsonschema.Validate(descriptor.ResourceOptionsSchema, resourceOptions); err != nil {
+			return ValidateResult{Valid: false}, err
+		}
+	}
+	return connector.ValidateResourceOptions(resourceOptions)
+}
+
+// ValidateActionTemplate validates the action template like ValidateResourceOptions does.
+func ValidateActionTemplate(id int, connector DataConnector, actionOptions map[string]interface{}) (ValidateResult, error) {
+	if descriptor, hit := RetrieveConnectorDescriptor(id); hit {
+		if err := jsonschema.Validate(descriptor.ActionTemplateSchema, actionOptions); err != nil {
+			return ValidateResult{Valid: false}, err
+		}
+	}
+	return connector.ValidateActionTemplate(actionOptions)
+}
+
+func resourceTypeName(id int) string {
+	if name := resourcelist.GetResourceIDMappedType(id); name != "" {
+		return name
+	}
+	return strconv.Itoa(id)
+}
@@ -0,0 +1,72 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/illacloud/builder-backend/src/utils/jsonschema"
+	"github.com/stretchr/testify/assert"
+)
+
+const testRegistryConnectorID = 9901
+
+type testRegistryOptions struct {
+	Host string `validate:"required"`
+	Port int    `validate:"gt=0"`
+}
+
+type testRegistryConnector struct {
+	resourceValidated bool
+	actionValidated   bool
+}
+
+func (c *testRegistryConnector) ValidateResourceOptions(resourceOptions map[string]interface{}) (ValidateResult, error) {
+	c.resourceValidated = true
+	return ValidateResult{Valid: true}, nil
+}
+
+func (c *testRegistryConnector) ValidateActionTemplate(actionOptions map[string]interface{}) (ValidateResult, error) {
+	c.actionValidated = true
+	return ValidateResult{Valid: true}, nil
+}
+
+func (c *testRegistryConnector) TestConnection(resourceOptions map[string]interface{}) (ConnectionResult, error) {
+	return ConnectionResult{Success: true}, nil
+}
+
+func (c *testRegistryConnector) GetMetaInfo(resourceOptions map[string]interface{}) (MetaInfoResult, error) {
+	return MetaInfoResult{Success: true}, nil
+}
+
+func (c *testRegistryConnector) Run(resourceOptions map[string]interface{}, actionOptions map[string]interface{}, rawActionOptions map[string]interface{}) (RuntimeResult, error) {
+	return RuntimeResult{Success: true}, nil
+}
+
+func TestValidateBySchemaOfDescriptor(t *testing.T) {
+	assert.Nil(t, TryRegisterConnector(&ConnectorDescriptor{
+		Name:                  "test-registry",
+		ID:                    testRegistryConnectorID,
+		ResourceOptionsSchema: jsonschema.Reflect(&testRegistryOptions{}),
+		Build: func() DataConnector {
+			return &testRegistryConnector{}
+		},
+	}))
+	defer UnregisterConnector(testRegistryConnectorID)
+
+	// the schema violation is reported before the connector decodes the options
+	connector := &testRegistryConnector{}
+	validateResult, err := ValidateResourceOptions(testRegistryConnectorID, connector, map[string]interface{}{"port": 0})
+	assert.False(t, validateResult.Valid)
+	assert.IsType(t, &jsonschema.ValidationError{}, err)
+	assert.False(t, connector.resourceValidated)
+
+	validateResult, err = ValidateResourceOptions(testRegistryConnectorID, connector, map[string]interface{}{"host": "localhost", "port": 5432})
+	assert.Nil(t, err)
+	assert.True(t, validateResult.Valid)
+	assert.True(t, connector.resourceValidated)
+
+	// no action template schema registered, only the connector validates
+	validateResult, err = ValidateActionTemplate(testRegistryConnectorID, connector, map[string]interface{}{"any": 1})
+	assert.Nil(t, err)
+	assert.True(t, validateResult.Valid)
+	assert.True(t, connector.actionValidated)
+}
@@ -0,0 +1,21 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+// ResourceBoundDataConnector is implemented by the connectors which refer to the resource after run, like the queued emails
+// of smtp outbox are delivered later with the options loaded from resource then. The identity is set by the caller before run.
+type ResourceBoundDataConnector interface {
+	SetResourceIdentity(teamID int, resourceID int)
+}
@@ -0,0 +1,42 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"errors"
+)
+
+const (
+	RESULT_STREAM_FIELD_TRUNCATED          = "truncated"
+	RESULT_STREAM_FIELD_MAX_ROWS           = "maxRows"
+	RESULT_STREAM_FIELD_ROW_COUNT          = "rowCount"
+	RESULT_STREAM_FIELD_HAS_MORE           = "hasMore"
+	RESULT_STREAM_FIELD_CONTINUATION_TOKEN = "continuationToken"
+)
+
+// ErrResultStreamLimitReached returned by ResultStream.WriteRow when the stream reached the max rows, the connector should stop reading and return normally.
+var ErrResultStreamLimitReached = errors.New("result stream reached the max rows limit")
+
+// ResultStream receives the query result row by row instead of collecting it into RuntimeResult.Rows
+type ResultStream interface {
+	WriteRow(row map[string]interface{}) error
+	ExportExtra() map[string]interface{}
+}
+
+// StreamableDataConnector is implemented by the connectors which can push rows into a ResultStream.
+// When a stream was set, the connector Run() method returns empty Rows and writes rows into the stream.
+type StreamableDataConnector interface {
+	SetResultStream(stream ResultStream)
+}
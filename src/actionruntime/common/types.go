@@ -15,9 +15,10 @@
 package common
 
 const (
-	MODE_GUI      = "gui"
-	MODE_SQL      = "sql"
-	MODE_SQL_SAFE = "sql-safe"
+	MODE_GUI       = "gui"
+	MODE_SQL       = "sql"
+	MODE_SQL_SAFE  = "sql-safe"
+	MODE_PROCEDURE = "procedure"
 )
 
 type ValidateResult struct {
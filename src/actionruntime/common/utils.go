@@ -24,11 +24,36 @@ import (
 )
 
 func RetrieveToMap(rows *sql.Rows) ([]map[string]interface{}, error) {
-	columns, err := rows.Columns()
-	if err != nil {
-		return nil, err
+	mapData := make([]map[string]interface{}, 0)
+	errInIterate := iterateRows(rows, func(entry map[string]interface{}) error {
+		mapData = append(mapData, entry)
+		return nil
+	})
+	if errInIterate != nil {
+		return nil, errInIterate
 	}
-	// rewrite columns for duplicate name
+	return mapData, nil
+}
+
+// RetrieveToStream writes every row into stream, it stops without error when the stream reached the max rows.
+func RetrieveToStream(rows *sql.Rows, stream ResultStream) error {
+	errInIterate := iterateRows(rows, stream.WriteRow)
+	if errInIterate == ErrResultStreamLimitReached {
+		return nil
+	}
+	return errInIterate
+}
+
+// RetrieveToMapOrStream retrieves rows to map when the stream is nil, otherwise writes them into stream and returns empty rows.
+func RetrieveToMapOrStream(rows *sql.Rows, stream ResultStream) ([]map[string]interface{}, error) {
+	if stream == nil {
+		return RetrieveToMap(rows)
+	}
+	return []map[string]interface{}{}, RetrieveToStream(rows, stream)
+}
+
+// RenameDuplicateColumns appends serial suffix for the duplicate column names, like ["id", "id"] to ["id_0", "id_1"]
+func RenameDuplicateColumns(columns []string) []string {
 	renamedColumns := make([]string, 0)
 	columnNameHitMap := make(map[string]int, 0)
 	columnNamePosMap := make(map[string]int, 0)
@@ -48,9 +73,18 @@ func RetrieveToMap(rows *sql.Rows) ([]map[string]interface{}, error) {
 		columnNamePosMap[cloName] = pos
 		renamedColumns = append(renamedColumns, cloName)
 	}
+	return renamedColumns
+}
+
+func iterateRows(rows *sql.Rows, handler func(entry map[string]interface{}) error) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	// rewrite columns for duplicate name
+	renamedColumns := RenameDuplicateColumns(columns)
 	// count of columns
 	count := len(renamedColumns)
-	mapData := make([]map[string]interface{}, 0)
 
 	// value of every row
 	values := make([]interface{}, count)
@@ -83,35 +117,19 @@ func RetrieveToMap(rows *sql.Rows) ([]map[string]interface{}, error) {
 			}
 			entry[col] = v
 		}
-		mapData = append(mapData, entry)
+		if errInHandle := handler(entry); errInHandle != nil {
+			return errInHandle
+		}
 	}
 
-	return mapData, nil
+	return nil
 }
 
 func RetrieveToMapByDriverRows(rows driver.Rows) ([]map[string]interface{}, error) {
 	columns := rows.Columns()
 	mapData := make([]map[string]interface{}, 0)
 	// rewrite columns for duplicate name
-	renamedColumns := make([]string, 0)
-	columnNameHitMap := make(map[string]int, 0)
-	columnNamePosMap := make(map[string]int, 0)
-	for pos, column := range columns {
-		hitColumnTimes, hitColumn := columnNameHitMap[column]
-		cloName := column
-		if hitColumn {
-			cloName += fmt.Sprintf("_%d", hitColumnTimes)
-			// rewrite first column to "_0"
-			if columnNameHitMap[column] == 1 {
-				firstHitPos := columnNamePosMap[column]
-				renamedColumns[firstHitPos] += "_0"
-			}
-			columnNameHitMap[column]++
-		}
-		columnNameHitMap[cloName] = 1
-		columnNamePosMap[cloName] = pos
-		renamedColumns = append(renamedColumns, cloName)
-	}
+	renamedColumns := RenameDuplicateColumns(columns)
 
 	// value of every row
 	values := make([]driver.Value, len(renamedColumns))
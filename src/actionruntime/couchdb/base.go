@@ -31,8 +31,22 @@ const (
 	DELETE_METHOD   = "deleteRecord"
 	FIND_METHOD     = "find"
 	GET_METHOD      = "getView"
+	VIEW_METHOD     = "queryView"
+	BULK_METHOD     = "bulkDocs"
+	CHANGES_METHOD  = "changes"
 )
 
+// exportIntOption converts the number option decoded from json into int.
+func exportIntOption(opts map[string]interface{}, name string) (int, bool) {
+	switch value := opts[name].(type) {
+	case float64:
+		return int(value), true
+	case int:
+		return value, true
+	}
+	return 0, false
+}
+
 func (c *Connector) getClient(resourceOptions map[string]interface{}) (*kivik.Client, error) {
 	// format resource options
 	if err := mapstructure.Decode(resourceOptions, &c.resourceOptions); err != nil {
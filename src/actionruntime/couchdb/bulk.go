@@ -0,0 +1,75 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package couchdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/mitchellh/mapstructure"
+)
+
+type bulkDocsOpts struct {
+	Docs     []interface{} `mapstructure:"docs"`
+	NewEdits *bool         `mapstructure:"newEdits"`
+}
+
+// bulkDocs creates, updates or deletes the documents in one request, the result of every document is returned in order.
+func (c *Connector) bulkDocs(db *kivik.DB) (common.RuntimeResult, error) {
+	res := common.RuntimeResult{
+		Success: false,
+		Rows:    []map[string]interface{}{},
+		Extra:   map[string]interface{}{},
+	}
+	var opts bulkDocsOpts
+	if err := mapstructure.Decode(c.actionOptions.Opts, &opts); err != nil {
+		return res, err
+	}
+	if len(opts.Docs) == 0 {
+		return res, errors.New("docs are required")
+	}
+	kOpts := kivik.Options{}
+	if opts.NewEdits != nil {
+		kOpts["new_edits"] = *opts.NewEdits
+	}
+
+	results, err := db.BulkDocs(context.TODO(), opts.Docs, kOpts)
+	if err != nil {
+		res.Rows = append(res.Rows, map[string]interface{}{"error": err.Error()})
+		return res, nil
+	}
+	defer results.Close()
+	rows := make([]map[string]interface{}, 0, len(opts.Docs))
+	failed := 0
+	for results.Next() {
+		item := map[string]interface{}{"id": results.ID(), "rev": results.Rev(), "ok": true}
+		if updateErr := results.UpdateErr(); updateErr != nil {
+			item["ok"] = false
+			item["error"] = updateErr.Error()
+			failed++
+		}
+		rows = append(rows, item)
+	}
+	if results.Err() != nil {
+		res.Rows = append(res.Rows, map[string]interface{}{"error": results.Err().Error()})
+		return res, nil
+	}
+	res.Rows = rows
+	res.Extra["failed"] = failed
+	res.Success = true
+	return res, nil
+}
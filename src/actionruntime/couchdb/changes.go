@@ -0,0 +1,141 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package couchdb
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	FIELD_LAST_SEQ = "lastSeq"
+	FIELD_PENDING  = "pending"
+
+	// the first run without since only records the current position, like the watch of mongodb
+	DEFAULT_CHANGES_SINCE = "now"
+	DEFAULT_CHANGES_LIMIT = 100
+	MAX_CHANGES_LIMIT     = 1000
+)
+
+type changesOpts struct {
+	Since       string   `mapstructure:"since"`
+	Filter      string   `mapstructure:"filter"`
+	DocIDs      []string `mapstructure:"docIDs"`
+	View        string   `mapstructure:"view"`
+	IncludeDocs bool     `mapstructure:"includeDocs"`
+}
+
+// changesResumeToken binds the last sequence to database, since the sequence of other database is meaningless.
+type changesResumeToken struct {
+	Database string `json:"database"`
+	Since    string `json:"since"`
+}
+
+func newChangesResumeToken(database string, since string) string {
+	resumeToken, _ := json.Marshal(&changesResumeToken{Database: database, Since: since})
+	return string(resumeToken)
+}
+
+// exportChangesSince prefers the position kept from last run on same database, so the feed continues from where the last run stopped.
+// The since in options is the start position of first run, and the first run without it starts from now.
+func exportChangesSince(resumeToken string, database string, sinceInOpts string) string {
+	var lastRun changesResumeToken
+	if resumeToken != "" && json.Unmarshal([]byte(resumeToken), &lastRun) == nil && lastRun.Database == database && lastRun.Since != "" {
+		return lastRun.Since
+	}
+	if sinceInOpts != "" {
+		return sinceInOpts
+	}
+	return DEFAULT_CHANGES_SINCE
+}
+
+// changes polls the changes feed of database, the last sequence is returned in extra and kept as resume token for next run.
+func (c *Connector) changes(db *kivik.DB) (common.RuntimeResult, error) {
+	res := common.RuntimeResult{
+		Success: false,
+		Rows:    []map[string]interface{}{},
+		Extra:   map[string]interface{}{},
+	}
+	var opts changesOpts
+	if err := mapstructure.Decode(c.actionOptions.Opts, &opts); err != nil {
+		return res, err
+	}
+	since := exportChangesSince(c.resumeToken, c.actionOptions.Database, opts.Since)
+	limit := DEFAULT_CHANGES_LIMIT
+	if limitInOpts, ok := exportIntOption(c.actionOptions.Opts, "limit"); ok && limitInOpts > 0 {
+		limit = limitInOpts
+	}
+	if limit > MAX_CHANGES_LIMIT {
+		limit = MAX_CHANGES_LIMIT
+	}
+
+	kOpts := kivik.Options{
+		"feed":  "normal",
+		"since": since,
+		"limit": limit,
+	}
+	if opts.Filter != "" {
+		kOpts["filter"] = opts.Filter
+	}
+	if len(opts.DocIDs) > 0 {
+		kOpts["filter"] = "_doc_ids"
+		kOpts["doc_ids"] = opts.DocIDs
+	}
+	if opts.View != "" {
+		kOpts["filter"] = "_view"
+		kOpts["view"] = opts.View
+	}
+	if opts.IncludeDocs {
+		kOpts["include_docs"] = true
+	}
+
+	feed, err := db.Changes(context.TODO(), kOpts)
+	if err != nil {
+		res.Rows = append(res.Rows, map[string]interface{}{"error": err.Error()})
+		return res, nil
+	}
+	defer feed.Close()
+	rows := make([]map[string]interface{}, 0)
+	for feed.Next() {
+		item := map[string]interface{}{
+			"id":      feed.ID(),
+			"seq":     feed.Seq(),
+			"changes": feed.Changes(),
+			"deleted": feed.Deleted(),
+		}
+		if opts.IncludeDocs {
+			var doc interface{}
+			feed.ScanDoc(&doc)
+			item["doc"] = doc
+		}
+		rows = append(rows, item)
+	}
+	if feed.Err() != nil {
+		res.Rows = append(res.Rows, map[string]interface{}{"error": feed.Err().Error()})
+		return res, nil
+	}
+	res.Rows = rows
+	res.Extra[FIELD_LAST_SEQ] = feed.LastSeq()
+	res.Extra[FIELD_PENDING] = feed.Pending()
+	res.Success = true
+	if lastSeq := feed.LastSeq(); lastSeq != "" {
+		c.nextResumeToken = newChangesResumeToken(c.actionOptions.Database, lastSeq)
+	}
+	return res, nil
+}
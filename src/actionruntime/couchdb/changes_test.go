@@ -0,0 +1,85 @@
+package couchdb
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportChangesSince(t *testing.T) {
+	resumeToken := newChangesResumeToken("orders", "12-abc")
+	assert.Equal(t, "12-abc", exportChangesSince(resumeToken, "orders", ""))
+	// the kept position is preferred, the since in options only starts the first run
+	assert.Equal(t, "12-abc", exportChangesSince(resumeToken, "orders", "0"))
+	// the sequence of other database is not used
+	assert.Equal(t, "0", exportChangesSince(resumeToken, "users", "0"))
+	assert.Equal(t, DEFAULT_CHANGES_SINCE, exportChangesSince(resumeToken, "users", ""))
+	assert.Equal(t, DEFAULT_CHANGES_SINCE, exportChangesSince("", "orders", ""))
+	assert.Equal(t, DEFAULT_CHANGES_SINCE, exportChangesSince("not json", "orders", ""))
+}
+
+// newFakeChangesServer answers the changes feed of orders database, the requested since are recorded.
+func newFakeChangesServer(t *testing.T, requestedSince *[]string) map[string]interface{} {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// the client authorizes by cookie session first
+		if r.URL.Path == "/_session" {
+			http.SetCookie(w, &http.Cookie{Name: "AuthSession", Value: "session"})
+			json.NewEncoder(w).Encode(map[string]interface{}{"ok": true})
+			return
+		}
+		if r.URL.Path != "/orders/_changes" {
+			http.NotFound(w, r)
+			return
+		}
+		since := r.URL.Query().Get("since")
+		*requestedSince = append(*requestedSince, since)
+		lastSeq := "1-a"
+		if since != DEFAULT_CHANGES_SINCE {
+			lastSeq = "2-b"
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"results":  []map[string]interface{}{{"seq": lastSeq, "id": "order-" + lastSeq, "changes": []map[string]string{{"rev": "1-x"}}}},
+			"last_seq": lastSeq,
+			"pending":  0,
+		})
+	}))
+	t.Cleanup(server.Close)
+	host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	return map[string]interface{}{"host": host, "port": port}
+}
+
+func TestChangesContinuesFromPersistedResumeToken(t *testing.T) {
+	requestedSince := make([]string, 0)
+	resourceOptions := newFakeChangesServer(t, &requestedSince)
+	actionOptions := map[string]interface{}{"method": CHANGES_METHOD, "database": "orders", "opts": map[string]interface{}{}}
+
+	// the first run without persisted token starts from now
+	var connector common.ResumableDataConnector = &Connector{}
+	connector.SetResumeToken("")
+	res, err := connector.(*Connector).Run(resourceOptions, actionOptions, nil)
+	assert.Nil(t, err)
+	assert.True(t, res.Success)
+	persistedResumeToken := connector.ExportResumeToken()
+	assert.NotEqual(t, "", persistedResumeToken)
+
+	// the next run is built as new connector, like the app and flow actions
+	connector = &Connector{}
+	connector.SetResumeToken(persistedResumeToken)
+	res, err = connector.(*Connector).Run(resourceOptions, actionOptions, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "2-b", res.Extra[FIELD_LAST_SEQ])
+	assert.Equal(t, []string{DEFAULT_CHANGES_SINCE, "1-a"}, requestedSince)
+	assert.Equal(t, newChangesResumeToken("orders", "2-b"), connector.ExportResumeToken())
+
+	// the other methods do not export resume token
+	connector = &Connector{}
+	connector.SetResumeToken(persistedResumeToken)
+	connector.(*Connector).Run(resourceOptions, map[string]interface{}{"method": "retrieveRecord", "database": "orders", "opts": map[string]interface{}{"_id": "order"}}, nil)
+	assert.Equal(t, "", connector.ExportResumeToken())
+}
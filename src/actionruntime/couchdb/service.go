@@ -28,9 +28,20 @@ import (
 	"github.com/mitchellh/mapstructure"
 )
 
+// Connector keeps the resume token of changes feed, only the succeeded changes run exports a new one.
 type Connector struct {
 	resourceOptions resource
 	actionOptions   action
+	resumeToken     string
+	nextResumeToken string
+}
+
+func (c *Connector) SetResumeToken(resumeToken string) {
+	c.resumeToken = resumeToken
+}
+
+func (c *Connector) ExportResumeToken() string {
+	return c.nextResumeToken
 }
 
 func (c *Connector) ValidateResourceOptions(resourceOptions map[string]interface{}) (common.ValidateResult, error) {
@@ -91,9 +102,19 @@ func (c *Connector) GetMetaInfo(resourceOptions map[string]interface{}) (common.
 		return common.MetaInfoResult{Success: false}, err
 	}
 
+	// get design documents and views of the database of resource, discovering all databases costs one request per database
+	schema := map[string]interface{}{"databases": dbs}
+	if c.resourceOptions.Database != "" {
+		designDocs, err := listDesignDocs(client.DB(c.resourceOptions.Database))
+		if err != nil {
+			return common.MetaInfoResult{Success: false}, err
+		}
+		schema["designDocs"] = map[string]interface{}{c.resourceOptions.Database: designDocs}
+	}
+
 	return common.MetaInfoResult{
 		Success: true,
-		Schema:  map[string]interface{}{"databases": dbs},
+		Schema:  schema,
 	}, nil
 }
 
@@ -298,6 +319,12 @@ func (c *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 		resObj["offset"] = resMetadata.Offset
 		res.Rows = append(res.Rows, resObj)
 		res.Success = true
+	case VIEW_METHOD:
+		return c.queryView(db)
+	case BULK_METHOD:
+		return c.bulkDocs(db)
+	case CHANGES_METHOD:
+		return c.changes(db)
 	default:
 		return res, errors.New("invalid method")
 	}
@@ -14,16 +14,18 @@
 
 package couchdb
 
+// resource lists the design documents of Database in meta info when it is set.
 type resource struct {
 	Host     string `validate:"required"`
 	Port     string `validate:"required"`
 	Username string
 	Password string
 	SSL      bool
+	Database string
 }
 
 type action struct {
-	Method   string `validate:"required,oneof=listRecords retrieveRecord createRecord updateRecord deleteRecord find getView"`
+	Method   string `validate:"required,oneof=listRecords retrieveRecord createRecord updateRecord deleteRecord find getView queryView bulkDocs changes"`
 	Database string
 	Opts     map[string]interface{}
 }
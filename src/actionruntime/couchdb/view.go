@@ -0,0 +1,157 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package couchdb
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/mitchellh/mapstructure"
+)
+
+type queryViewOpts struct {
+	DesignDoc    string        `mapstructure:"ddoc"`
+	View         string        `mapstructure:"view"`
+	Key          interface{}   `mapstructure:"key"`
+	Keys         []interface{} `mapstructure:"keys"`
+	StartKey     interface{}   `mapstructure:"startkey"`
+	EndKey       interface{}   `mapstructure:"endkey"`
+	InclusiveEnd *bool         `mapstructure:"inclusiveEnd"`
+	Reduce       *bool         `mapstructure:"reduce"`
+	Group        bool          `mapstructure:"group"`
+	Descending   bool          `mapstructure:"descending"`
+	IncludeDocs  bool          `mapstructure:"includeDocs"`
+}
+
+// exportKivikOptions builds the view query parameters, the keys are encoded into json by driver.
+func (opts *queryViewOpts) exportKivikOptions(rawOpts map[string]interface{}) kivik.Options {
+	kOpts := kivik.Options{}
+	if opts.Key != nil {
+		kOpts["key"] = opts.Key
+	}
+	if len(opts.Keys) > 0 {
+		kOpts["keys"] = opts.Keys
+	}
+	if opts.StartKey != nil {
+		kOpts["startkey"] = opts.StartKey
+	}
+	if opts.EndKey != nil {
+		kOpts["endkey"] = opts.EndKey
+	}
+	if opts.InclusiveEnd != nil {
+		kOpts["inclusive_end"] = *opts.InclusiveEnd
+	}
+	if opts.Reduce != nil {
+		kOpts["reduce"] = *opts.Reduce
+	}
+	if opts.Group {
+		kOpts["group"] = true
+	}
+	if groupLevel, ok := exportIntOption(rawOpts, "groupLevel"); ok {
+		kOpts["group_level"] = groupLevel
+	}
+	if limit, ok := exportIntOption(rawOpts, "limit"); ok {
+		kOpts["limit"] = limit
+	}
+	if skip, ok := exportIntOption(rawOpts, "skip"); ok {
+		kOpts["skip"] = skip
+	}
+	if opts.Descending {
+		kOpts["descending"] = true
+	}
+	// the reduced rows have no document
+	if opts.IncludeDocs && (opts.Reduce == nil || !*opts.Reduce) {
+		kOpts["include_docs"] = true
+	}
+	return kOpts
+}
+
+// queryView queries the view of design document, both reduced and mapped rows are returned with key and value.
+func (c *Connector) queryView(db *kivik.DB) (common.RuntimeResult, error) {
+	res := common.RuntimeResult{
+		Success: false,
+		Rows:    []map[string]interface{}{},
+		Extra:   map[string]interface{}{},
+	}
+	var opts queryViewOpts
+	if err := mapstructure.Decode(c.actionOptions.Opts, &opts); err != nil {
+		return res, err
+	}
+	if opts.DesignDoc == "" || opts.View == "" {
+		return res, errors.New("design document and view are required")
+	}
+	kOpts := opts.exportKivikOptions(c.actionOptions.Opts)
+
+	resSet := db.Query(context.TODO(), "_design/"+strings.TrimPrefix(opts.DesignDoc, "_design/"), "_view/"+opts.View, kOpts)
+	rows := make([]map[string]interface{}, 0)
+	for resSet.Next() {
+		item := make(map[string]interface{}, 4)
+		if id := resSet.ID(); id != "" {
+			item["id"] = id
+		}
+		var key, value interface{}
+		resSet.ScanKey(&key)
+		resSet.ScanValue(&value)
+		item["key"] = key
+		item["value"] = value
+		if _, hit := kOpts["include_docs"]; hit {
+			var doc interface{}
+			resSet.ScanDoc(&doc)
+			item["doc"] = doc
+		}
+		rows = append(rows, item)
+	}
+	resObj := make(map[string]interface{})
+	resMetadata, _ := resSet.Finish()
+	if resSet.Err() != nil {
+		res.Rows = append(res.Rows, map[string]interface{}{"error": resSet.Err().Error()})
+		return res, nil
+	}
+	resObj["rows"] = rows
+	resObj["total_rows"] = resMetadata.TotalRows
+	resObj["offset"] = resMetadata.Offset
+	res.Rows = append(res.Rows, resObj)
+	res.Success = true
+	return res, nil
+}
+
+// listDesignDocs returns the design documents with their view names.
+func listDesignDocs(db *kivik.DB) ([]map[string]interface{}, error) {
+	designDocs := make([]map[string]interface{}, 0)
+	resSet := db.DesignDocs(context.TODO(), kivik.Options{"include_docs": true})
+	defer resSet.Close()
+	for resSet.Next() {
+		var doc struct {
+			Views map[string]interface{} `json:"views"`
+		}
+		if err := resSet.ScanDoc(&doc); err != nil {
+			return nil, err
+		}
+		views := make([]string, 0, len(doc.Views))
+		for view := range doc.Views {
+			views = append(views, view)
+		}
+		sort.Strings(views)
+		designDocs = append(designDocs, map[string]interface{}{"id": resSet.ID(), "views": views})
+	}
+	if err := resSet.Err(); err != nil {
+		return nil, err
+	}
+	return designDocs, nil
+}
@@ -0,0 +1,65 @@
+package couchdb
+
+import (
+	"testing"
+
+	"github.com/go-kivik/kivik/v4"
+	"github.com/mitchellh/mapstructure"
+	"github.com/stretchr/testify/assert"
+)
+
+func decodeQueryViewOpts(t *testing.T, rawOpts map[string]interface{}) *queryViewOpts {
+	var opts queryViewOpts
+	assert.Nil(t, mapstructure.Decode(rawOpts, &opts))
+	return &opts
+}
+
+func TestExportKivikOptionsWithKeyRange(t *testing.T) {
+	rawOpts := map[string]interface{}{
+		"ddoc":         "_design/orders",
+		"view":         "by_date",
+		"startkey":     []interface{}{"2023", 1.0},
+		"endkey":       []interface{}{"2023", map[string]interface{}{}},
+		"inclusiveEnd": false,
+		"descending":   true,
+		"includeDocs":  true,
+		"limit":        10.0,
+		"skip":         20.0,
+	}
+	assert.Equal(t, kivik.Options{
+		"startkey":      []interface{}{"2023", 1.0},
+		"endkey":        []interface{}{"2023", map[string]interface{}{}},
+		"inclusive_end": false,
+		"descending":    true,
+		"include_docs":  true,
+		"limit":         10,
+		"skip":          20,
+	}, decodeQueryViewOpts(t, rawOpts).exportKivikOptions(rawOpts))
+}
+
+func TestExportKivikOptionsWithReduce(t *testing.T) {
+	rawOpts := map[string]interface{}{
+		"ddoc":        "orders",
+		"view":        "total",
+		"reduce":      true,
+		"group":       true,
+		"groupLevel":  2.0,
+		"includeDocs": true,
+	}
+	// the reduced rows have no document
+	assert.Equal(t, kivik.Options{
+		"reduce":      true,
+		"group":       true,
+		"group_level": 2,
+	}, decodeQueryViewOpts(t, rawOpts).exportKivikOptions(rawOpts))
+
+	rawOpts = map[string]interface{}{"key": "a", "keys": []interface{}{"a", "b"}, "reduce": false, "includeDocs": true}
+	assert.Equal(t, kivik.Options{
+		"key":          "a",
+		"keys":         []interface{}{"a", "b"},
+		"reduce":       false,
+		"include_docs": true,
+	}, decodeQueryViewOpts(t, rawOpts).exportKivikOptions(rawOpts))
+
+	assert.Equal(t, kivik.Options{}, decodeQueryViewOpts(t, map[string]interface{}{}).exportKivikOptions(map[string]interface{}{}))
+}
@@ -17,9 +17,11 @@ package dynamodb
 import (
 	"context"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -30,6 +32,12 @@ const (
 	GET_ITEM_METHOD    = "getItem"
 	UPDATE_ITEM_METHOD = "updateItem"
 	DELETE_ITEM_METHOD = "deleteItem"
+
+	TRANSACT_WRITE_ITEMS_METHOD = "transactWriteItems"
+	TRANSACT_GET_ITEMS_METHOD   = "transactGetItems"
+	BATCH_GET_ITEM_METHOD       = "batchGetItem"
+	BATCH_WRITE_ITEM_METHOD     = "batchWriteItem"
+	EXECUTE_STATEMENT_METHOD    = "executeStatement"
 )
 
 func (d *Connector) getClientWithOptions(resourceOptions map[string]interface{}) (*dynamodb.Client, error) {
@@ -47,7 +55,67 @@ func (d *Connector) getClientWithOptions(resourceOptions map[string]interface{})
 	}
 
 	// Using the Config value, create the DynamoDB client
-	client := dynamodb.NewFromConfig(cfg)
+	client := dynamodb.NewFromConfig(cfg, func(options *dynamodb.Options) {
+		if d.ResourceOpts.Endpoint != "" {
+			options.BaseEndpoint = aws.String(d.ResourceOpts.Endpoint)
+		}
+	})
 
 	return client, nil
 }
+
+// describeTable exports the keys and secondary indexes of table
+func describeTable(svc *dynamodb.Client, table string) (map[string]interface{}, error) {
+	out, err := svc.DescribeTable(context.TODO(), &dynamodb.DescribeTableInput{TableName: aws.String(table)})
+	if err != nil {
+		return nil, err
+	}
+	description := out.Table
+	attributeTypes := make(map[string]string, len(description.AttributeDefinitions))
+	for _, attributeDefinition := range description.AttributeDefinitions {
+		attributeTypes[aws.ToString(attributeDefinition.AttributeName)] = string(attributeDefinition.AttributeType)
+	}
+	exportKeySchema := func(keySchema []types.KeySchemaElement) []map[string]interface{} {
+		keys := make([]map[string]interface{}, 0, len(keySchema))
+		for _, key := range keySchema {
+			attributeName := aws.ToString(key.AttributeName)
+			keys = append(keys, map[string]interface{}{
+				"attributeName": attributeName,
+				"attributeType": attributeTypes[attributeName],
+				"keyType":       string(key.KeyType),
+			})
+		}
+		return keys
+	}
+	exportProjection := func(projection *types.Projection) string {
+		if projection == nil {
+			return ""
+		}
+		return string(projection.ProjectionType)
+	}
+
+	globalSecondaryIndexes := make([]map[string]interface{}, 0, len(description.GlobalSecondaryIndexes))
+	for _, index := range description.GlobalSecondaryIndexes {
+		globalSecondaryIndexes = append(globalSecondaryIndexes, map[string]interface{}{
+			"indexName":      aws.ToString(index.IndexName),
+			"keySchema":      exportKeySchema(index.KeySchema),
+			"projectionType": exportProjection(index.Projection),
+			"indexStatus":    string(index.IndexStatus),
+		})
+	}
+	localSecondaryIndexes := make([]map[string]interface{}, 0, len(description.LocalSecondaryIndexes))
+	for _, index := range description.LocalSecondaryIndexes {
+		localSecondaryIndexes = append(localSecondaryIndexes, map[string]interface{}{
+			"indexName":      aws.ToString(index.IndexName),
+			"keySchema":      exportKeySchema(index.KeySchema),
+			"projectionType": exportProjection(index.Projection),
+		})
+	}
+	return map[string]interface{}{
+		"keySchema":              exportKeySchema(description.KeySchema),
+		"globalSecondaryIndexes": globalSecondaryIndexes,
+		"localSecondaryIndexes":  localSecondaryIndexes,
+		"itemCount":              aws.ToInt64(description.ItemCount),
+		"tableStatus":            string(description.TableStatus),
+	}, nil
+}
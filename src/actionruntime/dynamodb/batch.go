@@ -0,0 +1,217 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	BATCH_GET_ITEM_MAX_KEYS       = 100
+	BATCH_WRITE_ITEM_MAX_REQUESTS = 25
+	// the unprocessed items are retried with exponential backoff
+	BATCH_MAX_RETRIES      = 8
+	BATCH_RETRY_BASE_DELAY = 50 * time.Millisecond
+	BATCH_RETRY_MAX_DELAY  = 2 * time.Second
+)
+
+var errUnprocessedItemsRemain = errors.New("unprocessed items remain after retries, please retry later or increase the table capacity")
+
+func batchRetryDelay(attempt int) time.Duration {
+	delay := BATCH_RETRY_BASE_DELAY << attempt
+	if delay > BATCH_RETRY_MAX_DELAY {
+		return BATCH_RETRY_MAX_DELAY
+	}
+	return delay
+}
+
+func batchGetItem(svc *dynamodb.Client, table string, params map[string]interface{}) (common.RuntimeResult, error) {
+	var batchGetItemParams BatchGetItemParams
+	if err := mapstructure.Decode(params, &batchGetItemParams); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	requestItems := batchGetItemParams.RequestItems
+	if len(requestItems) == 0 {
+		requestItems = map[string]BatchGetTableParams{table: {
+			Keys:                     batchGetItemParams.Keys,
+			ProjectionExpression:     batchGetItemParams.ProjectionExpression,
+			ExpressionAttributeNames: batchGetItemParams.ExpressionAttributeNames,
+			ConsistentRead:           batchGetItemParams.ConsistentRead,
+		}}
+	}
+
+	// split the keys of all tables into requests with max keys
+	tables := make([]string, 0, len(requestItems))
+	for tableName := range requestItems {
+		tables = append(tables, tableName)
+	}
+	sort.Strings(tables)
+	requests := make([]map[string]types.KeysAndAttributes, 0)
+	request := make(map[string]types.KeysAndAttributes)
+	keyCount := 0
+	for _, tableName := range tables {
+		tableParams := requestItems[tableName]
+		if tableName == "" || len(tableParams.Keys) == 0 {
+			return common.RuntimeResult{Success: false}, errors.New("table and keys are required in batch get item")
+		}
+		for _, key := range tableParams.Keys {
+			itemKey, err := attributevalue.MarshalMap(key)
+			if err != nil {
+				return common.RuntimeResult{Success: false}, err
+			}
+			keysAndAttributes, hit := request[tableName]
+			if !hit {
+				keysAndAttributes = types.KeysAndAttributes{ConsistentRead: aws.Bool(tableParams.ConsistentRead)}
+				if tableParams.ProjectionExpression != "" {
+					keysAndAttributes.ProjectionExpression = aws.String(tableParams.ProjectionExpression)
+				}
+				if len(tableParams.ExpressionAttributeNames) != 0 {
+					keysAndAttributes.ExpressionAttributeNames = tableParams.ExpressionAttributeNames
+				}
+			}
+			keysAndAttributes.Keys = append(keysAndAttributes.Keys, itemKey)
+			request[tableName] = keysAndAttributes
+			keyCount++
+			if keyCount == BATCH_GET_ITEM_MAX_KEYS {
+				requests = append(requests, request)
+				request = make(map[string]types.KeysAndAttributes)
+				keyCount = 0
+			}
+		}
+	}
+	if keyCount > 0 {
+		requests = append(requests, request)
+	}
+
+	// run requests, and retry the unprocessed keys
+	responses := make(map[string][]map[string]types.AttributeValue)
+	for _, request := range requests {
+		for attempt := 0; len(request) != 0; attempt++ {
+			if attempt > BATCH_MAX_RETRIES {
+				return common.RuntimeResult{Success: false}, errUnprocessedItemsRemain
+			}
+			if attempt > 0 {
+				time.Sleep(batchRetryDelay(attempt - 1))
+			}
+			out, err := svc.BatchGetItem(context.TODO(), &dynamodb.BatchGetItemInput{RequestItems: request})
+			if err != nil {
+				return common.RuntimeResult{Success: false}, err
+			}
+			for tableName, items := range out.Responses {
+				responses[tableName] = append(responses[tableName], items...)
+			}
+			request = out.UnprocessedKeys
+		}
+	}
+
+	// all items in rows, and items of every table in extra
+	rows := make([]map[string]interface{}, 0)
+	tableRows := make(map[string]interface{}, len(responses))
+	for _, tableName := range tables {
+		items := make([]map[string]interface{}, len(responses[tableName]))
+		if err := attributevalue.UnmarshalListOfMaps(responses[tableName], &items); err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		rows = append(rows, items...)
+		tableRows[tableName] = items
+	}
+	return common.RuntimeResult{Success: true, Rows: rows, Extra: map[string]interface{}{"responses": tableRows}}, nil
+}
+
+func batchWriteItem(svc *dynamodb.Client, table string, params map[string]interface{}) (common.RuntimeResult, error) {
+	var batchWriteItemParams BatchWriteItemParams
+	if err := mapstructure.Decode(params, &batchWriteItemParams); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	requestItems := batchWriteItemParams.RequestItems
+	if len(requestItems) == 0 {
+		requestItems = map[string]BatchWriteTableParams{table: {
+			PutItems:   batchWriteItemParams.PutItems,
+			DeleteKeys: batchWriteItemParams.DeleteKeys,
+		}}
+	}
+
+	// split the write requests of all tables into requests with max write requests
+	tables := make([]string, 0, len(requestItems))
+	for tableName := range requestItems {
+		tables = append(tables, tableName)
+	}
+	sort.Strings(tables)
+	requests := make([]map[string][]types.WriteRequest, 0)
+	request := make(map[string][]types.WriteRequest)
+	requestCount := 0
+	appendWriteRequest := func(tableName string, writeRequest types.WriteRequest) {
+		request[tableName] = append(request[tableName], writeRequest)
+		requestCount++
+		if requestCount == BATCH_WRITE_ITEM_MAX_REQUESTS {
+			requests = append(requests, request)
+			request = make(map[string][]types.WriteRequest)
+			requestCount = 0
+		}
+	}
+	rows := make([]map[string]interface{}, 0, len(tables))
+	for _, tableName := range tables {
+		tableParams := requestItems[tableName]
+		if tableName == "" || len(tableParams.PutItems)+len(tableParams.DeleteKeys) == 0 {
+			return common.RuntimeResult{Success: false}, errors.New("table and put items or delete keys are required in batch write item")
+		}
+		for _, putItem := range tableParams.PutItems {
+			item, err := attributevalue.MarshalMap(putItem)
+			if err != nil {
+				return common.RuntimeResult{Success: false}, err
+			}
+			appendWriteRequest(tableName, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+		}
+		for _, deleteKey := range tableParams.DeleteKeys {
+			itemKey, err := attributevalue.MarshalMap(deleteKey)
+			if err != nil {
+				return common.RuntimeResult{Success: false}, err
+			}
+			appendWriteRequest(tableName, types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: itemKey}})
+		}
+		rows = append(rows, map[string]interface{}{"table": tableName, "putCount": len(tableParams.PutItems), "deleteCount": len(tableParams.DeleteKeys)})
+	}
+	if requestCount > 0 {
+		requests = append(requests, request)
+	}
+
+	// run requests, and retry the unprocessed items
+	for _, request := range requests {
+		for attempt := 0; len(request) != 0; attempt++ {
+			if attempt > BATCH_MAX_RETRIES {
+				return common.RuntimeResult{Success: false}, errUnprocessedItemsRemain
+			}
+			if attempt > 0 {
+				time.Sleep(batchRetryDelay(attempt - 1))
+			}
+			out, err := svc.BatchWriteItem(context.TODO(), &dynamodb.BatchWriteItemInput{RequestItems: request})
+			if err != nil {
+				return common.RuntimeResult{Success: false}, err
+			}
+			request = out.UnprocessedItems
+		}
+	}
+	return common.RuntimeResult{Success: true, Rows: rows, Extra: map[string]interface{}{}}, nil
+}
@@ -0,0 +1,137 @@
+//go:build integration
+
+package dynamodb
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// the tests run against DynamoDB Local, start it by:
+// docker run -p 8000:8000 amazon/dynamodb-local
+// then run: DYNAMODB_TEST_ENDPOINT=http://127.0.0.1:8000 go test -tags integration ./src/actionruntime/dynamodb/
+func newDynamoDBLocalConnector(t *testing.T, itemCount int) (*Connector, map[string]interface{}, string) {
+	endpoint := os.Getenv("DYNAMODB_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("DYNAMODB_TEST_ENDPOINT is not set")
+	}
+	resourceOptions := map[string]interface{}{
+		"region":          "us-east-1",
+		"accessKeyID":     "test",
+		"secretAccessKey": "test",
+		"endpoint":        endpoint,
+	}
+	connector := &Connector{}
+	svc, err := connector.getClientWithOptions(resourceOptions)
+	assert.Nil(t, err)
+
+	table := "illa_test_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	_, err = svc.CreateTable(context.Background(), &dynamodb.CreateTableInput{
+		TableName: aws.String(table),
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("pk"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("sk"), AttributeType: types.ScalarAttributeTypeN},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("pk"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("sk"), KeyType: types.KeyTypeRange},
+		},
+		BillingMode: types.BillingModePayPerRequest,
+	})
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		svc.DeleteTable(context.Background(), &dynamodb.DeleteTableInput{TableName: aws.String(table)})
+	})
+	for i := 0; i < itemCount; i++ {
+		_, err := svc.PutItem(context.Background(), &dynamodb.PutItemInput{
+			TableName: aws.String(table),
+			Item: map[string]types.AttributeValue{
+				"pk":     &types.AttributeValueMemberS{Value: "user"},
+				"sk":     &types.AttributeValueMemberN{Value: strconv.Itoa(i)},
+				"parity": &types.AttributeValueMemberS{Value: []string{"even", "odd"}[i%2]},
+			},
+		})
+		assert.Nil(t, err)
+	}
+	return connector, resourceOptions, table
+}
+
+func runMethod(t *testing.T, connector *Connector, resourceOptions map[string]interface{}, table string, method string, params map[string]interface{}) (map[string]interface{}, []map[string]interface{}) {
+	result, err := connector.Run(resourceOptions, map[string]interface{}{"method": method, "table": table, "structParams": params}, nil)
+	assert.Nil(t, err)
+	assert.True(t, result.Success)
+	return result.Extra, result.Rows
+}
+
+func TestDynamoDBLocalQueryWithoutMaxItemsReturnsOnePage(t *testing.T) {
+	connector, resourceOptions, table := newDynamoDBLocalConnector(t, 30)
+
+	extra, rows := runMethod(t, connector, resourceOptions, table, QUERY_METHOD, map[string]interface{}{
+		"keyConditionExpression":    "pk = :pk",
+		"expressionAttributeValues": map[string]interface{}{":pk": "user"},
+		"limit":                     10,
+	})
+	assert.Equal(t, 10, len(rows))
+	assert.Equal(t, true, extra["truncated"])
+	assert.Equal(t, map[string]interface{}{"pk": "user", "sk": float64(9)}, extra["lastEvaluatedKey"])
+
+	// continue from the last evaluated key
+	extra, rows = runMethod(t, connector, resourceOptions, table, QUERY_METHOD, map[string]interface{}{
+		"keyConditionExpression":    "pk = :pk",
+		"expressionAttributeValues": map[string]interface{}{":pk": "user"},
+		"exclusiveStartKey":         extra["lastEvaluatedKey"],
+	})
+	assert.Equal(t, 20, len(rows))
+	assert.Equal(t, float64(10), rows[0]["sk"])
+	assert.Equal(t, false, extra["truncated"])
+}
+
+func TestDynamoDBLocalQueryWithMaxItemsMakesUpFilteredItems(t *testing.T) {
+	connector, resourceOptions, table := newDynamoDBLocalConnector(t, 30)
+
+	extra, rows := runMethod(t, connector, resourceOptions, table, QUERY_METHOD, map[string]interface{}{
+		"keyConditionExpression":    "pk = :pk",
+		"filterExpression":          "parity = :parity",
+		"expressionAttributeValues": map[string]interface{}{":pk": "user", ":parity": "even"},
+		"limit":                     4,
+		"maxItems":                  10,
+	})
+	assert.Equal(t, 10, len(rows))
+	assert.Equal(t, float64(18), rows[9]["sk"])
+	assert.Equal(t, true, extra["truncated"])
+}
+
+func TestDynamoDBLocalScanWithMaxItems(t *testing.T) {
+	connector, resourceOptions, table := newDynamoDBLocalConnector(t, 30)
+
+	extra, rows := runMethod(t, connector, resourceOptions, table, SCAN_METHOD, map[string]interface{}{"maxItems": 100})
+	assert.Equal(t, 30, len(rows))
+	assert.Equal(t, false, extra["truncated"])
+	assert.Nil(t, extra["lastEvaluatedKey"])
+
+	extra, rows = runMethod(t, connector, resourceOptions, table, SCAN_METHOD, map[string]interface{}{"maxItems": 25})
+	assert.Equal(t, 25, len(rows))
+	assert.Equal(t, true, extra["truncated"])
+}
+
+func TestDynamoDBLocalExecuteStatementWithMaxItems(t *testing.T) {
+	connector, resourceOptions, table := newDynamoDBLocalConnector(t, 30)
+
+	extra, rows := runMethod(t, connector, resourceOptions, table, EXECUTE_STATEMENT_METHOD, map[string]interface{}{
+		"statement":  `SELECT * FROM "` + table + `" WHERE pk = ?`,
+		"parameters": []interface{}{"user"},
+		"limit":      7,
+		"maxItems":   20,
+	})
+	assert.Equal(t, 20, len(rows))
+	assert.Equal(t, true, extra["truncated"])
+	assert.NotEqual(t, "", extra["nextToken"])
+}
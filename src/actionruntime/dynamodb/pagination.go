@@ -0,0 +1,98 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamodb
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+)
+
+type pageOutput struct {
+	items            []map[string]types.AttributeValue
+	lastEvaluatedKey map[string]types.AttributeValue
+	count            int32
+	scannedCount     int32
+}
+
+// paginate fetches pages by last evaluated key until the max items reached or no more pages,
+// the limit of every page is not greater than remaining items, so the last evaluated key returned can continue exactly.
+// Only one page is fetched like a single request when the max items is not set.
+// The truncated flag in extra tells there are more items after the last evaluated key.
+func paginate(pageLimit *int32, maxItems int, startKey map[string]types.AttributeValue, fetch func(limit *int32, startKey map[string]types.AttributeValue) (*pageOutput, error)) (common.RuntimeResult, error) {
+	rows := make([]map[string]interface{}, 0)
+	var count, scannedCount int32
+	var lastEvaluatedKey map[string]types.AttributeValue
+	for {
+		limit := pageLimit
+		if remaining := int32(maxItems - len(rows)); maxItems > 0 && (limit == nil || *limit > remaining) {
+			limit = aws.Int32(remaining)
+		}
+		page, err := fetch(limit, startKey)
+		if err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		pageRows := make([]map[string]interface{}, len(page.items))
+		if err := attributevalue.UnmarshalListOfMaps(page.items, &pageRows); err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		rows = append(rows, pageRows...)
+		count += page.count
+		scannedCount += page.scannedCount
+		lastEvaluatedKey = page.lastEvaluatedKey
+		if len(lastEvaluatedKey) == 0 || maxItems <= 0 || len(rows) >= maxItems {
+			break
+		}
+		startKey = lastEvaluatedKey
+	}
+
+	extra := map[string]interface{}{"count": count, "scannedCount": scannedCount, "lastEvaluatedKey": nil, "truncated": len(lastEvaluatedKey) != 0}
+	if len(lastEvaluatedKey) != 0 {
+		lastEvaluatedKeyInMap := make(map[string]interface{})
+		if err := attributevalue.UnmarshalMap(lastEvaluatedKey, &lastEvaluatedKeyInMap); err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		extra["lastEvaluatedKey"] = lastEvaluatedKeyInMap
+	}
+	return common.RuntimeResult{Success: true, Rows: rows, Extra: extra}, nil
+}
+
+func queryPages(svc *dynamodb.Client, in *dynamodb.QueryInput, maxItems int) (common.RuntimeResult, error) {
+	return paginate(in.Limit, maxItems, in.ExclusiveStartKey, func(limit *int32, startKey map[string]types.AttributeValue) (*pageOutput, error) {
+		in.Limit = limit
+		in.ExclusiveStartKey = startKey
+		out, err := svc.Query(context.TODO(), in)
+		if err != nil {
+			return nil, err
+		}
+		return &pageOutput{items: out.Items, lastEvaluatedKey: out.LastEvaluatedKey, count: out.Count, scannedCount: out.ScannedCount}, nil
+	})
+}
+
+func scanPages(svc *dynamodb.Client, in *dynamodb.ScanInput, maxItems int) (common.RuntimeResult, error) {
+	return paginate(in.Limit, maxItems, in.ExclusiveStartKey, func(limit *int32, startKey map[string]types.AttributeValue) (*pageOutput, error) {
+		in.Limit = limit
+		in.ExclusiveStartKey = startKey
+		out, err := svc.Scan(context.TODO(), in)
+		if err != nil {
+			return nil, err
+		}
+		return &pageOutput{items: out.Items, lastEvaluatedKey: out.LastEvaluatedKey, count: out.Count, scannedCount: out.ScannedCount}, nil
+	})
+}
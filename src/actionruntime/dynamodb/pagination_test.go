@@ -0,0 +1,85 @@
+package dynamodb
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// newFakeFetch serves the items in pages, every page evaluates at most pageSize items and the odd ids are filtered out
+func newFakeFetch(total int, pageSize int32, requestedLimits *[]int32) func(limit *int32, startKey map[string]types.AttributeValue) (*pageOutput, error) {
+	return func(limit *int32, startKey map[string]types.AttributeValue) (*pageOutput, error) {
+		evaluated := pageSize
+		if limit != nil {
+			*requestedLimits = append(*requestedLimits, *limit)
+			if *limit < evaluated {
+				evaluated = *limit
+			}
+		} else {
+			*requestedLimits = append(*requestedLimits, 0)
+		}
+		start := 0
+		if startKey != nil {
+			start, _ = strconv.Atoi(startKey["id"].(*types.AttributeValueMemberN).Value)
+			start++
+		}
+		page := &pageOutput{}
+		end := start + int(evaluated)
+		if end > total {
+			end = total
+		}
+		for id := start; id < end; id++ {
+			page.scannedCount++
+			if id%2 == 1 {
+				continue
+			}
+			page.items = append(page.items, map[string]types.AttributeValue{"id": &types.AttributeValueMemberN{Value: strconv.Itoa(id)}})
+			page.count++
+		}
+		if end < total {
+			page.lastEvaluatedKey = map[string]types.AttributeValue{"id": &types.AttributeValueMemberN{Value: strconv.Itoa(end - 1)}}
+		}
+		return page, nil
+	}
+}
+
+func TestPaginateWithoutMaxItemsFetchesOnePage(t *testing.T) {
+	requestedLimits := make([]int32, 0)
+	result, err := paginate(nil, 0, nil, newFakeFetch(3000, 100, &requestedLimits))
+	assert.Nil(t, err)
+	assert.Equal(t, []int32{0}, requestedLimits)
+	assert.Equal(t, 50, len(result.Rows))
+	assert.Equal(t, true, result.Extra["truncated"])
+	assert.Equal(t, map[string]interface{}{"id": float64(99)}, result.Extra["lastEvaluatedKey"])
+}
+
+func TestPaginateWithMaxItemsFetchesUntilReached(t *testing.T) {
+	requestedLimits := make([]int32, 0)
+	limit := int32(40)
+	result, err := paginate(&limit, 50, nil, newFakeFetch(3000, 100, &requestedLimits))
+	assert.Nil(t, err)
+	// the limit of every page is not greater than the remaining items
+	assert.Equal(t, []int32{40, 30, 15, 7, 4, 2, 1}, requestedLimits)
+	assert.Equal(t, 50, len(result.Rows))
+	assert.Equal(t, int32(50), result.Extra["count"])
+	assert.Equal(t, int32(99), result.Extra["scannedCount"])
+	assert.Equal(t, true, result.Extra["truncated"])
+
+	// continue from the last evaluated key
+	lastEvaluatedKey := result.Extra["lastEvaluatedKey"].(map[string]interface{})
+	assert.Equal(t, float64(98), lastEvaluatedKey["id"])
+	result, err = paginate(&limit, 5, map[string]types.AttributeValue{"id": &types.AttributeValueMemberN{Value: "98"}}, newFakeFetch(3000, 100, &requestedLimits))
+	assert.Nil(t, err)
+	assert.Equal(t, float64(100), result.Rows[0]["id"])
+}
+
+func TestPaginateReturnsAllItemsWhenNotTruncated(t *testing.T) {
+	requestedLimits := make([]int32, 0)
+	result, err := paginate(nil, 1000, nil, newFakeFetch(30, 10, &requestedLimits))
+	assert.Nil(t, err)
+	assert.Equal(t, 15, len(result.Rows))
+	assert.Equal(t, false, result.Extra["truncated"])
+	assert.Nil(t, result.Extra["lastEvaluatedKey"])
+}
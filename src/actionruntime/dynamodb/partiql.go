@@ -0,0 +1,91 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamodb
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/go-playground/validator/v10"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/mitchellh/mapstructure"
+)
+
+// executeStatement runs the PartiQL statement, the select statement fetches pages by next token like query when max items is set
+func executeStatement(svc *dynamodb.Client, params map[string]interface{}) (common.RuntimeResult, error) {
+	var executeStatementParams ExecuteStatementParams
+	if err := mapstructure.Decode(params, &executeStatementParams); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	validate := validator.New()
+	if err := validate.Struct(executeStatementParams); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	in := &dynamodb.ExecuteStatementInput{
+		Statement:      aws.String(executeStatementParams.Statement),
+		ConsistentRead: aws.Bool(executeStatementParams.ConsistentRead),
+	}
+	if len(executeStatementParams.Parameters) != 0 {
+		parameters := make([]types.AttributeValue, 0, len(executeStatementParams.Parameters))
+		for _, parameter := range executeStatementParams.Parameters {
+			attributeValue, err := attributevalue.Marshal(parameter)
+			if err != nil {
+				return common.RuntimeResult{Success: false}, err
+			}
+			parameters = append(parameters, attributeValue)
+		}
+		in.Parameters = parameters
+	}
+	if executeStatementParams.NextToken != "" {
+		in.NextToken = aws.String(executeStatementParams.NextToken)
+	}
+
+	// only the select statement returns items in pages
+	isSelectStatement := strings.HasPrefix(strings.ToUpper(strings.TrimSpace(executeStatementParams.Statement)), "SELECT")
+	maxItems := executeStatementParams.MaxItems
+	rows := make([]map[string]interface{}, 0)
+	for {
+		if executeStatementParams.Limit > 0 {
+			in.Limit = aws.Int32(executeStatementParams.Limit)
+		}
+		if remaining := int32(maxItems - len(rows)); isSelectStatement && maxItems > 0 && (in.Limit == nil || *in.Limit > remaining) {
+			in.Limit = aws.Int32(remaining)
+		}
+		out, err := svc.ExecuteStatement(context.TODO(), in)
+		if err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		pageRows := make([]map[string]interface{}, len(out.Items))
+		if err := attributevalue.UnmarshalListOfMaps(out.Items, &pageRows); err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		rows = append(rows, pageRows...)
+		in.NextToken = out.NextToken
+		if !isSelectStatement || in.NextToken == nil || maxItems <= 0 || len(rows) >= maxItems {
+			break
+		}
+	}
+
+	return common.RuntimeResult{
+		Success: true,
+		Rows:    rows,
+		Extra:   map[string]interface{}{"nextToken": aws.ToString(in.NextToken), "truncated": in.NextToken != nil},
+	}, nil
+}
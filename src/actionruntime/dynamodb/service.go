@@ -90,9 +90,19 @@ func (d *Connector) GetMetaInfo(resourceOptions map[string]interface{}) (common.
 		return common.MetaInfoResult{Success: false}, err
 	}
 
+	// describe tables
+	tableSchemas := make(map[string]interface{}, len(resp.TableNames))
+	for _, table := range resp.TableNames {
+		tableSchema, err := describeTable(svc, table)
+		if err != nil {
+			return common.MetaInfoResult{Success: false}, err
+		}
+		tableSchemas[table] = tableSchema
+	}
+
 	return common.MetaInfoResult{
 		Success: true,
-		Schema:  map[string]interface{}{"tables": resp.TableNames},
+		Schema:  map[string]interface{}{"tables": resp.TableNames, "tableSchemas": tableSchemas},
 	}, nil
 }
 
@@ -123,35 +133,17 @@ func (d *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 	}
 	switch d.ActionOpts.Method {
 	case QUERY_METHOD:
-		in, err := buildQueryInput(d.ActionOpts.Table, d.ActionOpts.StructParams)
-		if err != nil {
-			return res, err
-		}
-		out, err := svc.Query(context.TODO(), in)
+		in, maxItems, err := buildQueryInput(d.ActionOpts.Table, d.ActionOpts.StructParams)
 		if err != nil {
 			return res, err
 		}
-		rows := make([]map[string]interface{}, len(out.Items))
-		if err := attributevalue.UnmarshalListOfMaps(out.Items, &rows); err != nil {
-			return res, err
-		}
-		res.Success = true
-		res.Rows = rows
+		return queryPages(svc, in, maxItems)
 	case SCAN_METHOD:
-		in, err := buildScanInput(d.ActionOpts.Table, d.ActionOpts.StructParams)
+		in, maxItems, err := buildScanInput(d.ActionOpts.Table, d.ActionOpts.StructParams)
 		if err != nil {
 			return res, err
 		}
-		out, err := svc.Scan(context.TODO(), in)
-		if err != nil {
-			return res, err
-		}
-		rows := make([]map[string]interface{}, len(out.Items))
-		if err := attributevalue.UnmarshalListOfMaps(out.Items, &rows); err != nil {
-			return res, err
-		}
-		res.Success = true
-		res.Rows = rows
+		return scanPages(svc, in, maxItems)
 	case PUT_ITEM_METHOD:
 		in, err := buildPutItemInput(d.ActionOpts.Table, d.ActionOpts.StructParams)
 		if err != nil {
@@ -197,6 +189,16 @@ func (d *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 		}
 		res.Success = true
 		res.Rows = append(res.Rows, map[string]interface{}{"message": "delete item successfully"})
+	case TRANSACT_WRITE_ITEMS_METHOD:
+		return transactWriteItems(svc, d.ActionOpts.Table, d.ActionOpts.StructParams)
+	case TRANSACT_GET_ITEMS_METHOD:
+		return transactGetItems(svc, d.ActionOpts.Table, d.ActionOpts.StructParams)
+	case BATCH_GET_ITEM_METHOD:
+		return batchGetItem(svc, d.ActionOpts.Table, d.ActionOpts.StructParams)
+	case BATCH_WRITE_ITEM_METHOD:
+		return batchWriteItem(svc, d.ActionOpts.Table, d.ActionOpts.StructParams)
+	case EXECUTE_STATEMENT_METHOD:
+		return executeStatement(svc, d.ActionOpts.StructParams)
 	default:
 		return res, errors.New("unsupported dynamodb method")
 	}
@@ -204,10 +206,10 @@ func (d *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 	return res, nil
 }
 
-func buildQueryInput(table string, params map[string]interface{}) (*dynamodb.QueryInput, error) {
+func buildQueryInput(table string, params map[string]interface{}) (*dynamodb.QueryInput, int, error) {
 	var queryParams QueryParams
 	if err := mapstructure.Decode(params, &queryParams); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	res := &dynamodb.QueryInput{
@@ -226,7 +228,7 @@ func buildQueryInput(table string, params map[string]interface{}) (*dynamodb.Que
 	if len(queryParams.ExpressionAttributeValues) != 0 {
 		expressionAttributeValues, err := attributevalue.MarshalMap(queryParams.ExpressionAttributeValues)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		res.ExpressionAttributeValues = expressionAttributeValues
 	}
@@ -239,14 +241,21 @@ func buildQueryInput(table string, params map[string]interface{}) (*dynamodb.Que
 	if queryParams.Select != "" {
 		res.Select = types.Select(queryParams.Select)
 	}
+	if len(queryParams.ExclusiveStartKey) != 0 {
+		exclusiveStartKey, err := attributevalue.MarshalMap(queryParams.ExclusiveStartKey)
+		if err != nil {
+			return nil, 0, err
+		}
+		res.ExclusiveStartKey = exclusiveStartKey
+	}
 
-	return res, nil
+	return res, queryParams.MaxItems, nil
 }
 
-func buildScanInput(table string, params map[string]interface{}) (*dynamodb.ScanInput, error) {
+func buildScanInput(table string, params map[string]interface{}) (*dynamodb.ScanInput, int, error) {
 	var scanParams ScanParams
 	if err := mapstructure.Decode(params, &scanParams); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	res := &dynamodb.ScanInput{
@@ -264,7 +273,7 @@ func buildScanInput(table string, params map[string]interface{}) (*dynamodb.Scan
 	if len(scanParams.ExpressionAttributeValues) != 0 {
 		expressionAttributeValues, err := attributevalue.MarshalMap(scanParams.ExpressionAttributeValues)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		res.ExpressionAttributeValues = expressionAttributeValues
 	}
@@ -277,8 +286,15 @@ func buildScanInput(table string, params map[string]interface{}) (*dynamodb.Scan
 	if scanParams.Select != "" {
 		res.Select = types.Select(scanParams.Select)
 	}
+	if len(scanParams.ExclusiveStartKey) != 0 {
+		exclusiveStartKey, err := attributevalue.MarshalMap(scanParams.ExclusiveStartKey)
+		if err != nil {
+			return nil, 0, err
+		}
+		res.ExclusiveStartKey = exclusiveStartKey
+	}
 
-	return res, nil
+	return res, scanParams.MaxItems, nil
 }
 
 func buildPutItemInput(table string, params map[string]interface{}) (*dynamodb.PutItemInput, error) {
@@ -0,0 +1,190 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/go-playground/validator/v10"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/mitchellh/mapstructure"
+)
+
+func transactWriteItems(svc *dynamodb.Client, table string, params map[string]interface{}) (common.RuntimeResult, error) {
+	var transactWriteItemsParams TransactWriteItemsParams
+	if err := mapstructure.Decode(params, &transactWriteItemsParams); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	validate := validator.New()
+	if err := validate.Struct(transactWriteItemsParams); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	transactItems := make([]types.TransactWriteItem, 0, len(transactWriteItemsParams.TransactItems))
+	for serial, item := range transactWriteItemsParams.TransactItems {
+		transactItem, err := buildTransactWriteItem(table, item)
+		if err != nil {
+			return common.RuntimeResult{Success: false}, fmt.Errorf("transact item %d: %s", serial, err.Error())
+		}
+		transactItems = append(transactItems, transactItem)
+	}
+	in := &dynamodb.TransactWriteItemsInput{TransactItems: transactItems}
+	if transactWriteItemsParams.ClientRequestToken != "" {
+		in.ClientRequestToken = aws.String(transactWriteItemsParams.ClientRequestToken)
+	}
+
+	if _, err := svc.TransactWriteItems(context.TODO(), in); err != nil {
+		return exportCancellationReasons(err), err
+	}
+	return common.RuntimeResult{
+		Success: true,
+		Rows:    []map[string]interface{}{{"message": "transact write items successfully", "count": len(transactItems)}},
+		Extra:   map[string]interface{}{},
+	}, nil
+}
+
+func buildTransactWriteItem(table string, item TransactWriteItem) (types.TransactWriteItem, error) {
+	if item.Table != "" {
+		table = item.Table
+	}
+	operationCount := 0
+	for _, operation := range []map[string]interface{}{item.Put, item.Update, item.Delete, item.ConditionCheck} {
+		if len(operation) != 0 {
+			operationCount++
+		}
+	}
+	if operationCount != 1 {
+		return types.TransactWriteItem{}, errors.New("should contain exactly one of put, update, delete and condition check")
+	}
+
+	switch {
+	case len(item.Put) != 0:
+		in, err := buildPutItemInput(table, item.Put)
+		if err != nil {
+			return types.TransactWriteItem{}, err
+		}
+		return types.TransactWriteItem{Put: &types.Put{
+			TableName:                 in.TableName,
+			Item:                      in.Item,
+			ConditionExpression:       in.ConditionExpression,
+			ExpressionAttributeNames:  in.ExpressionAttributeNames,
+			ExpressionAttributeValues: in.ExpressionAttributeValues,
+		}}, nil
+	case len(item.Update) != 0:
+		in, err := buildUpdateItemInput(table, item.Update)
+		if err != nil {
+			return types.TransactWriteItem{}, err
+		}
+		return types.TransactWriteItem{Update: &types.Update{
+			TableName:                 in.TableName,
+			Key:                       in.Key,
+			UpdateExpression:          in.UpdateExpression,
+			ConditionExpression:       in.ConditionExpression,
+			ExpressionAttributeNames:  in.ExpressionAttributeNames,
+			ExpressionAttributeValues: in.ExpressionAttributeValues,
+		}}, nil
+	case len(item.Delete) != 0:
+		in, err := buildDeleteItemInput(table, item.Delete)
+		if err != nil {
+			return types.TransactWriteItem{}, err
+		}
+		return types.TransactWriteItem{Delete: &types.Delete{
+			TableName:                 in.TableName,
+			Key:                       in.Key,
+			ConditionExpression:       in.ConditionExpression,
+			ExpressionAttributeNames:  in.ExpressionAttributeNames,
+			ExpressionAttributeValues: in.ExpressionAttributeValues,
+		}}, nil
+	default:
+		in, err := buildDeleteItemInput(table, item.ConditionCheck)
+		if err != nil {
+			return types.TransactWriteItem{}, err
+		}
+		return types.TransactWriteItem{ConditionCheck: &types.ConditionCheck{
+			TableName:                 in.TableName,
+			Key:                       in.Key,
+			ConditionExpression:       in.ConditionExpression,
+			ExpressionAttributeNames:  in.ExpressionAttributeNames,
+			ExpressionAttributeValues: in.ExpressionAttributeValues,
+		}}, nil
+	}
+}
+
+// exportCancellationReasons exports the reason of every transact item when transaction canceled, so the failed item can be found
+func exportCancellationReasons(err error) common.RuntimeResult {
+	var transactionCanceledException *types.TransactionCanceledException
+	if !errors.As(err, &transactionCanceledException) {
+		return common.RuntimeResult{Success: false}
+	}
+	rows := make([]map[string]interface{}, 0, len(transactionCanceledException.CancellationReasons))
+	for serial, reason := range transactionCanceledException.CancellationReasons {
+		rows = append(rows, map[string]interface{}{
+			"index":   serial,
+			"code":    aws.ToString(reason.Code),
+			"message": aws.ToString(reason.Message),
+		})
+	}
+	return common.RuntimeResult{Success: false, Rows: rows}
+}
+
+func transactGetItems(svc *dynamodb.Client, table string, params map[string]interface{}) (common.RuntimeResult, error) {
+	var transactGetItemsParams TransactGetItemsParams
+	if err := mapstructure.Decode(params, &transactGetItemsParams); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	validate := validator.New()
+	if err := validate.Struct(transactGetItemsParams); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	transactItems := make([]types.TransactGetItem, 0, len(transactGetItemsParams.TransactItems))
+	for _, item := range transactGetItemsParams.TransactItems {
+		itemTable := table
+		if item.Table != "" {
+			itemTable = item.Table
+		}
+		in, err := buildGetItemInput(itemTable, item.Get)
+		if err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		transactItems = append(transactItems, types.TransactGetItem{Get: &types.Get{
+			TableName:                in.TableName,
+			Key:                      in.Key,
+			ProjectionExpression:     in.ProjectionExpression,
+			ExpressionAttributeNames: in.ExpressionAttributeNames,
+		}})
+	}
+
+	out, err := svc.TransactGetItems(context.TODO(), &dynamodb.TransactGetItemsInput{TransactItems: transactItems})
+	if err != nil {
+		return exportCancellationReasons(err), err
+	}
+	// the responses are in the same order of transact items, the item not found is empty
+	rows := make([]map[string]interface{}, 0, len(out.Responses))
+	for _, response := range out.Responses {
+		row := make(map[string]interface{})
+		if err := attributevalue.UnmarshalMap(response.Item, &row); err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		rows = append(rows, row)
+	}
+	return common.RuntimeResult{Success: true, Rows: rows, Extra: map[string]interface{}{}}, nil
+}
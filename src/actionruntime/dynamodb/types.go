@@ -18,10 +18,12 @@ type Resource struct {
 	Region          string `validate:"required"`
 	AccessKeyID     string `validate:"required"`
 	SecretAccessKey string `validate:"required"`
+	// Endpoint overrides the service endpoint, like http://localhost:8000 of DynamoDB Local
+	Endpoint string `validate:"omitempty,url"`
 }
 
 type Action struct {
-	Method       string `validate:"required,oneof=query scan putItem getItem updateItem deleteItem"`
+	Method       string `validate:"required,oneof=query scan putItem getItem updateItem deleteItem transactWriteItems transactGetItems batchGetItem batchWriteItem executeStatement"`
 	Table        string
 	UseJson      bool
 	Parameters   string
@@ -37,6 +39,9 @@ type QueryParams struct {
 	ExpressionAttributeValues map[string]interface{}
 	Limit                     int32
 	Select                    string
+	ExclusiveStartKey         map[string]interface{}
+	// MaxItems enables the pagination, the pages are fetched until max items reached, otherwise only one page is returned
+	MaxItems int
 }
 
 type ScanParams struct {
@@ -47,6 +52,9 @@ type ScanParams struct {
 	ExpressionAttributeValues map[string]interface{}
 	Limit                     int32
 	Select                    string
+	ExclusiveStartKey         map[string]interface{}
+	// MaxItems enables the pagination, the pages are fetched until max items reached, otherwise only one page is returned
+	MaxItems int
 }
 
 type PutItemParams struct {
@@ -76,3 +84,65 @@ type DeleteItemParams struct {
 	ExpressionAttributeNames  map[string]string
 	ExpressionAttributeValues map[string]interface{}
 }
+
+// TransactWriteItem contains one of put, update, delete and condition check, the params of them are same as the item methods.
+// The condition check params are same as delete item params.
+type TransactWriteItem struct {
+	Table          string
+	Put            map[string]interface{}
+	Update         map[string]interface{}
+	Delete         map[string]interface{}
+	ConditionCheck map[string]interface{}
+}
+
+type TransactWriteItemsParams struct {
+	TransactItems      []TransactWriteItem `validate:"required,gt=0,lte=100"`
+	ClientRequestToken string
+}
+
+type TransactGetItem struct {
+	Table string
+	Get   map[string]interface{} `validate:"required"`
+}
+
+type TransactGetItemsParams struct {
+	TransactItems []TransactGetItem `validate:"required,gt=0,lte=100,dive"`
+}
+
+type BatchGetTableParams struct {
+	Keys                     []map[string]interface{} `validate:"required,gt=0"`
+	ProjectionExpression     string
+	ExpressionAttributeNames map[string]string
+	ConsistentRead           bool
+}
+
+// BatchGetItemParams gets items from tables, the table of action is used when request items is empty
+type BatchGetItemParams struct {
+	RequestItems             map[string]BatchGetTableParams
+	Keys                     []map[string]interface{}
+	ProjectionExpression     string
+	ExpressionAttributeNames map[string]string
+	ConsistentRead           bool
+}
+
+type BatchWriteTableParams struct {
+	PutItems   []map[string]interface{}
+	DeleteKeys []map[string]interface{}
+}
+
+// BatchWriteItemParams puts and deletes items in tables, the table of action is used when request items is empty
+type BatchWriteItemParams struct {
+	RequestItems map[string]BatchWriteTableParams
+	PutItems     []map[string]interface{}
+	DeleteKeys   []map[string]interface{}
+}
+
+type ExecuteStatementParams struct {
+	Statement      string `validate:"required"`
+	Parameters     []interface{}
+	ConsistentRead bool
+	Limit          int32
+	NextToken      string
+	// MaxItems enables the pagination of select statement, the pages are fetched until max items reached
+	MaxItems int
+}
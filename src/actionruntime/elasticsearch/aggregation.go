@@ -0,0 +1,136 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"sort"
+)
+
+// the fields of bucket which are not sub aggregations
+var bucketReservedFields = map[string]bool{
+	"key":            true,
+	"key_as_string":  true,
+	"doc_count":      true,
+	"from":           true,
+	"from_as_string": true,
+	"to":             true,
+	"to_as_string":   true,
+}
+
+// flattenAggregations flattens the aggregations response into rows.
+// The metric aggregations at the same level become the columns of every row, and every bucket of bucket aggregations becomes a row
+// with the bucket key and doc count, the sub aggregations of bucket are flattened with the bucket columns recursively.
+// For example {"by_type": {"buckets": [{"key": "a", "doc_count": 2, "avg_price": {"value": 3}}]}} flattened into
+// [{"by_type": "a", "by_type.doc_count": 2, "avg_price": 3}].
+func flattenAggregations(aggregations map[string]interface{}, baseRow map[string]interface{}) []map[string]interface{} {
+	names := make([]string, 0, len(aggregations))
+	for name := range aggregations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// collect metrics first, then expand buckets
+	row := copyRow(baseRow)
+	bucketAggregations := make([]string, 0)
+	for _, name := range names {
+		aggregation, isMap := aggregations[name].(map[string]interface{})
+		if !isMap {
+			continue
+		}
+		if _, hit := aggregation["buckets"]; hit {
+			bucketAggregations = append(bucketAggregations, name)
+			continue
+		}
+		flattenMetric(row, name, aggregation)
+	}
+	if len(bucketAggregations) == 0 {
+		return []map[string]interface{}{row}
+	}
+
+	rows := make([]map[string]interface{}, 0)
+	for _, name := range bucketAggregations {
+		aggregation := aggregations[name].(map[string]interface{})
+		switch buckets := aggregation["buckets"].(type) {
+		case []interface{}:
+			for _, bucket := range buckets {
+				bucketInMap, _ := bucket.(map[string]interface{})
+				rows = append(rows, flattenBucket(row, name, bucketInMap["key"], bucketInMap)...)
+			}
+		case map[string]interface{}:
+			// keyed buckets like filters aggregation
+			keys := make([]string, 0, len(buckets))
+			for key := range buckets {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				bucketInMap, _ := buckets[key].(map[string]interface{})
+				rows = append(rows, flattenBucket(row, name, key, bucketInMap)...)
+			}
+		}
+	}
+	return rows
+}
+
+func flattenBucket(baseRow map[string]interface{}, name string, key interface{}, bucket map[string]interface{}) []map[string]interface{} {
+	row := copyRow(baseRow)
+	if keyAsString, hit := bucket["key_as_string"]; hit {
+		key = keyAsString
+	}
+	// composite aggregation has multiple sources in key
+	if compositeKey, isMap := key.(map[string]interface{}); isMap {
+		for source, value := range compositeKey {
+			row[name+"."+source] = value
+		}
+	} else {
+		row[name] = key
+	}
+	row[name+".doc_count"] = bucket["doc_count"]
+
+	subAggregations := make(map[string]interface{})
+	for field, value := range bucket {
+		if _, isMap := value.(map[string]interface{}); isMap && !bucketReservedFields[field] {
+			subAggregations[field] = value
+		}
+	}
+	return flattenAggregations(subAggregations, row)
+}
+
+func flattenMetric(row map[string]interface{}, name string, metric map[string]interface{}) {
+	// single value metric like avg, sum and cardinality
+	if value, hit := metric["value"]; hit {
+		row[name] = value
+		return
+	}
+	// multi values metric like percentiles
+	if values, isMap := metric["values"].(map[string]interface{}); isMap {
+		for key, value := range values {
+			row[name+"."+key] = value
+		}
+		return
+	}
+	// stats and other metrics
+	for field, value := range metric {
+		row[name+"."+field] = value
+	}
+}
+
+func copyRow(row map[string]interface{}) map[string]interface{} {
+	newRow := make(map[string]interface{}, len(row))
+	for key, value := range row {
+		newRow[key] = value
+	}
+	return newRow
+}
@@ -0,0 +1,69 @@
+package elasticsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlattenMetricAggregations(t *testing.T) {
+	aggregations := map[string]interface{}{
+		"avg_price":   map[string]interface{}{"value": 3.5},
+		"percentiles": map[string]interface{}{"values": map[string]interface{}{"50.0": 2.0, "99.0": 9.0}},
+		"stats":       map[string]interface{}{"min": 1.0, "max": 9.0},
+	}
+	assert.Equal(t, []map[string]interface{}{{
+		"avg_price":        3.5,
+		"percentiles.50.0": 2.0,
+		"percentiles.99.0": 9.0,
+		"stats.min":        1.0,
+		"stats.max":        9.0,
+	}}, flattenAggregations(aggregations, map[string]interface{}{}))
+}
+
+func TestFlattenBucketAggregations(t *testing.T) {
+	aggregations := map[string]interface{}{
+		"total": map[string]interface{}{"value": 10.0},
+		"by_type": map[string]interface{}{
+			"buckets": []interface{}{
+				map[string]interface{}{
+					"key":       "a",
+					"doc_count": 2.0,
+					"avg_price": map[string]interface{}{"value": 3.0},
+					"by_month": map[string]interface{}{"buckets": []interface{}{
+						map[string]interface{}{"key": 1.0, "key_as_string": "2023-01", "doc_count": 1.0},
+						map[string]interface{}{"key": 2.0, "key_as_string": "2023-02", "doc_count": 1.0},
+					}},
+				},
+				map[string]interface{}{"key": "b", "doc_count": 1.0, "avg_price": map[string]interface{}{"value": 5.0}},
+			},
+		},
+	}
+	assert.Equal(t, []map[string]interface{}{
+		{"total": 10.0, "by_type": "a", "by_type.doc_count": 2.0, "avg_price": 3.0, "by_month": "2023-01", "by_month.doc_count": 1.0},
+		{"total": 10.0, "by_type": "a", "by_type.doc_count": 2.0, "avg_price": 3.0, "by_month": "2023-02", "by_month.doc_count": 1.0},
+		{"total": 10.0, "by_type": "b", "by_type.doc_count": 1.0, "avg_price": 5.0},
+	}, flattenAggregations(aggregations, map[string]interface{}{}))
+}
+
+func TestFlattenKeyedAndCompositeBuckets(t *testing.T) {
+	aggregations := map[string]interface{}{
+		"status": map[string]interface{}{"buckets": map[string]interface{}{
+			"ok":    map[string]interface{}{"doc_count": 3.0},
+			"error": map[string]interface{}{"doc_count": 1.0},
+		}},
+	}
+	assert.Equal(t, []map[string]interface{}{
+		{"status": "error", "status.doc_count": 1.0},
+		{"status": "ok", "status.doc_count": 3.0},
+	}, flattenAggregations(aggregations, map[string]interface{}{}))
+
+	aggregations = map[string]interface{}{
+		"pairs": map[string]interface{}{"buckets": []interface{}{
+			map[string]interface{}{"key": map[string]interface{}{"city": "x", "type": "a"}, "doc_count": 4.0},
+		}},
+	}
+	assert.Equal(t, []map[string]interface{}{
+		{"pairs.city": "x", "pairs.type": "a", "pairs.doc_count": 4.0},
+	}, flattenAggregations(aggregations, map[string]interface{}{}))
+}
@@ -0,0 +1,48 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"fmt"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+)
+
+func (e *Connector) ClassifyError(err error) *common.ConnectorError {
+	return common.ClassifyNetworkError(err)
+}
+
+// newResponseError converts the error response body like {"error": {"type": "...", "reason": "..."}, "status": 400} into ConnectorError
+func newResponseError(res *esapi.Response, body map[string]interface{}) *common.ConnectorError {
+	errorType := ""
+	errorReason := ""
+	switch errorContent := body["error"].(type) {
+	case map[string]interface{}:
+		errorType, _ = errorContent["type"].(string)
+		errorReason, _ = errorContent["reason"].(string)
+	case string:
+		errorReason = errorContent
+	}
+	if errorReason == "" {
+		errorReason = "request failed"
+	}
+	err := fmt.Errorf("%s %s", res.Status(), errorReason)
+	connectorError := common.ClassifyHTTPStatusCode(res.StatusCode, err).SetMessage(errorReason)
+	if errorType != "" {
+		connectorError.SetVendorCode(errorType)
+	}
+	return connectorError
+}
@@ -0,0 +1,118 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"context"
+	"strings"
+
+	es "github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+)
+
+func (o *OperationRunner) createIndex() (common.RuntimeResult, error) {
+	options := []func(*esapi.IndicesCreateRequest){o.client.Indices.Create.WithContext(context.Background())}
+	// the body contains the settings and mappings of index, and can be empty
+	if o.operation.Body != "" {
+		body, err := encodeBody(o.operation.Body)
+		if err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		options = append(options, o.client.Indices.Create.WithBody(body))
+	}
+
+	// Perform the create index request.
+	res, err := o.client.Indices.Create(o.operation.Index, options...)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	defer res.Body.Close()
+
+	return formatResponse(res)
+}
+
+func (o *OperationRunner) deleteIndex() (common.RuntimeResult, error) {
+	// Perform the delete index request.
+	res, err := o.client.Indices.Delete(
+		o.exportIndices(),
+		o.client.Indices.Delete.WithContext(context.Background()),
+	)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	defer res.Body.Close()
+
+	return formatResponse(res)
+}
+
+func (o *OperationRunner) getMapping() (common.RuntimeResult, error) {
+	options := []func(*esapi.IndicesGetMappingRequest){o.client.Indices.GetMapping.WithContext(context.Background())}
+	if o.operation.Index != "" {
+		options = append(options, o.client.Indices.GetMapping.WithIndex(o.exportIndices()...))
+	}
+
+	// Perform the get mapping request.
+	res, err := o.client.Indices.GetMapping(options...)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	defer res.Body.Close()
+
+	return formatResponse(res)
+}
+
+func (o *OperationRunner) putMapping() (common.RuntimeResult, error) {
+	body, err := encodeBody(o.operation.Body)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// Perform the put mapping request.
+	res, err := o.client.Indices.PutMapping(
+		o.exportIndices(),
+		body,
+		o.client.Indices.PutMapping.WithContext(context.Background()),
+	)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	defer res.Body.Close()
+
+	return formatResponse(res)
+}
+
+// listIndexMappings returns the mappings of all indices except the hidden and system indices which name starts with dot.
+func listIndexMappings(client *es.Client) (map[string]interface{}, error) {
+	res, err := client.Indices.GetMapping(client.Indices.GetMapping.WithContext(context.Background()))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	result, err := decodeResponse(res)
+	if err != nil {
+		return nil, err
+	}
+
+	indices := make(map[string]interface{}, len(result))
+	for index, mappings := range result {
+		if strings.HasPrefix(index, ".") {
+			continue
+		}
+		mappingsInMap, _ := mappings.(map[string]interface{})
+		indices[index] = mappingsInMap["mappings"]
+	}
+	return indices, nil
+}
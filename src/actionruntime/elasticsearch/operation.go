@@ -18,10 +18,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
 
 	"github.com/illacloud/builder-backend/src/actionruntime/common"
 
 	es "github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
 )
 
 type OperationRunner struct {
@@ -30,6 +36,13 @@ type OperationRunner struct {
 }
 
 func (o *OperationRunner) search() (common.RuntimeResult, error) {
+	switch o.operation.Pagination {
+	case PAGINATION_SCROLL:
+		return o.scrollSearch()
+	case PAGINATION_PIT:
+		return o.pitSearch()
+	}
+
 	// Build the request body.
 	var buf bytes.Buffer
 	var searchQuery map[string]interface{}
@@ -48,18 +61,12 @@ func (o *OperationRunner) search() (common.RuntimeResult, error) {
 		o.client.Search.WithTrackTotalHits(true),
 		o.client.Search.WithPretty(),
 	)
-	defer res.Body.Close()
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
+	defer res.Body.Close()
 
-	// Format the response body.
-	var result map[string]interface{}
-	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
-		return common.RuntimeResult{Success: false}, err
-	}
-
-	return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{result}}, nil
+	return formatResponse(res)
 }
 
 func (o *OperationRunner) insert() (common.RuntimeResult, error) {
@@ -81,18 +88,12 @@ func (o *OperationRunner) insert() (common.RuntimeResult, error) {
 		o.client.Create.WithContext(context.Background()),
 		o.client.Create.WithPretty(),
 	)
-	defer res.Body.Close()
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
+	defer res.Body.Close()
 
-	// Format the response body.
-	var result map[string]interface{}
-	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
-		return common.RuntimeResult{Success: false}, err
-	}
-
-	return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{result}}, nil
+	return formatResponse(res)
 }
 
 func (o *OperationRunner) get() (common.RuntimeResult, error) {
@@ -104,18 +105,12 @@ func (o *OperationRunner) get() (common.RuntimeResult, error) {
 		o.client.Get.WithContext(context.Background()),
 		o.client.Get.WithPretty(),
 	)
-	defer res.Body.Close()
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
+	defer res.Body.Close()
 
-	// Format the response body.
-	var result map[string]interface{}
-	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
-		return common.RuntimeResult{Success: false}, err
-	}
-
-	return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{result}}, nil
+	return formatResponse(res)
 }
 
 func (o *OperationRunner) update() (common.RuntimeResult, error) {
@@ -137,18 +132,12 @@ func (o *OperationRunner) update() (common.RuntimeResult, error) {
 		o.client.Update.WithContext(context.Background()),
 		o.client.Update.WithPretty(),
 	)
-	defer res.Body.Close()
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
+	defer res.Body.Close()
 
-	// Format the response body.
-	var result map[string]interface{}
-	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
-		return common.RuntimeResult{Success: false}, err
-	}
-
-	return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{result}}, nil
+	return formatResponse(res)
 }
 
 func (o *OperationRunner) delete() (common.RuntimeResult, error) {
@@ -160,16 +149,268 @@ func (o *OperationRunner) delete() (common.RuntimeResult, error) {
 		o.client.Delete.WithContext(context.Background()),
 		o.client.Delete.WithPretty(),
 	)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	defer res.Body.Close()
+
+	return formatResponse(res)
+}
+
+func (o *OperationRunner) bulk() (common.RuntimeResult, error) {
+	var documents []map[string]interface{}
+	if err := json.Unmarshal([]byte(o.operation.Body), &documents); err != nil {
+		return common.RuntimeResult{Success: false}, errors.New("bulk body should be an array of documents: " + err.Error())
+	}
+	bulkAction := o.operation.BulkAction
+	if bulkAction == "" {
+		bulkAction = BULK_ACTION_INDEX
+	}
+
+	buf, err := buildBulkBody(documents, bulkAction, o.operation.IDField)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// Perform the bulk request.
+	res, err := o.client.Bulk(
+		buf,
+		o.client.Bulk.WithContext(context.Background()),
+		o.client.Bulk.WithIndex(o.operation.Index),
+		o.client.Bulk.WithRefresh(strconv.FormatBool(o.operation.Refresh)),
+	)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	defer res.Body.Close()
+
+	result, err := decodeResponse(res)
+	if err != nil {
+		return failedResponseResult(result), err
+	}
+
+	// every item of bulk response like {"index": {"_id": "...", "status": 201, "result": "created"}} becomes a row
+	items, _ := result["items"].([]interface{})
+	rows := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		itemInMap, _ := item.(map[string]interface{})
+		for action, itemResult := range itemInMap {
+			itemResultInMap, _ := itemResult.(map[string]interface{})
+			rows = append(rows, map[string]interface{}{
+				"action": action,
+				"id":     itemResultInMap["_id"],
+				"status": itemResultInMap["status"],
+				"result": itemResultInMap["result"],
+				"error":  itemResultInMap["error"],
+			})
+		}
+	}
+	return common.RuntimeResult{
+		Success: true,
+		Rows:    rows,
+		Extra:   map[string]interface{}{"took": result["took"], "errors": result["errors"]},
+	}, nil
+}
+
+// buildBulkBody builds the newline delimited request body, every document has an action line and a source line except delete.
+func buildBulkBody(documents []map[string]interface{}, bulkAction string, idField string) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for serial, document := range documents {
+		actionMeta := map[string]interface{}{}
+		if idField != "" {
+			documentID, hit := document[idField]
+			if !hit || documentID == nil {
+				return nil, fmt.Errorf("document %d missing id field %s", serial, idField)
+			}
+			actionMeta["_id"] = fmt.Sprint(documentID)
+		}
+		if err := encoder.Encode(map[string]interface{}{bulkAction: actionMeta}); err != nil {
+			return nil, err
+		}
+		var source interface{}
+		switch bulkAction {
+		case BULK_ACTION_DELETE:
+			continue
+		case BULK_ACTION_UPDATE:
+			source = map[string]interface{}{"doc": document}
+		default:
+			source = document
+		}
+		if err := encoder.Encode(source); err != nil {
+			return nil, err
+		}
+	}
+	return &buf, nil
+}
+
+func (o *OperationRunner) count() (common.RuntimeResult, error) {
+	options := []func(*esapi.CountRequest){o.client.Count.WithContext(context.Background())}
+	if o.operation.Index != "" {
+		options = append(options, o.client.Count.WithIndex(o.exportIndices()...))
+	}
+	if o.operation.Query != "" {
+		body, err := encodeBody(o.operation.Query)
+		if err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		options = append(options, o.client.Count.WithBody(body))
+	}
+
+	// Perform the count request.
+	res, err := o.client.Count(options...)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	defer res.Body.Close()
+
+	result, err := decodeResponse(res)
+	if err != nil {
+		return failedResponseResult(result), err
+	}
+	return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{{"count": result["count"]}}}, nil
+}
+
+func (o *OperationRunner) aggregate() (common.RuntimeResult, error) {
+	var searchQuery map[string]interface{}
+	if err := json.Unmarshal([]byte(o.operation.Query), &searchQuery); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	if searchQuery == nil {
+		return common.RuntimeResult{Success: false}, errors.New("aggregation query should be an object")
+	}
+	// only the aggregations are needed
+	searchQuery["size"] = 0
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(searchQuery); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// Perform the search request.
+	res, err := o.client.Search(
+		o.client.Search.WithContext(context.Background()),
+		o.client.Search.WithIndex(o.exportIndices()...),
+		o.client.Search.WithBody(&buf),
+	)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	defer res.Body.Close()
+
+	result, err := decodeResponse(res)
+	if err != nil {
+		return failedResponseResult(result), err
+	}
+	aggregations, _ := result["aggregations"].(map[string]interface{})
+	return common.RuntimeResult{
+		Success: true,
+		Rows:    flattenAggregations(aggregations, map[string]interface{}{}),
+		Extra:   map[string]interface{}{"took": result["took"], "hits": result["hits"]},
+	}, nil
+}
+
+func (o *OperationRunner) deleteByQuery() (common.RuntimeResult, error) {
+	body, err := encodeBody(o.operation.Query)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// Perform the delete by query request.
+	res, err := o.client.DeleteByQuery(
+		o.exportIndices(),
+		body,
+		o.client.DeleteByQuery.WithContext(context.Background()),
+		o.client.DeleteByQuery.WithRefresh(o.operation.Refresh),
+	)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
 	defer res.Body.Close()
+
+	return formatResponse(res)
+}
+
+func (o *OperationRunner) updateByQuery() (common.RuntimeResult, error) {
+	options := []func(*esapi.UpdateByQueryRequest){
+		o.client.UpdateByQuery.WithContext(context.Background()),
+		o.client.UpdateByQuery.WithRefresh(o.operation.Refresh),
+	}
+	if o.operation.Query != "" {
+		body, err := encodeBody(o.operation.Query)
+		if err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		options = append(options, o.client.UpdateByQuery.WithBody(body))
+	}
+
+	// Perform the update by query request.
+	res, err := o.client.UpdateByQuery(o.exportIndices(), options...)
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
+	defer res.Body.Close()
+
+	return formatResponse(res)
+}
+
+// exportIndices splits the comma separated index names
+func (o *OperationRunner) exportIndices() []string {
+	indices := make([]string, 0)
+	for _, index := range strings.Split(o.operation.Index, ",") {
+		if index = strings.TrimSpace(index); index != "" {
+			indices = append(indices, index)
+		}
+	}
+	return indices
+}
 
+func encodeBody(rawBody string) (io.Reader, error) {
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(rawBody), &body); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(body); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
+
+// decodeResponse decodes the response body, and returns the error described in body when request failed
+func decodeResponse(res *esapi.Response) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if res.IsError() {
+		return result, newResponseError(res, result)
+	}
+	return result, nil
+}
+
+// formatResponse responds the error response as a normal result like before, the structured error is attached in extra.
+func formatResponse(res *esapi.Response) (common.RuntimeResult, error) {
 	// Format the response body.
 	var result map[string]interface{}
 	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
 
-	return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{result}}, nil
+	runtimeResult := common.RuntimeResult{
+		Success: true,
+		Rows:    []map[string]interface{}{result},
+		Extra:   map[string]interface{}{"statusCode": res.StatusCode},
+	}
+	if res.IsError() {
+		runtimeResult.Extra["errorData"] = newResponseError(res, result)
+	}
+	return runtimeResult, nil
+}
+
+// failedResponseResult keeps the response body in rows, so the error details are still visible
+func failedResponseResult(result map[string]interface{}) common.RuntimeResult {
+	if result == nil {
+		return common.RuntimeResult{Success: false}
+	}
+	return common.RuntimeResult{Success: false, Rows: []map[string]interface{}{result}}
 }
@@ -0,0 +1,67 @@
+package elasticsearch
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatErrorResponse(t *testing.T) {
+	res := &esapi.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(strings.NewReader(`{"error":{"type":"index_not_found_exception","reason":"no such index [users]"},"status":404}`)),
+	}
+	runtimeResult, err := formatResponse(res)
+	assert.Nil(t, err)
+	assert.True(t, runtimeResult.Success)
+	assert.Equal(t, float64(404), runtimeResult.Rows[0]["status"])
+	assert.Equal(t, http.StatusNotFound, runtimeResult.Extra["statusCode"])
+	connectorError := runtimeResult.Extra["errorData"].(*common.ConnectorError)
+	assert.Equal(t, common.CONNECTOR_ERROR_CATEGORY_NOT_FOUND, connectorError.Category)
+	assert.Equal(t, "index_not_found_exception", connectorError.VendorCode)
+	assert.Equal(t, "no such index [users]", connectorError.Message)
+}
+
+func TestFormatResponse(t *testing.T) {
+	res := &esapi.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"found":true}`)),
+	}
+	runtimeResult, err := formatResponse(res)
+	assert.Nil(t, err)
+	assert.True(t, runtimeResult.Success)
+	assert.Equal(t, true, runtimeResult.Rows[0]["found"])
+	assert.NotContains(t, runtimeResult.Extra, "errorData")
+}
+
+func TestBuildBulkBody(t *testing.T) {
+	documents := []map[string]interface{}{{"id": 1, "name": "a"}, {"id": "b", "name": "b"}}
+
+	buf, err := buildBulkBody(documents, BULK_ACTION_INDEX, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "{\"index\":{}}\n{\"id\":1,\"name\":\"a\"}\n{\"index\":{}}\n{\"id\":\"b\",\"name\":\"b\"}\n", buf.String())
+
+	buf, err = buildBulkBody(documents, BULK_ACTION_UPDATE, "id")
+	assert.Nil(t, err)
+	assert.Equal(t, "{\"update\":{\"_id\":\"1\"}}\n{\"doc\":{\"id\":1,\"name\":\"a\"}}\n{\"update\":{\"_id\":\"b\"}}\n{\"doc\":{\"id\":\"b\",\"name\":\"b\"}}\n", buf.String())
+
+	// the delete action has no source line
+	buf, err = buildBulkBody(documents, BULK_ACTION_DELETE, "id")
+	assert.Nil(t, err)
+	assert.Equal(t, "{\"delete\":{\"_id\":\"1\"}}\n{\"delete\":{\"_id\":\"b\"}}\n", buf.String())
+
+	_, err = buildBulkBody([]map[string]interface{}{{"name": "a"}}, BULK_ACTION_UPDATE, "id")
+	assert.NotNil(t, err)
+}
+
+func TestAggregateRejectsNullQuery(t *testing.T) {
+	operationRunner := &OperationRunner{operation: Action{Operation: "aggregate", Query: "null"}}
+	result, err := operationRunner.aggregate()
+	assert.NotNil(t, err)
+	assert.False(t, result.Success)
+}
@@ -0,0 +1,225 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+)
+
+const (
+	FIELD_SCROLL_ID    = "scrollID"
+	FIELD_PIT_ID       = "pitID"
+	FIELD_SEARCH_AFTER = "searchAfter"
+	FIELD_HAS_MORE     = "hasMore"
+)
+
+// scrollSearch starts a scroll search, or fetches the next page when the scroll id of last page is given.
+func (o *OperationRunner) scrollSearch() (common.RuntimeResult, error) {
+	keepAlive := o.operation.exportKeepAlive()
+	var result common.RuntimeResult
+	var err error
+	if o.operation.ScrollID != "" {
+		res, errInScroll := o.client.Scroll(
+			o.client.Scroll.WithContext(context.Background()),
+			o.client.Scroll.WithScrollID(o.operation.ScrollID),
+			o.client.Scroll.WithScroll(keepAlive),
+		)
+		if errInScroll != nil {
+			return common.RuntimeResult{Success: false}, errInScroll
+		}
+		defer res.Body.Close()
+		result, err = formatResponse(res)
+	} else {
+		body, errInEncode := encodeBody(o.operation.Query)
+		if errInEncode != nil {
+			return common.RuntimeResult{Success: false}, errInEncode
+		}
+		res, errInSearch := o.client.Search(
+			o.client.Search.WithContext(context.Background()),
+			o.client.Search.WithIndex(o.exportIndices()...),
+			o.client.Search.WithBody(body),
+			o.client.Search.WithTrackTotalHits(true),
+			o.client.Search.WithScroll(keepAlive),
+		)
+		if errInSearch != nil {
+			return common.RuntimeResult{Success: false}, errInSearch
+		}
+		defer res.Body.Close()
+		result, err = formatResponse(res)
+	}
+	if err != nil {
+		return result, err
+	}
+	if errInPage := exportPageError(result); errInPage != nil {
+		return common.RuntimeResult{Success: false, Rows: result.Rows, Extra: result.Extra}, errInPage
+	}
+
+	// release the scroll context when all hits fetched
+	scrollID, _ := result.Rows[0]["_scroll_id"].(string)
+	hasMore := len(exportHits(result.Rows[0])) > 0
+	if !hasMore && scrollID != "" {
+		o.clearScroll(scrollID)
+		scrollID = ""
+	}
+	result.Extra[FIELD_SCROLL_ID] = scrollID
+	result.Extra[FIELD_HAS_MORE] = hasMore
+	return result, nil
+}
+
+func (o *OperationRunner) clearScroll(scrollID string) {
+	res, err := o.client.ClearScroll(
+		o.client.ClearScroll.WithContext(context.Background()),
+		o.client.ClearScroll.WithScrollID(scrollID),
+	)
+	if err == nil {
+		res.Body.Close()
+	}
+}
+
+// pitSearch pages the search by point in time and search after, the point in time is opened in the first page.
+func (o *OperationRunner) pitSearch() (common.RuntimeResult, error) {
+	keepAlive := formatKeepAlive(o.operation.exportKeepAlive())
+	searchQuery := make(map[string]interface{})
+	if o.operation.Query != "" {
+		if err := json.Unmarshal([]byte(o.operation.Query), &searchQuery); err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+	}
+
+	pitID := o.operation.PitID
+	if pitID == "" {
+		var err error
+		if pitID, err = o.openPointInTime(keepAlive); err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+	}
+	searchQuery["pit"] = map[string]interface{}{"id": pitID, "keep_alive": keepAlive}
+	// the search after needs a stable sort
+	if _, hit := searchQuery["sort"]; !hit {
+		searchQuery["sort"] = []interface{}{map[string]interface{}{"_shard_doc": "asc"}}
+	}
+	if o.operation.SearchAfter != "" {
+		var searchAfter []interface{}
+		if err := json.Unmarshal([]byte(o.operation.SearchAfter), &searchAfter); err != nil {
+			return common.RuntimeResult{Success: false}, fmt.Errorf("search after should be an array: %s", err.Error())
+		}
+		searchQuery["search_after"] = searchAfter
+	}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(searchQuery); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// Perform the search request, the index is carried by point in time.
+	res, err := o.client.Search(
+		o.client.Search.WithContext(context.Background()),
+		o.client.Search.WithBody(&buf),
+		o.client.Search.WithTrackTotalHits(true),
+	)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	defer res.Body.Close()
+	result, err := formatResponse(res)
+	if err != nil {
+		return result, err
+	}
+	// the point in time is kept when the page failed, so the page can be retried with it
+	if errInPage := exportPageError(result); errInPage != nil {
+		return common.RuntimeResult{Success: false, Rows: result.Rows, Extra: result.Extra}, errInPage
+	}
+
+	// the point in time id may change between pages
+	if newPitID, hit := result.Rows[0]["pit_id"].(string); hit && newPitID != "" {
+		pitID = newPitID
+	}
+	hits := exportHits(result.Rows[0])
+	hasMore := len(hits) > 0
+	searchAfter := ""
+	if hasMore {
+		lastHit, _ := hits[len(hits)-1].(map[string]interface{})
+		if sortValues, hit := lastHit["sort"]; hit {
+			sortValuesInJSON, _ := json.Marshal(sortValues)
+			searchAfter = string(sortValuesInJSON)
+		}
+	} else {
+		// release the point in time when all hits fetched
+		o.closePointInTime(pitID)
+		pitID = ""
+	}
+	result.Extra[FIELD_PIT_ID] = pitID
+	result.Extra[FIELD_SEARCH_AFTER] = searchAfter
+	result.Extra[FIELD_HAS_MORE] = hasMore
+	return result, nil
+}
+
+func (o *OperationRunner) openPointInTime(keepAlive string) (string, error) {
+	res, err := o.client.OpenPointInTime(
+		o.exportIndices(),
+		keepAlive,
+		o.client.OpenPointInTime.WithContext(context.Background()),
+	)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	result, err := decodeResponse(res)
+	if err != nil {
+		return "", err
+	}
+	pitID, _ := result["id"].(string)
+	return pitID, nil
+}
+
+func (o *OperationRunner) closePointInTime(pitID string) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(map[string]interface{}{"id": pitID}); err != nil {
+		return
+	}
+	res, err := o.client.ClosePointInTime(
+		o.client.ClosePointInTime.WithContext(context.Background()),
+		o.client.ClosePointInTime.WithBody(&buf),
+	)
+	if err == nil {
+		res.Body.Close()
+	}
+}
+
+// exportPageError returns the error of failed page attached by formatResponse, e.g. the scroll id or point in time expired.
+func exportPageError(result common.RuntimeResult) *common.ConnectorError {
+	connectorError, _ := result.Extra["errorData"].(*common.ConnectorError)
+	return connectorError
+}
+
+func exportHits(result map[string]interface{}) []interface{} {
+	hits, _ := result["hits"].(map[string]interface{})
+	hitList, _ := hits["hits"].([]interface{})
+	return hitList
+}
+
+// formatKeepAlive formats the duration in elasticsearch time units
+func formatKeepAlive(keepAlive time.Duration) string {
+	seconds := int64(keepAlive / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return fmt.Sprintf("%ds", seconds)
+}
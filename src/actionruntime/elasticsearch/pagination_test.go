@@ -0,0 +1,137 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	es "github.com/elastic/go-elasticsearch/v8"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testHitsPage     = `{"hits":{"total":{"value":1},"hits":[{"_id":"1","sort":[1, "a"]}]}}`
+	testEmptyPage    = `{"hits":{"total":{"value":1},"hits":[]}}`
+	testExpiredError = `{"error":{"type":"search_context_missing_exception","reason":"No search context found"},"status":404}`
+)
+
+// newPaginationTestServer fakes the scroll and point in time apis, the cursors named expired are missing.
+func newPaginationTestServer(t *testing.T) (*es.Client, *[]string) {
+	var mutex sync.Mutex
+	released := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Header().Set("Content-Type", "application/json")
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/_search/scroll/"):
+			released = append(released, "scroll:"+strings.TrimPrefix(r.URL.Path, "/_search/scroll/"))
+			w.Write([]byte(`{"succeeded":true}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/_pit":
+			released = append(released, "pit:"+body["id"].(string))
+			w.Write([]byte(`{"succeeded":true}`))
+		case r.URL.Path == "/users/_pit":
+			w.Write([]byte(`{"id":"p1"}`))
+		case r.URL.Path == "/_search/scroll":
+			switch r.URL.Query().Get("scroll_id") {
+			case "expired":
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(testExpiredError))
+			default:
+				w.Write([]byte(`{"_scroll_id":"s1",` + strings.TrimPrefix(testEmptyPage, "{")))
+			}
+		case r.URL.Path == "/users/_search":
+			w.Write([]byte(`{"_scroll_id":"s1",` + strings.TrimPrefix(testHitsPage, "{")))
+		case r.URL.Path == "/_search":
+			pit, _ := body["pit"].(map[string]interface{})
+			switch {
+			case pit["id"] == "expired":
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(testExpiredError))
+			case body["search_after"] != nil:
+				w.Write([]byte(`{"pit_id":"p2",` + strings.TrimPrefix(testEmptyPage, "{")))
+			default:
+				w.Write([]byte(`{"pit_id":"p2",` + strings.TrimPrefix(testHitsPage, "{")))
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	client, err := es.NewClient(es.Config{Addresses: []string{server.URL}})
+	assert.Nil(t, err)
+	return client, &released
+}
+
+func TestScrollSearchCursor(t *testing.T) {
+	client, released := newPaginationTestServer(t)
+	operation := Action{Operation: "search", Index: "users", Query: `{"query":{"match_all":{}}}`, Pagination: PAGINATION_SCROLL}
+
+	result, err := (&OperationRunner{client: client, operation: operation}).search()
+	assert.Nil(t, err)
+	assert.Equal(t, "s1", result.Extra[FIELD_SCROLL_ID])
+	assert.Equal(t, true, result.Extra[FIELD_HAS_MORE])
+	assert.Equal(t, http.StatusOK, result.Extra["statusCode"])
+
+	// the scroll context is cleared after the last page
+	operation.ScrollID = "s1"
+	result, err = (&OperationRunner{client: client, operation: operation}).search()
+	assert.Nil(t, err)
+	assert.Equal(t, "", result.Extra[FIELD_SCROLL_ID])
+	assert.Equal(t, false, result.Extra[FIELD_HAS_MORE])
+	assert.Equal(t, []string{"scroll:s1"}, *released)
+}
+
+func TestScrollSearchExpired(t *testing.T) {
+	client, released := newPaginationTestServer(t)
+	operation := Action{Operation: "search", Index: "users", Pagination: PAGINATION_SCROLL, ScrollID: "expired"}
+
+	result, err := (&OperationRunner{client: client, operation: operation}).search()
+	assert.False(t, result.Success)
+	connectorError, ok := err.(*common.ConnectorError)
+	assert.True(t, ok)
+	assert.Equal(t, common.CONNECTOR_ERROR_CATEGORY_NOT_FOUND, connectorError.Category)
+	assert.Equal(t, "search_context_missing_exception", connectorError.VendorCode)
+	assert.Equal(t, http.StatusNotFound, result.Extra["statusCode"])
+	assert.NotContains(t, result.Extra, FIELD_HAS_MORE)
+	assert.Empty(t, *released)
+}
+
+func TestPitSearchCursor(t *testing.T) {
+	client, released := newPaginationTestServer(t)
+	operation := Action{Operation: "search", Index: "users", Pagination: PAGINATION_PIT}
+
+	result, err := (&OperationRunner{client: client, operation: operation}).search()
+	assert.Nil(t, err)
+	assert.Equal(t, "p2", result.Extra[FIELD_PIT_ID])
+	assert.Equal(t, `[1,"a"]`, result.Extra[FIELD_SEARCH_AFTER])
+	assert.Equal(t, true, result.Extra[FIELD_HAS_MORE])
+	assert.Equal(t, http.StatusOK, result.Extra["statusCode"])
+
+	// the point in time is closed after the last page
+	operation.PitID = "p2"
+	operation.SearchAfter = `[1,"a"]`
+	result, err = (&OperationRunner{client: client, operation: operation}).search()
+	assert.Nil(t, err)
+	assert.Equal(t, "", result.Extra[FIELD_PIT_ID])
+	assert.Equal(t, false, result.Extra[FIELD_HAS_MORE])
+	assert.Equal(t, []string{"pit:p2"}, *released)
+}
+
+func TestPitSearchExpired(t *testing.T) {
+	client, released := newPaginationTestServer(t)
+	operation := Action{Operation: "search", Index: "users", Pagination: PAGINATION_PIT, PitID: "expired", SearchAfter: `[1]`}
+
+	result, err := (&OperationRunner{client: client, operation: operation}).search()
+	assert.False(t, result.Success)
+	assert.NotNil(t, err)
+	assert.NotContains(t, result.Extra, FIELD_PIT_ID)
+	assert.Empty(t, *released)
+}
@@ -55,6 +55,9 @@ func (e *Connector) ValidateActionTemplate(actionOptions map[string]interface{})
 	if err := validate.Struct(e.ActionOpts); err != nil {
 		return common.ValidateResult{Valid: false}, err
 	}
+	if err := e.ActionOpts.validateOperation(); err != nil {
+		return common.ValidateResult{Valid: false}, err
+	}
 	return common.ValidateResult{Valid: true}, nil
 }
 
@@ -80,9 +83,20 @@ func (e *Connector) TestConnection(resourceOptions map[string]interface{}) (comm
 }
 
 func (e *Connector) GetMetaInfo(resourceOptions map[string]interface{}) (common.MetaInfoResult, error) {
+	// get es connection
+	esClient, err := e.getConnectionWithOptions(resourceOptions)
+	if err != nil {
+		return common.MetaInfoResult{Success: false}, err
+	}
+
+	// list indices with mappings
+	indices, err := listIndexMappings(esClient)
+	if err != nil {
+		return common.MetaInfoResult{Success: false}, err
+	}
 	return common.MetaInfoResult{
 		Success: true,
-		Schema:  nil,
+		Schema:  map[string]interface{}{"indices": indices},
 	}, nil
 }
 
@@ -111,6 +125,24 @@ func (e *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 		result, err = operationRunner.update()
 	case DELETE_OPERATION:
 		result, err = operationRunner.delete()
+	case BULK_OPERATION:
+		result, err = operationRunner.bulk()
+	case COUNT_OPERATION:
+		result, err = operationRunner.count()
+	case AGGREGATE_OPERATION:
+		result, err = operationRunner.aggregate()
+	case DELETE_BY_QUERY_OPERATION:
+		result, err = operationRunner.deleteByQuery()
+	case UPDATE_BY_QUERY_OPERATION:
+		result, err = operationRunner.updateByQuery()
+	case CREATE_INDEX_OPERATION:
+		result, err = operationRunner.createIndex()
+	case DELETE_INDEX_OPERATION:
+		result, err = operationRunner.deleteIndex()
+	case GET_MAPPING_OPERATION:
+		result, err = operationRunner.getMapping()
+	case PUT_MAPPING_OPERATION:
+		result, err = operationRunner.putMapping()
 	default:
 		result.Success = false
 		err = errors.New("unsupported elasticsearch operation")
@@ -14,12 +14,41 @@
 
 package elasticsearch
 
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	SEARCH_OPERATION          = "search"
+	INSERT_OPERATION          = "insert"
+	GET_OPERATION             = "get"
+	UPDATE_OPERATION          = "update"
+	DELETE_OPERATION          = "delete"
+	BULK_OPERATION            = "bulk"
+	COUNT_OPERATION           = "count"
+	AGGREGATE_OPERATION       = "aggregate"
+	DELETE_BY_QUERY_OPERATION = "delete_by_query"
+	UPDATE_BY_QUERY_OPERATION = "update_by_query"
+	CREATE_INDEX_OPERATION    = "create_index"
+	DELETE_INDEX_OPERATION    = "delete_index"
+	GET_MAPPING_OPERATION     = "get_mapping"
+	PUT_MAPPING_OPERATION     = "put_mapping"
+)
+
 const (
-	SEARCH_OPERATION = "search"
-	INSERT_OPERATION = "insert"
-	GET_OPERATION    = "get"
-	UPDATE_OPERATION = "update"
-	DELETE_OPERATION = "delete"
+	BULK_ACTION_INDEX  = "index"
+	BULK_ACTION_CREATE = "create"
+	BULK_ACTION_UPDATE = "update"
+	BULK_ACTION_DELETE = "delete"
+)
+
+const (
+	PAGINATION_SCROLL = "scroll"
+	PAGINATION_PIT    = "pit"
+
+	DEFAULT_KEEP_ALIVE = time.Minute
 )
 
 type Resource struct {
@@ -30,9 +59,66 @@ type Resource struct {
 }
 
 type Action struct {
-	Operation string `validate:"required,oneof=search insert get update delete"`
+	Operation string `validate:"required,oneof=search insert get update delete bulk count aggregate delete_by_query update_by_query create_index delete_index get_mapping put_mapping"`
 	Index     string
 	ID        string
 	Body      string
 	Query     string
+	// BulkAction is the action applied to every document of bulk body, the IDField names the document field used as document id
+	BulkAction string `validate:"omitempty,oneof=index create update delete"`
+	IDField    string
+	Refresh    bool
+	// Pagination pages the large search by scroll or point in time, the cursor returned in extra is used in the next run
+	Pagination  string `validate:"omitempty,oneof=scroll pit"`
+	KeepAlive   string
+	ScrollID    string
+	PitID       string
+	SearchAfter string
+}
+
+// operations need the index, the search, count and aggregate runs on all indices when index is empty
+var indexRequiredOperations = map[string]bool{
+	INSERT_OPERATION:          true,
+	GET_OPERATION:             true,
+	UPDATE_OPERATION:          true,
+	DELETE_OPERATION:          true,
+	BULK_OPERATION:            true,
+	DELETE_BY_QUERY_OPERATION: true,
+	UPDATE_BY_QUERY_OPERATION: true,
+	CREATE_INDEX_OPERATION:    true,
+	DELETE_INDEX_OPERATION:    true,
+	PUT_MAPPING_OPERATION:     true,
+}
+
+func (action *Action) validateOperation() error {
+	if indexRequiredOperations[action.Operation] && action.Index == "" {
+		return fmt.Errorf("index is required in %s operation", action.Operation)
+	}
+	switch action.Operation {
+	case BULK_OPERATION:
+		if action.Body == "" {
+			return errors.New("body is required in bulk operation")
+		}
+		if (action.BulkAction == BULK_ACTION_UPDATE || action.BulkAction == BULK_ACTION_DELETE) && action.IDField == "" {
+			return fmt.Errorf("id field is required in bulk %s action", action.BulkAction)
+		}
+	case PUT_MAPPING_OPERATION:
+		if action.Body == "" {
+			return errors.New("body is required in put_mapping operation")
+		}
+	}
+	if action.KeepAlive != "" {
+		if _, err := time.ParseDuration(action.KeepAlive); err != nil {
+			return fmt.Errorf("invalid keep alive %s", action.KeepAlive)
+		}
+	}
+	return nil
+}
+
+func (action *Action) exportKeepAlive() time.Duration {
+	keepAlive, err := time.ParseDuration(action.KeepAlive)
+	if err != nil || keepAlive <= 0 {
+		return DEFAULT_KEEP_ALIVE
+	}
+	return keepAlive
 }
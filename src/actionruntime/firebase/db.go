@@ -21,6 +21,7 @@ import (
 	"github.com/illacloud/builder-backend/src/actionruntime/common"
 
 	firebase "firebase.google.com/go/v4"
+	"firebase.google.com/go/v4/db"
 	"github.com/go-playground/validator/v10"
 	"github.com/mitchellh/mapstructure"
 )
@@ -39,8 +40,14 @@ type DBOperationRunner struct {
 }
 
 type DBOptions struct {
-	Ref    string
-	Object map[string]interface{}
+	Ref          string
+	Object       map[string]interface{}
+	OrderByChild string
+	LimitToFirst int `validate:"gte=0"`
+	LimitToLast  int `validate:"gte=0"`
+	StartAt      interface{}
+	EndAt        interface{}
+	EqualTo      interface{}
 }
 
 func (d *DBOperationRunner) run() (common.RuntimeResult, error) {
@@ -81,6 +88,10 @@ func (d *DBOperationRunner) query() (common.RuntimeResult, error) {
 	}
 	ref := client.NewRef(queryOptions.Ref)
 
+	if queryOptions.OrderByChild != "" {
+		return queryOrderedByChild(ctx, ref, &queryOptions)
+	}
+
 	var res interface{}
 	if err := ref.Get(ctx, &res); err != nil {
 		return common.RuntimeResult{Success: false}, err
@@ -89,6 +100,41 @@ func (d *DBOperationRunner) query() (common.RuntimeResult, error) {
 	return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{{"result": res}}}, nil
 }
 
+// queryOrderedByChild returns the children in order as key and value pairs, since the order is lost in json object.
+func queryOrderedByChild(ctx context.Context, ref *db.Ref, queryOptions *DBOptions) (common.RuntimeResult, error) {
+	query := ref.OrderByChild(queryOptions.OrderByChild)
+	if queryOptions.LimitToFirst > 0 {
+		query = query.LimitToFirst(queryOptions.LimitToFirst)
+	}
+	if queryOptions.LimitToLast > 0 {
+		query = query.LimitToLast(queryOptions.LimitToLast)
+	}
+	if queryOptions.StartAt != nil {
+		query = query.StartAt(queryOptions.StartAt)
+	}
+	if queryOptions.EndAt != nil {
+		query = query.EndAt(queryOptions.EndAt)
+	}
+	if queryOptions.EqualTo != nil {
+		query = query.EqualTo(queryOptions.EqualTo)
+	}
+
+	nodes, err := query.GetOrdered(ctx)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	res := make([]map[string]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		var value interface{}
+		if err := node.Unmarshal(&value); err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		res = append(res, map[string]interface{}{"key": node.Key(), "value": value})
+	}
+
+	return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{{"result": res}}}, nil
+}
+
 func (d *DBOperationRunner) set() (common.RuntimeResult, error) {
 	var setOptions DBOptions
 	if err := mapstructure.Decode(d.options, &setOptions); err != nil {
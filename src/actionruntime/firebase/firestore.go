@@ -16,6 +16,7 @@ package firebase
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"strings"
 
@@ -28,13 +29,18 @@ import (
 )
 
 const (
-	FS_QUERY_FS_OP   = "query_fs"
-	FS_INSERT_DOC_OP = "insert_doc"
-	FS_UPDATE_DOC_OP = "update_doc"
-	FS_GET_DOC_OP    = "get_doc"
-	FS_DELETE_DOC_OP = "delete_doc"
-	FS_GET_COLLS_OP  = "get_colls"
-	FS_QUERY_COLL_OP = "query_coll"
+	FS_QUERY_FS_OP    = "query_fs"
+	FS_INSERT_DOC_OP  = "insert_doc"
+	FS_UPDATE_DOC_OP  = "update_doc"
+	FS_GET_DOC_OP     = "get_doc"
+	FS_DELETE_DOC_OP  = "delete_doc"
+	FS_GET_COLLS_OP   = "get_colls"
+	FS_QUERY_COLL_OP  = "query_coll"
+	FS_BATCH_OP       = "batch_write"
+	FS_TRANSACTION_OP = "transaction"
+
+	FIELD_NEXT_PAGE_TOKEN       = "nextPageToken"
+	FS_DOCUMENTS_PATH_SEPARATOR = "/documents/"
 )
 
 type FirestoreOperationRunner struct {
@@ -52,6 +58,7 @@ type FSQueryOptions struct {
 	OrderDirection string
 	StartAt        SimpleCursor `validate:"required"`
 	EndAt          SimpleCursor `validate:"required"`
+	PageToken      string
 }
 
 type QueryCondition struct {
@@ -98,6 +105,10 @@ func (f *FirestoreOperationRunner) run() (common.RuntimeResult, error) {
 		result, err = f.getCollections()
 	case FS_QUERY_COLL_OP:
 		result, err = f.queryCollectionGroup()
+	case FS_BATCH_OP:
+		result, err = f.batchWrite()
+	case FS_TRANSACTION_OP:
+		result, err = f.runTransaction()
 	default:
 		result.Success = false
 		err = errors.New("unsupported operation")
@@ -146,13 +157,20 @@ func (f *FirestoreOperationRunner) queryFirestore() (common.RuntimeResult, error
 	}
 
 	if queryFSOptions.StartAt.Trigger {
-		query = query.EndAt(queryFSOptions.StartAt.Value)
+		query = query.StartAt(queryFSOptions.StartAt.Value)
 	}
 
 	if queryFSOptions.EndAt.Trigger {
 		query = query.EndAt(queryFSOptions.EndAt.Value)
 	}
 
+	if queryFSOptions.PageToken != "" {
+		query, err = startAfterPageToken(ctx, client, query, queryFSOptions.PageToken)
+		if err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+	}
+
 	docs, err := query.Documents(ctx).GetAll()
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
@@ -162,7 +180,7 @@ func (f *FirestoreOperationRunner) queryFirestore() (common.RuntimeResult, error
 		res = append(res, doc.Data())
 	}
 
-	return common.RuntimeResult{Success: true, Rows: res}, err
+	return common.RuntimeResult{Success: true, Rows: res, Extra: exportPageExtra(docs, queryFSOptions.Limit)}, err
 }
 
 func (f *FirestoreOperationRunner) insertDoc() (common.RuntimeResult, error) {
@@ -361,6 +379,13 @@ func (f *FirestoreOperationRunner) queryCollectionGroup() (common.RuntimeResult,
 		query = query.EndAt(queryCGOptions.EndAt.Value)
 	}
 
+	if queryCGOptions.PageToken != "" {
+		query, err = startAfterPageToken(ctx, client, query, queryCGOptions.PageToken)
+		if err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+	}
+
 	docs, err := query.Documents(ctx).GetAll()
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
@@ -370,5 +395,39 @@ func (f *FirestoreOperationRunner) queryCollectionGroup() (common.RuntimeResult,
 		res = append(res, doc.Data())
 	}
 
-	return common.RuntimeResult{Success: true, Rows: res}, err
+	return common.RuntimeResult{Success: true, Rows: res, Extra: exportPageExtra(docs, queryCGOptions.Limit)}, err
+}
+
+// the page token is the encoded path of last document in page, the next page starts after its snapshot
+func encodePageToken(doc *firestore.DocumentSnapshot) string {
+	docPath := doc.Ref.Path
+	if index := strings.Index(docPath, FS_DOCUMENTS_PATH_SEPARATOR); index >= 0 {
+		docPath = docPath[index+len(FS_DOCUMENTS_PATH_SEPARATOR):]
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(docPath))
+}
+
+func startAfterPageToken(ctx context.Context, client *firestore.Client, query firestore.Query, pageToken string) (firestore.Query, error) {
+	docPath, err := base64.RawURLEncoding.DecodeString(pageToken)
+	if err != nil {
+		return query, errors.New("invalid page token")
+	}
+	docRef := client.Doc(string(docPath))
+	if docRef == nil {
+		return query, errors.New("invalid page token")
+	}
+	doc, err := docRef.Get(ctx)
+	if err != nil {
+		return query, err
+	}
+	return query.StartAfter(doc), nil
+}
+
+// exportPageExtra returns the next page token when the page is full, the page is the last one otherwise.
+func exportPageExtra(docs []*firestore.DocumentSnapshot, limit int) map[string]interface{} {
+	extra := map[string]interface{}{}
+	if limit > 0 && len(docs) == limit {
+		extra[FIELD_NEXT_PAGE_TOKEN] = encodePageToken(docs[len(docs)-1])
+	}
+	return extra
 }
@@ -0,0 +1,89 @@
+//go:build integration
+
+package firebase
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	firebase "firebase.google.com/go/v4"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/option"
+)
+
+// the tests run against the Firestore emulator, start it by:
+// docker run -p 8080:8080 gcr.io/google.com/cloudsdktool/google-cloud-cli:emulators gcloud emulators firestore start --host-port=0.0.0.0:8080
+// then run: FIRESTORE_EMULATOR_HOST=127.0.0.1:8080 go test -tags integration ./src/actionruntime/firebase/
+func newFirestoreEmulatorApp(t *testing.T) (*firebase.App, string) {
+	if os.Getenv("FIRESTORE_EMULATOR_HOST") == "" {
+		t.Skip("FIRESTORE_EMULATOR_HOST is not set")
+	}
+	app, err := firebase.NewApp(context.Background(), &firebase.Config{ProjectID: "illa-test"}, option.WithoutAuthentication())
+	assert.Nil(t, err)
+	return app, "illa_test_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+}
+
+func runFirestoreOperation(app *firebase.App, operation string, options map[string]interface{}) (map[string]interface{}, error) {
+	operationRunner := &FirestoreOperationRunner{client: app, operation: operation, options: options}
+	result, err := operationRunner.run()
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Rows) == 0 {
+		return nil, nil
+	}
+	return result.Rows[0], nil
+}
+
+func TestBatchWriteUpdateMissingDocumentFails(t *testing.T) {
+	app, collection := newFirestoreEmulatorApp(t)
+
+	_, err := runFirestoreOperation(app, FS_BATCH_OP, map[string]interface{}{
+		"operations": []map[string]interface{}{
+			{"type": FS_WRITE_OP_CREATE, "collection": collection, "id": "a", "value": map[string]interface{}{"name": "a"}},
+			{"type": FS_WRITE_OP_UPDATE, "collection": collection, "id": "missing", "value": map[string]interface{}{"name": "b"}},
+		},
+	})
+	assert.NotNil(t, err)
+
+	// the batch is atomic, so the created document is rolled back
+	row, err := runFirestoreOperation(app, FS_TRANSACTION_OP, map[string]interface{}{
+		"operations": []map[string]interface{}{{"type": FS_WRITE_OP_GET, "collection": collection, "id": "a"}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, false, row["exists"])
+}
+
+func TestTransactionUpdateKeepsOtherFields(t *testing.T) {
+	app, collection := newFirestoreEmulatorApp(t)
+
+	_, err := runFirestoreOperation(app, FS_BATCH_OP, map[string]interface{}{
+		"operations": []map[string]interface{}{
+			{"type": FS_WRITE_OP_SET, "collection": collection, "id": "a", "value": map[string]interface{}{"name": "a", "age": 1}},
+		},
+	})
+	assert.Nil(t, err)
+
+	_, err = runFirestoreOperation(app, FS_TRANSACTION_OP, map[string]interface{}{
+		"operations": []map[string]interface{}{
+			{"type": FS_WRITE_OP_UPDATE, "collection": collection, "id": "a", "value": map[string]interface{}{"a.b": 2}},
+		},
+	})
+	assert.Nil(t, err)
+
+	row, err := runFirestoreOperation(app, FS_TRANSACTION_OP, map[string]interface{}{
+		"operations": []map[string]interface{}{{"type": FS_WRITE_OP_GET, "collection": collection, "id": "a"}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "a", "age": int64(1), "a.b": int64(2)}, row["data"])
+
+	_, err = runFirestoreOperation(app, FS_TRANSACTION_OP, map[string]interface{}{
+		"operations": []map[string]interface{}{
+			{"type": FS_WRITE_OP_UPDATE, "collection": collection, "id": "missing", "value": map[string]interface{}{"name": "b"}},
+		},
+	})
+	assert.NotNil(t, err)
+}
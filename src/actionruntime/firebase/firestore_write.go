@@ -0,0 +1,209 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firebase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+
+	"cloud.google.com/go/firestore"
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/mapstructure"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	FS_WRITE_OP_CREATE = "create"
+	FS_WRITE_OP_SET    = "set"
+	FS_WRITE_OP_UPDATE = "update"
+	FS_WRITE_OP_DELETE = "delete"
+	FS_WRITE_OP_GET    = "get"
+
+	// the limit of writes in one commit of firestore
+	FS_MAX_BATCH_WRITES = 500
+)
+
+type FSBatchWriteOptions struct {
+	Operations []FSWriteOperation `validate:"required,min=1,max=500,dive"`
+}
+
+type FSTransactionOptions struct {
+	Operations []FSWriteOperation `validate:"required,min=1,max=500,dive"`
+}
+
+type FSWriteOperation struct {
+	Type       string `validate:"required,oneof=create set update delete get"`
+	Collection string `validate:"required"`
+	ID         string `validate:"required"`
+	Value      map[string]interface{}
+}
+
+func (o *FSWriteOperation) validateValue() error {
+	if o.Type != FS_WRITE_OP_DELETE && o.Type != FS_WRITE_OP_GET && o.Value == nil {
+		return fmt.Errorf("value is required for %s operation of document %s", o.Type, o.ID)
+	}
+	if o.Type == FS_WRITE_OP_UPDATE && len(o.Value) == 0 {
+		return fmt.Errorf("at least one field is required for update operation of document %s", o.ID)
+	}
+	return nil
+}
+
+// exportFirestoreUpdates converts the value to the updates of top-level fields, the keys are not split by dots.
+// Unlike set with merge, the update fails when the document does not exist.
+func exportFirestoreUpdates(value map[string]interface{}) []firestore.Update {
+	fields := make([]string, 0, len(value))
+	for field := range value {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	updates := make([]firestore.Update, 0, len(fields))
+	for _, field := range fields {
+		updates = append(updates, firestore.Update{FieldPath: firestore.FieldPath{field}, Value: value[field]})
+	}
+	return updates
+}
+
+// batchWrite commits the operations atomically, reading is not allowed in batch.
+func (f *FirestoreOperationRunner) batchWrite() (common.RuntimeResult, error) {
+	var batchWriteOptions FSBatchWriteOptions
+	if err := mapstructure.Decode(f.options, &batchWriteOptions); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	// validate Firebase Firestore `batch write` action options
+	validate := validator.New()
+	if err := validate.Struct(batchWriteOptions); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	for _, operation := range batchWriteOptions.Operations {
+		if operation.Type == FS_WRITE_OP_GET {
+			return common.RuntimeResult{Success: false}, errors.New("get operation is not supported in batch write, use transaction instead")
+		}
+		if err := operation.validateValue(); err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+	}
+
+	// build batch write action
+	ctx := context.TODO()
+	client, err := f.client.Firestore(ctx)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	defer client.Close()
+
+	batch := client.Batch()
+	for _, operation := range batchWriteOptions.Operations {
+		docRef := client.Collection(operation.Collection).Doc(operation.ID)
+		switch operation.Type {
+		case FS_WRITE_OP_CREATE:
+			batch.Create(docRef, operation.Value)
+		case FS_WRITE_OP_SET:
+			batch.Set(docRef, operation.Value)
+		case FS_WRITE_OP_UPDATE:
+			batch.Update(docRef, exportFirestoreUpdates(operation.Value))
+		case FS_WRITE_OP_DELETE:
+			batch.Delete(docRef)
+		}
+	}
+	writeResults, err := batch.Commit(ctx)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	res := make([]map[string]interface{}, 0, len(writeResults))
+	for i, writeResult := range writeResults {
+		res = append(res, map[string]interface{}{
+			"type":       batchWriteOptions.Operations[i].Type,
+			"collection": batchWriteOptions.Operations[i].Collection,
+			"id":         batchWriteOptions.Operations[i].ID,
+			"updateTime": writeResult.UpdateTime,
+		})
+	}
+	return common.RuntimeResult{Success: true, Rows: res}, nil
+}
+
+// runTransaction runs the operations in order in one read-write transaction, firestore requires all the get
+// operations come before the writes. The documents of get operations are returned.
+func (f *FirestoreOperationRunner) runTransaction() (common.RuntimeResult, error) {
+	var transactionOptions FSTransactionOptions
+	if err := mapstructure.Decode(f.options, &transactionOptions); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	// validate Firebase Firestore `transaction` action options
+	validate := validator.New()
+	if err := validate.Struct(transactionOptions); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	hasWrite := false
+	for _, operation := range transactionOptions.Operations {
+		if err := operation.validateValue(); err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		if operation.Type != FS_WRITE_OP_GET {
+			hasWrite = true
+		} else if hasWrite {
+			return common.RuntimeResult{Success: false}, errors.New("get operations must come before write operations in transaction")
+		}
+	}
+
+	// build transaction action
+	ctx := context.TODO()
+	client, err := f.client.Firestore(ctx)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	defer client.Close()
+
+	var res []map[string]interface{}
+	err = client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		// the function may be retried on contention, so the result is reset every time
+		res = make([]map[string]interface{}, 0)
+		for _, operation := range transactionOptions.Operations {
+			docRef := client.Collection(operation.Collection).Doc(operation.ID)
+			var errInOperation error
+			switch operation.Type {
+			case FS_WRITE_OP_GET:
+				// the missing document is returned with exists false
+				doc, errInGet := tx.Get(docRef)
+				if errInGet != nil && status.Code(errInGet) != codes.NotFound {
+					errInOperation = errInGet
+					break
+				}
+				res = append(res, map[string]interface{}{"id": operation.ID, "collection": operation.Collection, "exists": doc.Exists(), "data": doc.Data()})
+			case FS_WRITE_OP_CREATE:
+				errInOperation = tx.Create(docRef, operation.Value)
+			case FS_WRITE_OP_SET:
+				errInOperation = tx.Set(docRef, operation.Value)
+			case FS_WRITE_OP_UPDATE:
+				errInOperation = tx.Update(docRef, exportFirestoreUpdates(operation.Value))
+			case FS_WRITE_OP_DELETE:
+				errInOperation = tx.Delete(docRef)
+			}
+			if errInOperation != nil {
+				return errInOperation
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	return common.RuntimeResult{Success: true, Rows: res}, nil
+}
@@ -19,7 +19,9 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
+	"github.com/illacloud/builder-backend/src/utils/oauthgeneric"
 	"github.com/mitchellh/mapstructure"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
@@ -33,6 +35,7 @@ const (
 	OAUTH2_AUTH          = "oauth2"
 
 	READ_ACTION       = "read"
+	BATCH_READ_ACTION = "batchRead"
 	APPEND_ACTION     = "append"
 	UPDATE_ACTION     = "update"
 	BULKUPDATE_ACTION = "bulkUpdate"
@@ -85,8 +88,13 @@ func getSheetsWithKey(privateKey string) (*sheets.Service, error) {
 }
 
 func getSheetsWithOAuth2(opts OAuth2Opts) (*sheets.Service, error) {
+	// the token is encrypted when authorized by generic oauth2 flow
+	accessToken, err := oauthgeneric.DecryptToken(opts.AccessToken)
+	if err != nil {
+		return nil, err
+	}
 	ctx := context.Background()
-	httpClient := oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: opts.AccessToken}))
+	httpClient := oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: accessToken}))
 
 	srv, err := sheets.NewService(ctx, option.WithHTTPClient(httpClient))
 	if err != nil {
@@ -138,8 +146,13 @@ func getDriveWithKey(privateKey string) (*drive.Service, error) {
 }
 
 func getDriveWithOAuth2(opts OAuth2Opts) (*drive.Service, error) {
+	// the token is encrypted when authorized by generic oauth2 flow
+	accessToken, err := oauthgeneric.DecryptToken(opts.AccessToken)
+	if err != nil {
+		return nil, err
+	}
 	ctx := context.Background()
-	httpClient := oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: opts.AccessToken}))
+	httpClient := oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: accessToken}))
 
 	srv, err := drive.NewService(ctx, option.WithHTTPClient(httpClient))
 	if err != nil {
@@ -164,3 +177,112 @@ func interfaceToString(i interface{}) string {
 		return fmt.Sprintf("%v", v)
 	}
 }
+
+func exportValueRenderOption(valueRenderOption string) string {
+	switch valueRenderOption {
+	case "formatted":
+		return "FORMATTED_VALUE"
+	case "unformatted":
+		return "UNFORMATTED_VALUE"
+	case "formula":
+		return "FORMULA"
+	default:
+		return ""
+	}
+}
+
+// the date time render option is ignored by sheets api when the values are formatted
+func exportDateTimeRenderOption(dateTimeRenderOption string) string {
+	switch dateTimeRenderOption {
+	case "serialNumber":
+		return "SERIAL_NUMBER"
+	case "formattedString":
+		return "FORMATTED_STRING"
+	default:
+		return ""
+	}
+}
+
+func exportHeaderRow(headerRow int) int {
+	if headerRow <= 0 {
+		return 1
+	}
+	return headerRow
+}
+
+// exportHeaderColumns returns the columns in the 1-based header row of values, it is empty when the row does not exist.
+func exportHeaderColumns(values [][]interface{}, headerRow int) []string {
+	headerRow = exportHeaderRow(headerRow)
+	if len(values) < headerRow {
+		return []string{}
+	}
+	columns := make([]string, 0, len(values[headerRow-1]))
+	for _, column := range values[headerRow-1] {
+		columns = append(columns, interfaceToString(column))
+	}
+	return columns
+}
+
+// mapRowsByHeader maps the rows below the header row to objects keyed by header columns,
+// the header row is 1-based in values and defaults to the first row, the rows above it are skipped.
+func mapRowsByHeader(values [][]interface{}, headerRow int) []map[string]interface{} {
+	headerRow = exportHeaderRow(headerRow)
+	if len(values) < headerRow {
+		return []map[string]interface{}{}
+	}
+
+	headers := values[headerRow-1]
+	data := make([]map[string]interface{}, len(values)-headerRow)
+	for i, row := range values[headerRow:] {
+		data[i] = make(map[string]interface{}, len(headers))
+		for j, cell := range row {
+			if j >= len(headers) {
+				break
+			}
+			header := interfaceToString(headers[j])
+			data[i][header] = cell
+		}
+	}
+	return data
+}
+
+// listSheetHeaders returns the sheets with the columns in first row, and the named ranges of spreadsheet.
+func listSheetHeaders(service *sheets.Service, spreadsheetID string) ([]map[string]interface{}, []map[string]interface{}, error) {
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetID).IncludeGridData(false).Do()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sheetsInfo := make([]map[string]interface{}, 0, len(spreadsheet.Sheets))
+	headerRanges := make([]string, 0, len(spreadsheet.Sheets))
+	for _, sheet := range spreadsheet.Sheets {
+		headerRanges = append(headerRanges, fmt.Sprintf("'%s'!1:1", strings.ReplaceAll(sheet.Properties.Title, "'", "''")))
+		sheetsInfo = append(sheetsInfo, map[string]interface{}{
+			"sheetId": sheet.Properties.SheetId,
+			"title":   sheet.Properties.Title,
+			"headers": []string{},
+		})
+	}
+	if len(headerRanges) > 0 {
+		headersResp, err := service.Spreadsheets.Values.BatchGet(spreadsheetID).Ranges(headerRanges...).Do()
+		if err != nil {
+			return nil, nil, err
+		}
+		for i, valueRange := range headersResp.ValueRanges {
+			if i >= len(sheetsInfo) || len(valueRange.Values) == 0 {
+				continue
+			}
+			headers := make([]string, 0, len(valueRange.Values[0]))
+			for _, header := range valueRange.Values[0] {
+				headers = append(headers, interfaceToString(header))
+			}
+			sheetsInfo[i]["headers"] = headers
+		}
+	}
+
+	namedRanges := make([]map[string]interface{}, 0, len(spreadsheet.NamedRanges))
+	for _, namedRange := range spreadsheet.NamedRanges {
+		namedRanges = append(namedRanges, map[string]interface{}{"id": namedRange.NamedRangeId, "name": namedRange.Name})
+	}
+	return sheetsInfo, namedRanges, nil
+}
@@ -86,10 +86,26 @@ func (g *Connector) GetMetaInfo(resourceOptions map[string]interface{}) (common.
 	for i, v := range files.Files {
 		res[i] = map[string]interface{}{"id": v.Id, "name": v.Name}
 	}
+	schema := map[string]interface{}{"spreadsheets": res}
+
+	// get sheets and header columns of the chosen spreadsheet
+	if g.resourceOptions.Spreadsheet != "" {
+		sheetsService, err := g.getSheetsWithOpts(resourceOptions)
+		if err != nil {
+			return common.MetaInfoResult{Success: false}, err
+		}
+		sheetsInfo, namedRanges, err := listSheetHeaders(sheetsService, g.resourceOptions.Spreadsheet)
+		if err != nil {
+			return common.MetaInfoResult{Success: false}, err
+		}
+		schema["spreadsheet"] = g.resourceOptions.Spreadsheet
+		schema["sheets"] = sheetsInfo
+		schema["namedRanges"] = namedRanges
+	}
 
 	return common.MetaInfoResult{
 		Success: true,
-		Schema:  map[string]interface{}{"spreadsheets": res},
+		Schema:  schema,
 	}, nil
 }
 
@@ -125,6 +141,12 @@ func (g *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 			res.Success = false
 			return res, err
 		}
+	case BATCH_READ_ACTION:
+		res, err = actionRunner.BatchRead()
+		if err != nil {
+			res.Success = false
+			return res, err
+		}
 	case APPEND_ACTION:
 		res, err = actionRunner.Append()
 		if err != nil {
@@ -206,9 +228,12 @@ func (r *ActionRunner) Read() (common.RuntimeResult, error) {
 	}
 
 	readRange := readOpts.A1Notation
+	if readOpts.RangeType == "namedRange" {
+		readRange = readOpts.NamedRange
+	}
 	if readOpts.RangeType == "limit" {
-		sheetName := ""
-		if readOpts.SheetName == "" {
+		sheetName := readOpts.SheetName
+		if sheetName == "" {
 			sheetName = "Sheet1"
 		}
 
@@ -246,7 +271,14 @@ func (r *ActionRunner) Read() (common.RuntimeResult, error) {
 
 	}
 
-	valuesResp, err := r.service.Spreadsheets.Values.Get(readOpts.Spreadsheet, readRange).Do()
+	valuesCall := r.service.Spreadsheets.Values.Get(readOpts.Spreadsheet, readRange)
+	if valueRenderOption := exportValueRenderOption(readOpts.ValueRenderOption); valueRenderOption != "" {
+		valuesCall.ValueRenderOption(valueRenderOption)
+	}
+	if dateTimeRenderOption := exportDateTimeRenderOption(readOpts.DateTimeRenderOption); dateTimeRenderOption != "" {
+		valuesCall.DateTimeRenderOption(dateTimeRenderOption)
+	}
+	valuesResp, err := valuesCall.Do()
 	if err != nil {
 		return common.RuntimeResult{Success: false, Rows: []map[string]interface{}{0: {"message": err.Error()}}}, nil
 	}
@@ -255,25 +287,44 @@ func (r *ActionRunner) Read() (common.RuntimeResult, error) {
 		return common.RuntimeResult{Success: true}, nil
 	}
 
-	// Create a slice of maps to store the data
-	data := make([]map[string]interface{}, len(valuesResp.Values)-1)
+	return common.RuntimeResult{Success: true, Rows: mapRowsByHeader(valuesResp.Values, readOpts.HeaderRow)}, nil
+}
 
-	// Extract column headers
-	headers := valuesResp.Values[0]
+func (r *ActionRunner) BatchRead() (common.RuntimeResult, error) {
+	// format batchRead action options
+	var batchReadOpts BatchReadOpts
+	if err := mapstructure.Decode(r.opts, &batchReadOpts); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	// validate batchRead action options
+	validate := validator.New()
+	if err := validate.Struct(batchReadOpts); err != nil {
+		return common.RuntimeResult{Success: false, Rows: []map[string]interface{}{0: {"message": err.Error()}}}, nil
+	}
 
-	// Iterate through the rows, skipping the header row
-	for i, row := range valuesResp.Values[1:] {
-		data[i] = make(map[string]interface{}, len(headers))
-		for j, cell := range row {
-			if j >= len(headers) {
-				break
-			}
-			header := interfaceToString(headers[j])
-			data[i][header] = cell
-		}
+	batchGetCall := r.service.Spreadsheets.Values.BatchGet(batchReadOpts.Spreadsheet).Ranges(batchReadOpts.Ranges...)
+	if valueRenderOption := exportValueRenderOption(batchReadOpts.ValueRenderOption); valueRenderOption != "" {
+		batchGetCall.ValueRenderOption(valueRenderOption)
+	}
+	if dateTimeRenderOption := exportDateTimeRenderOption(batchReadOpts.DateTimeRenderOption); dateTimeRenderOption != "" {
+		batchGetCall.DateTimeRenderOption(dateTimeRenderOption)
+	}
+	batchGetResp, err := batchGetCall.Do()
+	if err != nil {
+		return common.RuntimeResult{Success: false, Rows: []map[string]interface{}{0: {"message": err.Error()}}}, nil
 	}
 
-	return common.RuntimeResult{Success: true, Rows: data}, nil
+	// the value ranges are in the same order as the requested ranges
+	res := make([]map[string]interface{}, 0, len(batchGetResp.ValueRanges))
+	for i, valueRange := range batchGetResp.ValueRanges {
+		res = append(res, map[string]interface{}{
+			"range":         batchReadOpts.Ranges[i],
+			"resolvedRange": valueRange.Range,
+			"values":        mapRowsByHeader(valueRange.Values, batchReadOpts.HeaderRow),
+		})
+	}
+
+	return common.RuntimeResult{Success: true, Rows: res}, nil
 }
 
 func (r *ActionRunner) Append() (common.RuntimeResult, error) {
@@ -292,6 +343,10 @@ func (r *ActionRunner) Append() (common.RuntimeResult, error) {
 	if appendOpts.SheetName != "" {
 		sheet = appendOpts.SheetName
 	}
+	// the named range is used as the table to append
+	if appendOpts.NamedRange != "" {
+		sheet = appendOpts.NamedRange
+	}
 	// get the last non-empty row in the sheet
 	resp, err := r.service.Spreadsheets.Values.Get(appendOpts.Spreadsheet, sheet).Do()
 	if err != nil {
@@ -299,42 +354,10 @@ func (r *ActionRunner) Append() (common.RuntimeResult, error) {
 	}
 
 	// calculate the range to append based on the existing data
-	rangeToAppend := fmt.Sprintf("%s!A%d", sheet, len(resp.Values)+1)
-	valuesToAppend := make([][]interface{}, len(appendOpts.Values)+1)
-	if len(resp.Values) == 0 {
-		keys := make([]string, 0, 0)
-		if len(appendOpts.Values) != 0 {
-			for k := range appendOpts.Values[0] {
-				keys = append(keys, k)
-			}
-			sort.Strings(keys)
-			rowValues := make([]interface{}, 0)
-			for _, k := range keys {
-				rowValues = append(rowValues, k)
-			}
-			valuesToAppend[0] = rowValues
-		}
-		// convert the input data format to the required format for appending
-		for i, row := range appendOpts.Values {
-			rowValues := make([]interface{}, 0)
-			for _, k := range keys {
-				rowValues = append(rowValues, row[k])
-			}
-			valuesToAppend[i+1] = rowValues
-		}
-	} else {
-		keys := make([]string, 0, len(resp.Values[0]))
-		for _, k := range resp.Values[0] {
-			keys = append(keys, interfaceToString(k))
-		}
-		valuesToAppend = make([][]interface{}, len(appendOpts.Values))
-		for i, row := range appendOpts.Values {
-			rowValues := make([]interface{}, 0)
-			for _, k := range keys {
-				rowValues = append(rowValues, row[k])
-			}
-			valuesToAppend[i] = rowValues
-		}
+	valuesToAppend, rowToAppend := buildValuesToAppend(resp.Values, appendOpts.HeaderRow, appendOpts.Values)
+	rangeToAppend := fmt.Sprintf("%s!A%d", sheet, rowToAppend)
+	if appendOpts.NamedRange != "" {
+		rangeToAppend = appendOpts.NamedRange
 	}
 
 	rb := &sheets.ValueRange{
@@ -362,6 +385,40 @@ func (r *ActionRunner) Append() (common.RuntimeResult, error) {
 	return common.RuntimeResult{Success: true, Rows: res}, nil
 }
 
+// buildValuesToAppend converts the values to rows in the order of columns in header row. When the sheet has no header row,
+// it is written with the sorted keys of the first value at the header row before the values. The 1-based row to append is returned.
+func buildValuesToAppend(existingValues [][]interface{}, headerRow int, values []map[string]interface{}) ([][]interface{}, int) {
+	headerRow = exportHeaderRow(headerRow)
+	valuesToAppend := make([][]interface{}, 0, len(values)+1)
+	keys := exportHeaderColumns(existingValues, headerRow)
+	rowToAppend := len(existingValues) + 1
+	if len(existingValues) < headerRow {
+		keys = make([]string, 0)
+		if len(values) != 0 {
+			for k := range values[0] {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+		}
+		rowValues := make([]interface{}, 0, len(keys))
+		for _, k := range keys {
+			rowValues = append(rowValues, k)
+		}
+		valuesToAppend = append(valuesToAppend, rowValues)
+		rowToAppend = headerRow
+	}
+
+	// convert the input data format to the required format for appending
+	for _, row := range values {
+		rowValues := make([]interface{}, 0, len(keys))
+		for _, k := range keys {
+			rowValues = append(rowValues, row[k])
+		}
+		valuesToAppend = append(valuesToAppend, rowValues)
+	}
+	return valuesToAppend, rowToAppend
+}
+
 func (r *ActionRunner) Update() (common.RuntimeResult, error) {
 	// format update action options
 	var updateOpts UpdateOpts
@@ -379,18 +436,13 @@ func (r *ActionRunner) Update() (common.RuntimeResult, error) {
 	}
 
 	// get the header row in the sheet
-	readRange := fmt.Sprintf("%s!A1:Z1", updateOpts.SheetName)
+	headerRow := exportHeaderRow(updateOpts.HeaderRow)
+	readRange := fmt.Sprintf("%s!A%d:Z%d", updateOpts.SheetName, headerRow, headerRow)
 	resp, err := r.service.Spreadsheets.Values.Get(updateOpts.Spreadsheet, readRange).Do()
 	if err != nil {
 		return common.RuntimeResult{Success: false, Rows: []map[string]interface{}{0: {"message": err.Error()}}}, nil
 	}
-	keys := make([]string, 0, 0)
-	if len(resp.Values) != 0 {
-		keys = make([]string, 0, len(resp.Values[0]))
-		for _, k := range resp.Values[0] {
-			keys = append(keys, interfaceToString(k))
-		}
-	}
+	keys := exportHeaderColumns(resp.Values, 1)
 
 	// convert the input data format to the required format for updating.
 	valuesToUpdate := make([][]interface{}, len(updateOpts.Values))
@@ -404,13 +456,20 @@ func (r *ActionRunner) Update() (common.RuntimeResult, error) {
 
 	res := make([]map[string]interface{}, 1, 1)
 
-	if updateOpts.FilterType == "a1" {
+	if updateOpts.FilterType == "a1" || updateOpts.FilterType == "namedRange" {
 		rb := &sheets.ValueRange{
 			MajorDimension: "ROWS",
 			Values:         valuesToUpdate,
 		}
 
-		resp, err := r.service.Spreadsheets.Values.Update(updateOpts.Spreadsheet, updateOpts.A1Notation, rb).ValueInputOption("RAW").Do()
+		rangeToUpdate := updateOpts.A1Notation
+		if updateOpts.FilterType == "namedRange" {
+			rangeToUpdate = updateOpts.NamedRange
+		}
+		resp, err := r.service.Spreadsheets.Values.Update(updateOpts.Spreadsheet, rangeToUpdate, rb).ValueInputOption("RAW").Do()
+		if err != nil {
+			return common.RuntimeResult{Success: false, Rows: []map[string]interface{}{0: {"message": err.Error()}}}, nil
+		}
 		res[0] = map[string]interface{}{
 			"spreadsheetId": resp.SpreadsheetId,
 			"updates": map[string]interface{}{
@@ -421,11 +480,8 @@ func (r *ActionRunner) Update() (common.RuntimeResult, error) {
 				"updatedCells":   resp.UpdatedCells,
 			},
 		}
-		if err != nil {
-			return common.RuntimeResult{Success: false, Rows: []map[string]interface{}{0: {"message": err.Error()}}}, nil
-		}
 	} else if updateOpts.FilterType == "filter" {
-		return updateSpreadsheetByFilters(r.service, updateOpts.Spreadsheet, updateOpts.SheetName, updateOpts.Filters, updateOpts.Values)
+		return updateSpreadsheetByFilters(r.service, updateOpts.Spreadsheet, updateOpts.SheetName, updateOpts.Filters, updateOpts.Values, headerRow)
 	}
 
 	return common.RuntimeResult{Success: true, Rows: res}, nil
@@ -453,33 +509,29 @@ func (r *ActionRunner) BulkUpdate() (common.RuntimeResult, error) {
 	if err != nil {
 		return common.RuntimeResult{Success: false, Rows: []map[string]interface{}{0: {"message": err.Error()}}}, nil
 	}
-	keys := make(map[string]int)
-	if len(resp.Values) != 0 {
-		for i, k := range resp.Values[0] {
-			keys[interfaceToString(k)] = i
-		}
-	}
-
-	if len(resp.Values) == 0 {
+	headerRow := exportHeaderRow(bulkUpdateOpts.HeaderRow)
+	if len(resp.Values) < headerRow {
 		return common.RuntimeResult{Success: false, Rows: []map[string]interface{}{0: {"message": "no data found"}}}, nil
 	}
+	keys := make(map[string]int)
+	for i, k := range exportHeaderColumns(resp.Values, headerRow) {
+		keys[k] = i
+	}
 
 	// create a map to store row numbers for each primary key
 	rowNumbers := make(map[string]int)
 
-	// iterate through rows to find the primary key column index and row numbers
-	primaryKeyIndex := -1
-	for rowIndex, row := range resp.Values {
-		for colIndex, cell := range row {
-			if rowIndex == 0 && cell == bulkUpdateOpts.PrimaryKey {
-				primaryKeyIndex = colIndex
-			} else if rowIndex > 0 && colIndex == primaryKeyIndex {
-				rowNumbers[interfaceToString(cell)] = rowIndex + 1
+	// find the primary key column in header row and the row numbers below it
+	primaryKeyIndex, ok := keys[bulkUpdateOpts.PrimaryKey]
+	if ok {
+		for rowIndex := headerRow; rowIndex < len(resp.Values); rowIndex++ {
+			if primaryKeyIndex < len(resp.Values[rowIndex]) {
+				rowNumbers[interfaceToString(resp.Values[rowIndex][primaryKeyIndex])] = rowIndex + 1
 			}
 		}
 	}
 
-	if primaryKeyIndex == -1 {
+	if !ok {
 		return common.RuntimeResult{Success: false, Rows: []map[string]interface{}{0: {"message": "primary key column not found"}}}, nil
 	}
 
@@ -744,7 +796,8 @@ func (r *ActionRunner) GetSpreadsheetInfo() (common.RuntimeResult, error) {
 	return common.RuntimeResult{Success: true, Rows: res}, nil
 }
 
-func updateSpreadsheetByFilters(srv *sheets.Service, spreadsheetID, sheetName string, filters []Filter, values []map[string]interface{}) (common.RuntimeResult, error) {
+// updateSpreadsheetByFilters updates the rows below the header row which match the filters, the header row is 1-based.
+func updateSpreadsheetByFilters(srv *sheets.Service, spreadsheetID, sheetName string, filters []Filter, values []map[string]interface{}, headerRow int) (common.RuntimeResult, error) {
 	// get the sheet data
 	readRange := fmt.Sprintf("%s!A1:Z", sheetName)
 	response, err := srv.Spreadsheets.Values.Get(spreadsheetID, readRange).Do()
@@ -752,14 +805,18 @@ func updateSpreadsheetByFilters(srv *sheets.Service, spreadsheetID, sheetName st
 		return common.RuntimeResult{Success: false, Rows: []map[string]interface{}{0: {"message": err.Error()}}}, nil
 	}
 
+	if len(response.Values) < headerRow {
+		return common.RuntimeResult{Success: false, Rows: []map[string]interface{}{0: {"message": "no data found"}}}, nil
+	}
+
 	// Find the matching rows
-	matchingRows := findMatchingRows(response.Values, filters)
+	matchingRows := findMatchingRows(response.Values, filters, headerRow)
 
 	// Update the matching rows with the new values
 	var updateRows []*sheets.ValueRange
 	for i, rowIndex := range matchingRows {
 		row := response.Values[rowIndex]
-		updateValues(row, values[i], response.Values[0])
+		updateValues(row, values[i], response.Values[headerRow-1])
 		updateRange := fmt.Sprintf("%s!A%d:Z%d", sheetName, rowIndex+1, rowIndex+1)
 		updateRow := &sheets.ValueRange{
 			Range:  updateRange,
@@ -793,15 +850,16 @@ func updateSpreadsheetByFilters(srv *sheets.Service, spreadsheetID, sheetName st
 	return common.RuntimeResult{Success: true, Rows: res}, nil
 }
 
-// findMatchingRows is a helper function that returns the indices of rows matching the filters.
-func findMatchingRows(sheetData [][]interface{}, filters []Filter) []int {
+// findMatchingRows is a helper function that returns the indices of rows below the header row matching the filters.
+func findMatchingRows(sheetData [][]interface{}, filters []Filter, headerRow int) []int {
 	var matchingRows []int
 
-	for rowIndex, row := range sheetData {
+	for rowIndex := headerRow; rowIndex < len(sheetData); rowIndex++ {
+		row := sheetData[rowIndex]
 		matches := true
 		for _, filter := range filters {
-			columnIndex := getColumnIndex(sheetData[0], filter.Key)
-			if columnIndex == -1 || interfaceToString(row[columnIndex]) != filter.Value {
+			columnIndex := getColumnIndex(sheetData[headerRow-1], filter.Key)
+			if columnIndex == -1 || columnIndex >= len(row) || interfaceToString(row[columnIndex]) != filter.Value {
 				matches = false
 				break
 			}
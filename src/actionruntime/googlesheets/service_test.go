@@ -0,0 +1,52 @@
+package googlesheets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildValuesToAppendWithHeaderRow(t *testing.T) {
+	existingValues := [][]interface{}{
+		{"Report"},
+		{},
+		{"name", "age"},
+		{"a", "1"},
+	}
+	values := []map[string]interface{}{{"age": "2", "name": "b"}}
+
+	valuesToAppend, rowToAppend := buildValuesToAppend(existingValues, 3, values)
+	assert.Equal(t, [][]interface{}{{"b", "2"}}, valuesToAppend)
+	assert.Equal(t, 5, rowToAppend)
+
+	// the first row is taken as header by default
+	valuesToAppend, _ = buildValuesToAppend(existingValues, 0, values)
+	assert.Equal(t, [][]interface{}{{nil}}, valuesToAppend)
+}
+
+func TestBuildValuesToAppendWritesMissingHeaderRow(t *testing.T) {
+	values := []map[string]interface{}{{"name": "a", "age": "1"}}
+
+	valuesToAppend, rowToAppend := buildValuesToAppend([][]interface{}{{"Report"}}, 2, values)
+	assert.Equal(t, [][]interface{}{{"age", "name"}, {"1", "a"}}, valuesToAppend)
+	assert.Equal(t, 2, rowToAppend)
+
+	valuesToAppend, rowToAppend = buildValuesToAppend(nil, 0, values)
+	assert.Equal(t, [][]interface{}{{"age", "name"}, {"1", "a"}}, valuesToAppend)
+	assert.Equal(t, 1, rowToAppend)
+}
+
+func TestFindMatchingRowsBelowHeaderRow(t *testing.T) {
+	sheetData := [][]interface{}{
+		{"name"},
+		{"name", "age"},
+		{"a", "1"},
+		{"b"},
+		{"c", "1"},
+	}
+	filters := []Filter{{Key: "age", Value: "1"}}
+
+	assert.Equal(t, []int{2, 4}, findMatchingRows(sheetData, filters, 2))
+	// the rows above and at the header row are not matched
+	assert.Empty(t, findMatchingRows(sheetData, []Filter{{Key: "name", Value: "name"}}, 2))
+}
@@ -14,9 +14,11 @@
 
 package googlesheets
 
+// the sheets and header columns of spreadsheet are listed in meta info when it is chosen
 type Resource struct {
 	Authentication string                 `validate:"required,oneof=serviceAccount oauth2"`
 	Opts           map[string]interface{} `validate:"required"`
+	Spreadsheet    string
 }
 
 type SAOpts struct {
@@ -32,32 +34,50 @@ type OAuth2Opts struct {
 }
 
 type Action struct {
-	Method string                 `validate:"required,oneof=read append update bulkUpdate delete create copy list get"`
+	Method string                 `validate:"required,oneof=read batchRead append update bulkUpdate delete create copy list get"`
 	Opts   map[string]interface{} `validate:"required_unless=Method list"`
 }
 
 type ReadOpts struct {
-	Spreadsheet string `validate:"required"`
-	SheetName   string
-	Limit       int
-	Offset      int
-	RangeType   string `validate:"required,oneof=a1 limit"`
-	A1Notation  string
+	Spreadsheet          string `validate:"required"`
+	SheetName            string
+	Limit                int
+	Offset               int
+	RangeType            string `validate:"required,oneof=a1 limit namedRange"`
+	A1Notation           string
+	NamedRange           string `validate:"required_if=RangeType namedRange"`
+	ValueRenderOption    string `validate:"omitempty,oneof=formatted unformatted formula"`
+	DateTimeRenderOption string `validate:"omitempty,oneof=serialNumber formattedString"`
+	HeaderRow            int    `validate:"gte=0"`
+}
+
+// the ranges can be in A1 notation or named range
+type BatchReadOpts struct {
+	Spreadsheet          string   `validate:"required"`
+	Ranges               []string `validate:"required,gt=0,dive,required"`
+	ValueRenderOption    string   `validate:"omitempty,oneof=formatted unformatted formula"`
+	DateTimeRenderOption string   `validate:"omitempty,oneof=serialNumber formattedString"`
+	HeaderRow            int      `validate:"gte=0"`
 }
 
+// the header row is 1-based and defaults to the first row, the same as reading
 type AppendOpts struct {
 	Spreadsheet string `validate:"required"`
 	SheetName   string
+	NamedRange  string
 	Values      []map[string]interface{}
+	HeaderRow   int `validate:"gte=0"`
 }
 
 type UpdateOpts struct {
 	Spreadsheet string `validate:"required"`
-	FilterType  string `validate:"required,oneof=a1 filter"`
+	FilterType  string `validate:"required,oneof=a1 filter namedRange"`
 	A1Notation  string
+	NamedRange  string `validate:"required_if=FilterType namedRange"`
 	Values      []map[string]interface{}
 	SheetName   string
 	Filters     []Filter
+	HeaderRow   int `validate:"gte=0"`
 }
 
 type Filter struct {
@@ -71,6 +91,7 @@ type BulkUpdateOpts struct {
 	SheetName   string
 	PrimaryKey  string `validate:"required"`
 	RowsArray   []map[string]interface{}
+	HeaderRow   int `validate:"gte=0"`
 }
 
 type DeleteOpts struct {
@@ -19,6 +19,7 @@ import (
 	"net/url"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/illacloud/builder-backend/src/utils/oauthgeneric"
 )
 
 const (
@@ -26,6 +27,7 @@ const (
 	AUTH_BASIC  = "basic"
 	AUTH_BEARER = "bearer"
 	AUTH_APIKEY = "apiKey"
+	AUTH_OAUTH2 = "oauth2"
 )
 
 func (g *Connector) doQuery(baseURL string, queryParams, headers, cookies map[string]string, authentication string,
@@ -65,6 +67,12 @@ func (g *Connector) doQuery(baseURL string, queryParams, headers, cookies map[st
 		client.SetAuthScheme(authContent["headerPrefix"])
 		client.SetAuthToken(authContent["value"])
 		break
+	case AUTH_OAUTH2:
+		accessToken, err := oauthgeneric.ExportAccessToken(authContent)
+		if err != nil {
+			return nil, err
+		}
+		client.SetAuthToken(accessToken)
 	case AUTH_NONE:
 		break
 	}
@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/illacloud/builder-backend/src/utils/oauthgeneric"
 	parser_template "github.com/illacloud/builder-backend/src/utils/parser/template"
 
 	"github.com/go-playground/validator/v10"
@@ -44,6 +45,13 @@ func (g *Connector) ValidateResourceOptions(resourceOptions map[string]interface
 		return common.ValidateResult{Valid: false}, err
 	}
 
+	// validate oauth2 provider
+	if g.ResourceOpts.Authentication == AUTH_OAUTH2 {
+		if _, err := oauthgeneric.NewProviderByAuthContent(g.ResourceOpts.AuthContent); err != nil {
+			return common.ValidateResult{Valid: false}, err
+		}
+	}
+
 	return common.ValidateResult{Valid: true}, nil
 }
 
@@ -270,13 +278,17 @@ func (g *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 		return common.RuntimeResult{Success: false}, err
 	}
 
+	extra := map[string]interface{}{
+		"statusCode": resp.StatusCode(),
+		"statusText": resp.Status(),
+	}
 	if resp.IsError() {
-		return common.RuntimeResult{Success: false}, errors.New("unknown error")
+		return common.RuntimeResult{Success: false, Extra: extra}, errors.New("unknown error")
 	}
 	body := make(map[string]interface{})
 	if err := json.Unmarshal(resp.Body(), &body); err != nil {
-		return common.RuntimeResult{Success: false}, err
+		return common.RuntimeResult{Success: false, Extra: extra}, err
 	}
 
-	return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{body}}, nil
+	return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{body}, Extra: extra}, nil
 }
@@ -19,7 +19,7 @@ type Resource struct {
 	URLParams            []map[string]string
 	Headers              []map[string]string
 	Cookies              []map[string]string
-	Authentication       string `validate:"required,oneof=none basic bearer apiKey"`
+	Authentication       string `validate:"required,oneof=none basic bearer apiKey oauth2"`
 	AuthContent          map[string]string
 	DisableIntrospection bool
 }
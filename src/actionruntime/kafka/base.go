@@ -0,0 +1,173 @@
+// Copyright 2023 Illa Soft, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+const (
+	DEFAULT_CLIENT_ID = "illa-builder"
+	DIAL_TIMEOUT      = 10 * time.Second
+	REQUEST_TIMEOUT   = 30 * time.Second
+)
+
+func (k *Connector) formatResourceOptions(resourceOptions map[string]interface{}) error {
+	return mapstructure.Decode(resourceOptions, &k.resourceOptions)
+}
+
+func (k *Connector) exportClientID() string {
+	if k.resourceOptions.ClientID == "" {
+		return DEFAULT_CLIENT_ID
+	}
+	return k.resourceOptions.ClientID
+}
+
+func (k *Connector) getTLSConfig() (*tls.Config, error) {
+	if !k.resourceOptions.SSL.SSL {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	// the self-signed server certificate, the system roots are used when it is empty
+	if k.resourceOptions.SSL.ServerCert != "" {
+		pool := x509.NewCertPool()
+		if ok := pool.AppendCertsFromPEM([]byte(k.resourceOptions.SSL.ServerCert)); !ok {
+			return nil, errors.New("format Kafka TLS server cert failed")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if k.resourceOptions.SSL.ClientCert != "" {
+		cert, err := tls.X509KeyPair([]byte(k.resourceOptions.SSL.ClientCert), []byte(k.resourceOptions.SSL.ClientKey))
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+func (k *Connector) getSASLMechanism() (sasl.Mechanism, error) {
+	switch k.resourceOptions.SASL.Mechanism {
+	case "":
+		return nil, nil
+	case SASL_PLAIN:
+		return plain.Mechanism{Username: k.resourceOptions.SASL.Username, Password: k.resourceOptions.SASL.Password}, nil
+	case SASL_SCRAM_SHA_256:
+		return scram.Mechanism(scram.SHA256, k.resourceOptions.SASL.Username, k.resourceOptions.SASL.Password)
+	case SASL_SCRAM_SHA_512:
+		return scram.Mechanism(scram.SHA512, k.resourceOptions.SASL.Username, k.resourceOptions.SASL.Password)
+	default:
+		return nil, errors.New("unsupported SASL mechanism")
+	}
+}
+
+// getClient returns the client sending requests to cluster, the requests are routed to partition leaders and group coordinators by transport.
+func (k *Connector) getClient(resourceOptions map[string]interface{}) (*kafka.Client, error) {
+	if err := k.formatResourceOptions(resourceOptions); err != nil {
+		return nil, err
+	}
+	if len(k.resourceOptions.Brokers) == 0 {
+		return nil, errors.New("missing Kafka brokers")
+	}
+	tlsConfig, err := k.getTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	mechanism, err := k.getSASLMechanism()
+	if err != nil {
+		return nil, err
+	}
+
+	return &kafka.Client{
+		Addr:    kafka.TCP(k.resourceOptions.Brokers...),
+		Timeout: REQUEST_TIMEOUT,
+		Transport: &kafka.Transport{
+			Dial:        (&net.Dialer{Timeout: DIAL_TIMEOUT}).DialContext,
+			DialTimeout: DIAL_TIMEOUT,
+			ClientID:    k.exportClientID(),
+			TLS:         tlsConfig,
+			SASL:        mechanism,
+		},
+	}, nil
+}
+
+// getDialer returns the dialer of consumer group reader, it should be called after getClient.
+func (k *Connector) getDialer() (*kafka.Dialer, error) {
+	tlsConfig, err := k.getTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	mechanism, err := k.getSASLMechanism()
+	if err != nil {
+		return nil, err
+	}
+	return &kafka.Dialer{
+		ClientID:      k.exportClientID(),
+		Timeout:       DIAL_TIMEOUT,
+		DualStack:     true,
+		TLS:           tlsConfig,
+		SASLMechanism: mechanism,
+	}, nil
+}
+
+// encodeMessageValue keeps the string value as it is, and encodes other values in json, the nil value produces a tombstone.
+func encodeMessageValue(value interface{}) ([]byte, error) {
+	switch typedValue := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []byte(typedValue), nil
+	default:
+		return json.Marshal(typedValue)
+	}
+}
+
+// decodeMessageValue decodes the json value, and returns the value in string when it is not json.
+func decodeMessageValue(value []byte) interface{} {
+	if value == nil {
+		return nil
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(value, &decoded); err == nil {
+		return decoded
+	}
+	return string(value)
+}
+
+func exportHeaders(headers []kafka.Header) []map[string]interface{} {
+	res := make([]map[string]interface{}, 0, len(headers))
+	for _, header := range headers {
+		res = append(res, map[string]interface{}{"key": header.Key, "value": string(header.Value)})
+	}
+	return res
+}
+
+// closeClient closes the connections and stops the metadata refreshing of client transport.
+func closeClient(client *kafka.Client) {
+	if transport, ok := client.Transport.(*kafka.Transport); ok {
+		transport.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2023 Illa Soft, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/mapstructure"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	DEFAULT_CONSUME_MAX_MESSAGES = 100
+	DEFAULT_CONSUME_MAX_WAIT     = 10 * time.Second
+	// the consumer stops when no more message arrives in this period after the first one
+	CONSUME_IDLE_TIMEOUT = time.Second
+	CONSUME_FETCH_WAIT   = 500 * time.Millisecond
+	CONSUME_MAX_BYTES    = 10e6
+
+	// the offsets committed out of consumer group generation are accepted only when the group has no active member
+	OFFSET_COMMIT_OUT_OF_GENERATION = -1
+)
+
+// consume reads a bounded batch of messages as a member of consumer group, the offsets are committed only when
+// the commit option is set, otherwise the same messages will be consumed again in next run.
+func (k *Connector) consume(client *kafka.Client) (common.RuntimeResult, error) {
+	var consumeOpts ConsumeOpts
+	if err := mapstructure.Decode(k.actionOptions.Opts, &consumeOpts); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	validate := validator.New()
+	if err := validate.Struct(consumeOpts); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	maxMessages := consumeOpts.MaxMessages
+	if maxMessages == 0 {
+		maxMessages = DEFAULT_CONSUME_MAX_MESSAGES
+	}
+	maxWait := time.Duration(consumeOpts.MaxWait) * time.Millisecond
+	if maxWait == 0 {
+		maxWait = DEFAULT_CONSUME_MAX_WAIT
+	}
+	startOffset := kafka.FirstOffset
+	if consumeOpts.StartOffset == START_OFFSET_LATEST {
+		startOffset = kafka.LastOffset
+	}
+
+	// the reader retries silently on connection failure, so check the topic first for the error feedback
+	if _, err := countPartitions(context.Background(), client, k.actionOptions.Topic); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	dialer, err := k.getDialer()
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     k.resourceOptions.Brokers,
+		GroupID:     consumeOpts.GroupID,
+		Topic:       k.actionOptions.Topic,
+		Dialer:      dialer,
+		StartOffset: startOffset,
+		MinBytes:    1,
+		MaxBytes:    CONSUME_MAX_BYTES,
+		MaxWait:     CONSUME_FETCH_WAIT,
+	})
+	defer reader.Close()
+
+	deadline := time.Now().Add(maxWait)
+	messages := make([]kafka.Message, 0)
+	for len(messages) < maxMessages {
+		timeout := time.Until(deadline)
+		if len(messages) > 0 && timeout > CONSUME_IDLE_TIMEOUT {
+			timeout = CONSUME_IDLE_TIMEOUT
+		}
+		if timeout <= 0 {
+			break
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		message, err := reader.FetchMessage(ctx)
+		cancel()
+		if errors.Is(err, context.DeadlineExceeded) {
+			break
+		}
+		if err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		messages = append(messages, message)
+	}
+
+	committed := false
+	if consumeOpts.Commit && len(messages) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), REQUEST_TIMEOUT)
+		defer cancel()
+		if err := reader.CommitMessages(ctx, messages...); err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		committed = true
+	}
+
+	rows := make([]map[string]interface{}, 0, len(messages))
+	for _, message := range messages {
+		row := map[string]interface{}{
+			"topic":     message.Topic,
+			"partition": message.Partition,
+			"offset":    message.Offset,
+			"key":       nil,
+			"headers":   exportHeaders(message.Headers),
+			"timestamp": message.Time,
+		}
+		if message.Key != nil {
+			row["key"] = string(message.Key)
+		}
+		value := message.Value
+		if k.isSchemaRegistryEnabled() {
+			if schemaID, payload, ok := decodeWireFormat(value); ok {
+				row["schemaId"] = schemaID
+				value = payload
+			}
+		}
+		row["value"] = decodeMessageValue(value)
+		rows = append(rows, row)
+	}
+
+	return common.RuntimeResult{
+		Success: true,
+		Rows:    rows,
+		Extra:   map[string]interface{}{"groupId": consumeOpts.GroupID, "committed": committed},
+	}, nil
+}
+
+// commit commits the offsets after the messages consumed without commit option are processed.
+func (k *Connector) commit(client *kafka.Client) (common.RuntimeResult, error) {
+	var commitOpts CommitOpts
+	if err := mapstructure.Decode(k.actionOptions.Opts, &commitOpts); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	validate := validator.New()
+	if err := validate.Struct(commitOpts); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	offsetCommits := make([]kafka.OffsetCommit, 0, len(commitOpts.Offsets))
+	for _, offset := range commitOpts.Offsets {
+		offsetCommits = append(offsetCommits, kafka.OffsetCommit{Partition: offset.Partition, Offset: offset.Offset + 1})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), REQUEST_TIMEOUT)
+	defer cancel()
+	resp, err := client.OffsetCommit(ctx, &kafka.OffsetCommitRequest{
+		GroupID:      commitOpts.GroupID,
+		GenerationID: OFFSET_COMMIT_OUT_OF_GENERATION,
+		Topics:       map[string][]kafka.OffsetCommit{k.actionOptions.Topic: offsetCommits},
+	})
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	for _, partition := range resp.Topics[k.actionOptions.Topic] {
+		if partition.Error != nil {
+			return common.RuntimeResult{Success: false}, fmt.Errorf("commit offset of partition %d failed: %w", partition.Partition, partition.Error)
+		}
+	}
+
+	rows := make([]map[string]interface{}, 0, len(offsetCommits))
+	for _, offsetCommit := range offsetCommits {
+		rows = append(rows, map[string]interface{}{"partition": offsetCommit.Partition, "committedOffset": offsetCommit.Offset})
+	}
+	return common.RuntimeResult{Success: true, Rows: rows, Extra: map[string]interface{}{"groupId": commitOpts.GroupID}}, nil
+}
@@ -0,0 +1,101 @@
+//go:build integration
+
+package kafka
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// the tests run against a single kafka broker, start it by:
+// docker run -p 9092:9092 apache/kafka:3.7.0
+// then run: KAFKA_TEST_BROKER=127.0.0.1:9092 go test -tags integration ./src/actionruntime/kafka/
+func newKafkaTestTopic(t *testing.T) (map[string]interface{}, string) {
+	broker := os.Getenv("KAFKA_TEST_BROKER")
+	if broker == "" {
+		t.Skip("KAFKA_TEST_BROKER is not set")
+	}
+	resourceOptions := map[string]interface{}{"brokers": []string{broker}}
+	connector := &Connector{}
+	client, err := connector.getClient(resourceOptions)
+	assert.Nil(t, err)
+	defer closeClient(client)
+
+	topic := "illa_test_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	resp, err := client.CreateTopics(context.Background(), &kafka.CreateTopicsRequest{
+		Topics: []kafka.TopicConfig{{Topic: topic, NumPartitions: 1, ReplicationFactor: 1}},
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, resp.Errors[topic])
+	t.Cleanup(func() {
+		client, err := connector.getClient(resourceOptions)
+		if err != nil {
+			return
+		}
+		defer closeClient(client)
+		client.DeleteTopics(context.Background(), &kafka.DeleteTopicsRequest{Topics: []string{topic}})
+	})
+	return resourceOptions, topic
+}
+
+func runKafkaAction(t *testing.T, resourceOptions map[string]interface{}, method string, topic string, opts map[string]interface{}) ([]map[string]interface{}, map[string]interface{}) {
+	connector := &Connector{}
+	result, err := connector.Run(resourceOptions, map[string]interface{}{"method": method, "topic": topic, "opts": opts}, nil)
+	assert.Nil(t, err)
+	assert.True(t, result.Success)
+	return result.Rows, result.Extra
+}
+
+func TestProduceConsumeAndCommit(t *testing.T) {
+	resourceOptions, topic := newKafkaTestTopic(t)
+	groupID := topic + "_group"
+
+	rows, _ := runKafkaAction(t, resourceOptions, PRODUCE_METHOD, topic, map[string]interface{}{
+		"messages": []map[string]interface{}{
+			{"key": "a", "value": map[string]interface{}{"id": 1}},
+			{"key": "b", "value": "plain"},
+			{"key": "c", "value": map[string]interface{}{"id": 3}, "headers": []map[string]interface{}{{"key": "source", "value": "illa"}}},
+		},
+	})
+	assert.Equal(t, 3, len(rows))
+	for i, row := range rows {
+		assert.Equal(t, int64(i), row["offset"])
+	}
+
+	// the offsets are not committed, so the same messages are consumed again
+	consumeOpts := map[string]interface{}{"groupId": groupID, "maxMessages": 2, "maxWait": 10000}
+	rows, extra := runKafkaAction(t, resourceOptions, CONSUME_METHOD, topic, consumeOpts)
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, false, extra["committed"])
+	assert.Equal(t, map[string]interface{}{"id": float64(1)}, rows[0]["value"])
+	assert.Equal(t, "plain", rows[1]["value"])
+
+	rows, _ = runKafkaAction(t, resourceOptions, CONSUME_METHOD, topic, consumeOpts)
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, "a", rows[0]["key"])
+
+	// commit the processed messages, then the consuming continues after them
+	rows, _ = runKafkaAction(t, resourceOptions, COMMIT_METHOD, topic, map[string]interface{}{
+		"groupId": groupID,
+		"offsets": []map[string]interface{}{{"partition": 0, "offset": 1}},
+	})
+	assert.Equal(t, []map[string]interface{}{{"partition": 0, "committedOffset": int64(2)}}, rows)
+
+	consumeOpts["commit"] = true
+	rows, extra = runKafkaAction(t, resourceOptions, CONSUME_METHOD, topic, consumeOpts)
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, "c", rows[0]["key"])
+	assert.Equal(t, []map[string]interface{}{{"key": "source", "value": "illa"}}, rows[0]["headers"])
+	assert.Equal(t, true, extra["committed"])
+
+	// all the messages are committed
+	consumeOpts["maxWait"] = 3000
+	rows, _ = runKafkaAction(t, resourceOptions, CONSUME_METHOD, topic, consumeOpts)
+	assert.Equal(t, 0, len(rows))
+}
@@ -0,0 +1,183 @@
+// Copyright 2023 Illa Soft, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/mapstructure"
+	"github.com/segmentio/kafka-go"
+)
+
+func exportRequiredAcks(acks string) kafka.RequiredAcks {
+	switch acks {
+	case ACKS_NONE:
+		return kafka.RequireNone
+	case ACKS_LEADER:
+		return kafka.RequireOne
+	default:
+		return kafka.RequireAll
+	}
+}
+
+// countPartitions returns the partition count of topic, the topic is not created automatically.
+func countPartitions(ctx context.Context, client *kafka.Client, topic string) (int, error) {
+	metadata, err := client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{topic}})
+	if err != nil {
+		return 0, err
+	}
+	for _, t := range metadata.Topics {
+		if t.Name != topic {
+			continue
+		}
+		if t.Error != nil {
+			return 0, t.Error
+		}
+		return len(t.Partitions), nil
+	}
+	return 0, kafka.UnknownTopicOrPartition
+}
+
+// produce writes the messages to the partition chosen in message, the messages without partition are assigned by
+// murmur2 hash of key like the java client does, and the ones without key are assigned randomly.
+func (k *Connector) produce(client *kafka.Client) (common.RuntimeResult, error) {
+	var produceOpts ProduceOpts
+	if err := mapstructure.Decode(k.actionOptions.Opts, &produceOpts); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	validate := validator.New()
+	if err := validate.Struct(produceOpts); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	ctx := context.Background()
+	numPartitions, err := countPartitions(ctx, client, k.actionOptions.Topic)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	partitions := make([]int, numPartitions)
+	for i := range partitions {
+		partitions[i] = i
+	}
+
+	schemaID := 0
+	if produceOpts.SchemaSubject != "" {
+		if !k.isSchemaRegistryEnabled() {
+			return common.RuntimeResult{Success: false}, fmt.Errorf("schema registry is not configured for subject %s", produceOpts.SchemaSubject)
+		}
+		if schemaID, err = k.getLatestJSONSchemaID(produceOpts.SchemaSubject); err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+	}
+
+	// group the records by partition, the order of messages in same partition is kept
+	balancer := kafka.Murmur2Balancer{}
+	records := make(map[int][]kafka.Record)
+	assignments := make([][2]int, len(produceOpts.Messages)) // message index => partition and index in partition
+	for i, message := range produceOpts.Messages {
+		value, err := encodeMessageValue(message.Value)
+		if err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		if schemaID > 0 && value != nil {
+			if !json.Valid(value) {
+				return common.RuntimeResult{Success: false}, fmt.Errorf("value of message %d is not json, which is required by schema subject %s", i, produceOpts.SchemaSubject)
+			}
+			value = encodeWireFormat(schemaID, value)
+		}
+		var key []byte
+		if message.Key != "" {
+			key = []byte(message.Key)
+		}
+		headers := make([]kafka.Header, 0, len(message.Headers))
+		for _, header := range message.Headers {
+			headers = append(headers, kafka.Header{Key: header.Key, Value: []byte(header.Value)})
+		}
+
+		partition := 0
+		if message.Partition != nil {
+			partition = *message.Partition
+			if partition >= numPartitions {
+				return common.RuntimeResult{Success: false}, fmt.Errorf("partition %d of message %d is out of range, topic %s has %d partitions", partition, i, k.actionOptions.Topic, numPartitions)
+			}
+		} else {
+			partition = balancer.Balance(kafka.Message{Key: key}, partitions...)
+		}
+
+		record := kafka.Record{Headers: headers}
+		if key != nil {
+			record.Key = kafka.NewBytes(key)
+		}
+		if value != nil {
+			record.Value = kafka.NewBytes(value)
+		}
+		assignments[i] = [2]int{partition, len(records[partition])}
+		records[partition] = append(records[partition], record)
+	}
+
+	sortedPartitions := make([]int, 0, len(records))
+	for partition := range records {
+		sortedPartitions = append(sortedPartitions, partition)
+	}
+	sort.Ints(sortedPartitions)
+
+	baseOffsets := make(map[int]int64, len(records))
+	for _, partition := range sortedPartitions {
+		resp, err := client.Produce(ctx, &kafka.ProduceRequest{
+			Topic:        k.actionOptions.Topic,
+			Partition:    partition,
+			RequiredAcks: exportRequiredAcks(produceOpts.Acks),
+			Records:      kafka.NewRecordReader(records[partition]...),
+		})
+		if err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		// no response is returned when the acks is none
+		if resp == nil {
+			baseOffsets[partition] = -1
+			continue
+		}
+		if resp.Error != nil {
+			return common.RuntimeResult{Success: false}, fmt.Errorf("produce to partition %d failed: %w", partition, resp.Error)
+		}
+		for index, recordErr := range resp.RecordErrors {
+			return common.RuntimeResult{Success: false}, fmt.Errorf("produce record %d to partition %d failed: %w", index, partition, recordErr)
+		}
+		baseOffsets[partition] = resp.BaseOffset
+	}
+
+	rows := make([]map[string]interface{}, 0, len(produceOpts.Messages))
+	for i, message := range produceOpts.Messages {
+		partition, indexInPartition := assignments[i][0], assignments[i][1]
+		offset := int64(-1)
+		if baseOffset := baseOffsets[partition]; baseOffset >= 0 {
+			offset = baseOffset + int64(indexInPartition)
+		}
+		rows = append(rows, map[string]interface{}{
+			"topic":     k.actionOptions.Topic,
+			"partition": partition,
+			"offset":    offset,
+			"key":       message.Key,
+		})
+	}
+
+	return common.RuntimeResult{Success: true, Rows: rows}, nil
+}
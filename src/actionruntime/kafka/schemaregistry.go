@@ -0,0 +1,104 @@
+// Copyright 2023 Illa Soft, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	// the schema registry wire format is the magic byte and 4 bytes schema id in big endian before payload
+	WIRE_FORMAT_MAGIC_BYTE   = 0
+	WIRE_FORMAT_HEADER_BYTES = 5
+
+	SCHEMA_REGISTRY_CONTENT_TYPE = "application/vnd.schemaregistry.v1+json"
+
+	// the schema type is omitted by schema registry for avro schemas
+	SCHEMA_TYPE_AVRO = "AVRO"
+	SCHEMA_TYPE_JSON = "JSON"
+)
+
+func (k *Connector) isSchemaRegistryEnabled() bool {
+	return k.resourceOptions.SchemaRegistry.URL != ""
+}
+
+func (k *Connector) newSchemaRegistryRequest() *resty.Request {
+	req := resty.New().SetTimeout(REQUEST_TIMEOUT).R().SetHeader("Accept", SCHEMA_REGISTRY_CONTENT_TYPE)
+	if k.resourceOptions.SchemaRegistry.Username != "" {
+		req.SetBasicAuth(k.resourceOptions.SchemaRegistry.Username, k.resourceOptions.SchemaRegistry.Password)
+	}
+	return req
+}
+
+func (k *Connector) getSchemaRegistry(path string, result interface{}) error {
+	resp, err := k.newSchemaRegistryRequest().SetResult(result).Get(strings.TrimSuffix(k.resourceOptions.SchemaRegistry.URL, "/") + path)
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return fmt.Errorf("schema registry responded %s: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
+
+func (k *Connector) listSubjects() ([]string, error) {
+	subjects := make([]string, 0)
+	if err := k.getSchemaRegistry("/subjects", &subjects); err != nil {
+		return nil, err
+	}
+	return subjects, nil
+}
+
+// getLatestJSONSchemaID returns the id of latest schema of subject, only the json schemas are supported since the messages
+// are produced in json, the avro and protobuf messages can not be serialized.
+func (k *Connector) getLatestJSONSchemaID(subject string) (int, error) {
+	var schema struct {
+		ID         int    `json:"id"`
+		SchemaType string `json:"schemaType"`
+	}
+	if err := k.getSchemaRegistry("/subjects/"+url.PathEscape(subject)+"/versions/latest", &schema); err != nil {
+		return 0, err
+	}
+	if schema.ID <= 0 {
+		return 0, errors.New("invalid schema id of subject " + subject)
+	}
+	if schema.SchemaType == "" {
+		schema.SchemaType = SCHEMA_TYPE_AVRO
+	}
+	if schema.SchemaType != SCHEMA_TYPE_JSON {
+		return 0, fmt.Errorf("schema type %s of subject %s is not supported, only %s is supported", schema.SchemaType, subject, SCHEMA_TYPE_JSON)
+	}
+	return schema.ID, nil
+}
+
+func encodeWireFormat(schemaID int, payload []byte) []byte {
+	value := make([]byte, WIRE_FORMAT_HEADER_BYTES, WIRE_FORMAT_HEADER_BYTES+len(payload))
+	value[0] = WIRE_FORMAT_MAGIC_BYTE
+	binary.BigEndian.PutUint32(value[1:WIRE_FORMAT_HEADER_BYTES], uint32(schemaID))
+	return append(value, payload...)
+}
+
+func decodeWireFormat(value []byte) (int, []byte, bool) {
+	if len(value) < WIRE_FORMAT_HEADER_BYTES || value[0] != WIRE_FORMAT_MAGIC_BYTE {
+		return 0, value, false
+	}
+	return int(binary.BigEndian.Uint32(value[1:WIRE_FORMAT_HEADER_BYTES])), value[WIRE_FORMAT_HEADER_BYTES:], true
+}
@@ -0,0 +1,53 @@
+package kafka
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetLatestJSONSchemaID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", SCHEMA_REGISTRY_CONTENT_TYPE)
+		switch r.URL.Path {
+		case "/subjects/orders-value/versions/latest":
+			w.Write([]byte(`{"subject":"orders-value","version":2,"id":7,"schemaType":"JSON","schema":"{}"}`))
+		case "/subjects/users-value/versions/latest":
+			w.Write([]byte(`{"subject":"users-value","version":1,"id":8,"schema":"{\"type\":\"record\"}"}`))
+		case "/subjects/events-value/versions/latest":
+			w.Write([]byte(`{"subject":"events-value","version":1,"id":9,"schemaType":"PROTOBUF","schema":"syntax = \"proto3\";"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	connector := &Connector{resourceOptions: Resource{SchemaRegistry: SchemaRegistryOptions{URL: server.URL}}}
+	schemaID, err := connector.getLatestJSONSchemaID("orders-value")
+	assert.Nil(t, err)
+	assert.Equal(t, 7, schemaID)
+
+	// the schema type is omitted for avro
+	_, err = connector.getLatestJSONSchemaID("users-value")
+	assert.ErrorContains(t, err, SCHEMA_TYPE_AVRO)
+
+	_, err = connector.getLatestJSONSchemaID("events-value")
+	assert.ErrorContains(t, err, "PROTOBUF")
+
+	_, err = connector.getLatestJSONSchemaID("missing-value")
+	assert.NotNil(t, err)
+}
+
+func TestWireFormat(t *testing.T) {
+	value := encodeWireFormat(7, []byte(`{"id":1}`))
+	schemaID, payload, ok := decodeWireFormat(value)
+	assert.True(t, ok)
+	assert.Equal(t, 7, schemaID)
+	assert.Equal(t, []byte(`{"id":1}`), payload)
+
+	_, payload, ok = decodeWireFormat([]byte(`{"id":1}`))
+	assert.False(t, ok)
+	assert.Equal(t, []byte(`{"id":1}`), payload)
+}
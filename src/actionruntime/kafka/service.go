@@ -0,0 +1,143 @@
+// Copyright 2023 Illa Soft, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/mapstructure"
+	"github.com/segmentio/kafka-go"
+)
+
+type Connector struct {
+	resourceOptions Resource
+	actionOptions   Action
+}
+
+func (k *Connector) ValidateResourceOptions(resourceOptions map[string]interface{}) (common.ValidateResult, error) {
+	// format resource options
+	if err := mapstructure.Decode(resourceOptions, &k.resourceOptions); err != nil {
+		return common.ValidateResult{Valid: false}, err
+	}
+
+	// validate kafka options
+	validate := validator.New()
+	if err := validate.Struct(k.resourceOptions); err != nil {
+		return common.ValidateResult{Valid: false}, err
+	}
+
+	return common.ValidateResult{Valid: true}, nil
+}
+
+func (k *Connector) ValidateActionTemplate(actionOptions map[string]interface{}) (common.ValidateResult, error) {
+	// format action options
+	if err := mapstructure.Decode(actionOptions, &k.actionOptions); err != nil {
+		return common.ValidateResult{Valid: false}, err
+	}
+
+	// validate kafka options
+	validate := validator.New()
+	if err := validate.Struct(k.actionOptions); err != nil {
+		return common.ValidateResult{Valid: false}, err
+	}
+
+	return common.ValidateResult{Valid: true}, nil
+}
+
+func (k *Connector) TestConnection(resourceOptions map[string]interface{}) (common.ConnectionResult, error) {
+	// get kafka client
+	client, err := k.getClient(resourceOptions)
+	if err != nil {
+		return common.ConnectionResult{Success: false}, err
+	}
+	defer closeClient(client)
+
+	// test kafka connection
+	if _, err := client.Metadata(context.Background(), &kafka.MetadataRequest{Topics: []string{}}); err != nil {
+		return common.ConnectionResult{Success: false}, err
+	}
+
+	return common.ConnectionResult{Success: true}, nil
+}
+
+func (k *Connector) GetMetaInfo(resourceOptions map[string]interface{}) (common.MetaInfoResult, error) {
+	// get kafka client
+	client, err := k.getClient(resourceOptions)
+	if err != nil {
+		return common.MetaInfoResult{Success: false}, err
+	}
+	defer closeClient(client)
+
+	// get all topics, the internal topics are skipped
+	metadata, err := client.Metadata(context.Background(), &kafka.MetadataRequest{})
+	if err != nil {
+		return common.MetaInfoResult{Success: false}, err
+	}
+	sort.Slice(metadata.Topics, func(i, j int) bool {
+		return metadata.Topics[i].Name < metadata.Topics[j].Name
+	})
+	topics := make([]map[string]interface{}, 0, len(metadata.Topics))
+	for _, topic := range metadata.Topics {
+		if topic.Internal || topic.Error != nil {
+			continue
+		}
+		topics = append(topics, map[string]interface{}{"name": topic.Name, "partitions": len(topic.Partitions)})
+	}
+	schema := map[string]interface{}{"topics": topics}
+
+	// get subjects of schema registry
+	if k.isSchemaRegistryEnabled() {
+		subjects, err := k.listSubjects()
+		if err != nil {
+			return common.MetaInfoResult{Success: false}, err
+		}
+		schema["subjects"] = subjects
+	}
+
+	return common.MetaInfoResult{
+		Success: true,
+		Schema:  schema,
+	}, nil
+}
+
+func (k *Connector) Run(resourceOptions map[string]interface{}, actionOptions map[string]interface{}, rawActionOptions map[string]interface{}) (common.RuntimeResult, error) {
+	// get kafka client
+	client, err := k.getClient(resourceOptions)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	defer closeClient(client)
+
+	// format action options
+	if err := mapstructure.Decode(actionOptions, &k.actionOptions); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	switch k.actionOptions.Method {
+	case PRODUCE_METHOD:
+		return k.produce(client)
+	case CONSUME_METHOD:
+		return k.consume(client)
+	case COMMIT_METHOD:
+		return k.commit(client)
+	default:
+		return common.RuntimeResult{Success: false}, errors.New("unsupported kafka method")
+	}
+}
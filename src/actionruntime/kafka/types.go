@@ -0,0 +1,104 @@
+// Copyright 2023 Illa Soft, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+const (
+	PRODUCE_METHOD = "produce"
+	CONSUME_METHOD = "consume"
+	COMMIT_METHOD  = "commit"
+
+	SASL_PLAIN         = "plain"
+	SASL_SCRAM_SHA_256 = "scram-sha-256"
+	SASL_SCRAM_SHA_512 = "scram-sha-512"
+
+	ACKS_NONE   = "none"
+	ACKS_LEADER = "leader"
+	ACKS_ALL    = "all"
+
+	START_OFFSET_EARLIEST = "earliest"
+	START_OFFSET_LATEST   = "latest"
+)
+
+type Resource struct {
+	Brokers        []string `validate:"required,gt=0,dive,required"`
+	ClientID       string
+	SASL           SASLOptions
+	SSL            SSLOptions
+	SchemaRegistry SchemaRegistryOptions
+}
+
+type SASLOptions struct {
+	Mechanism string `validate:"omitempty,oneof=plain scram-sha-256 scram-sha-512"`
+	Username  string `validate:"required_with=Mechanism"`
+	Password  string `validate:"required_with=Mechanism"`
+}
+
+type SSLOptions struct {
+	SSL        bool
+	ServerCert string
+	ClientKey  string `validate:"required_with=ClientCert"`
+	ClientCert string `validate:"required_with=ClientKey"`
+}
+
+type SchemaRegistryOptions struct {
+	URL      string `validate:"omitempty,url"`
+	Username string
+	Password string
+}
+
+type Action struct {
+	Method string `validate:"required,oneof=produce consume commit"`
+	Topic  string `validate:"required"`
+	Opts   map[string]interface{}
+}
+
+type ProduceOpts struct {
+	Messages []Message `validate:"required,gt=0,lte=1000,dive"`
+	Acks     string    `validate:"omitempty,oneof=none leader all"`
+	// the values are encoded in schema registry wire format with the latest schema of subject
+	SchemaSubject string
+}
+
+type Message struct {
+	Key       string
+	Value     interface{}
+	Headers   []MessageHeader `validate:"dive"`
+	Partition *int            `validate:"omitempty,gte=0"`
+}
+
+type MessageHeader struct {
+	Key   string `validate:"required"`
+	Value string
+}
+
+type ConsumeOpts struct {
+	GroupID     string `validate:"required"`
+	MaxMessages int    `validate:"gte=0,lte=1000"`
+	// MaxWait is in milliseconds, it includes the time of joining consumer group
+	MaxWait     int    `validate:"gte=0,lte=60000"`
+	StartOffset string `validate:"omitempty,oneof=earliest latest"`
+	Commit      bool
+}
+
+type CommitOpts struct {
+	GroupID string         `validate:"required"`
+	Offsets []CommitOffset `validate:"required,gt=0,dive"`
+}
+
+// CommitOffset is the offset of last processed message in partition, the next offset will be committed.
+type CommitOffset struct {
+	Partition int   `validate:"gte=0"`
+	Offset    int64 `validate:"gte=0"`
+}
@@ -0,0 +1,164 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/mitchellh/mapstructure"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	ACTION_TYPE_WATCH = "watch"
+
+	FIELD_RESUME_TOKEN = "resumeToken"
+
+	DEFAULT_WATCH_MAX_AWAIT_TIME = 5 * time.Second
+	MAX_WATCH_MAX_AWAIT_TIME     = 60 * time.Second
+	DEFAULT_WATCH_MAX_EVENTS     = 100
+	MAX_WATCH_MAX_EVENTS         = 1000
+)
+
+// watchResumeToken binds the resume token to the watched stream, the token of other namespace or pipeline is not reused
+// after the action edited, and the changed resume after in options restarts the stream from it.
+type watchResumeToken struct {
+	Database    string `json:"database"`
+	Collection  string `json:"collection"`
+	Pipeline    string `json:"pipeline"`
+	ResumeAfter string `json:"resumeAfter"`
+	Token       string `json:"token"`
+}
+
+func newWatchResumeToken(binding watchResumeToken, token string) string {
+	binding.Token = token
+	resumeToken, _ := json.Marshal(&binding)
+	return string(resumeToken)
+}
+
+func decodeWatchContent(typeContent map[string]interface{}) (*WatchContent, error) {
+	var watchOptions WatchContent
+	if err := mapstructure.Decode(typeContent, &watchOptions); err != nil {
+		return nil, err
+	}
+	validate := validator.New()
+	if err := validate.Struct(watchOptions); err != nil {
+		return nil, err
+	}
+	return &watchOptions, nil
+}
+
+// watch returns the change events of collection, or the whole database when collection is empty.
+// The resume token kept from last run of the same stream makes the stream continue from where the last run stopped, the resume token
+// in options is only the start position of the first run. The new resume token is returned in extra.
+func (q *QueryRunner) watch() (common.RuntimeResult, error) {
+	watchOptions, err := decodeWatchContent(q.query.TypeContent)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	pipeline := bson.A{}
+	if watchOptions.Pipeline != "" && watchOptions.Pipeline != "[]" {
+		if err := bson.UnmarshalExtJSON([]byte(watchOptions.Pipeline), true, &pipeline); err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+	}
+	maxAwaitTime := DEFAULT_WATCH_MAX_AWAIT_TIME
+	if watchOptions.MaxAwaitTime > 0 {
+		maxAwaitTime = time.Duration(watchOptions.MaxAwaitTime) * time.Millisecond
+	}
+	if maxAwaitTime > MAX_WATCH_MAX_AWAIT_TIME {
+		maxAwaitTime = MAX_WATCH_MAX_AWAIT_TIME
+	}
+	maxEvents := DEFAULT_WATCH_MAX_EVENTS
+	if watchOptions.MaxEvents > 0 && watchOptions.MaxEvents < MAX_WATCH_MAX_EVENTS {
+		maxEvents = watchOptions.MaxEvents
+	} else if watchOptions.MaxEvents >= MAX_WATCH_MAX_EVENTS {
+		maxEvents = MAX_WATCH_MAX_EVENTS
+	}
+
+	opts := options.ChangeStream()
+	if watchOptions.FullDocument != "" {
+		opts = opts.SetFullDocument(options.FullDocument(watchOptions.FullDocument))
+	}
+	binding := watchResumeToken{Database: q.db, Collection: q.query.Collection, Pipeline: watchOptions.Pipeline, ResumeAfter: watchOptions.ResumeAfter}
+	if resumeToken := exportWatchResumeToken(q.resumeToken, binding); resumeToken != "" {
+		var resumeAfter bson.Raw
+		if err := bson.UnmarshalExtJSON([]byte(resumeToken), false, &resumeAfter); err != nil {
+			return common.RuntimeResult{Success: false}, errors.New("invalid resume token: " + err.Error())
+		}
+		opts = opts.SetResumeAfter(resumeAfter)
+	}
+
+	// the window is bounded by max await time
+	ctx, cancel := context.WithTimeout(q.ctx, maxAwaitTime)
+	defer cancel()
+	var stream *mongo.ChangeStream
+	if q.query.Collection == "" {
+		stream, err = q.client.Database(q.db).Watch(ctx, pipeline, opts)
+	} else {
+		stream, err = q.client.Database(q.db).Collection(q.query.Collection).Watch(ctx, pipeline, opts)
+	}
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	defer stream.Close(context.Background())
+
+	events := make([]bson.M, 0)
+	for len(events) < maxEvents && stream.Next(ctx) {
+		var event bson.M
+		if err := stream.Decode(&event); err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		events = append(events, event)
+	}
+	// the stream stops with error when the window elapsed
+	if err := stream.Err(); err != nil && ctx.Err() == nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// the resume token is kept even no event received, it is the post batch resume token
+	extra := map[string]interface{}{}
+	if stream.ResumeToken() != nil {
+		resumeTokenInJSON, err := bson.MarshalExtJSON(stream.ResumeToken(), false, false)
+		if err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		extra[FIELD_RESUME_TOKEN] = string(resumeTokenInJSON)
+		q.nextResumeToken = newWatchResumeToken(binding, string(resumeTokenInJSON))
+	}
+	return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{{"result": events}}, Extra: extra}, nil
+}
+
+// exportWatchResumeToken prefers the resume token stored by last run of the same stream, otherwise the explicit one in options
+// would replay the same events on every run.
+func exportWatchResumeToken(storedResumeToken string, binding watchResumeToken) string {
+	var lastRun watchResumeToken
+	if storedResumeToken != "" && json.Unmarshal([]byte(storedResumeToken), &lastRun) == nil && lastRun.Token != "" {
+		token := lastRun.Token
+		lastRun.Token = ""
+		if lastRun == binding {
+			return token
+		}
+	}
+	return binding.ResumeAfter
+}
@@ -0,0 +1,40 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportWatchResumeTokenPrefersStoredToken(t *testing.T) {
+	binding := watchResumeToken{Database: "shop", Collection: "orders", Pipeline: `[{"$match":{"operationType":"insert"}}]`, ResumeAfter: `{"_data":"explicit"}`}
+	storedResumeToken := newWatchResumeToken(binding, `{"_data":"stored"}`)
+	assert.Equal(t, `{"_data":"stored"}`, exportWatchResumeToken(storedResumeToken, binding))
+	assert.Equal(t, `{"_data":"explicit"}`, exportWatchResumeToken("", binding))
+	assert.Equal(t, "", exportWatchResumeToken("", watchResumeToken{Database: "shop"}))
+}
+
+func TestExportWatchResumeTokenIgnoresOtherStream(t *testing.T) {
+	binding := watchResumeToken{Database: "shop", Collection: "orders"}
+	storedResumeToken := newWatchResumeToken(binding, `{"_data":"stored"}`)
+
+	otherCollection := binding
+	otherCollection.Collection = "users"
+	assert.Equal(t, "", exportWatchResumeToken(storedResumeToken, otherCollection))
+
+	otherDatabase := binding
+	otherDatabase.Database = "crm"
+	assert.Equal(t, "", exportWatchResumeToken(storedResumeToken, otherDatabase))
+
+	otherPipeline := binding
+	otherPipeline.Pipeline = `[{"$match":{"operationType":"delete"}}]`
+	assert.Equal(t, "", exportWatchResumeToken(storedResumeToken, otherPipeline))
+
+	// the changed resume after in options resets the stream
+	resetByResumeAfter := binding
+	resetByResumeAfter.ResumeAfter = `{"_data":"explicit"}`
+	assert.Equal(t, `{"_data":"explicit"}`, exportWatchResumeToken(storedResumeToken, resetByResumeAfter))
+
+	// the legacy token without binding is not reused
+	assert.Equal(t, "", exportWatchResumeToken(`{"_data":"stored"}`, binding))
+}
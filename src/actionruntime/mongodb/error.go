@@ -0,0 +1,69 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (m *Connector) ClassifyError(err error) *common.ConnectorError {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_SYNTAX, err).SetPosition(int(syntaxErr.Offset))
+	}
+	switch {
+	case mongo.IsTimeout(err):
+		return common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_TIMEOUT, err).SetRetryable(true)
+	case mongo.IsNetworkError(err):
+		return common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_CONNECTION, err).SetRetryable(true)
+	case mongo.IsDuplicateKeyError(err):
+		return common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_CONSTRAINT, err).SetVendorCode("11000")
+	}
+
+	var commandErr mongo.CommandError
+	if !errors.As(err, &commandErr) {
+		var writeException mongo.WriteException
+		if !errors.As(err, &writeException) || writeException.WriteConcernError == nil {
+			return common.ClassifyNetworkError(err)
+		}
+		commandErr = mongo.CommandError{Code: int32(writeException.WriteConcernError.Code), Message: writeException.WriteConcernError.Message}
+	}
+	var connectorError *common.ConnectorError
+	switch commandErr.Code {
+	case 9:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_SYNTAX, err)
+	case 18:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_AUTH, err)
+	case 13:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_PERMISSION, err)
+	case 26:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_NOT_FOUND, err)
+	case 50:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_TIMEOUT, err)
+	case 121:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_CONSTRAINT, err)
+	default:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_UNKNOWN, err)
+	}
+	if commandErr.HasErrorLabel("TransientTransactionError") || commandErr.HasErrorLabel("RetryableWriteError") {
+		connectorError.SetRetryable(true)
+	}
+	return connectorError.SetVendorCode(strconv.Itoa(int(commandErr.Code))).SetMessage(commandErr.Message)
+}
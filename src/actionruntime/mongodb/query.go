@@ -29,9 +29,53 @@ import (
 )
 
 type QueryRunner struct {
-	client *mongo.Client
-	query  Query
-	db     string
+	ctx         context.Context
+	client      *mongo.Client
+	query       Query
+	db          string
+	resumeToken string
+	// nextResumeToken is the resume token bound to the watched stream, it is set after watched successfully
+	nextResumeToken string
+}
+
+func (q *QueryRunner) run() (common.RuntimeResult, error) {
+	switch q.query.ActionType {
+	case "aggregate":
+		return q.aggregate()
+	case "bulkWrite":
+		return q.bulkWrite()
+	case "count":
+		return q.count()
+	case "deleteMany":
+		return q.deleteMany()
+	case "deleteOne":
+		return q.deleteOne()
+	case "distinct":
+		return q.distinct()
+	case "find":
+		return q.find()
+	case "findOne":
+		return q.findOne()
+	case "findOneAndUpdate":
+		return q.findOneAndUpdate()
+	case "insertOne":
+		return q.insertOne()
+	case "insertMany":
+		return q.insertMany()
+	case "listCollections":
+		return q.listCollections()
+	case "updateMany":
+		return q.updateMany()
+	case "updateOne":
+		return q.updateOne()
+	case "command":
+		return q.command()
+	case ACTION_TYPE_TRANSACTION:
+		return q.transaction()
+	case ACTION_TYPE_WATCH:
+		return q.watch()
+	}
+	return common.RuntimeResult{}, nil
 }
 
 func (q *QueryRunner) aggregate() (common.RuntimeResult, error) {
@@ -70,13 +114,13 @@ func (q *QueryRunner) aggregate() (common.RuntimeResult, error) {
 		opts = opts.SetBatchSize(parsedAggregateOptions.BatchSize)
 	}
 
-	cursor, err := coll.Aggregate(context.TODO(), aggregateStage, opts)
+	cursor, err := coll.Aggregate(q.ctx, aggregateStage, opts)
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
 
 	var results []bson.M
-	if err = cursor.All(context.TODO(), &results); err != nil {
+	if err = cursor.All(q.ctx, &results); err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
 	return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{{"result": results}}}, nil
@@ -144,7 +188,7 @@ func (q *QueryRunner) bulkWrite() (common.RuntimeResult, error) {
 			break
 		}
 	}
-	results, err := coll.BulkWrite(context.TODO(), models)
+	results, err := coll.BulkWrite(q.ctx, models)
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
@@ -164,7 +208,7 @@ func (q *QueryRunner) count() (common.RuntimeResult, error) {
 		}
 	}
 
-	count, err := coll.CountDocuments(context.TODO(), filter)
+	count, err := coll.CountDocuments(q.ctx, filter)
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
@@ -184,7 +228,7 @@ func (q *QueryRunner) deleteMany() (common.RuntimeResult, error) {
 		}
 	}
 
-	results, err := coll.DeleteMany(context.TODO(), filter)
+	results, err := coll.DeleteMany(q.ctx, filter)
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
@@ -204,7 +248,7 @@ func (q *QueryRunner) deleteOne() (common.RuntimeResult, error) {
 		}
 	}
 
-	results, err := coll.DeleteOne(context.TODO(), filter)
+	results, err := coll.DeleteOne(q.ctx, filter)
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
@@ -241,7 +285,7 @@ func (q *QueryRunner) distinct() (common.RuntimeResult, error) {
 		opts = opts.SetCollation(parsedAggregateOptions.Collation)
 	}
 
-	results, err := coll.Distinct(context.TODO(), distinctOptions.Field, filter, opts)
+	results, err := coll.Distinct(q.ctx, distinctOptions.Field, filter, opts)
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
@@ -291,13 +335,13 @@ func (q *QueryRunner) find() (common.RuntimeResult, error) {
 		opts = opts.SetSkip(skip)
 	}
 
-	cursor, err := coll.Find(context.TODO(), filter, opts)
+	cursor, err := coll.Find(q.ctx, filter, opts)
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
 
 	var results []bson.M
-	if err = cursor.All(context.TODO(), &results); err != nil {
+	if err = cursor.All(q.ctx, &results); err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
 
@@ -335,7 +379,7 @@ func (q *QueryRunner) findOne() (common.RuntimeResult, error) {
 	}
 
 	var results bson.M
-	err := coll.FindOne(context.TODO(), filter, opts).Decode(&results)
+	err := coll.FindOne(q.ctx, filter, opts).Decode(&results)
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
@@ -403,7 +447,7 @@ func (q *QueryRunner) findOneAndUpdate() (common.RuntimeResult, error) {
 	}
 
 	var results bson.M
-	if err := coll.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&results); err != nil {
+	if err := coll.FindOneAndUpdate(q.ctx, filter, update, opts).Decode(&results); err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
 	return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{{"result": results}}}, nil
@@ -423,7 +467,7 @@ func (q *QueryRunner) insertOne() (common.RuntimeResult, error) {
 		}
 	}
 
-	results, err := coll.InsertOne(context.TODO(), doc)
+	results, err := coll.InsertOne(q.ctx, doc)
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
@@ -450,7 +494,7 @@ func (q *QueryRunner) insertMany() (common.RuntimeResult, error) {
 		docs = append(docs, v)
 	}
 
-	results, err := coll.InsertMany(context.TODO(), docs)
+	results, err := coll.InsertMany(q.ctx, docs)
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
@@ -471,13 +515,13 @@ func (q *QueryRunner) listCollections() (common.RuntimeResult, error) {
 		}
 	}
 
-	cursor, err := db.ListCollections(context.TODO(), filter)
+	cursor, err := db.ListCollections(q.ctx, filter)
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
 
 	var results []bson.M
-	if err = cursor.All(context.TODO(), &results); err != nil {
+	if err = cursor.All(q.ctx, &results); err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
 	return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{{"result": results}}}, nil
@@ -529,7 +573,7 @@ func (q *QueryRunner) updateMany() (common.RuntimeResult, error) {
 		opts = opts.SetUpsert(parsedUpdateManyOptions.Upsert)
 	}
 
-	results, err := coll.UpdateMany(context.TODO(), filter, update, opts)
+	results, err := coll.UpdateMany(q.ctx, filter, update, opts)
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
@@ -582,7 +626,7 @@ func (q *QueryRunner) updateOne() (common.RuntimeResult, error) {
 		opts = opts.SetUpsert(parsedUpdateOneOptions.Upsert)
 	}
 
-	results, err := coll.UpdateOne(context.TODO(), filter, update, opts)
+	results, err := coll.UpdateOne(q.ctx, filter, update, opts)
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
@@ -602,7 +646,7 @@ func (q *QueryRunner) command() (common.RuntimeResult, error) {
 	}
 
 	var results bson.M
-	if err := db.RunCommand(context.TODO(), doc).Decode(&results); err != nil {
+	if err := db.RunCommand(q.ctx, doc).Decode(&results); err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
 
@@ -0,0 +1,248 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const DEFAULT_SCHEMA_SAMPLE_SIZE = 100
+
+// the type names follow the $type aliases of MongoDB
+var bsonTypeNames = map[bsontype.Type]string{
+	bsontype.Double:           "double",
+	bsontype.String:           "string",
+	bsontype.EmbeddedDocument: "object",
+	bsontype.Array:            "array",
+	bsontype.Binary:           "binData",
+	bsontype.Undefined:        "undefined",
+	bsontype.ObjectID:         "objectId",
+	bsontype.Boolean:          "bool",
+	bsontype.DateTime:         "date",
+	bsontype.Null:             "null",
+	bsontype.Regex:            "regex",
+	bsontype.DBPointer:        "dbPointer",
+	bsontype.JavaScript:       "javascript",
+	bsontype.Symbol:           "symbol",
+	bsontype.CodeWithScope:    "javascriptWithScope",
+	bsontype.Int32:            "int",
+	bsontype.Timestamp:        "timestamp",
+	bsontype.Int64:            "long",
+	bsontype.Decimal128:       "decimal",
+	bsontype.MinKey:           "minKey",
+	bsontype.MaxKey:           "maxKey",
+}
+
+var systemDatabases = map[string]bool{"admin": true, "local": true, "config": true}
+
+type fieldStats struct {
+	count int
+	types map[string]int
+}
+
+// inferSchema lists the collections of databases, infers the fields by sampling documents and lists the indexes
+func inferSchema(ctx context.Context, client *mongo.Client, databaseNames []string, sampleSize int) ([]map[string]interface{}, error) {
+	databases := make([]map[string]interface{}, 0, len(databaseNames))
+	for _, databaseName := range databaseNames {
+		db := client.Database(databaseName)
+		specifications, err := db.ListCollectionSpecifications(ctx, bson.D{})
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(specifications, func(i, j int) bool {
+			return specifications[i].Name < specifications[j].Name
+		})
+		collections := make([]map[string]interface{}, 0, len(specifications))
+		for _, specification := range specifications {
+			if strings.HasPrefix(specification.Name, "system.") {
+				continue
+			}
+			collection := db.Collection(specification.Name)
+			documents, err := sampleDocuments(ctx, collection, sampleSize)
+			if err != nil {
+				return nil, err
+			}
+			collections = append(collections, map[string]interface{}{
+				"name":         specification.Name,
+				"type":         specification.Type,
+				"sampledCount": len(documents),
+				"fields":       inferFields(documents),
+				"indexes":      listIndexes(ctx, collection),
+			})
+		}
+		databases = append(databases, map[string]interface{}{
+			"name":        databaseName,
+			"collections": collections,
+		})
+	}
+	return databases, nil
+}
+
+func listDatabaseNames(ctx context.Context, client *mongo.Client) ([]string, error) {
+	names, err := client.ListDatabaseNames(ctx, bson.D{}, options.ListDatabases().SetNameOnly(true))
+	if err != nil {
+		return nil, err
+	}
+	databaseNames := make([]string, 0, len(names))
+	for _, name := range names {
+		if !systemDatabases[name] {
+			databaseNames = append(databaseNames, name)
+		}
+	}
+	sort.Strings(databaseNames)
+	return databaseNames, nil
+}
+
+// sampleDocuments uses $sample stage, and falls back to find for the collections which not support it, like views on old servers
+func sampleDocuments(ctx context.Context, collection *mongo.Collection, sampleSize int) ([]bson.Raw, error) {
+	cursor, err := collection.Aggregate(ctx, mongo.Pipeline{{{Key: "$sample", Value: bson.D{{Key: "size", Value: sampleSize}}}}})
+	if err != nil {
+		cursor, err = collection.Find(ctx, bson.D{}, options.Find().SetLimit(int64(sampleSize)))
+		if err != nil {
+			return nil, err
+		}
+	}
+	defer cursor.Close(ctx)
+
+	documents := make([]bson.Raw, 0, sampleSize)
+	for cursor.Next(ctx) {
+		documents = append(documents, append(bson.Raw(nil), cursor.Current...))
+	}
+	return documents, cursor.Err()
+}
+
+// inferFields returns the dotted field paths with their types and frequencies,
+// the fields of documents in array are merged into the path of array.
+func inferFields(documents []bson.Raw) []map[string]interface{} {
+	stats := make(map[string]*fieldStats)
+	for _, document := range documents {
+		seen := make(map[string]map[string]bool)
+		walkDocument(document, "", seen)
+		for path, types := range seen {
+			if _, hit := stats[path]; !hit {
+				stats[path] = &fieldStats{types: make(map[string]int)}
+			}
+			stats[path].count++
+			for typeName := range types {
+				stats[path].types[typeName]++
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(stats))
+	for path := range stats {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	fields := make([]map[string]interface{}, 0, len(paths))
+	for _, path := range paths {
+		types := make([]map[string]interface{}, 0, len(stats[path].types))
+		for typeName, count := range stats[path].types {
+			types = append(types, map[string]interface{}{"type": typeName, "count": count})
+		}
+		sort.Slice(types, func(i, j int) bool {
+			if types[i]["count"].(int) != types[j]["count"].(int) {
+				return types[i]["count"].(int) > types[j]["count"].(int)
+			}
+			return types[i]["type"].(string) < types[j]["type"].(string)
+		})
+		fields = append(fields, map[string]interface{}{
+			"name":      path,
+			"types":     types,
+			"count":     stats[path].count,
+			"frequency": float64(stats[path].count) / float64(len(documents)),
+		})
+	}
+	return fields
+}
+
+func walkDocument(document bson.Raw, prefix string, seen map[string]map[string]bool) {
+	elements, err := document.Elements()
+	if err != nil {
+		return
+	}
+	for _, element := range elements {
+		path := element.Key()
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+		walkValue(element.Value(), path, seen)
+	}
+}
+
+func walkValue(value bson.RawValue, path string, seen map[string]map[string]bool) {
+	if _, hit := seen[path]; !hit {
+		seen[path] = make(map[string]bool)
+	}
+	seen[path][bsonTypeName(value.Type)] = true
+	switch value.Type {
+	case bsontype.EmbeddedDocument:
+		walkDocument(value.Document(), path, seen)
+	case bsontype.Array:
+		values, err := value.Array().Values()
+		if err != nil {
+			return
+		}
+		for _, item := range values {
+			if item.Type == bsontype.EmbeddedDocument {
+				walkDocument(item.Document(), path, seen)
+			}
+		}
+	}
+}
+
+func bsonTypeName(t bsontype.Type) string {
+	if name, hit := bsonTypeNames[t]; hit {
+		return name
+	}
+	return t.String()
+}
+
+// listIndexes returns empty list for the views, which have no index
+func listIndexes(ctx context.Context, collection *mongo.Collection) []map[string]interface{} {
+	indexes := make([]map[string]interface{}, 0)
+	specifications, err := collection.Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return indexes
+	}
+	for _, specification := range specifications {
+		var keysDocument bson.D
+		if err := bson.Unmarshal(specification.KeysDocument, &keysDocument); err != nil {
+			continue
+		}
+		keys := make([]map[string]interface{}, 0, len(keysDocument))
+		for _, key := range keysDocument {
+			keys = append(keys, map[string]interface{}{"field": key.Key, "direction": key.Value})
+		}
+		index := map[string]interface{}{
+			"name":   specification.Name,
+			"keys":   keys,
+			"unique": specification.Unique != nil && *specification.Unique,
+			"sparse": specification.Sparse != nil && *specification.Sparse,
+		}
+		if specification.ExpireAfterSeconds != nil {
+			index["expireAfterSeconds"] = *specification.ExpireAfterSeconds
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes
+}
@@ -0,0 +1,104 @@
+package mongodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func mustMarshalDocument(t *testing.T, document bson.D) bson.Raw {
+	raw, err := bson.Marshal(document)
+	assert.Nil(t, err)
+	return raw
+}
+
+func TestWalkDocument(t *testing.T) {
+	document := mustMarshalDocument(t, bson.D{
+		{Key: "_id", Value: primitive.NewObjectID()},
+		{Key: "name", Value: "alice"},
+		{Key: "address", Value: bson.D{{Key: "city", Value: "Paris"}, {Key: "geo", Value: bson.D{{Key: "lat", Value: 48.85}}}}},
+		{Key: "orders", Value: bson.A{
+			bson.D{{Key: "sku", Value: "a-1"}, {Key: "qty", Value: int32(2)}},
+			bson.D{{Key: "sku", Value: "b-2"}, {Key: "qty", Value: int64(1)}},
+			"gift",
+		}},
+	})
+	seen := make(map[string]map[string]bool)
+	walkDocument(document, "", seen)
+
+	assert.Equal(t, map[string]bool{"objectId": true}, seen["_id"])
+	assert.Equal(t, map[string]bool{"string": true}, seen["name"])
+	assert.Equal(t, map[string]bool{"object": true}, seen["address"])
+	assert.Equal(t, map[string]bool{"string": true}, seen["address.city"])
+	assert.Equal(t, map[string]bool{"double": true}, seen["address.geo.lat"])
+	// the documents in array are merged into the array path, scalar items are not walked
+	assert.Equal(t, map[string]bool{"array": true}, seen["orders"])
+	assert.Equal(t, map[string]bool{"string": true}, seen["orders.sku"])
+	assert.Equal(t, map[string]bool{"int": true, "long": true}, seen["orders.qty"])
+	assert.Equal(t, map[string]bool{"object": true}, seen["address.geo"])
+	assert.Equal(t, 9, len(seen))
+}
+
+func TestWalkInvalidDocument(t *testing.T) {
+	seen := make(map[string]map[string]bool)
+	walkDocument(bson.Raw{0x01}, "", seen)
+	assert.Equal(t, 0, len(seen))
+}
+
+func TestInferFields(t *testing.T) {
+	documents := []bson.Raw{
+		mustMarshalDocument(t, bson.D{{Key: "name", Value: "alice"}, {Key: "age", Value: int32(30)}, {Key: "tags", Value: bson.A{"a"}}}),
+		mustMarshalDocument(t, bson.D{{Key: "name", Value: "bob"}, {Key: "age", Value: "unknown"}}),
+		mustMarshalDocument(t, bson.D{{Key: "name", Value: nil}, {Key: "age", Value: int32(20)}, {Key: "createdAt", Value: primitive.NewDateTimeFromTime(time.Unix(0, 0))}}),
+		mustMarshalDocument(t, bson.D{{Key: "name", Value: "dave"}}),
+	}
+	fields := inferFields(documents)
+
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		names = append(names, field["name"].(string))
+	}
+	assert.Equal(t, []string{"age", "createdAt", "name", "tags"}, names)
+
+	age := fields[0]
+	assert.Equal(t, 3, age["count"])
+	assert.Equal(t, 0.75, age["frequency"])
+	assert.Equal(t, []map[string]interface{}{
+		{"type": "int", "count": 2},
+		{"type": "string", "count": 1},
+	}, age["types"])
+
+	createdAt := fields[1]
+	assert.Equal(t, 1, createdAt["count"])
+	assert.Equal(t, 0.25, createdAt["frequency"])
+	assert.Equal(t, []map[string]interface{}{{"type": "date", "count": 1}}, createdAt["types"])
+
+	name := fields[2]
+	assert.Equal(t, 4, name["count"])
+	assert.Equal(t, 1.0, name["frequency"])
+	assert.Equal(t, []map[string]interface{}{
+		{"type": "string", "count": 3},
+		{"type": "null", "count": 1},
+	}, name["types"])
+}
+
+func TestInferFieldsCountsPathOncePerDocument(t *testing.T) {
+	// the same path appeared in many array items of one document is counted once
+	documents := []bson.Raw{
+		mustMarshalDocument(t, bson.D{{Key: "items", Value: bson.A{bson.D{{Key: "id", Value: int32(1)}}, bson.D{{Key: "id", Value: int32(2)}}}}}),
+		mustMarshalDocument(t, bson.D{{Key: "items", Value: bson.A{}}}),
+	}
+	fields := inferFields(documents)
+	assert.Equal(t, 2, len(fields))
+	assert.Equal(t, "items", fields[0]["name"])
+	assert.Equal(t, 2, fields[0]["count"])
+	assert.Equal(t, "items.id", fields[1]["name"])
+	assert.Equal(t, 1, fields[1]["count"])
+	assert.Equal(t, 0.5, fields[1]["frequency"])
+	assert.Equal(t, []map[string]interface{}{{"type": "int", "count": 1}}, fields[1]["types"])
+
+	assert.Equal(t, 0, len(inferFields(nil)))
+}
@@ -25,8 +25,17 @@ import (
 )
 
 type Connector struct {
-	Resource Options
-	Action   Query
+	Resource    Options
+	Action      Query
+	resumeToken string
+}
+
+func (m *Connector) SetResumeToken(resumeToken string) {
+	m.resumeToken = resumeToken
+}
+
+func (m *Connector) ExportResumeToken() string {
+	return m.resumeToken
 }
 
 func (m *Connector) ValidateResourceOptions(resourceOptions map[string]interface{}) (common.ValidateResult, error) {
@@ -74,6 +83,18 @@ func (m *Connector) ValidateActionTemplate(actionOptions map[string]interface{})
 	if err := validate.Struct(m.Action); err != nil {
 		return common.ValidateResult{Valid: false}, err
 	}
+
+	// validate transaction and watch options
+	switch m.Action.ActionType {
+	case ACTION_TYPE_TRANSACTION:
+		if _, err := decodeTransactionContent(m.Action.TypeContent); err != nil {
+			return common.ValidateResult{Valid: false}, err
+		}
+	case ACTION_TYPE_WATCH:
+		if _, err := decodeWatchContent(m.Action.TypeContent); err != nil {
+			return common.ValidateResult{Valid: false}, err
+		}
+	}
 	return common.ValidateResult{Valid: true}, nil
 }
 
@@ -93,10 +114,41 @@ func (m *Connector) TestConnection(resourceOptions map[string]interface{}) (comm
 }
 
 func (m *Connector) GetMetaInfo(resourceOptions map[string]interface{}) (common.MetaInfoResult, error) {
+	// get mongodb connection
+	client, err := m.getConnectionWithOptions(resourceOptions)
+	if err != nil {
+		return common.MetaInfoResult{Success: false}, err
+	}
+	defer client.Disconnect(context.Background())
+
+	// only the database in options is inferred, or all the databases which are not system databases
+	db, err := m.exportDatabaseName()
+	if err != nil {
+		return common.MetaInfoResult{Success: false}, err
+	}
+	databaseNames := []string{db}
+	if db == "" {
+		if databaseNames, err = listDatabaseNames(context.Background(), client); err != nil {
+			return common.MetaInfoResult{Success: false}, err
+		}
+	}
+
+	// infer schema by sampling documents
+	sampleSize := m.Resource.SchemaSampleSize
+	if sampleSize <= 0 {
+		sampleSize = DEFAULT_SCHEMA_SAMPLE_SIZE
+	}
+	databases, err := inferSchema(context.Background(), client, databaseNames, sampleSize)
+	if err != nil {
+		return common.MetaInfoResult{Success: false}, err
+	}
 
 	return common.MetaInfoResult{
 		Success: true,
-		Schema:  nil,
+		Schema: map[string]interface{}{
+			"databases":  databases,
+			"sampleSize": sampleSize,
+		},
 	}, nil
 }
 
@@ -108,63 +160,42 @@ func (m *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 	}
 	defer client.Disconnect(context.Background())
 
-	db := ""
+	db, err := m.exportDatabaseName()
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	if db == "" {
+		db = "test"
+	}
+
+	queryRunner := QueryRunner{ctx: context.Background(), client: client, query: m.Action, db: db, resumeToken: m.resumeToken}
+	result, err := queryRunner.run()
+
+	// keep the resume token of change stream for next run
+	if queryRunner.nextResumeToken != "" && err == nil {
+		m.resumeToken = queryRunner.nextResumeToken
+	}
+	return result, err
+}
+
+// exportDatabaseName returns the database in resource options, it is empty when not given
+func (m *Connector) exportDatabaseName() (string, error) {
 	if m.Resource.ConfigType == GUI_OPTIONS {
 		var mOptions GUIOptions
 		if err := mapstructure.Decode(m.Resource.ConfigContent, &mOptions); err != nil {
-			return common.RuntimeResult{Success: false}, err
+			return "", err
 		}
-		db = mOptions.DatabaseName
+		return mOptions.DatabaseName, nil
 	} else if m.Resource.ConfigType == URI_OPTIONS {
 		mOptions := URIOptions{}
 		if err := mapstructure.Decode(m.Resource.ConfigContent, &mOptions); err != nil {
-			return common.RuntimeResult{Success: false}, err
+			return "", err
 		}
-		uri := mOptions.URI
-		matchedStrs, err := connstring.Parse(uri)
+		matchedStrs, err := connstring.Parse(mOptions.URI)
 		if err != nil {
-			return common.RuntimeResult{Success: false}, err
+			return "", err
 		}
-		db = matchedStrs.Database
-	}
-	if db == "" {
-		db = "test"
+		return matchedStrs.Database, nil
 	}
-
-	var result common.RuntimeResult
-	queryRunner := QueryRunner{client: client, query: m.Action, db: db}
-	switch m.Action.ActionType {
-	case "aggregate":
-		result, err = queryRunner.aggregate()
-	case "bulkWrite":
-		result, err = queryRunner.bulkWrite()
-	case "count":
-		result, err = queryRunner.count()
-	case "deleteMany":
-		result, err = queryRunner.deleteMany()
-	case "deleteOne":
-		result, err = queryRunner.deleteOne()
-	case "distinct":
-		result, err = queryRunner.distinct()
-	case "find":
-		result, err = queryRunner.find()
-	case "findOne":
-		result, err = queryRunner.findOne()
-	case "findOneAndUpdate":
-		result, err = queryRunner.findOneAndUpdate()
-	case "insertOne":
-		result, err = queryRunner.insertOne()
-	case "insertMany":
-		result, err = queryRunner.insertMany()
-	case "listCollections":
-		result, err = queryRunner.listCollections()
-	case "updateMany":
-		result, err = queryRunner.updateMany()
-	case "updateOne":
-		result, err = queryRunner.updateOne()
-	case "command":
-		result, err = queryRunner.command()
-	}
-
-	return result, err
+	return "", nil
 }
@@ -0,0 +1,113 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/mitchellh/mapstructure"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+const ACTION_TYPE_TRANSACTION = "transaction"
+
+// the operations can be run in transaction
+var transactionActionTypes = map[string]bool{
+	"aggregate":        true,
+	"bulkWrite":        true,
+	"count":            true,
+	"deleteMany":       true,
+	"deleteOne":        true,
+	"distinct":         true,
+	"find":             true,
+	"findOne":          true,
+	"findOneAndUpdate": true,
+	"insertOne":        true,
+	"insertMany":       true,
+	"updateMany":       true,
+	"updateOne":        true,
+}
+
+func decodeTransactionContent(typeContent map[string]interface{}) (*TransactionContent, error) {
+	var transactionOptions TransactionContent
+	if err := mapstructure.Decode(typeContent, &transactionOptions); err != nil {
+		return nil, err
+	}
+	validate := validator.New()
+	if err := validate.Struct(transactionOptions); err != nil {
+		return nil, err
+	}
+	for i, operation := range transactionOptions.Operations {
+		if !transactionActionTypes[operation.ActionType] {
+			return nil, fmt.Errorf("operation %d: %s is not supported in transaction", i+1, operation.ActionType)
+		}
+	}
+	return &transactionOptions, nil
+}
+
+// transaction commits when all the operations succeeded, the driver retries the transient transaction errors
+func (q *QueryRunner) transaction() (common.RuntimeResult, error) {
+	transactionOptions, err := decodeTransactionContent(q.query.TypeContent)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	opts := options.Transaction()
+	if transactionOptions.MaxCommitTime > 0 {
+		maxCommitTime := time.Duration(transactionOptions.MaxCommitTime) * time.Millisecond
+		opts = opts.SetMaxCommitTime(&maxCommitTime)
+	}
+	if transactionOptions.ReadConcern != "" {
+		opts = opts.SetReadConcern(readconcern.New(readconcern.Level(transactionOptions.ReadConcern)))
+	}
+	if transactionOptions.WriteConcern == "majority" {
+		opts = opts.SetWriteConcern(writeconcern.New(writeconcern.WMajority()))
+	} else if transactionOptions.WriteConcern != "" {
+		opts = opts.SetWriteConcern(writeconcern.New(writeconcern.WTagSet(transactionOptions.WriteConcern)))
+	}
+
+	session, err := q.client.StartSession()
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	defer session.EndSession(q.ctx)
+
+	results, err := session.WithTransaction(q.ctx, func(sessionContext mongo.SessionContext) (interface{}, error) {
+		operationResults := make([]map[string]interface{}, 0, len(transactionOptions.Operations))
+		for i, operation := range transactionOptions.Operations {
+			operationRunner := QueryRunner{ctx: sessionContext, client: q.client, query: operation, db: q.db}
+			operationResult, err := operationRunner.run()
+			if err != nil {
+				return nil, fmt.Errorf("operation %d: %w", i+1, err)
+			}
+			operationResults = append(operationResults, map[string]interface{}{
+				"actionType": operation.ActionType,
+				"collection": operation.Collection,
+				"result":     operationResult.Rows,
+			})
+		}
+		return operationResults, nil
+	}, opts)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{{"result": results}}}, nil
+}
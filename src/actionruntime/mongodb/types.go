@@ -28,9 +28,10 @@ var (
 )
 
 type Options struct {
-	ConfigType    string                 `validate:"required,oneof=gui uri"`
-	ConfigContent map[string]interface{} `validate:"required"`
-	SSL           SSLOptions
+	ConfigType       string                 `validate:"required,oneof=gui uri"`
+	ConfigContent    map[string]interface{} `validate:"required"`
+	SSL              SSLOptions
+	SchemaSampleSize int `validate:"gte=0"`
 }
 
 type GUIOptions struct {
@@ -134,6 +135,23 @@ type CommandContent struct {
 	Document string
 }
 
+// TransactionContent runs the operations in order in one session, all of them are rolled back when any failed
+type TransactionContent struct {
+	Operations    []Query `validate:"required,gt=0,dive"`
+	MaxCommitTime int     `validate:"gte=0"`
+	ReadConcern   string  `validate:"omitempty,oneof=local majority snapshot"`
+	WriteConcern  string
+}
+
+// WatchContent tails the change stream until the max events received or the max await time elapsed
+type WatchContent struct {
+	Pipeline     string
+	FullDocument string `validate:"omitempty,oneof=default updateLookup whenAvailable required"`
+	MaxAwaitTime int    `validate:"gte=0"`
+	MaxEvents    int    `validate:"gte=0"`
+	ResumeAfter  string
+}
+
 type AggregateOptions struct {
 	Collation *options.Collation
 	Hint      interface{}
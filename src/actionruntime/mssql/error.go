@@ -0,0 +1,52 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mssql
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	mssql "github.com/microsoft/go-mssqldb"
+)
+
+func (m *Connector) ClassifyError(err error) *common.ConnectorError {
+	var mssqlErr mssql.Error
+	if !errors.As(err, &mssqlErr) {
+		return common.ClassifyNetworkError(err)
+	}
+	var connectorError *common.ConnectorError
+	switch mssqlErr.Number {
+	case 102, 105, 156, 170:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_SYNTAX, err)
+	case 4060, 18456:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_AUTH, err)
+	case 229, 230, 262, 297, 300:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_PERMISSION, err)
+	case 207, 208:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_NOT_FOUND, err)
+	case 515, 547, 2601, 2627:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_CONSTRAINT, err)
+	case 1222:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_TIMEOUT, err).SetRetryable(true)
+	default:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_UNKNOWN, err)
+	}
+	// chosen as deadlock victim
+	if mssqlErr.Number == 1205 {
+		connectorError.SetRetryable(true)
+	}
+	return connectorError.SetVendorCode(strconv.Itoa(int(mssqlErr.Number))).SetLineNumber(int(mssqlErr.LineNo)).SetMessage(mssqlErr.Message)
+}
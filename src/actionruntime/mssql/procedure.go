@@ -0,0 +1,110 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	mssql "github.com/microsoft/go-mssqldb"
+)
+
+// callProcedure sends the procedure as an RPC call, the go-mssqldb driver treats a bare identifier with arguments as procedure name.
+// OUT parameters are only filled after all of the result sets have been consumed and the rows closed.
+func (m *Connector) callProcedure(db *sql.DB) (common.RuntimeResult, error) {
+	procedure := m.ActionOpts.Procedure
+	if err := procedure.Validate(); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// build arguments
+	args := make([]interface{}, 0)
+	outputDests := make(map[string]interface{})
+	placeholders := make([]string, 0)
+	for _, param := range procedure.Parameters {
+		if param.IsCursor() {
+			return common.RuntimeResult{Success: false}, errors.New("mssql does not support cursor output parameter, return a result set instead")
+		}
+		if param.Name == "" {
+			return common.RuntimeResult{Success: false}, errors.New("mssql procedure parameter must have a name")
+		}
+		name := strings.TrimPrefix(param.Name, "@")
+		placeholders = append(placeholders, "@"+name)
+		if param.IsOutput() {
+			if procedure.IsFunction() {
+				return common.RuntimeResult{Success: false}, fmt.Errorf("mssql function does not support output parameter \"%s\"", param.Name)
+			}
+			dest, errInNewDest := param.NewOutputDest()
+			if errInNewDest != nil {
+				return common.RuntimeResult{Success: false}, errInNewDest
+			}
+			outputDests[param.Name] = dest
+			args = append(args, sql.Named(name, sql.Out{Dest: dest, In: param.IsInOut()}))
+			continue
+		}
+		value, errInConvert := param.ExportTypedValue()
+		if errInConvert != nil {
+			return common.RuntimeResult{Success: false}, errInConvert
+		}
+		args = append(args, sql.Named(name, value))
+	}
+	statement := procedure.Name
+	var returnStatus mssql.ReturnStatus
+	if procedure.IsFunction() {
+		statement = fmt.Sprintf("SELECT %s(%s) AS %s", procedure.Name, strings.Join(placeholders, ", "), common.PROCEDURE_RESULT_FIELD_RETURN_VALUE)
+	} else {
+		args = append(args, &returnStatus)
+	}
+
+	// call it, procedure may return multiple result sets
+	procedureResult := common.NewProcedureResult()
+	rows, err := db.QueryContext(context.Background(), statement, args...)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	for {
+		resultSet, err := common.RetrieveToMap(rows)
+		if err != nil {
+			rows.Close()
+			return common.RuntimeResult{Success: false}, err
+		}
+		procedureResult.AppendResultSet(resultSet)
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// collect output
+	for name, dest := range outputDests {
+		procedureResult.SetOutParameter(name, common.ExportOutputDestValue(dest))
+	}
+	if procedure.IsFunction() {
+		if len(procedureResult.ResultSets) > 0 && len(procedureResult.ResultSets[0]) > 0 {
+			procedureResult.SetReturnValue(procedureResult.ResultSets[0][0][common.PROCEDURE_RESULT_FIELD_RETURN_VALUE])
+		}
+	} else {
+		procedureResult.SetReturnValue(int32(returnStatus))
+	}
+
+	return procedureResult.ExportRuntimeResult(), nil
+}
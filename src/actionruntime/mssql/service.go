@@ -30,6 +30,25 @@ import (
 type Connector struct {
 	ResourceOpts Resource
 	ActionOpts   Action
+	resultStream common.ResultStream
+}
+
+func (m *Connector) SetResultStream(stream common.ResultStream) {
+	m.resultStream = stream
+}
+
+func (m *Connector) IsReadOnlyAction(actionOptions map[string]interface{}) bool {
+	mode, _ := actionOptions["mode"].(string)
+	query, _ := actionOptions[FIELD_QUERY].(map[string]interface{})
+	sql, _ := query[FIELD_SQL].(string)
+	return common.IsReadOnlySQLQuery(mode, sql)
+}
+
+func (m *Connector) IsWriteAction(actionOptions map[string]interface{}) bool {
+	mode, _ := actionOptions["mode"].(string)
+	query, _ := actionOptions[FIELD_QUERY].(map[string]interface{})
+	sql, _ := query[FIELD_SQL].(string)
+	return common.IsWriteSQLQuery(mode, sql)
 }
 
 func (m *Connector) ValidateResourceOptions(resourceOptions map[string]interface{}) (common.ValidateResult, error) {
@@ -59,6 +78,13 @@ func (m *Connector) ValidateActionTemplate(actionOptions map[string]interface{})
 		return common.ValidateResult{Valid: false}, err
 	}
 
+	// validate procedure call
+	if m.ActionOpts.IsProcedureMode() {
+		if err := m.ActionOpts.Procedure.Validate(); err != nil {
+			return common.ValidateResult{Valid: false}, err
+		}
+	}
+
 	return common.ValidateResult{Valid: true}, nil
 }
 
@@ -112,6 +138,11 @@ func (m *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 		return common.RuntimeResult{Success: false}, err
 	}
 
+	// call stored procedure or function
+	if m.ActionOpts.IsProcedureMode() {
+		return m.callProcedure(db)
+	}
+
 	// set context field
 	errInSetRawQuery := m.ActionOpts.SetRawQueryAndContext(rawActionOptions)
 	if errInSetRawQuery != nil {
@@ -153,7 +184,7 @@ func (m *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 			if err != nil {
 				return queryResult, err
 			}
-			mapRes, err := common.RetrieveToMap(rows)
+			mapRes, err := common.RetrieveToMapOrStream(rows, m.resultStream)
 			if err != nil {
 				return queryResult, err
 			}
@@ -165,7 +196,7 @@ func (m *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 			if err != nil {
 				return queryResult, err
 			}
-			mapRes, err := common.RetrieveToMap(rows)
+			mapRes, err := common.RetrieveToMapOrStream(rows, m.resultStream)
 			if err != nil {
 				return queryResult, err
 			}
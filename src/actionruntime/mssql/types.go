@@ -45,16 +45,21 @@ type SSLOptions struct {
 }
 
 type Action struct {
-	Query    map[string]interface{} `validate:"required"`
-	Mode     string                 `validate:"required,oneof=gui sql sql-safe"`
-	RawQuery string
-	Context  map[string]interface{}
+	Query     map[string]interface{} `validate:"required_unless=Mode procedure"`
+	Mode      string                 `validate:"required,oneof=gui sql sql-safe procedure"`
+	Procedure common.Procedure
+	RawQuery  string
+	Context   map[string]interface{}
 }
 
 func (q *Action) IsSafeMode() bool {
 	return q.Mode == common.MODE_SQL_SAFE
 }
 
+func (q *Action) IsProcedureMode() bool {
+	return q.Mode == common.MODE_PROCEDURE
+}
+
 type GUIQuery struct {
 	Table   string
 	Type    string
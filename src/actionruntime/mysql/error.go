@@ -0,0 +1,72 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+)
+
+var syntaxErrorLineRegexp = regexp.MustCompile(`at line (\d+)$`)
+
+func (m *MySQLConnector) ClassifyError(err error) *common.ConnectorError {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return common.ClassifyNetworkError(err)
+	}
+	var connectorError *common.ConnectorError
+	switch mysqlErr.Number {
+	case 1064, 1149:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_SYNTAX, err)
+	case 1045, 1698:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_AUTH, err)
+	case 1044, 1142, 1143, 1227:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_PERMISSION, err)
+	case 1046, 1049, 1054, 1146, 1305:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_NOT_FOUND, err)
+	case 1048, 1062, 1216, 1217, 1451, 1452:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_CONSTRAINT, err)
+	case 1205, 3024:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_TIMEOUT, err).SetRetryable(true)
+	case 1040, 1053:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_CONNECTION, err).SetRetryable(true)
+	default:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_UNKNOWN, err)
+	}
+	// deadlock found when trying to get lock
+	if mysqlErr.Number == 1213 {
+		connectorError.SetRetryable(true)
+	}
+	connectorError.SetVendorCode(strconv.Itoa(int(mysqlErr.Number))).SetMessage(mysqlErr.Message)
+
+	// syntax error message looks like "You have an error in your SQL syntax; check the manual ... to use near 'FORM users' at line 1",
+	// keep the "SQL syntax error" feedback message and "SQL syntax error ..." error data message for compatibility.
+	if mysqlErr.Number == 1064 {
+		connectorError.SetFeedbackMessage("SQL syntax error")
+		if matched := syntaxErrorLineRegexp.FindStringSubmatch(mysqlErr.Message); len(matched) == 2 {
+			lineNumber, _ := strconv.Atoi(matched[1])
+			connectorError.SetLineNumber(lineNumber)
+		}
+		if index := strings.Index(mysqlErr.Message, "to use"); index != -1 {
+			connectorError.SetMessage("SQL syntax error" + mysqlErr.Message[index+len("to use"):])
+		}
+	}
+	return connectorError
+}
@@ -0,0 +1,30 @@
+package mysql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifySyntaxError(t *testing.T) {
+	err := fmt.Errorf("run: %w", &mysql.MySQLError{
+		Number:   1064,
+		SQLState: [5]byte{'4', '2', '0', '0', '0'},
+		Message:  "You have an error in your SQL syntax; check the manual that corresponds to your MySQL server version for the right syntax to use near 'FORM users' at line 2",
+	})
+	connectorError := (&MySQLConnector{}).ClassifyError(err)
+	assert.Equal(t, common.CONNECTOR_ERROR_CATEGORY_SYNTAX, connectorError.Category)
+	assert.Equal(t, "1064", connectorError.VendorCode)
+	assert.Equal(t, 2, connectorError.LineNumber)
+	assert.Equal(t, "SQL syntax error near 'FORM users' at line 2", connectorError.Message)
+	assert.Equal(t, "SQL syntax error", connectorError.ExportFeedbackMessage("run action error: "+err.Error()))
+}
+
+func TestClassifyDeadlockError(t *testing.T) {
+	connectorError := (&MySQLConnector{}).ClassifyError(&mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"})
+	assert.True(t, connectorError.Retryable)
+	assert.Equal(t, "run action error", connectorError.ExportFeedbackMessage("run action error"))
+}
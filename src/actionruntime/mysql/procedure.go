@@ -0,0 +1,130 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+)
+
+const (
+	PROCEDURE_SESSION_VARIABLE_PREFIX = "@illa_procedure_param_"
+)
+
+// callProcedure binds OUT and INOUT parameters to session variables and selects them after the call,
+// so the whole call must happen on a single connection.
+func (m *MySQLConnector) callProcedure(db *sql.DB) (common.RuntimeResult, error) {
+	procedure := m.Action.Procedure
+	if err := procedure.Validate(); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	defer conn.Close()
+
+	// build statement
+	placeholders := make([]string, 0)
+	args := make([]interface{}, 0)
+	outputVariables := make([]string, 0)
+	outputParams := make([]*common.ProcedureParameter, 0)
+	for serial, param := range procedure.Parameters {
+		if !param.IsOutput() {
+			value, errInConvert := param.ExportTypedValue()
+			if errInConvert != nil {
+				return common.RuntimeResult{Success: false}, errInConvert
+			}
+			placeholders = append(placeholders, "?")
+			args = append(args, value)
+			continue
+		}
+		if procedure.IsFunction() {
+			return common.RuntimeResult{Success: false}, fmt.Errorf("mysql function does not support output parameter \"%s\"", param.Name)
+		}
+		variable := fmt.Sprintf("%s%d", PROCEDURE_SESSION_VARIABLE_PREFIX, serial)
+		if param.IsInOut() {
+			value, errInConvert := param.ExportTypedValue()
+			if errInConvert != nil {
+				return common.RuntimeResult{Success: false}, errInConvert
+			}
+			if _, err := conn.ExecContext(ctx, fmt.Sprintf("SET %s = ?", variable), value); err != nil {
+				return common.RuntimeResult{Success: false}, err
+			}
+		}
+		placeholders = append(placeholders, variable)
+		outputVariables = append(outputVariables, variable)
+		outputParams = append(outputParams, param)
+	}
+	statement := fmt.Sprintf("CALL %s(%s)", procedure.Name, strings.Join(placeholders, ", "))
+	if procedure.IsFunction() {
+		statement = fmt.Sprintf("SELECT %s(%s) AS %s", procedure.Name, strings.Join(placeholders, ", "), common.PROCEDURE_RESULT_FIELD_RETURN_VALUE)
+	}
+
+	// call it, procedure may return multiple result sets
+	procedureResult := common.NewProcedureResult()
+	rows, err := conn.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	for {
+		resultSet, err := common.RetrieveToMap(rows)
+		if err != nil {
+			rows.Close()
+			return common.RuntimeResult{Success: false}, err
+		}
+		procedureResult.AppendResultSet(resultSet)
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	if procedure.IsFunction() && len(procedureResult.ResultSets) > 0 && len(procedureResult.ResultSets[0]) > 0 {
+		procedureResult.SetReturnValue(procedureResult.ResultSets[0][0][common.PROCEDURE_RESULT_FIELD_RETURN_VALUE])
+	}
+
+	// fetch output values
+	if len(outputVariables) > 0 {
+		outputRows, err := conn.QueryContext(ctx, "SELECT "+strings.Join(outputVariables, ", "))
+		if err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		defer outputRows.Close()
+		if outputRows.Next() {
+			values := make([]interface{}, len(outputVariables))
+			valuePointers := make([]interface{}, len(outputVariables))
+			for i := range values {
+				valuePointers[i] = &values[i]
+			}
+			if err := outputRows.Scan(valuePointers...); err != nil {
+				return common.RuntimeResult{Success: false}, err
+			}
+			for i, param := range outputParams {
+				procedureResult.SetOutParameter(param.Name, common.ExportOutputDestValue(values[i]))
+			}
+		}
+	}
+
+	return procedureResult.ExportRuntimeResult(), nil
+}
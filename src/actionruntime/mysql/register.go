@@ -0,0 +1,42 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/illacloud/builder-backend/src/utils/jsonschema"
+	"github.com/illacloud/builder-backend/src/utils/resourcelist"
+)
+
+func init() {
+	common.RegisterConnector(&common.ConnectorDescriptor{
+		Name: resourcelist.TYPE_MYSQL,
+		ID:   resourcelist.TYPE_MYSQL_ID,
+		Aliases: []*common.ConnectorAlias{
+			common.NewConnectorAlias(resourcelist.TYPE_MARIADB),
+			common.NewConnectorAlias(resourcelist.TYPE_TIDB),
+		},
+		Capability: common.ConnectorCapability{
+			MetaInfo:       true,
+			TestConnection: true,
+			GUIMode:        true,
+		},
+		ResourceOptionsSchema: jsonschema.Reflect(&MySQLOptions{}),
+		ActionTemplateSchema:  jsonschema.Reflect(&MySQLQuery{}),
+		Build: func() common.DataConnector {
+			return &MySQLConnector{}
+		},
+	})
+}
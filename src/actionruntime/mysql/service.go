@@ -26,8 +26,25 @@ import (
 )
 
 type MySQLConnector struct {
-	Resource MySQLOptions
-	Action   MySQLQuery
+	Resource     MySQLOptions
+	Action       MySQLQuery
+	resultStream common.ResultStream
+}
+
+func (m *MySQLConnector) SetResultStream(stream common.ResultStream) {
+	m.resultStream = stream
+}
+
+func (m *MySQLConnector) IsReadOnlyAction(actionOptions map[string]interface{}) bool {
+	mode, _ := actionOptions["mode"].(string)
+	query, _ := actionOptions["query"].(string)
+	return common.IsReadOnlySQLQuery(mode, query)
+}
+
+func (m *MySQLConnector) IsWriteAction(actionOptions map[string]interface{}) bool {
+	mode, _ := actionOptions["mode"].(string)
+	query, _ := actionOptions["query"].(string)
+	return common.IsWriteSQLQuery(mode, query)
 }
 
 func (m *MySQLConnector) ValidateResourceOptions(resourceOptions map[string]interface{}) (common.ValidateResult, error) {
@@ -55,6 +72,13 @@ func (m *MySQLConnector) ValidateActionTemplate(actionOptions map[string]interfa
 	if err := validate.Struct(m.Action); err != nil {
 		return common.ValidateResult{Valid: false}, err
 	}
+
+	// validate procedure call
+	if m.Action.IsProcedureMode() {
+		if err := m.Action.Procedure.Validate(); err != nil {
+			return common.ValidateResult{Valid: false}, err
+		}
+	}
 	return common.ValidateResult{Valid: true}, nil
 }
 
@@ -107,6 +131,11 @@ func (m *MySQLConnector) Run(resourceOptions map[string]interface{}, actionOptio
 		return common.RuntimeResult{Success: false}, err
 	}
 
+	// call stored procedure or function
+	if m.Action.IsProcedureMode() {
+		return m.callProcedure(db)
+	}
+
 	// set context field
 	errInSetRawQuery := m.Action.SetRawQueryAndContext(rawActionOptions)
 	if errInSetRawQuery != nil {
@@ -138,7 +167,7 @@ func (m *MySQLConnector) Run(resourceOptions map[string]interface{}, actionOptio
 		if err != nil {
 			return queryResult, err
 		}
-		mapRes, err := common.RetrieveToMap(rows)
+		mapRes, err := common.RetrieveToMapOrStream(rows, m.resultStream)
 		if err != nil {
 			return queryResult, err
 		}
@@ -150,7 +179,7 @@ func (m *MySQLConnector) Run(resourceOptions map[string]interface{}, actionOptio
 		if err != nil {
 			return queryResult, err
 		}
-		mapRes, err := common.RetrieveToMap(rows)
+		mapRes, err := common.RetrieveToMapOrStream(rows, m.resultStream)
 		if err != nil {
 			return queryResult, err
 		}
@@ -42,16 +42,21 @@ type SSLOptions struct {
 }
 
 type MySQLQuery struct {
-	Mode     string `validate:"required,oneof=gui sql sql-safe"`
-	Query    string
-	RawQuery string
-	Context  map[string]interface{}
+	Mode      string `validate:"required,oneof=gui sql sql-safe procedure"`
+	Query     string
+	Procedure common.Procedure
+	RawQuery  string
+	Context   map[string]interface{}
 }
 
 func (q *MySQLQuery) IsSafeMode() bool {
 	return q.Mode == common.MODE_SQL_SAFE
 }
 
+func (q *MySQLQuery) IsProcedureMode() bool {
+	return q.Mode == common.MODE_PROCEDURE
+}
+
 func (q *MySQLQuery) SetRawQueryAndContext(rawTemplate map[string]interface{}) error {
 	queryRaw, hit := rawTemplate[FIELD_QUERY]
 	if !hit {
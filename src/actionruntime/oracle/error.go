@@ -0,0 +1,54 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracle
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/sijms/go-ora/v2/network"
+)
+
+func (o *Connector) ClassifyError(err error) *common.ConnectorError {
+	var oracleErr *network.OracleError
+	if !errors.As(err, &oracleErr) {
+		return common.ClassifyNetworkError(err)
+	}
+	var connectorError *common.ConnectorError
+	switch oracleErr.ErrCode {
+	case 900, 907, 923, 933, 936:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_SYNTAX, err)
+	case 904, 942:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_NOT_FOUND, err)
+	case 1017:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_AUTH, err)
+	case 1031:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_PERMISSION, err)
+	case 1, 1400, 2291, 2292:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_CONSTRAINT, err)
+	case 1013:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_TIMEOUT, err)
+	case 3113, 3114, 12170, 12514, 12537, 12541:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_CONNECTION, err).SetRetryable(true)
+	default:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_UNKNOWN, err)
+	}
+	// deadlock detected while waiting for resource
+	if oracleErr.ErrCode == 60 {
+		connectorError.SetRetryable(true)
+	}
+	return connectorError.SetVendorCode(fmt.Sprintf("ORA-%05d", oracleErr.ErrCode))
+}
@@ -0,0 +1,118 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracle
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	go_ora "github.com/sijms/go-ora/v2"
+)
+
+// callProcedure wraps the call into an anonymous PL/SQL block, function return value is bound to the first placeholder.
+// The ref cursors are fetched on the same connection before it is released.
+func (o *Connector) callProcedure(db *sql.DB) (common.RuntimeResult, error) {
+	procedure := o.actionOptions.Procedure
+	if err := procedure.Validate(); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	defer conn.Close()
+
+	// build arguments
+	args := make([]interface{}, 0)
+	outputDests := make(map[string]interface{})
+	cursors := make(map[string]*go_ora.RefCursor)
+	var returnDest interface{}
+	if procedure.IsFunction() {
+		var errInNewDest error
+		returnParam := procedure.ExportReturnParameter()
+		returnDest, errInNewDest = returnParam.NewOutputDest()
+		if errInNewDest != nil {
+			return common.RuntimeResult{Success: false}, errInNewDest
+		}
+		args = append(args, go_ora.Out{Dest: returnDest, Size: returnParam.ExportSize()})
+	}
+	placeholders := make([]string, 0)
+	for _, param := range procedure.Parameters {
+		placeholders = append(placeholders, fmt.Sprintf(":%d", len(args)+1))
+		if param.IsCursor() {
+			cursor := &go_ora.RefCursor{}
+			cursors[param.Name] = cursor
+			args = append(args, sql.Out{Dest: cursor})
+			continue
+		}
+		if param.IsOutput() {
+			dest, errInNewDest := param.NewOutputDest()
+			if errInNewDest != nil {
+				return common.RuntimeResult{Success: false}, errInNewDest
+			}
+			outputDests[param.Name] = dest
+			args = append(args, go_ora.Out{Dest: dest, Size: param.ExportSize(), In: param.IsInOut()})
+			continue
+		}
+		value, errInConvert := param.ExportTypedValue()
+		if errInConvert != nil {
+			return common.RuntimeResult{Success: false}, errInConvert
+		}
+		args = append(args, value)
+	}
+	statement := fmt.Sprintf("BEGIN %s(%s); END;", procedure.Name, strings.Join(placeholders, ", "))
+	if procedure.IsFunction() {
+		statement = fmt.Sprintf("BEGIN :1 := %s(%s); END;", procedure.Name, strings.Join(placeholders, ", "))
+	}
+
+	// call it
+	if _, err := conn.ExecContext(ctx, statement, args...); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// collect output
+	procedureResult := common.NewProcedureResult()
+	for name, dest := range outputDests {
+		procedureResult.SetOutParameter(name, common.ExportOutputDestValue(dest))
+	}
+	if procedure.IsFunction() {
+		procedureResult.SetReturnValue(common.ExportOutputDestValue(returnDest))
+	}
+	for _, param := range procedure.Parameters {
+		cursor, hit := cursors[param.Name]
+		if !hit {
+			continue
+		}
+		cursorRows, err := cursor.Query()
+		if err != nil {
+			cursor.Close()
+			return common.RuntimeResult{Success: false}, err
+		}
+		cursorResultSet, err := common.RetrieveToMapByDriverRows(cursorRows)
+		cursorRows.Close()
+		cursor.Close()
+		if err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		procedureResult.SetCursor(param.Name, cursorResultSet)
+	}
+
+	return procedureResult.ExportRuntimeResult(), nil
+}
@@ -28,6 +28,25 @@ import (
 type Connector struct {
 	resourceOptions Resource
 	actionOptions   Action
+	resultStream    common.ResultStream
+}
+
+func (o *Connector) SetResultStream(stream common.ResultStream) {
+	o.resultStream = stream
+}
+
+func (o *Connector) IsReadOnlyAction(actionOptions map[string]interface{}) bool {
+	mode, _ := actionOptions["mode"].(string)
+	opts, _ := actionOptions["opts"].(map[string]interface{})
+	raw, _ := opts["raw"].(string)
+	return common.IsReadOnlySQLQuery(mode, raw)
+}
+
+func (o *Connector) IsWriteAction(actionOptions map[string]interface{}) bool {
+	mode, _ := actionOptions["mode"].(string)
+	opts, _ := actionOptions["opts"].(map[string]interface{})
+	raw, _ := opts["raw"].(string)
+	return common.IsWriteSQLQuery(mode, raw)
 }
 
 func (o *Connector) ValidateResourceOptions(resourceOptions map[string]interface{}) (common.ValidateResult, error) {
@@ -57,6 +76,13 @@ func (o *Connector) ValidateActionTemplate(actionOptions map[string]interface{})
 		return common.ValidateResult{Valid: false}, err
 	}
 
+	// validate procedure call
+	if o.actionOptions.IsProcedureMode() {
+		if err := o.actionOptions.Procedure.Validate(); err != nil {
+			return common.ValidateResult{Valid: false}, err
+		}
+	}
+
 	return common.ValidateResult{Valid: true}, nil
 }
 
@@ -108,6 +134,10 @@ func (o *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 	if err := mapstructure.Decode(actionOptions, &o.actionOptions); err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
+	// call stored procedure or function, it does not carry raw query
+	if o.actionOptions.Mode == ACTION_PROCEDURE_MODE {
+		return o.callProcedure(db)
+	}
 	// set context field
 	errInSetRawQuery := o.actionOptions.SetRawQueryAndContext(rawActionOptions)
 	if errInSetRawQuery != nil {
@@ -149,7 +179,7 @@ func (o *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 			if err != nil {
 				return queryResult, err
 			}
-			mapRes, err := common.RetrieveToMap(rows)
+			mapRes, err := common.RetrieveToMapOrStream(rows, o.resultStream)
 			if err != nil {
 				return queryResult, err
 			}
@@ -162,7 +192,7 @@ func (o *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 			if err != nil {
 				return queryResult, err
 			}
-			mapRes, err := common.RetrieveToMap(rows)
+			mapRes, err := common.RetrieveToMapOrStream(rows, o.resultStream)
 			if err != nil {
 				return queryResult, err
 			}
@@ -0,0 +1,149 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oracle9i
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	go_ora_v1 "github.com/illacloud/go-ora-v1"
+)
+
+// callProcedure wraps the call into an anonymous PL/SQL block, function return value is bound to the first placeholder.
+// go-ora-v1 takes the parameter direction from stmt.Pars, so the statement is executed with nil args.
+func (o *Connector) callProcedure(db *go_ora_v1.Connection) (common.RuntimeResult, error) {
+	procedure := o.actionOptions.Procedure
+	if err := procedure.Validate(); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// build statement
+	placeholders := make([]string, 0)
+	offset := 0
+	if procedure.IsFunction() {
+		offset = 1
+	}
+	for serial := range procedure.Parameters {
+		placeholders = append(placeholders, fmt.Sprintf(":%d", serial+offset+1))
+	}
+	statement := fmt.Sprintf("BEGIN %s(%s); END;", procedure.Name, strings.Join(placeholders, ", "))
+	if procedure.IsFunction() {
+		statement = fmt.Sprintf("BEGIN :1 := %s(%s); END;", procedure.Name, strings.Join(placeholders, ", "))
+	}
+	stmt := go_ora_v1.NewStmt(statement, db)
+	defer stmt.Close()
+
+	// bind parameters
+	if procedure.IsFunction() {
+		returnParam := procedure.ExportReturnParameter()
+		value, errInConvert := convertProcedureParameterToDriverValue(returnParam)
+		if errInConvert != nil {
+			return common.RuntimeResult{Success: false}, errInConvert
+		}
+		stmt.AddParam(returnParam.Name, value, returnParam.ExportSize(), go_ora_v1.Output)
+	}
+	for _, param := range procedure.Parameters {
+		if param.IsCursor() {
+			stmt.AddRefCursorParam(param.Name)
+			continue
+		}
+		value, errInConvert := convertProcedureParameterToDriverValue(param)
+		if errInConvert != nil {
+			return common.RuntimeResult{Success: false}, errInConvert
+		}
+		direction := go_ora_v1.Input
+		switch param.Direction {
+		case common.PROCEDURE_PARAMETER_DIRECTION_OUT:
+			direction = go_ora_v1.Output
+		case common.PROCEDURE_PARAMETER_DIRECTION_INOUT:
+			direction = go_ora_v1.InOut
+		}
+		stmt.AddParam(param.Name, value, param.ExportSize(), direction)
+	}
+
+	// call it
+	if _, err := stmt.Exec(nil); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// collect output
+	procedureResult := common.NewProcedureResult()
+	if procedure.IsFunction() {
+		procedureResult.SetReturnValue(stmt.Pars[0].Value)
+	}
+	for serial, param := range procedure.Parameters {
+		if !param.IsOutput() {
+			continue
+		}
+		value := stmt.Pars[serial+offset].Value
+		if !param.IsCursor() {
+			procedureResult.SetOutParameter(param.Name, value)
+			continue
+		}
+		cursor, assertPass := value.(go_ora_v1.RefCursor)
+		if !assertPass {
+			return common.RuntimeResult{Success: false}, fmt.Errorf("procedure parameter \"%s\" did not return a cursor", param.Name)
+		}
+		cursorRows, err := cursor.Query()
+		if err != nil {
+			cursor.Close()
+			return common.RuntimeResult{Success: false}, err
+		}
+		cursorResultSet, err := common.RetrieveToMapByDriverRows(cursorRows)
+		cursorRows.Close()
+		cursor.Close()
+		if err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		procedureResult.SetCursor(param.Name, cursorResultSet)
+	}
+
+	return procedureResult.ExportRuntimeResult(), nil
+}
+
+// convertProcedureParameterToDriverValue returns the typed value for binding, the output parameter needs a typed zero value
+// since go-ora-v1 infers the oracle type from it. Oracle has no boolean in SQL, so bool is bound as number.
+func convertProcedureParameterToDriverValue(param *common.ProcedureParameter) (driver.Value, error) {
+	value, errInConvert := param.ExportTypedValue()
+	if errInConvert != nil {
+		return nil, errInConvert
+	}
+	if param.Direction == common.PROCEDURE_PARAMETER_DIRECTION_OUT {
+		value = nil
+	}
+	switch param.Type {
+	case common.PROCEDURE_PARAMETER_TYPE_INT:
+		valueInInt, _ := value.(int64)
+		return valueInInt, nil
+	case common.PROCEDURE_PARAMETER_TYPE_FLOAT:
+		valueInFloat, _ := value.(float64)
+		return valueInFloat, nil
+	case common.PROCEDURE_PARAMETER_TYPE_BOOL:
+		valueInBool, _ := value.(bool)
+		if valueInBool {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case common.PROCEDURE_PARAMETER_TYPE_DATETIME:
+		valueInTime, _ := value.(time.Time)
+		return valueInTime, nil
+	default:
+		valueInString, _ := value.(string)
+		return valueInString, nil
+	}
+}
@@ -65,6 +65,13 @@ func (o *Connector) ValidateActionTemplate(actionOptions map[string]interface{})
 		return common.ValidateResult{Valid: false}, err
 	}
 
+	// validate procedure call
+	if o.actionOptions.IsProcedureMode() {
+		if err := o.actionOptions.Procedure.Validate(); err != nil {
+			return common.ValidateResult{Valid: false}, err
+		}
+	}
+
 	return common.ValidateResult{Valid: true}, nil
 }
 
@@ -120,6 +127,10 @@ func (o *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 	if err := mapstructure.Decode(actionOptions, &o.actionOptions); err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
+	// call stored procedure or function, it does not carry raw query
+	if o.actionOptions.Mode == ACTION_PROCEDURE_MODE {
+		return o.callProcedure(db)
+	}
 	// set context field
 	errInSetRawQuery := o.actionOptions.SetRawQueryAndContext(rawActionOptions)
 	if errInSetRawQuery != nil {
@@ -38,16 +38,21 @@ type Resource struct {
 }
 
 type Action struct {
-	Mode     string                 `mapstructure:"mode" validate:"oneof=gui sql sql-safe"`
-	Opts     map[string]interface{} `mapstructure:"opts"`
-	RawQuery string
-	Context  map[string]interface{}
+	Mode      string                 `mapstructure:"mode" validate:"oneof=gui sql sql-safe procedure"`
+	Opts      map[string]interface{} `mapstructure:"opts"`
+	Procedure common.Procedure       `mapstructure:"procedure"`
+	RawQuery  string
+	Context   map[string]interface{}
 }
 
 func (q *Action) IsSafeMode() bool {
 	return q.Mode == common.MODE_SQL_SAFE
 }
 
+func (q *Action) IsProcedureMode() bool {
+	return q.Mode == common.MODE_PROCEDURE
+}
+
 func (q *Action) SetRawQueryAndContext(rawTemplate map[string]interface{}) error {
 	optsRaw, hitOpts := rawTemplate[FIELD_OPTS]
 	if !hitOpts {
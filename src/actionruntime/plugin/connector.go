@@ -0,0 +1,92 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+)
+
+// Connector forwards the DataConnector calls to plugin process
+type Connector struct {
+	plugin *Plugin
+}
+
+func NewConnector(plugin *Plugin) *Connector {
+	return &Connector{plugin: plugin}
+}
+
+func (p *Connector) call(method string, request map[string]interface{}) (map[string]interface{}, error) {
+	requestStruct, errInNewStruct := newStruct(request)
+	if errInNewStruct != nil {
+		return nil, errInNewStruct
+	}
+	return p.plugin.invoke(method, requestStruct)
+}
+
+func (p *Connector) ValidateResourceOptions(resourceOptions map[string]interface{}) (common.ValidateResult, error) {
+	response, err := p.call(METHOD_VALIDATE_RESOURCE_OPTIONS, map[string]interface{}{
+		FIELD_RESOURCE_OPTIONS: resourceOptions,
+	})
+	if err != nil {
+		return common.ValidateResult{Valid: false}, err
+	}
+	return common.ValidateResult{Valid: exportBool(response, FIELD_VALID), Extra: exportMap(response, FIELD_EXTRA)}, nil
+}
+
+func (p *Connector) ValidateActionTemplate(actionOptions map[string]interface{}) (common.ValidateResult, error) {
+	response, err := p.call(METHOD_VALIDATE_ACTION_TEMPLATE, map[string]interface{}{
+		FIELD_ACTION_OPTIONS: actionOptions,
+	})
+	if err != nil {
+		return common.ValidateResult{Valid: false}, err
+	}
+	return common.ValidateResult{Valid: exportBool(response, FIELD_VALID), Extra: exportMap(response, FIELD_EXTRA)}, nil
+}
+
+func (p *Connector) TestConnection(resourceOptions map[string]interface{}) (common.ConnectionResult, error) {
+	response, err := p.call(METHOD_TEST_CONNECTION, map[string]interface{}{
+		FIELD_RESOURCE_OPTIONS: resourceOptions,
+	})
+	if err != nil {
+		return common.ConnectionResult{Success: false}, err
+	}
+	return common.ConnectionResult{Success: exportBool(response, FIELD_SUCCESS)}, nil
+}
+
+func (p *Connector) GetMetaInfo(resourceOptions map[string]interface{}) (common.MetaInfoResult, error) {
+	response, err := p.call(METHOD_GET_META_INFO, map[string]interface{}{
+		FIELD_RESOURCE_OPTIONS: resourceOptions,
+	})
+	if err != nil {
+		return common.MetaInfoResult{Success: false}, err
+	}
+	return common.MetaInfoResult{Success: exportBool(response, FIELD_SUCCESS), Schema: exportMap(response, FIELD_SCHEMA)}, nil
+}
+
+func (p *Connector) Run(resourceOptions map[string]interface{}, actionOptions map[string]interface{}, rawActionOptions map[string]interface{}) (common.RuntimeResult, error) {
+	response, err := p.call(METHOD_RUN, map[string]interface{}{
+		FIELD_RESOURCE_OPTIONS:   resourceOptions,
+		FIELD_ACTION_OPTIONS:     actionOptions,
+		FIELD_RAW_ACTION_OPTIONS: rawActionOptions,
+	})
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	return common.RuntimeResult{
+		Success: exportBool(response, FIELD_SUCCESS),
+		Rows:    exportRows(response),
+		Extra:   exportMap(response, FIELD_EXTRA),
+	}, nil
+}
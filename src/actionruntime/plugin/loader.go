@@ -0,0 +1,83 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/illacloud/builder-backend/src/utils/resourcelist"
+)
+
+// LoadPlugins starts every executable in the directory and registers them as resource types.
+// The plugin failed to load will be skipped, so it will not block the builder startup.
+func LoadPlugins(directory string, healthCheckInterval time.Duration, callTimeout time.Duration) []*Plugin {
+	entries, errInReadDir := os.ReadDir(directory)
+	if errInReadDir != nil {
+		log.Printf("[ERROR] read connector plugin directory %s failed: %s\n", directory, errInReadDir.Error())
+		return nil
+	}
+	plugins := make([]*Plugin, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		fileInfo, errInStat := entry.Info()
+		if errInStat != nil || fileInfo.Mode()&0111 == 0 {
+			continue
+		}
+		plugin := NewPlugin(filepath.Join(directory, entry.Name()), healthCheckInterval, callTimeout)
+		if errInLoad := plugin.Load(); errInLoad != nil {
+			log.Printf("[ERROR] load connector plugin %s failed: %s\n", entry.Name(), errInLoad.Error())
+			continue
+		}
+		if errInRegister := registerPlugin(plugin); errInRegister != nil {
+			log.Printf("[ERROR] register connector plugin %s failed: %s\n", entry.Name(), errInRegister.Error())
+			plugin.Stop()
+			continue
+		}
+		log.Printf("[INFO] connector plugin %s loaded as resource type %d\n", plugin.ExportName(), plugin.ExportDescriptor().ID)
+		plugins = append(plugins, plugin)
+	}
+	return plugins
+}
+
+func StopPlugins(plugins []*Plugin) {
+	for _, plugin := range plugins {
+		common.UnregisterConnector(plugin.ExportDescriptor().ID)
+		plugin.Stop()
+	}
+}
+
+func registerPlugin(plugin *Plugin) error {
+	descriptor := plugin.ExportDescriptor()
+	if err := resourcelist.RegisterPluginResourceType(descriptor.Name, descriptor.ID); err != nil {
+		return err
+	}
+	return common.TryRegisterConnector(&common.ConnectorDescriptor{
+		Name:                  descriptor.Name,
+		ID:                    descriptor.ID,
+		Capability:            descriptor.Capability,
+		ResourceOptionsSchema: descriptor.ResourceOptionsSchema,
+		ActionTemplateSchema:  descriptor.ActionTemplateSchema,
+		Build: func() common.DataConnector {
+			return NewConnector(plugin)
+		},
+	})
+}
@@ -0,0 +1,322 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	PLUGIN_HANDSHAKE_TIMEOUT          = 10 * time.Second
+	PLUGIN_HEALTH_CHECK_TIMEOUT       = 5 * time.Second
+	PLUGIN_HEALTH_CHECK_MAX_FAILURES  = 3
+	PLUGIN_RESTART_BACKOFF_MIN        = 1 * time.Second
+	PLUGIN_RESTART_BACKOFF_MAX        = 1 * time.Minute
+	PLUGIN_HANDSHAKE_NETWORK_TCP      = "tcp"
+	PLUGIN_HANDSHAKE_NETWORK_UNIX     = "unix"
+	PLUGIN_HANDSHAKE_FIELD_SEPARATOR  = "|"
+	PLUGIN_HANDSHAKE_FIELD_COUNT      = 3
+	PLUGIN_UNAVAILABLE_ERROR_TEMPLATE = "connector plugin %s is not available"
+)
+
+// Plugin is a connector executable running in a separate process, so its crash will not take down the builder.
+// The process will be restarted with backoff when it exited or failed the health checks.
+type Plugin struct {
+	path                string
+	descriptor          *PluginDescriptor
+	healthCheckInterval time.Duration
+	callTimeout         time.Duration
+
+	mutex  sync.RWMutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	conn   *grpc.ClientConn
+	exited chan struct{}
+
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func NewPlugin(path string, healthCheckInterval time.Duration, callTimeout time.Duration) *Plugin {
+	return &Plugin{
+		path:                path,
+		healthCheckInterval: healthCheckInterval,
+		callTimeout:         callTimeout,
+		done:                make(chan struct{}),
+	}
+}
+
+func (plugin *Plugin) ExportName() string {
+	if plugin.descriptor != nil {
+		return plugin.descriptor.Name
+	}
+	return filepath.Base(plugin.path)
+}
+
+func (plugin *Plugin) ExportDescriptor() *PluginDescriptor {
+	return plugin.descriptor
+}
+
+// Load starts the plugin process and fetches its descriptor, then starts supervising it
+func (plugin *Plugin) Load() error {
+	if err := plugin.start(); err != nil {
+		return err
+	}
+	response, errInDescribe := plugin.invoke(METHOD_DESCRIBE, &structpb.Struct{})
+	if errInDescribe != nil {
+		plugin.Stop()
+		return errInDescribe
+	}
+	descriptor := &PluginDescriptor{}
+	if errInDecode := decodeStruct(response, descriptor); errInDecode != nil {
+		plugin.Stop()
+		return errInDecode
+	}
+	if descriptor.Name == "" || descriptor.ID == 0 {
+		plugin.Stop()
+		return errors.New("invalid descriptor: missing name or id")
+	}
+	descriptor.Capability = maskCapability(descriptor.Capability)
+	plugin.descriptor = descriptor
+	go plugin.supervise()
+	return nil
+}
+
+func (plugin *Plugin) Stop() {
+	plugin.stopOnce.Do(func() {
+		close(plugin.done)
+		plugin.mutex.Lock()
+		defer plugin.mutex.Unlock()
+		plugin.closeProcess()
+	})
+}
+
+func (plugin *Plugin) start() error {
+	cmd := exec.Command(plugin.path)
+	cmd.Env = append(os.Environ(), PLUGIN_ENV_PROTOCOL_VERSION+"="+PLUGIN_PROTOCOL_VERSION)
+	cmd.Stderr = newPluginLogWriter(plugin.ExportName())
+	// the plugin exits when stdin closed, so it will not outlive the builder
+	stdin, errInPipeStdin := cmd.StdinPipe()
+	if errInPipeStdin != nil {
+		return errInPipeStdin
+	}
+	stdoutReader, stdoutWriter, errInPipeStdout := os.Pipe()
+	if errInPipeStdout != nil {
+		return errInPipeStdout
+	}
+	cmd.Stdout = stdoutWriter
+	errInStart := cmd.Start()
+	stdoutWriter.Close()
+	if errInStart != nil {
+		stdoutReader.Close()
+		return errInStart
+	}
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(exited)
+	}()
+
+	// read handshake line, then forward the rest output to log
+	handshake := make(chan string, 1)
+	go func() {
+		defer stdoutReader.Close()
+		reader := bufio.NewReader(stdoutReader)
+		line, _ := reader.ReadString('\n')
+		handshake <- strings.TrimSpace(line)
+		io.Copy(newPluginLogWriter(plugin.ExportName()), reader)
+	}()
+	var handshakeLine string
+	select {
+	case handshakeLine = <-handshake:
+	case <-exited:
+		return errors.New("plugin process exited before handshake")
+	case <-time.After(PLUGIN_HANDSHAKE_TIMEOUT):
+		cmd.Process.Kill()
+		return errors.New("plugin handshake timeout")
+	}
+	target, errInParse := parseHandshake(handshakeLine)
+	if errInParse != nil {
+		cmd.Process.Kill()
+		return errInParse
+	}
+	conn, errInDial := grpc.Dial(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if errInDial != nil {
+		cmd.Process.Kill()
+		return errInDial
+	}
+
+	plugin.mutex.Lock()
+	defer plugin.mutex.Unlock()
+	plugin.cmd = cmd
+	plugin.stdin = stdin
+	plugin.conn = conn
+	plugin.exited = exited
+	return nil
+}
+
+// parseHandshake parses line like "1|tcp|127.0.0.1:40001" into grpc dial target
+func parseHandshake(line string) (string, error) {
+	fields := strings.Split(line, PLUGIN_HANDSHAKE_FIELD_SEPARATOR)
+	if len(fields) != PLUGIN_HANDSHAKE_FIELD_COUNT {
+		return "", fmt.Errorf("invalid plugin handshake: %q", line)
+	}
+	if fields[0] != PLUGIN_PROTOCOL_VERSION {
+		return "", fmt.Errorf("unsupported plugin protocol version %s, expected %s", fields[0], PLUGIN_PROTOCOL_VERSION)
+	}
+	switch fields[1] {
+	case PLUGIN_HANDSHAKE_NETWORK_TCP:
+		return fields[2], nil
+	case PLUGIN_HANDSHAKE_NETWORK_UNIX:
+		return "unix://" + fields[2], nil
+	default:
+		return "", fmt.Errorf("unsupported plugin network %s", fields[1])
+	}
+}
+
+// closeProcess should be called with lock held
+func (plugin *Plugin) closeProcess() {
+	if plugin.conn != nil {
+		plugin.conn.Close()
+		plugin.conn = nil
+	}
+	if plugin.stdin != nil {
+		plugin.stdin.Close()
+		plugin.stdin = nil
+	}
+	if plugin.cmd != nil && plugin.cmd.Process != nil {
+		plugin.cmd.Process.Kill()
+	}
+}
+
+func (plugin *Plugin) supervise() {
+	backoff := PLUGIN_RESTART_BACKOFF_MIN
+	healthCheckFailures := 0
+	ticker := time.NewTicker(plugin.healthCheckInterval)
+	defer ticker.Stop()
+	for {
+		plugin.mutex.RLock()
+		exited := plugin.exited
+		plugin.mutex.RUnlock()
+
+		select {
+		case <-plugin.done:
+			return
+		case <-exited:
+			plugin.mutex.Lock()
+			plugin.closeProcess()
+			plugin.mutex.Unlock()
+			log.Printf("[WARN] connector plugin %s exited, restart it after %s\n", plugin.ExportName(), backoff)
+			select {
+			case <-plugin.done:
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > PLUGIN_RESTART_BACKOFF_MAX {
+				backoff = PLUGIN_RESTART_BACKOFF_MAX
+			}
+			if errInStart := plugin.start(); errInStart != nil {
+				log.Printf("[ERROR] restart connector plugin %s failed: %s\n", plugin.ExportName(), errInStart.Error())
+			}
+			healthCheckFailures = 0
+		case <-ticker.C:
+			if errInCheck := plugin.checkHealth(); errInCheck != nil {
+				healthCheckFailures++
+				log.Printf("[WARN] connector plugin %s health check failed (%d/%d): %s\n", plugin.ExportName(), healthCheckFailures, PLUGIN_HEALTH_CHECK_MAX_FAILURES, errInCheck.Error())
+				if healthCheckFailures >= PLUGIN_HEALTH_CHECK_MAX_FAILURES {
+					plugin.mutex.Lock()
+					plugin.closeProcess()
+					plugin.mutex.Unlock()
+					healthCheckFailures = 0
+				}
+				continue
+			}
+			// the plugin keeps healthy, the next crash can be restarted quickly
+			healthCheckFailures = 0
+			backoff = PLUGIN_RESTART_BACKOFF_MIN
+		}
+	}
+}
+
+func (plugin *Plugin) connection() *grpc.ClientConn {
+	plugin.mutex.RLock()
+	defer plugin.mutex.RUnlock()
+	return plugin.conn
+}
+
+func (plugin *Plugin) checkHealth() error {
+	conn := plugin.connection()
+	if conn == nil {
+		return fmt.Errorf(PLUGIN_UNAVAILABLE_ERROR_TEMPLATE, plugin.ExportName())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), PLUGIN_HEALTH_CHECK_TIMEOUT)
+	defer cancel()
+	response, errInCheck := grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: CONNECTOR_SERVICE_NAME})
+	if errInCheck != nil {
+		return errInCheck
+	}
+	if response.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		return errors.New("plugin is " + response.GetStatus().String())
+	}
+	return nil
+}
+
+func (plugin *Plugin) invoke(method string, request *structpb.Struct) (map[string]interface{}, error) {
+	conn := plugin.connection()
+	if conn == nil {
+		return nil, common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_CONNECTION, fmt.Errorf(PLUGIN_UNAVAILABLE_ERROR_TEMPLATE, plugin.ExportName())).SetRetryable(true)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), plugin.callTimeout)
+	defer cancel()
+	response := &structpb.Struct{}
+	if errInInvoke := conn.Invoke(ctx, methodPath(method), request, response); errInInvoke != nil {
+		return nil, newConnectorErrorFromStatus(errInInvoke)
+	}
+	return response.AsMap(), nil
+}
+
+// pluginLogWriter forwards the plugin output to builder log line by line
+type pluginLogWriter struct {
+	name string
+}
+
+func newPluginLogWriter(name string) *pluginLogWriter {
+	return &pluginLogWriter{name: name}
+}
+
+func (writer *pluginLogWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		log.Printf("[plugin %s] %s\n", writer.name, line)
+	}
+	return len(p), nil
+}
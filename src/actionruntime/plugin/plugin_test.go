@@ -0,0 +1,181 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestParseHandshake(t *testing.T) {
+	target, err := parseHandshake("1|tcp|127.0.0.1:40001")
+	assert.Nil(t, err)
+	assert.Equal(t, "127.0.0.1:40001", target)
+
+	target, err = parseHandshake("1|unix|/tmp/plugin.sock")
+	assert.Nil(t, err)
+	assert.Equal(t, "unix:///tmp/plugin.sock", target)
+
+	_, err = parseHandshake("2|tcp|127.0.0.1:40001")
+	assert.NotNil(t, err)
+	_, err = parseHandshake("1|udp|127.0.0.1:40001")
+	assert.NotNil(t, err)
+	_, err = parseHandshake("1|tcp")
+	assert.NotNil(t, err)
+	_, err = parseHandshake("plugin started")
+	assert.NotNil(t, err)
+}
+
+func TestStructRoundTrip(t *testing.T) {
+	content, err := newStruct(map[string]interface{}{
+		FIELD_SUCCESS: true,
+		FIELD_ROWS:    []map[string]interface{}{{"id": 1, "name": "alice"}, {"id": 2, "tags": []string{"a"}}},
+		FIELD_EXTRA:   map[string]interface{}{"total": int64(2), "at": time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)},
+	})
+	assert.Nil(t, err)
+
+	decoded := content.AsMap()
+	assert.True(t, exportBool(decoded, FIELD_SUCCESS))
+	assert.False(t, exportBool(decoded, FIELD_VALID))
+	rows := exportRows(decoded)
+	assert.Equal(t, 2, len(rows))
+	// numbers are float64 after round trip, like json
+	assert.Equal(t, float64(1), rows[0]["id"])
+	assert.Equal(t, "alice", rows[0]["name"])
+	assert.Equal(t, []interface{}{"a"}, rows[1]["tags"])
+	extra := exportMap(decoded, FIELD_EXTRA)
+	assert.Equal(t, float64(2), extra["total"])
+	assert.Equal(t, "2023-01-02T03:04:05Z", extra["at"])
+	assert.Nil(t, exportMap(decoded, FIELD_SCHEMA))
+
+	_, err = newStruct(map[string]interface{}{"invalid": make(chan int)})
+	assert.NotNil(t, err)
+}
+
+func TestErrorMapping(t *testing.T) {
+	connectorError := common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_SYNTAX, errors.New("syntax error at or near \"FORM\"")).
+		SetVendorCode("42601").SetPosition(10).SetLineNumber(2).SetMessage("syntax error")
+	statusError := newStatusError(connectorError)
+	assert.Equal(t, codes.InvalidArgument, status.Code(statusError))
+
+	restored, isConnectorError := common.AsConnectorError(newConnectorErrorFromStatus(statusError))
+	assert.True(t, isConnectorError)
+	assert.Equal(t, common.CONNECTOR_ERROR_CATEGORY_SYNTAX, restored.Category)
+	assert.Equal(t, "42601", restored.VendorCode)
+	assert.Equal(t, 10, restored.Position)
+	assert.Equal(t, 2, restored.LineNumber)
+	assert.Equal(t, "syntax error", restored.Message)
+	assert.Equal(t, "syntax error at or near \"FORM\"", restored.Error())
+
+	// plain errors are unknown, the connection errors without detail are still retryable
+	assert.Equal(t, codes.Unknown, status.Code(newStatusError(errors.New("boom"))))
+	restored, _ = common.AsConnectorError(newConnectorErrorFromStatus(status.Error(codes.Unavailable, "plugin restarting")))
+	assert.Equal(t, common.CONNECTOR_ERROR_CATEGORY_CONNECTION, restored.Category)
+	assert.True(t, restored.Retryable)
+
+	// not a grpc status
+	plainError := errors.New("plain")
+	assert.Equal(t, plainError, newConnectorErrorFromStatus(plainError))
+}
+
+func TestMaskCapability(t *testing.T) {
+	capability := maskCapability(common.ConnectorCapability{MetaInfo: true, TestConnection: true, Streaming: true, GUIMode: true})
+	assert.Equal(t, common.ConnectorCapability{MetaInfo: true, TestConnection: true, GUIMode: true}, capability)
+}
+
+type fakeConnector struct{}
+
+func (f *fakeConnector) ValidateResourceOptions(resourceOptions map[string]interface{}) (common.ValidateResult, error) {
+	return common.ValidateResult{Valid: true}, nil
+}
+
+func (f *fakeConnector) ValidateActionTemplate(actionOptions map[string]interface{}) (common.ValidateResult, error) {
+	return common.ValidateResult{Valid: true}, nil
+}
+
+func (f *fakeConnector) TestConnection(resourceOptions map[string]interface{}) (common.ConnectionResult, error) {
+	return common.ConnectionResult{Success: true}, nil
+}
+
+func (f *fakeConnector) GetMetaInfo(resourceOptions map[string]interface{}) (common.MetaInfoResult, error) {
+	return common.MetaInfoResult{Success: true, Schema: map[string]interface{}{"tables": []string{"users"}}}, nil
+}
+
+func (f *fakeConnector) Run(resourceOptions map[string]interface{}, actionOptions map[string]interface{}, rawActionOptions map[string]interface{}) (common.RuntimeResult, error) {
+	if actionOptions["fail"] == true {
+		return common.RuntimeResult{}, common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_NOT_FOUND, errors.New("table not found")).SetVendorCode("404")
+	}
+	return common.RuntimeResult{Success: true, Rows: []map[string]interface{}{{"query": actionOptions["query"]}}}, nil
+}
+
+func newTestPlugin(t *testing.T) *Plugin {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	server.RegisterService(&connectorServiceDesc, &connectorServer{
+		descriptor: &PluginDescriptor{Name: "fake", ID: 1001},
+		build:      func() common.DataConnector { return &fakeConnector{} },
+	})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+	plugin := NewPlugin("fake", time.Second, 5*time.Second)
+	plugin.conn = conn
+	return plugin
+}
+
+func TestConnectorCallsPlugin(t *testing.T) {
+	plugin := newTestPlugin(t)
+	response, err := plugin.invoke(METHOD_DESCRIBE, newEmptyStruct(t))
+	assert.Nil(t, err)
+	descriptor := &PluginDescriptor{}
+	assert.Nil(t, decodeStruct(response, descriptor))
+	assert.Equal(t, "fake", descriptor.Name)
+	assert.Equal(t, 1001, descriptor.ID)
+
+	connector := NewConnector(plugin)
+	runtimeResult, err := connector.Run(map[string]interface{}{}, map[string]interface{}{"query": "select 1"}, map[string]interface{}{})
+	assert.Nil(t, err)
+	assert.True(t, runtimeResult.Success)
+	assert.Equal(t, []map[string]interface{}{{"query": "select 1"}}, runtimeResult.Rows)
+
+	metaInfo, err := connector.GetMetaInfo(map[string]interface{}{})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"users"}, metaInfo.Schema["tables"])
+
+	_, err = connector.Run(map[string]interface{}{}, map[string]interface{}{"fail": true}, map[string]interface{}{})
+	connectorError, isConnectorError := common.AsConnectorError(err)
+	assert.True(t, isConnectorError)
+	assert.Equal(t, common.CONNECTOR_ERROR_CATEGORY_NOT_FOUND, connectorError.Category)
+	assert.Equal(t, "404", connectorError.VendorCode)
+	assert.Equal(t, "table not found", connectorError.Error())
+}
+
+func TestConnectorCallsUnavailablePlugin(t *testing.T) {
+	_, err := NewConnector(NewPlugin("missing", time.Second, time.Second)).TestConnection(map[string]interface{}{})
+	connectorError, isConnectorError := common.AsConnectorError(err)
+	assert.True(t, isConnectorError)
+	assert.Equal(t, common.CONNECTOR_ERROR_CATEGORY_CONNECTION, connectorError.Category)
+	assert.True(t, connectorError.Retryable)
+}
+
+func newEmptyStruct(t *testing.T) *structpb.Struct {
+	content, err := newStruct(map[string]interface{}{})
+	assert.Nil(t, err)
+	return content
+}
@@ -0,0 +1,166 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/illacloud/builder-backend/src/utils/jsonschema"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// the messages are google.protobuf.Struct, so the service can be served without generated code, see connector.proto.
+const (
+	PLUGIN_PROTOCOL_VERSION     = "1"
+	PLUGIN_ENV_PROTOCOL_VERSION = "ILLA_PLUGIN_PROTOCOL_VERSION"
+
+	CONNECTOR_SERVICE_NAME = "illa.connector.v1.Connector"
+
+	METHOD_DESCRIBE                  = "Describe"
+	METHOD_VALIDATE_RESOURCE_OPTIONS = "ValidateResourceOptions"
+	METHOD_VALIDATE_ACTION_TEMPLATE  = "ValidateActionTemplate"
+	METHOD_TEST_CONNECTION           = "TestConnection"
+	METHOD_GET_META_INFO             = "GetMetaInfo"
+	METHOD_RUN                       = "Run"
+
+	FIELD_RESOURCE_OPTIONS   = "resourceOptions"
+	FIELD_ACTION_OPTIONS     = "actionOptions"
+	FIELD_RAW_ACTION_OPTIONS = "rawActionOptions"
+	FIELD_VALID              = "valid"
+	FIELD_SUCCESS            = "success"
+	FIELD_EXTRA              = "extra"
+	FIELD_SCHEMA             = "schema"
+	FIELD_ROWS               = "rows"
+)
+
+// PluginDescriptor is responded by the Describe method
+type PluginDescriptor struct {
+	Name                  string                     `json:"name"`
+	ID                    int                        `json:"id"`
+	Capability            common.ConnectorCapability `json:"capability"`
+	ResourceOptionsSchema *jsonschema.Schema         `json:"resourceOptionsSchema,omitempty"`
+	ActionTemplateSchema  *jsonschema.Schema         `json:"actionTemplateSchema,omitempty"`
+}
+
+// maskCapability turns off the capabilities claimed by plugin but can not be served through the plugin protocol,
+// the rows are responded in one message, so the result streaming is not supported.
+func maskCapability(capability common.ConnectorCapability) common.ConnectorCapability {
+	capability.Streaming = false
+	return capability
+}
+
+func methodPath(method string) string {
+	return "/" + CONNECTOR_SERVICE_NAME + "/" + method
+}
+
+// newStruct round trips the value through json, so the values like []map[string]interface{} can be converted
+func newStruct(value interface{}) (*structpb.Struct, error) {
+	valueInJSON, errInMarshal := json.Marshal(value)
+	if errInMarshal != nil {
+		return nil, errInMarshal
+	}
+	content := make(map[string]interface{})
+	if errInUnmarshal := json.Unmarshal(valueInJSON, &content); errInUnmarshal != nil {
+		return nil, errInUnmarshal
+	}
+	return structpb.NewStruct(content)
+}
+
+func decodeStruct(content map[string]interface{}, target interface{}) error {
+	contentInJSON, errInMarshal := json.Marshal(content)
+	if errInMarshal != nil {
+		return errInMarshal
+	}
+	return json.Unmarshal(contentInJSON, target)
+}
+
+func exportMap(content map[string]interface{}, field string) map[string]interface{} {
+	value, _ := content[field].(map[string]interface{})
+	return value
+}
+
+func exportBool(content map[string]interface{}, field string) bool {
+	value, _ := content[field].(bool)
+	return value
+}
+
+func exportRows(content map[string]interface{}) []map[string]interface{} {
+	rawRows, _ := content[FIELD_ROWS].([]interface{})
+	rows := make([]map[string]interface{}, 0, len(rawRows))
+	for _, rawRow := range rawRows {
+		if row, ok := rawRow.(map[string]interface{}); ok {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+var categoryStatusCodes = map[string]codes.Code{
+	common.CONNECTOR_ERROR_CATEGORY_SYNTAX:     codes.InvalidArgument,
+	common.CONNECTOR_ERROR_CATEGORY_AUTH:       codes.Unauthenticated,
+	common.CONNECTOR_ERROR_CATEGORY_CONNECTION: codes.Unavailable,
+	common.CONNECTOR_ERROR_CATEGORY_TIMEOUT:    codes.DeadlineExceeded,
+	common.CONNECTOR_ERROR_CATEGORY_PERMISSION: codes.PermissionDenied,
+	common.CONNECTOR_ERROR_CATEGORY_CONSTRAINT: codes.FailedPrecondition,
+	common.CONNECTOR_ERROR_CATEGORY_NOT_FOUND:  codes.NotFound,
+}
+
+// newStatusError is used by plugin side, the ConnectorError is attached as status detail
+func newStatusError(err error) error {
+	connectorError, isConnectorError := common.AsConnectorError(err)
+	if !isConnectorError {
+		return status.Error(codes.Unknown, err.Error())
+	}
+	code, hit := categoryStatusCodes[connectorError.Category]
+	if !hit {
+		code = codes.Unknown
+	}
+	st := status.New(code, err.Error())
+	detail, errInNewStruct := newStruct(connectorError)
+	if errInNewStruct != nil {
+		return st.Err()
+	}
+	if stWithDetail, errInAttach := st.WithDetails(detail); errInAttach == nil {
+		return stWithDetail.Err()
+	}
+	return st.Err()
+}
+
+// newConnectorErrorFromStatus is used by builder side, it restores the ConnectorError responded by plugin
+func newConnectorErrorFromStatus(err error) error {
+	st, isStatus := status.FromError(err)
+	if !isStatus {
+		return err
+	}
+	connectorError := common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_UNKNOWN, errors.New(st.Message()))
+	for category, code := range categoryStatusCodes {
+		if code == st.Code() {
+			connectorError.Category = category
+		}
+	}
+	if st.Code() == codes.Unavailable || st.Code() == codes.DeadlineExceeded {
+		connectorError.SetRetryable(true)
+	}
+	for _, detail := range st.Details() {
+		if detailStruct, ok := detail.(*structpb.Struct); ok {
+			decodeStruct(detailStruct.AsMap(), connectorError)
+		}
+	}
+	return connectorError
+}
@@ -0,0 +1,170 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// connectorServer serves the plugin side of the protocol.
+// The connector is built for every call, so the state stored in connector will not be shared between requests.
+type connectorServer struct {
+	descriptor *PluginDescriptor
+	build      func() common.DataConnector
+}
+
+type connectorMethod func(server *connectorServer, request map[string]interface{}) (map[string]interface{}, error)
+
+var connectorServiceDesc = grpc.ServiceDesc{
+	ServiceName: CONNECTOR_SERVICE_NAME,
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		newMethodDesc(METHOD_DESCRIBE, describe),
+		newMethodDesc(METHOD_VALIDATE_RESOURCE_OPTIONS, validateResourceOptions),
+		newMethodDesc(METHOD_VALIDATE_ACTION_TEMPLATE, validateActionTemplate),
+		newMethodDesc(METHOD_TEST_CONNECTION, testConnection),
+		newMethodDesc(METHOD_GET_META_INFO, getMetaInfo),
+		newMethodDesc(METHOD_RUN, run),
+	},
+	Metadata: "connector.proto",
+}
+
+// Serve is called in the main() of plugins written in Go, it does the handshake and serves until the builder exited.
+func Serve(descriptor *PluginDescriptor, build func() common.DataConnector) error {
+	if os.Getenv(PLUGIN_ENV_PROTOCOL_VERSION) != PLUGIN_PROTOCOL_VERSION {
+		return errors.New("the connector plugin should be started by ILLA Builder")
+	}
+	listener, errInListen := net.Listen(PLUGIN_HANDSHAKE_NETWORK_TCP, "127.0.0.1:0")
+	if errInListen != nil {
+		return errInListen
+	}
+	server := grpc.NewServer()
+	server.RegisterService(&connectorServiceDesc, &connectorServer{descriptor: descriptor, build: build})
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(CONNECTOR_SERVICE_NAME, grpc_health_v1.HealthCheckResponse_SERVING)
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+
+	// handshake
+	fmt.Fprintf(os.Stdout, "%s|%s|%s\n", PLUGIN_PROTOCOL_VERSION, PLUGIN_HANDSHAKE_NETWORK_TCP, listener.Addr().String())
+
+	// stdin closed means the builder exited
+	go func() {
+		io.Copy(io.Discard, os.Stdin)
+		server.Stop()
+	}()
+	return server.Serve(listener)
+}
+
+func newMethodDesc(method string, call connectorMethod) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: method,
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			request := &structpb.Struct{}
+			if err := dec(request); err != nil {
+				return nil, err
+			}
+			handle := func(ctx context.Context, req interface{}) (response interface{}, err error) {
+				// a panic in connector should not take down the plugin
+				defer func() {
+					if recovered := recover(); recovered != nil {
+						response, err = nil, status.Errorf(codes.Internal, "connector panic: %v", recovered)
+					}
+				}()
+				content, errInCall := call(srv.(*connectorServer), req.(*structpb.Struct).AsMap())
+				if errInCall != nil {
+					return nil, newStatusError(errInCall)
+				}
+				return newStruct(content)
+			}
+			if interceptor == nil {
+				return handle(ctx, request)
+			}
+			return interceptor(ctx, request, &grpc.UnaryServerInfo{Server: srv, FullMethod: methodPath(method)}, handle)
+		},
+	}
+}
+
+func describe(server *connectorServer, request map[string]interface{}) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"name":                  server.descriptor.Name,
+		"id":                    server.descriptor.ID,
+		"capability":            server.descriptor.Capability,
+		"resourceOptionsSchema": server.descriptor.ResourceOptionsSchema,
+		"actionTemplateSchema":  server.descriptor.ActionTemplateSchema,
+	}, nil
+}
+
+func validateResourceOptions(server *connectorServer, request map[string]interface{}) (map[string]interface{}, error) {
+	validateResult, err := server.build().ValidateResourceOptions(exportMap(request, FIELD_RESOURCE_OPTIONS))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{FIELD_VALID: validateResult.Valid, FIELD_EXTRA: validateResult.Extra}, nil
+}
+
+func validateActionTemplate(server *connectorServer, request map[string]interface{}) (map[string]interface{}, error) {
+	validateResult, err := server.build().ValidateActionTemplate(exportMap(request, FIELD_ACTION_OPTIONS))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{FIELD_VALID: validateResult.Valid, FIELD_EXTRA: validateResult.Extra}, nil
+}
+
+func testConnection(server *connectorServer, request map[string]interface{}) (map[string]interface{}, error) {
+	connectionResult, err := server.build().TestConnection(exportMap(request, FIELD_RESOURCE_OPTIONS))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{FIELD_SUCCESS: connectionResult.Success}, nil
+}
+
+func getMetaInfo(server *connectorServer, request map[string]interface{}) (map[string]interface{}, error) {
+	metaInfoResult, err := server.build().GetMetaInfo(exportMap(request, FIELD_RESOURCE_OPTIONS))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{FIELD_SUCCESS: metaInfoResult.Success, FIELD_SCHEMA: metaInfoResult.Schema}, nil
+}
+
+// run validates the options before run like the builder does, since the connectors keep the parsed options in themselves.
+func run(server *connectorServer, request map[string]interface{}) (map[string]interface{}, error) {
+	connector := server.build()
+	resourceOptions := exportMap(request, FIELD_RESOURCE_OPTIONS)
+	actionOptions := exportMap(request, FIELD_ACTION_OPTIONS)
+	if _, err := connector.ValidateResourceOptions(resourceOptions); err != nil {
+		return nil, err
+	}
+	if _, err := connector.ValidateActionTemplate(actionOptions); err != nil {
+		return nil, err
+	}
+	runtimeResult, err := connector.Run(resourceOptions, actionOptions, exportMap(request, FIELD_RAW_ACTION_OPTIONS))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{FIELD_SUCCESS: runtimeResult.Success, FIELD_ROWS: runtimeResult.Rows, FIELD_EXTRA: runtimeResult.Extra}, nil
+}
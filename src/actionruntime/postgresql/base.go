@@ -25,6 +25,7 @@ import (
 	"reflect"
 
 	"github.com/google/uuid"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
 	"github.com/jackc/pgx/v5"
 	"github.com/mitchellh/mapstructure"
 )
@@ -137,28 +138,37 @@ func fieldsInfo(db *pgx.Conn, tableSchema string, tableNames []string) map[strin
 }
 
 func RetrieveToMap(rows pgx.Rows) ([]map[string]interface{}, error) {
+	tableData := make([]map[string]interface{}, 0)
+	errInIterate := iterateRows(rows, func(entry map[string]interface{}) error {
+		tableData = append(tableData, entry)
+		return nil
+	})
+	if errInIterate != nil {
+		return nil, errInIterate
+	}
+	return tableData, nil
+}
+
+// RetrieveToMapOrStream retrieves rows to map when the stream is nil, otherwise writes them into stream and returns empty rows.
+func RetrieveToMapOrStream(rows pgx.Rows, stream common.ResultStream) ([]map[string]interface{}, error) {
+	if stream == nil {
+		return RetrieveToMap(rows)
+	}
+	errInIterate := iterateRows(rows, stream.WriteRow)
+	if errInIterate != nil && errInIterate != common.ErrResultStreamLimitReached {
+		return nil, errInIterate
+	}
+	return []map[string]interface{}{}, nil
+}
+
+func iterateRows(rows pgx.Rows, handler func(entry map[string]interface{}) error) error {
 	fieldDescriptions := rows.FieldDescriptions()
-	renamedColumns := make([]string, 0)
-	columnNameHitMap := make(map[string]int, 0)
-	columnNamePosMap := make(map[string]int, 0)
-	for pos, col := range fieldDescriptions {
-		hitColumnTimes, hitColumn := columnNameHitMap[col.Name]
-		cloName := col.Name
-		if hitColumn {
-			cloName += fmt.Sprintf("_%d", hitColumnTimes)
-			// rewrite first column to "_0"
-			if columnNameHitMap[col.Name] == 1 {
-				firstHitPos := columnNamePosMap[col.Name]
-				renamedColumns[firstHitPos] += "_0"
-			}
-			columnNameHitMap[col.Name]++
-		}
-		columnNameHitMap[cloName] = 1
-		columnNamePosMap[cloName] = pos
-		renamedColumns = append(renamedColumns, cloName)
+	columns := make([]string, 0, len(fieldDescriptions))
+	for _, col := range fieldDescriptions {
+		columns = append(columns, col.Name)
 	}
+	renamedColumns := common.RenameDuplicateColumns(columns)
 	count := len(renamedColumns)
-	tableData := make([]map[string]interface{}, 0)
 	values := make([]interface{}, count)
 	valuePtrs := make([]interface{}, count)
 
@@ -180,7 +190,9 @@ func RetrieveToMap(rows pgx.Rows) ([]map[string]interface{}, error) {
 			val := values[i]
 			entry[col] = val
 		}
-		tableData = append(tableData, entry)
+		if errInHandle := handler(entry); errInHandle != nil {
+			return errInHandle
+		}
 	}
-	return tableData, nil
+	return nil
 }
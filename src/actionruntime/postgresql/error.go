@@ -0,0 +1,58 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresql
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func (p *Connector) ClassifyError(err error) *common.ConnectorError {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return common.ClassifyNetworkError(err)
+	}
+	var connectorError *common.ConnectorError
+	switch {
+	case pgErr.Code == "42601":
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_SYNTAX, err)
+	case pgErr.Code == "42501":
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_PERMISSION, err)
+	case pgErr.Code == "42P01" || pgErr.Code == "42703" || pgErr.Code == "42883" || pgErr.Code == "3D000":
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_NOT_FOUND, err)
+	case pgErr.Code == "57014":
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_TIMEOUT, err)
+	case pgErr.Code == "53300" || strings.HasPrefix(pgErr.Code, "08"):
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_CONNECTION, err).SetRetryable(true)
+	case strings.HasPrefix(pgErr.Code, "23"):
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_CONSTRAINT, err)
+	case strings.HasPrefix(pgErr.Code, "28"):
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_AUTH, err)
+	default:
+		connectorError = common.NewConnectorError(common.CONNECTOR_ERROR_CATEGORY_UNKNOWN, err)
+	}
+	// serialization failure and deadlock can be retried
+	if pgErr.Code == "40001" || pgErr.Code == "40P01" {
+		connectorError.SetRetryable(true)
+	}
+	connectorError.SetVendorCode(pgErr.Code).SetMessage(pgErr.Message)
+	if pgErr.Position > 0 {
+		connectorError.SetPosition(int(pgErr.Position)).SetLineNumber(common.LineNumberOfPosition(p.executedSQL, int(pgErr.Position)))
+	}
+	return connectorError
+}
@@ -0,0 +1,22 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyErrorPositionOfExecutedSQL(t *testing.T) {
+	// the template "{{ query }}" expanded into multiple lines, the position is based on the executed statement
+	connector := &Connector{
+		Action:      Query{Query: "{{ query }}"},
+		executedSQL: "SELECT *\nFROM users\nWHRE id = 1",
+	}
+	connectorError := connector.ClassifyError(&pgconn.PgError{Code: "42601", Message: `syntax error at or near "WHRE"`, Position: 21})
+	assert.Equal(t, common.CONNECTOR_ERROR_CATEGORY_SYNTAX, connectorError.Category)
+	assert.Equal(t, 21, connectorError.Position)
+	assert.Equal(t, 3, connectorError.LineNumber)
+	assert.Equal(t, "42601", connectorError.VendorCode)
+}
@@ -0,0 +1,163 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/jackc/pgx/v5"
+)
+
+// callProcedure runs "CALL proc(...)" for procedures and "SELECT * FROM func(...)" for functions.
+// It runs in a transaction since the returned refcursors only live until the transaction ends.
+func (p *Connector) callProcedure(db *pgx.Conn) (common.RuntimeResult, error) {
+	procedure := p.Action.Procedure
+	if err := procedure.Validate(); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// build statement, the OUT parameters of procedure are passed as NULL, and omitted for function
+	placeholders := make([]string, 0)
+	args := make([]interface{}, 0)
+	outputParams := make([]*common.ProcedureParameter, 0)
+	for _, param := range procedure.Parameters {
+		if param.IsOutput() {
+			outputParams = append(outputParams, param)
+		}
+		if procedure.IsFunction() && !param.IsInput() {
+			continue
+		}
+		var value interface{}
+		if param.IsInput() {
+			var errInConvert error
+			value, errInConvert = param.ExportTypedValue()
+			if errInConvert != nil {
+				return common.RuntimeResult{Success: false}, errInConvert
+			}
+		}
+		args = append(args, value)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+	statement := fmt.Sprintf("CALL %s(%s)", procedure.Name, strings.Join(placeholders, ", "))
+	if procedure.IsFunction() {
+		statement = fmt.Sprintf("SELECT * FROM %s(%s)", procedure.Name, strings.Join(placeholders, ", "))
+	}
+	p.executedSQL = statement
+
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	defer tx.Rollback(ctx)
+
+	// call it
+	rows, err := tx.Query(ctx, statement, args...)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	fieldDescriptions := rows.FieldDescriptions()
+	resultSet, err := RetrieveToMap(rows)
+	rows.Close()
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	if err := rows.Err(); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	columns := make([]string, 0, len(fieldDescriptions))
+	for _, fieldDescription := range fieldDescriptions {
+		columns = append(columns, fieldDescription.Name)
+	}
+	procedureResult := common.NewProcedureResult()
+	fillProcedureResult(procedureResult, &procedure, outputParams, columns, resultSet)
+
+	// fetch refcursor
+	for _, param := range outputParams {
+		if !param.IsCursor() {
+			continue
+		}
+		cursorName, ok := procedureResult.OutParameters[param.Name].(string)
+		if !ok || cursorName == "" {
+			continue
+		}
+		cursorRows, err := tx.Query(ctx, "FETCH ALL FROM "+pgx.Identifier{cursorName}.Sanitize())
+		if err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		cursorResultSet, err := RetrieveToMap(cursorRows)
+		cursorRows.Close()
+		if err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		procedureResult.SetCursor(param.Name, cursorResultSet)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	return procedureResult.ExportRuntimeResult(), nil
+}
+
+// fillProcedureResult maps the rows returned by call statement into procedure result, the output values are
+// returned in the order of OUT and INOUT parameters. The procedure returns them as one row, and the function
+// returns one row for each element when it is a set-returning function.
+func fillProcedureResult(procedureResult *common.ProcedureResult, procedure *common.Procedure, outputParams []*common.ProcedureParameter, columns []string, resultSet []map[string]interface{}) {
+	if !procedure.IsFunction() {
+		if len(outputParams) == 0 || len(resultSet) == 0 {
+			procedureResult.AppendResultSet(resultSet)
+			return
+		}
+		for serial, param := range outputParams {
+			if serial >= len(columns) {
+				break
+			}
+			procedureResult.SetOutParameter(param.Name, resultSet[0][columns[serial]])
+		}
+		return
+	}
+
+	// the output values of set-returning function are collected from every row
+	for serial, param := range outputParams {
+		if serial >= len(columns) {
+			break
+		}
+		if len(resultSet) == 1 {
+			procedureResult.SetOutParameter(param.Name, resultSet[0][columns[serial]])
+			continue
+		}
+		values := make([]interface{}, 0, len(resultSet))
+		for _, row := range resultSet {
+			values = append(values, row[columns[serial]])
+		}
+		procedureResult.SetOutParameter(param.Name, values)
+	}
+
+	// the return value is scalar for single value, the row for composite type, and all rows for set-returning function
+	switch {
+	case len(resultSet) == 0:
+		procedureResult.SetReturnValue(nil)
+	case len(resultSet) == 1 && len(columns) == 1:
+		procedureResult.SetReturnValue(resultSet[0][columns[0]])
+	case len(resultSet) == 1:
+		procedureResult.SetReturnValue(resultSet[0])
+	default:
+		procedureResult.SetReturnValue(resultSet)
+	}
+	procedureResult.AppendResultSet(resultSet)
+}
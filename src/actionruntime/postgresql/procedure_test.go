@@ -0,0 +1,58 @@
+package postgresql
+
+import (
+	"testing"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFillProcedureResultOfProcedure(t *testing.T) {
+	procedure := &common.Procedure{Name: "transfer", Kind: common.PROCEDURE_KIND_PROCEDURE}
+	outputParams := []*common.ProcedureParameter{
+		{Name: "balance", Direction: common.PROCEDURE_PARAMETER_DIRECTION_OUT},
+		{Name: "status", Direction: common.PROCEDURE_PARAMETER_DIRECTION_INOUT},
+	}
+	procedureResult := common.NewProcedureResult()
+	fillProcedureResult(procedureResult, procedure, outputParams, []string{"balance", "status"}, []map[string]interface{}{{"balance": 100, "status": "ok"}})
+
+	assert.Equal(t, map[string]interface{}{"balance": 100, "status": "ok"}, procedureResult.OutParameters)
+	assert.Nil(t, procedureResult.ReturnValue)
+	assert.Equal(t, 0, len(procedureResult.ResultSets))
+}
+
+func TestFillProcedureResultOfScalarFunction(t *testing.T) {
+	procedure := &common.Procedure{Name: "add", Kind: common.PROCEDURE_KIND_FUNCTION}
+	procedureResult := common.NewProcedureResult()
+	fillProcedureResult(procedureResult, procedure, nil, []string{"add"}, []map[string]interface{}{{"add": 3}})
+
+	assert.Equal(t, 3, procedureResult.ReturnValue)
+	assert.Equal(t, [][]map[string]interface{}{{{"add": 3}}}, procedureResult.ResultSets)
+}
+
+func TestFillProcedureResultOfSetReturningFunction(t *testing.T) {
+	procedure := &common.Procedure{Name: "list_users", Kind: common.PROCEDURE_KIND_FUNCTION}
+	outputParams := []*common.ProcedureParameter{
+		{Name: "id", Direction: common.PROCEDURE_PARAMETER_DIRECTION_OUT},
+		{Name: "name", Direction: common.PROCEDURE_PARAMETER_DIRECTION_OUT},
+	}
+	resultSet := []map[string]interface{}{{"id": 1, "name": "a"}, {"id": 2, "name": "b"}}
+	procedureResult := common.NewProcedureResult()
+	fillProcedureResult(procedureResult, procedure, outputParams, []string{"id", "name"}, resultSet)
+
+	assert.Equal(t, []interface{}{1, 2}, procedureResult.OutParameters["id"])
+	assert.Equal(t, []interface{}{"a", "b"}, procedureResult.OutParameters["name"])
+	assert.Equal(t, resultSet, procedureResult.ReturnValue)
+	assert.Equal(t, resultSet, procedureResult.ExportRuntimeResult().Rows)
+
+	// the composite value of single row
+	procedureResult = common.NewProcedureResult()
+	fillProcedureResult(procedureResult, procedure, nil, []string{"id", "name"}, resultSet[:1])
+	assert.Equal(t, resultSet[0], procedureResult.ReturnValue)
+
+	// the empty set
+	procedureResult = common.NewProcedureResult()
+	fillProcedureResult(procedureResult, procedure, nil, []string{"id", "name"}, []map[string]interface{}{})
+	assert.Nil(t, procedureResult.ReturnValue)
+	assert.Equal(t, []map[string]interface{}{}, procedureResult.ExportRuntimeResult().Rows)
+}
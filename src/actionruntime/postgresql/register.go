@@ -0,0 +1,43 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresql
+
+import (
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/illacloud/builder-backend/src/utils/jsonschema"
+	"github.com/illacloud/builder-backend/src/utils/resourcelist"
+)
+
+func init() {
+	common.RegisterConnector(&common.ConnectorDescriptor{
+		Name: resourcelist.TYPE_POSTGRESQL,
+		ID:   resourcelist.TYPE_POSTGRESQL_ID,
+		Aliases: []*common.ConnectorAlias{
+			common.NewConnectorAlias(resourcelist.TYPE_SUPABASEDB),
+			common.NewConnectorAlias(resourcelist.TYPE_NEON),
+			common.NewConnectorAlias(resourcelist.TYPE_HYDRA),
+		},
+		Capability: common.ConnectorCapability{
+			MetaInfo:       true,
+			TestConnection: true,
+			GUIMode:        true,
+		},
+		ResourceOptionsSchema: jsonschema.Reflect(&Options{}),
+		ActionTemplateSchema:  jsonschema.Reflect(&Query{}),
+		Build: func() common.DataConnector {
+			return &Connector{}
+		},
+	})
+}
@@ -28,8 +28,26 @@ import (
 )
 
 type Connector struct {
-	Resource Options
-	Action   Query
+	Resource     Options
+	Action       Query
+	resultStream common.ResultStream
+	executedSQL  string // the escaped statement sent to server, the error position is based on it
+}
+
+func (p *Connector) SetResultStream(stream common.ResultStream) {
+	p.resultStream = stream
+}
+
+func (p *Connector) IsReadOnlyAction(actionOptions map[string]interface{}) bool {
+	mode, _ := actionOptions["mode"].(string)
+	query, _ := actionOptions["query"].(string)
+	return common.IsReadOnlySQLQuery(mode, query)
+}
+
+func (p *Connector) IsWriteAction(actionOptions map[string]interface{}) bool {
+	mode, _ := actionOptions["mode"].(string)
+	query, _ := actionOptions["query"].(string)
+	return common.IsWriteSQLQuery(mode, query)
 }
 
 func (p *Connector) ValidateResourceOptions(resourceOptions map[string]interface{}) (common.ValidateResult, error) {
@@ -57,6 +75,13 @@ func (p *Connector) ValidateActionTemplate(actionOptions map[string]interface{})
 	if err := validate.Struct(p.Action); err != nil {
 		return common.ValidateResult{Valid: false}, err
 	}
+
+	// validate procedure call
+	if p.Action.IsProcedureMode() {
+		if err := p.Action.Procedure.Validate(); err != nil {
+			return common.ValidateResult{Valid: false}, err
+		}
+	}
 	return common.ValidateResult{Valid: true}, nil
 }
 
@@ -112,6 +137,11 @@ func (p *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 		return common.RuntimeResult{Success: false}, err
 	}
 
+	// call stored procedure or function
+	if p.Action.IsProcedureMode() {
+		return p.callProcedure(db)
+	}
+
 	// set context field
 	errInSetRawQuery := p.Action.SetRawQueryAndContext(rawActionOptions)
 	if errInSetRawQuery != nil {
@@ -129,6 +159,7 @@ func (p *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 	if errInEscapeSQL != nil {
 		return queryResult, errInEscapeSQL
 	}
+	p.executedSQL = escapedSQL
 	isSelectQuery := false
 
 	lexer := parser_sql.NewLexer(escapedSQL)
@@ -145,7 +176,7 @@ func (p *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 		if err != nil {
 			return queryResult, err
 		}
-		mapRes, err := RetrieveToMap(rows)
+		mapRes, err := RetrieveToMapOrStream(rows, p.resultStream)
 		if err != nil {
 			return queryResult, err
 		}
@@ -157,7 +188,7 @@ func (p *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 		if err != nil {
 			return queryResult, err
 		}
-		mapRes, err := RetrieveToMap(rows)
+		mapRes, err := RetrieveToMapOrStream(rows, p.resultStream)
 		if err != nil {
 			return queryResult, err
 		}
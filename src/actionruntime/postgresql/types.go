@@ -42,16 +42,21 @@ type SSLOptions struct {
 }
 
 type Query struct {
-	Mode     string `validate:"required,oneof=gui sql sql-safe"`
-	Query    string
-	RawQuery string
-	Context  map[string]interface{}
+	Mode      string `validate:"required,oneof=gui sql sql-safe procedure"`
+	Query     string
+	Procedure common.Procedure
+	RawQuery  string
+	Context   map[string]interface{}
 }
 
 func (q *Query) IsSafeMode() bool {
 	return q.Mode == common.MODE_SQL_SAFE
 }
 
+func (q *Query) IsProcedureMode() bool {
+	return q.Mode == common.MODE_PROCEDURE
+}
+
 func (q *Query) SetRawQueryAndContext(rawTemplate map[string]interface{}) error {
 	queryRaw, hit := rawTemplate[FIELD_QUERY]
 	if !hit {
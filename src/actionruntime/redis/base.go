@@ -15,31 +15,127 @@
 package redis
 
 import (
+	"context"
 	"crypto/tls"
+	"sort"
+	"sync"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/mitchellh/mapstructure"
 )
 
-func (r *Connector) getConnectionWithOptions(resourceOptions map[string]interface{}) (*redis.Client, error) {
+const (
+	TOPOLOGY_STANDALONE = "standalone"
+	TOPOLOGY_CLUSTER    = "cluster"
+	TOPOLOGY_SENTINEL   = "sentinel"
+
+	MODE_SELECT      = "select"
+	MODE_RAW         = "raw"
+	MODE_GUI         = "gui"
+	MODE_PIPELINE    = "pipeline"
+	MODE_TRANSACTION = "transaction"
+
+	// the key browser stops scanning when the keys reach the limit
+	KEY_BROWSER_SCAN_COUNT = 100
+	KEY_BROWSER_MAX_KEYS   = 1000
+)
+
+func (r *Connector) getConnectionWithOptions(resourceOptions map[string]interface{}) (redis.UniversalClient, error) {
 	if err := mapstructure.Decode(resourceOptions, &r.Resource); err != nil {
 		return nil, err
 	}
 
-	options := redis.Options{
-		Addr:     r.Resource.Host + ":" + r.Resource.Port,
-		Username: r.Resource.DatabaseUsername,
-		Password: r.Resource.DatabasePassword,
-		DB:       r.Resource.DatabaseIndex,
-	}
+	var tlsConfig *tls.Config
 	if r.Resource.SSL {
-		tlsConfig := tls.Config{
+		tlsConfig = &tls.Config{
 			MinVersion: tls.VersionTLS12,
 			ServerName: r.Resource.Host,
 		}
-		options.TLSConfig = &tlsConfig
 	}
-	rdb := redis.NewClient(&options)
 
-	return rdb, nil
+	// the host and port is the first node of cluster or sentinels
+	addrs := append([]string{r.Resource.Host + ":" + r.Resource.Port}, r.Resource.Nodes...)
+	switch r.Resource.Topology {
+	case TOPOLOGY_CLUSTER:
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:     addrs,
+			Username:  r.Resource.DatabaseUsername,
+			Password:  r.Resource.DatabasePassword,
+			TLSConfig: tlsConfig,
+		}), nil
+	case TOPOLOGY_SENTINEL:
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       r.Resource.MasterName,
+			SentinelAddrs:    addrs,
+			SentinelUsername: r.Resource.SentinelUsername,
+			SentinelPassword: r.Resource.SentinelPassword,
+			Username:         r.Resource.DatabaseUsername,
+			Password:         r.Resource.DatabasePassword,
+			DB:               r.Resource.DatabaseIndex,
+			TLSConfig:        tlsConfig,
+		}), nil
+	default:
+		return redis.NewClient(&redis.Options{
+			Addr:      addrs[0],
+			Username:  r.Resource.DatabaseUsername,
+			Password:  r.Resource.DatabasePassword,
+			DB:        r.Resource.DatabaseIndex,
+			TLSConfig: tlsConfig,
+		}), nil
+	}
+}
+
+// browseKeys scans the keys with their type and ttl, every master node is scanned in cluster mode
+func browseKeys(ctx context.Context, rdb redis.UniversalClient) ([]map[string]interface{}, bool, error) {
+	var mutex sync.Mutex
+	keys := make([]string, 0)
+	truncated := false
+	scan := func(ctx context.Context, client redis.Cmdable) error {
+		iter := client.Scan(ctx, 0, "*", KEY_BROWSER_SCAN_COUNT).Iterator()
+		for iter.Next(ctx) {
+			mutex.Lock()
+			if len(keys) >= KEY_BROWSER_MAX_KEYS {
+				truncated = true
+				mutex.Unlock()
+				return nil
+			}
+			keys = append(keys, iter.Val())
+			mutex.Unlock()
+		}
+		return iter.Err()
+	}
+	if clusterClient, isCluster := rdb.(*redis.ClusterClient); isCluster {
+		errInScan := clusterClient.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			return scan(ctx, client)
+		})
+		if errInScan != nil {
+			return nil, false, errInScan
+		}
+	} else if errInScan := scan(ctx, rdb); errInScan != nil {
+		return nil, false, errInScan
+	}
+	sort.Strings(keys)
+
+	// fetch type and ttl in one round trip
+	pipe := rdb.Pipeline()
+	typeCmds := make([]*redis.StatusCmd, len(keys))
+	ttlCmds := make([]*redis.DurationCmd, len(keys))
+	for i, key := range keys {
+		typeCmds[i] = pipe.Type(ctx, key)
+		ttlCmds[i] = pipe.TTL(ctx, key)
+	}
+	if len(keys) > 0 {
+		if _, errInExec := pipe.Exec(ctx); errInExec != nil {
+			return nil, false, errInExec
+		}
+	}
+	keyInfos := make([]map[string]interface{}, 0, len(keys))
+	for i, key := range keys {
+		keyInfos = append(keyInfos, map[string]interface{}{
+			"key":  key,
+			"type": typeCmds[i].Val(),
+			"ttl":  exportCmdResult(ttlCmds[i]),
+		})
+	}
+	return keyInfos, truncated, nil
 }
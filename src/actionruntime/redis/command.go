@@ -0,0 +1,350 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/go-redis/redis/v8"
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	GUI_COMMAND_GET      = "get"
+	GUI_COMMAND_SET      = "set"
+	GUI_COMMAND_DEL      = "del"
+	GUI_COMMAND_EXPIRE   = "expire"
+	GUI_COMMAND_TTL      = "ttl"
+	GUI_COMMAND_HGET     = "hget"
+	GUI_COMMAND_HGETALL  = "hgetall"
+	GUI_COMMAND_HSET     = "hset"
+	GUI_COMMAND_HDEL     = "hdel"
+	GUI_COMMAND_LRANGE   = "lrange"
+	GUI_COMMAND_LPUSH    = "lpush"
+	GUI_COMMAND_RPUSH    = "rpush"
+	GUI_COMMAND_LPOP     = "lpop"
+	GUI_COMMAND_RPOP     = "rpop"
+	GUI_COMMAND_SMEMBERS = "smembers"
+	GUI_COMMAND_SADD     = "sadd"
+	GUI_COMMAND_SREM     = "srem"
+	GUI_COMMAND_ZRANGE   = "zrange"
+	GUI_COMMAND_ZADD     = "zadd"
+	GUI_COMMAND_ZREM     = "zrem"
+	GUI_COMMAND_XADD     = "xadd"
+	GUI_COMMAND_XRANGE   = "xrange"
+	GUI_COMMAND_XLEN     = "xlen"
+	GUI_COMMAND_XDEL     = "xdel"
+
+	SET_CONDITION_NX = "nx"
+	SET_CONDITION_XX = "xx"
+)
+
+// commandRunner is implemented by both the client and the pipeline
+type commandRunner interface {
+	redis.Cmdable
+	Do(ctx context.Context, args ...interface{}) *redis.Cmd
+}
+
+func parseRawCommand(query string) []interface{} {
+	redisCMDSlice := strings.Fields(strings.TrimSpace(query))
+	inputRedisCMDSlice := make([]interface{}, len(redisCMDSlice))
+	for i, v := range redisCMDSlice {
+		inputRedisCMDSlice[i] = v
+	}
+	return inputRedisCMDSlice
+}
+
+// exportCommandItems returns the commands of pipeline or transaction, every line of raw query is a command when no commands given
+func (c *Command) exportCommandItems() []CommandItem {
+	if len(c.Commands) > 0 {
+		return c.Commands
+	}
+	items := make([]CommandItem, 0)
+	for _, line := range strings.Split(c.Query, "\n") {
+		if strings.TrimSpace(line) != "" {
+			items = append(items, CommandItem{Query: line})
+		}
+	}
+	return items
+}
+
+func validateCommandItem(item CommandItem) error {
+	if item.Command == "" {
+		if len(parseRawCommand(item.Query)) == 0 {
+			return errors.New("missing redis command")
+		}
+		return nil
+	}
+	_, err := decodeCommandOptions(item.Command, item.CommandOptions)
+	return err
+}
+
+func decodeCommandOptions(command string, commandOptions map[string]interface{}) (interface{}, error) {
+	var options interface{}
+	switch command {
+	case GUI_COMMAND_GET, GUI_COMMAND_TTL, GUI_COMMAND_HGETALL, GUI_COMMAND_SMEMBERS, GUI_COMMAND_XLEN:
+		options = &KeyOptions{}
+	case GUI_COMMAND_DEL:
+		options = &KeysOptions{}
+	case GUI_COMMAND_SET:
+		options = &SetOptions{}
+	case GUI_COMMAND_EXPIRE:
+		options = &ExpireOptions{}
+	case GUI_COMMAND_HGET:
+		options = &HashFieldOptions{}
+	case GUI_COMMAND_HSET:
+		options = &HashSetOptions{}
+	case GUI_COMMAND_HDEL:
+		options = &HashDeleteOptions{}
+	case GUI_COMMAND_LRANGE, GUI_COMMAND_ZRANGE:
+		options = &RangeOptions{}
+	case GUI_COMMAND_LPUSH, GUI_COMMAND_RPUSH:
+		options = &PushOptions{}
+	case GUI_COMMAND_LPOP, GUI_COMMAND_RPOP:
+		options = &PopOptions{}
+	case GUI_COMMAND_SADD, GUI_COMMAND_SREM, GUI_COMMAND_ZREM:
+		options = &MembersOptions{}
+	case GUI_COMMAND_ZADD:
+		options = &SortedSetAddOptions{}
+	case GUI_COMMAND_XADD:
+		options = &StreamAddOptions{}
+	case GUI_COMMAND_XRANGE:
+		options = &StreamRangeOptions{}
+	case GUI_COMMAND_XDEL:
+		options = &StreamDeleteOptions{}
+	default:
+		return nil, errors.New("unsupported redis command: " + command)
+	}
+	if err := mapstructure.Decode(commandOptions, options); err != nil {
+		return nil, err
+	}
+	validate := validator.New()
+	if err := validate.Struct(options); err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+// prepareCommand sends the command to runner, the command is executed immediately by client or queued by pipeline
+func prepareCommand(ctx context.Context, runner commandRunner, item CommandItem) (redis.Cmder, error) {
+	if item.Command == "" {
+		args := parseRawCommand(item.Query)
+		if len(args) == 0 {
+			return nil, errors.New("missing redis command")
+		}
+		return runner.Do(ctx, args...), nil
+	}
+
+	decodedOptions, err := decodeCommandOptions(item.Command, item.CommandOptions)
+	if err != nil {
+		return nil, err
+	}
+	switch options := decodedOptions.(type) {
+	case *KeyOptions:
+		switch item.Command {
+		case GUI_COMMAND_GET:
+			return runner.Get(ctx, options.Key), nil
+		case GUI_COMMAND_TTL:
+			return runner.TTL(ctx, options.Key), nil
+		case GUI_COMMAND_HGETALL:
+			return runner.HGetAll(ctx, options.Key), nil
+		case GUI_COMMAND_SMEMBERS:
+			return runner.SMembers(ctx, options.Key), nil
+		default:
+			return runner.XLen(ctx, options.Key), nil
+		}
+	case *KeysOptions:
+		return runner.Del(ctx, options.Keys...), nil
+	case *SetOptions:
+		expiration := time.Duration(options.TTL) * time.Second
+		switch options.Condition {
+		case SET_CONDITION_NX:
+			return runner.SetNX(ctx, options.Key, options.Value, expiration), nil
+		case SET_CONDITION_XX:
+			return runner.SetXX(ctx, options.Key, options.Value, expiration), nil
+		default:
+			return runner.Set(ctx, options.Key, options.Value, expiration), nil
+		}
+	case *ExpireOptions:
+		return runner.Expire(ctx, options.Key, time.Duration(options.TTL)*time.Second), nil
+	case *HashFieldOptions:
+		return runner.HGet(ctx, options.Key, options.Field), nil
+	case *HashSetOptions:
+		return runner.HSet(ctx, options.Key, options.Fields), nil
+	case *HashDeleteOptions:
+		return runner.HDel(ctx, options.Key, options.Fields...), nil
+	case *RangeOptions:
+		if item.Command == GUI_COMMAND_LRANGE {
+			return runner.LRange(ctx, options.Key, options.Start, options.Stop), nil
+		}
+		if options.WithScores {
+			return runner.ZRangeWithScores(ctx, options.Key, options.Start, options.Stop), nil
+		}
+		return runner.ZRange(ctx, options.Key, options.Start, options.Stop), nil
+	case *PushOptions:
+		if item.Command == GUI_COMMAND_LPUSH {
+			return runner.LPush(ctx, options.Key, options.Values...), nil
+		}
+		return runner.RPush(ctx, options.Key, options.Values...), nil
+	case *PopOptions:
+		switch {
+		case item.Command == GUI_COMMAND_LPOP && options.Count > 0:
+			return runner.LPopCount(ctx, options.Key, options.Count), nil
+		case item.Command == GUI_COMMAND_LPOP:
+			return runner.LPop(ctx, options.Key), nil
+		case options.Count > 0:
+			return runner.Do(ctx, "rpop", options.Key, options.Count), nil
+		default:
+			return runner.RPop(ctx, options.Key), nil
+		}
+	case *MembersOptions:
+		switch item.Command {
+		case GUI_COMMAND_SADD:
+			return runner.SAdd(ctx, options.Key, options.Members...), nil
+		case GUI_COMMAND_SREM:
+			return runner.SRem(ctx, options.Key, options.Members...), nil
+		default:
+			return runner.ZRem(ctx, options.Key, options.Members...), nil
+		}
+	case *SortedSetAddOptions:
+		members := make([]*redis.Z, 0, len(options.Members))
+		for _, member := range options.Members {
+			members = append(members, &redis.Z{Score: member.Score, Member: member.Member})
+		}
+		return runner.ZAdd(ctx, options.Key, members...), nil
+	case *StreamAddOptions:
+		return runner.XAdd(ctx, &redis.XAddArgs{
+			Stream: options.Key,
+			ID:     options.ID,
+			MaxLen: options.MaxLen,
+			Approx: options.MaxLen > 0,
+			Values: options.Values,
+		}), nil
+	case *StreamRangeOptions:
+		start, end := options.Start, options.End
+		if start == "" {
+			start = "-"
+		}
+		if end == "" {
+			end = "+"
+		}
+		if options.Count > 0 {
+			return runner.XRangeN(ctx, options.Key, start, end, options.Count), nil
+		}
+		return runner.XRange(ctx, options.Key, start, end), nil
+	case *StreamDeleteOptions:
+		return runner.XDel(ctx, options.Key, options.IDs...), nil
+	}
+	return nil, errors.New("unsupported redis command: " + item.Command)
+}
+
+// runPipeline sends all the commands in one round trip, the transaction wraps them with MULTI/EXEC.
+// Every command has its own row, the failed command reports error in the row instead of failing the whole run,
+// but the failed transaction like EXECABORT fails the run with the rows.
+func runPipeline(ctx context.Context, rdb redis.UniversalClient, items []CommandItem, transaction bool) ([]map[string]interface{}, error) {
+	if len(items) == 0 {
+		return nil, errors.New("missing redis commands")
+	}
+	var pipe redis.Pipeliner
+	if transaction {
+		pipe = rdb.TxPipeline()
+	} else {
+		pipe = rdb.Pipeline()
+	}
+	cmders := make([]redis.Cmder, 0, len(items))
+	for i, item := range items {
+		cmder, err := prepareCommand(ctx, pipe, item)
+		if err != nil {
+			pipe.Discard()
+			return nil, fmt.Errorf("command %d: %w", i+1, err)
+		}
+		cmders = append(cmders, cmder)
+	}
+	// the errors are kept by every command
+	_, errInExec := pipe.Exec(ctx)
+
+	rows := make([]map[string]interface{}, 0, len(cmders))
+	for _, cmder := range cmders {
+		row := map[string]interface{}{
+			"command": exportCommandLine(cmder),
+			"result":  nil,
+		}
+		if err := cmder.Err(); err != nil && err != redis.Nil {
+			row["error"] = err.Error()
+		} else {
+			row["result"] = exportCmdResult(cmder)
+		}
+		rows = append(rows, row)
+	}
+	if transaction && errInExec != nil && errInExec != redis.Nil {
+		return rows, fmt.Errorf("redis transaction failed: %w", errInExec)
+	}
+	return rows, nil
+}
+
+func exportCommandLine(cmder redis.Cmder) string {
+	args := make([]string, 0, len(cmder.Args()))
+	for _, arg := range cmder.Args() {
+		args = append(args, fmt.Sprint(arg))
+	}
+	return strings.Join(args, " ")
+}
+
+// exportCmdResult converts the command value for json, ttl is in seconds with -1 for no expiration and -2 for missing key
+func exportCmdResult(cmder redis.Cmder) interface{} {
+	switch cmd := cmder.(type) {
+	case *redis.Cmd:
+		return cmd.Val()
+	case *redis.StringCmd:
+		if cmd.Err() == redis.Nil {
+			return nil
+		}
+		return cmd.Val()
+	case *redis.StatusCmd:
+		return cmd.Val()
+	case *redis.IntCmd:
+		return cmd.Val()
+	case *redis.BoolCmd:
+		return cmd.Val()
+	case *redis.DurationCmd:
+		if cmd.Val() < 0 {
+			return int64(cmd.Val())
+		}
+		return int64(cmd.Val() / time.Second)
+	case *redis.StringSliceCmd:
+		return cmd.Val()
+	case *redis.StringStringMapCmd:
+		return cmd.Val()
+	case *redis.ZSliceCmd:
+		members := make([]map[string]interface{}, 0, len(cmd.Val()))
+		for _, z := range cmd.Val() {
+			members = append(members, map[string]interface{}{"member": z.Member, "score": z.Score})
+		}
+		return members
+	case *redis.XMessageSliceCmd:
+		messages := make([]map[string]interface{}, 0, len(cmd.Val()))
+		for _, message := range cmd.Val() {
+			messages = append(messages, map[string]interface{}{"id": message.ID, "values": message.Values})
+		}
+		return messages
+	default:
+		return cmder.String()
+	}
+}
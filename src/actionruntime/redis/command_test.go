@@ -0,0 +1,153 @@
+package redis
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestClient(t *testing.T) (*miniredis.Miniredis, redis.UniversalClient) {
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	return server, rdb
+}
+
+func TestParseRawCommand(t *testing.T) {
+	assert.Equal(t, []interface{}{"set", "key", "value"}, parseRawCommand("  set key   value \n"))
+	assert.Empty(t, parseRawCommand("   "))
+}
+
+func TestExportCommandItems(t *testing.T) {
+	command := &Command{Query: "set a 1\n\n  \nget a"}
+	assert.Equal(t, []CommandItem{{Query: "set a 1"}, {Query: "get a"}}, command.exportCommandItems())
+
+	command.Commands = []CommandItem{{Command: GUI_COMMAND_GET, CommandOptions: map[string]interface{}{"key": "a"}}}
+	assert.Equal(t, command.Commands, command.exportCommandItems())
+}
+
+func TestDecodeCommandOptions(t *testing.T) {
+	options, err := decodeCommandOptions(GUI_COMMAND_SET, map[string]interface{}{"key": "a", "value": "1", "ttl": 10, "condition": "nx"})
+	assert.Nil(t, err)
+	assert.Equal(t, &SetOptions{Key: "a", Value: "1", TTL: 10, Condition: SET_CONDITION_NX}, options)
+
+	options, err = decodeCommandOptions(GUI_COMMAND_ZADD, map[string]interface{}{
+		"key":     "z",
+		"members": []interface{}{map[string]interface{}{"score": 1.5, "member": "m"}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &SortedSetAddOptions{Key: "z", Members: []SortedSetMember{{Score: 1.5, Member: "m"}}}, options)
+}
+
+func TestDecodeCommandOptionsRejectsInvalidOptions(t *testing.T) {
+	_, err := decodeCommandOptions("flushall", nil)
+	assert.EqualError(t, err, "unsupported redis command: flushall")
+
+	_, err = decodeCommandOptions(GUI_COMMAND_GET, map[string]interface{}{})
+	assert.NotNil(t, err)
+
+	_, err = decodeCommandOptions(GUI_COMMAND_SET, map[string]interface{}{"key": "a", "condition": "always"})
+	assert.NotNil(t, err)
+
+	_, err = decodeCommandOptions(GUI_COMMAND_DEL, map[string]interface{}{"keys": []string{}})
+	assert.NotNil(t, err)
+
+	_, err = decodeCommandOptions(GUI_COMMAND_EXPIRE, map[string]interface{}{"key": "a", "ttl": "soon"})
+	assert.NotNil(t, err)
+}
+
+func TestExportCmdResult(t *testing.T) {
+	ctx := context.Background()
+	server, rdb := newTestClient(t)
+	server.Set("string", "value")
+	server.SetTTL("string", 90*time.Second)
+	server.Set("persistent", "value")
+	server.ZAdd("zset", 2, "b")
+	server.ZAdd("zset", 1, "a")
+
+	assert.Equal(t, "value", exportCmdResult(rdb.Get(ctx, "string")))
+	assert.Nil(t, exportCmdResult(rdb.Get(ctx, "missing")))
+	assert.Equal(t, int64(90), exportCmdResult(rdb.TTL(ctx, "string")))
+	assert.Equal(t, int64(-1), exportCmdResult(rdb.TTL(ctx, "persistent")))
+	assert.Equal(t, int64(-2), exportCmdResult(rdb.TTL(ctx, "missing")))
+	assert.Equal(t, []map[string]interface{}{
+		{"member": "a", "score": float64(1)},
+		{"member": "b", "score": float64(2)},
+	}, exportCmdResult(rdb.ZRangeWithScores(ctx, "zset", 0, -1)))
+	assert.Equal(t, "OK", exportCmdResult(rdb.Do(ctx, "set", "raw", "1")))
+}
+
+func TestRunPipeline(t *testing.T) {
+	server, rdb := newTestClient(t)
+	items := []CommandItem{
+		{Command: GUI_COMMAND_SET, CommandOptions: map[string]interface{}{"key": "a", "value": "1"}},
+		{Query: "incr a"},
+		{Query: "hget a field"},
+		{Command: GUI_COMMAND_GET, CommandOptions: map[string]interface{}{"key": "a"}},
+	}
+
+	rows, err := runPipeline(context.Background(), rdb, items, false)
+	assert.Nil(t, err)
+	assert.Len(t, rows, 4)
+	assert.Equal(t, map[string]interface{}{"command": "set a 1", "result": "OK"}, rows[0])
+	assert.Equal(t, int64(2), rows[1]["result"])
+	assert.True(t, strings.HasPrefix(rows[2]["error"].(string), "WRONGTYPE"))
+	assert.Equal(t, "2", rows[3]["result"])
+	server.CheckGet(t, "a", "2")
+}
+
+func TestRunPipelineRejectsInvalidCommand(t *testing.T) {
+	server, rdb := newTestClient(t)
+	items := []CommandItem{
+		{Query: "set a 1"},
+		{Command: GUI_COMMAND_GET},
+	}
+
+	rows, err := runPipeline(context.Background(), rdb, items, true)
+	assert.Nil(t, rows)
+	assert.True(t, strings.HasPrefix(err.Error(), "command 2: "))
+	assert.False(t, server.Exists("a"))
+
+	_, err = runPipeline(context.Background(), rdb, nil, false)
+	assert.EqualError(t, err, "missing redis commands")
+}
+
+func TestRunTransaction(t *testing.T) {
+	server, rdb := newTestClient(t)
+	items := []CommandItem{
+		{Query: "set a 1"},
+		{Command: GUI_COMMAND_GET, CommandOptions: map[string]interface{}{"key": "a"}},
+		{Command: GUI_COMMAND_GET, CommandOptions: map[string]interface{}{"key": "missing"}},
+	}
+
+	rows, err := runPipeline(context.Background(), rdb, items, true)
+	assert.Nil(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"command": "set a 1", "result": "OK"},
+		{"command": "get a", "result": "1"},
+		{"command": "get missing", "result": nil},
+	}, rows)
+	server.CheckGet(t, "a", "1")
+}
+
+func TestRunTransactionFailsWhenAborted(t *testing.T) {
+	server, rdb := newTestClient(t)
+	items := []CommandItem{
+		{Query: "set a 1"},
+		{Query: "set b"},
+	}
+
+	rows, err := runPipeline(context.Background(), rdb, items, true)
+	assert.NotNil(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "redis transaction failed: EXECABORT"))
+	assert.Len(t, rows, 2)
+	for _, row := range rows {
+		assert.NotEmpty(t, row["error"])
+	}
+	assert.False(t, server.Exists("a"))
+}
@@ -16,9 +16,11 @@ package redis
 
 import (
 	"context"
-	"strings"
+	"errors"
+	"fmt"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/go-redis/redis/v8"
 	"github.com/mitchellh/mapstructure"
 
 	"github.com/illacloud/builder-backend/src/actionruntime/common"
@@ -54,6 +56,24 @@ func (r *Connector) ValidateActionTemplate(actionOptions map[string]interface{})
 	if err := validate.Struct(r.Action); err != nil {
 		return common.ValidateResult{Valid: false}, err
 	}
+
+	// validate gui command options
+	switch r.Action.Mode {
+	case MODE_GUI:
+		if _, err := decodeCommandOptions(r.Action.Command, r.Action.CommandOptions); err != nil {
+			return common.ValidateResult{Valid: false}, err
+		}
+	case MODE_PIPELINE, MODE_TRANSACTION:
+		items := r.Action.exportCommandItems()
+		if len(items) == 0 {
+			return common.ValidateResult{Valid: false}, errors.New("missing redis commands")
+		}
+		for i, item := range items {
+			if err := validateCommandItem(item); err != nil {
+				return common.ValidateResult{Valid: false}, fmt.Errorf("command %d: %w", i+1, err)
+			}
+		}
+	}
 	return common.ValidateResult{Valid: true}, nil
 }
 
@@ -74,10 +94,25 @@ func (r *Connector) TestConnection(resourceOptions map[string]interface{}) (comm
 }
 
 func (r *Connector) GetMetaInfo(resourceOptions map[string]interface{}) (common.MetaInfoResult, error) {
+	// get redis client
+	rdb, err := r.getConnectionWithOptions(resourceOptions)
+	if err != nil {
+		return common.MetaInfoResult{Success: false}, err
+	}
+	defer rdb.Close()
+
+	// browse keys
+	keys, truncated, err := browseKeys(context.Background(), rdb)
+	if err != nil {
+		return common.MetaInfoResult{Success: false}, err
+	}
 
 	return common.MetaInfoResult{
 		Success: true,
-		Schema:  nil,
+		Schema: map[string]interface{}{
+			"keys":      keys,
+			"truncated": truncated,
+		},
 	}, nil
 }
 
@@ -98,23 +133,40 @@ func (r *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 	if err := mapstructure.Decode(actionOptions, &r.Action); err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
-	redisCMD := strings.TrimSpace(r.Action.Query)
-	redisCMDSlice := strings.Fields(redisCMD)
-	inputRedisCMDSlice := make([]interface{}, len(redisCMDSlice))
-	for i, v := range redisCMDSlice {
-		inputRedisCMDSlice[i] = v
+	cmdResult := common.RuntimeResult{
+		Success: true,
+		Rows:    []map[string]interface{}{},
+		Extra:   map[string]interface{}{},
+	}
+
+	// run redis commands in one round trip
+	if r.Action.Mode == MODE_PIPELINE || r.Action.Mode == MODE_TRANSACTION {
+		rows, err := runPipeline(context.Background(), rdb, r.Action.exportCommandItems(), r.Action.Mode == MODE_TRANSACTION)
+		if err != nil {
+			return common.RuntimeResult{Success: false, Rows: rows}, err
+		}
+		cmdResult.Rows = rows
+		return cmdResult, nil
+	}
+
+	// run gui command
+	if r.Action.Mode == MODE_GUI {
+		cmder, err := prepareCommand(context.Background(), rdb, CommandItem{Command: r.Action.Command, CommandOptions: r.Action.CommandOptions})
+		if err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		if err := cmder.Err(); err != nil && err != redis.Nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		cmdResult.Rows = append(cmdResult.Rows, map[string]interface{}{"result": exportCmdResult(cmder)})
+		return cmdResult, nil
 	}
 
 	// run redis command
-	val, err := rdb.Do(context.Background(), inputRedisCMDSlice...).Result()
+	val, err := rdb.Do(context.Background(), parseRawCommand(r.Action.Query)...).Result()
 	if err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
-	cmdResult := common.RuntimeResult{
-		Success: true,
-		Rows:    []map[string]interface{}{},
-		Extra:   map[string]interface{}{},
-	}
 	cmdResult.Rows = append(cmdResult.Rows, map[string]interface{}{"result": val})
 
 	return cmdResult, nil
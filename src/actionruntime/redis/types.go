@@ -21,9 +21,110 @@ type Options struct {
 	DatabaseUsername string
 	DatabasePassword string
 	SSL              bool
+	Topology         string `validate:"omitempty,oneof=standalone cluster sentinel"`
+	Nodes            []string
+	MasterName       string `validate:"required_if=Topology sentinel"`
+	SentinelUsername string
+	SentinelPassword string
 }
 
 type Command struct {
-	Mode  string `validate:"required,oneof=select raw"`
-	Query string
+	Mode           string `validate:"required,oneof=select raw gui pipeline transaction"`
+	Query          string
+	Command        string `validate:"required_if=Mode gui"`
+	CommandOptions map[string]interface{}
+	Commands       []CommandItem `validate:"dive"`
+}
+
+// CommandItem is one command of pipeline or transaction, the raw query is used when the gui command is empty
+type CommandItem struct {
+	Query          string
+	Command        string
+	CommandOptions map[string]interface{}
+}
+
+type KeyOptions struct {
+	Key string `validate:"required"`
+}
+
+type KeysOptions struct {
+	Keys []string `validate:"required,gt=0,dive,required"`
+}
+
+type SetOptions struct {
+	Key       string `validate:"required"`
+	Value     interface{}
+	TTL       int    `validate:"gte=0"`
+	Condition string `validate:"omitempty,oneof=nx xx"`
+}
+
+type ExpireOptions struct {
+	Key string `validate:"required"`
+	TTL int    `validate:"gt=0"`
+}
+
+type HashFieldOptions struct {
+	Key   string `validate:"required"`
+	Field string `validate:"required"`
+}
+
+type HashSetOptions struct {
+	Key    string                 `validate:"required"`
+	Fields map[string]interface{} `validate:"required,gt=0"`
+}
+
+type HashDeleteOptions struct {
+	Key    string   `validate:"required"`
+	Fields []string `validate:"required,gt=0"`
+}
+
+type RangeOptions struct {
+	Key        string `validate:"required"`
+	Start      int64
+	Stop       int64
+	WithScores bool
+}
+
+type PushOptions struct {
+	Key    string        `validate:"required"`
+	Values []interface{} `validate:"required,gt=0"`
+}
+
+type PopOptions struct {
+	Key   string `validate:"required"`
+	Count int    `validate:"gte=0"`
+}
+
+type MembersOptions struct {
+	Key     string        `validate:"required"`
+	Members []interface{} `validate:"required,gt=0"`
+}
+
+type SortedSetAddOptions struct {
+	Key     string            `validate:"required"`
+	Members []SortedSetMember `validate:"required,gt=0"`
+}
+
+type SortedSetMember struct {
+	Score  float64
+	Member interface{}
+}
+
+type StreamAddOptions struct {
+	Key    string `validate:"required"`
+	ID     string
+	Values map[string]interface{} `validate:"required,gt=0"`
+	MaxLen int64                  `validate:"gte=0"`
+}
+
+type StreamRangeOptions struct {
+	Key   string `validate:"required"`
+	Start string
+	End   string
+	Count int64 `validate:"gte=0"`
+}
+
+type StreamDeleteOptions struct {
+	Key string   `validate:"required"`
+	IDs []string `validate:"required,gt=0"`
 }
@@ -36,6 +36,7 @@ const (
 	AUTH_OAUTH1 = "oauth1.0"
 	AUTH_HAWK   = "hawk"
 	AUTH_AWS    = "aws"
+	AUTH_OAUTH2 = "oauth2"
 
 	VERIFY_MODE_SKIP = "skip"
 	VERIFY_MODE_FULL = "verify-full"
@@ -0,0 +1,24 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restapi
+
+import (
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+)
+
+// ClassifyError only handles the transport errors, the HTTP error status is responded as a normal result with "statusCode" in extra.
+func (r *RESTAPIConnector) ClassifyError(err error) *common.ConnectorError {
+	return common.ClassifyNetworkError(err)
+}
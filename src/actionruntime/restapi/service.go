@@ -27,6 +27,7 @@ import (
 	"github.com/go-resty/resty/v2"
 	"github.com/icholy/digest"
 	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/illacloud/builder-backend/src/utils/oauthgeneric"
 	parser_template "github.com/illacloud/builder-backend/src/utils/parser/template"
 	"github.com/mitchellh/mapstructure"
 )
@@ -66,6 +67,10 @@ func (r *RESTAPIConnector) ValidateResourceOptions(resourceOptions map[string]in
 		if !ok || bearerToken == "" {
 			return common.ValidateResult{Valid: false}, errors.New("missing bearer token")
 		}
+	case AUTH_OAUTH2:
+		if _, err := oauthgeneric.NewProviderByAuthContent(r.Resource.AuthContent); err != nil {
+			return common.ValidateResult{Valid: false}, err
+		}
 	}
 	return common.ValidateResult{Valid: true}, nil
 }
@@ -199,6 +204,12 @@ func (r *RESTAPIConnector) Run(resourceOptions map[string]interface{}, actionOpt
 		break
 	case AUTH_OAUTH1:
 		break
+	case AUTH_OAUTH2:
+		accessToken, errInExportAccessToken := oauthgeneric.ExportAccessToken(r.Resource.AuthContent)
+		if errInExportAccessToken != nil {
+			return res, errInExportAccessToken
+		}
+		client.SetAuthToken(accessToken)
 	}
 
 	// resty client instance set `action` options
@@ -43,7 +43,7 @@ type RESTOptions struct {
 	Cookies        []map[string]string
 	SelfSignedCert bool
 	Certs          map[string]string `validate:"required_unless=SelfSignedCert false"`
-	Authentication string            `validate:"oneof=none basic bearer digest oauth1.0 hawk aws"`
+	Authentication string            `validate:"oneof=none basic bearer digest oauth1.0 hawk aws oauth2"`
 	AuthContent    map[string]string `validate:"required_unless=Authentication none"`
 }
 
@@ -39,7 +39,8 @@ func (s *Connector) getConnectionWithOptions(resourceOptions map[string]interfac
 	if s.ResourceOpts.Endpoint {
 		customResolver := aws.EndpointResolverWithOptionsFunc(func(service, region string, options ...interface{}) (aws.Endpoint, error) {
 			return aws.Endpoint{
-				URL: s.ResourceOpts.BaseURL,
+				URL:           s.ResourceOpts.BaseURL,
+				SigningRegion: region,
 			}, nil
 		})
 		cfg, err = config.LoadDefaultConfig(context.Background(),
@@ -57,7 +58,9 @@ func (s *Connector) getConnectionWithOptions(resourceOptions map[string]interfac
 	}
 
 	// create an S3 service client
-	s3Client := s3.NewFromConfig(cfg)
+	s3Client := s3.NewFromConfig(cfg, func(options *s3.Options) {
+		options.UsePathStyle = s.ResourceOpts.ForcePathStyle
+	})
 
 	return s3Client, nil
 }
@@ -92,3 +95,21 @@ func presignPutObject(client *s3.Client, bucket, objectKey, ACL string, expiry t
 	}
 	return output.URL, nil
 }
+
+func listPrefixes(client *s3.Client, bucket string) ([]string, error) {
+	delimiter := "/"
+	output, err := client.ListObjectsV2(context.TODO(), &s3.ListObjectsV2Input{
+		Bucket:    &bucket,
+		Delimiter: &delimiter,
+	})
+	if err != nil {
+		return nil, err
+	}
+	prefixes := make([]string, 0, len(output.CommonPrefixes))
+	for _, commonPrefix := range output.CommonPrefixes {
+		if commonPrefix.Prefix != nil {
+			prefixes = append(prefixes, *commonPrefix.Prefix)
+		}
+	}
+	return prefixes, nil
+}
@@ -335,3 +335,22 @@ func (c *CommandExecutor) uploadMultipleObjects(ACL string) (common.RuntimeResul
 		Extra:   nil,
 	}, nil
 }
+
+// exportBucketName returns the bucket name of command, the bucket name of resource is used when it is empty
+func (c *CommandExecutor) exportBucketName(bucketName string) (string, error) {
+	if bucketName != "" {
+		return bucketName, nil
+	}
+	if c.bucket == "" {
+		return "", errors.New("no bucket name")
+	}
+	return c.bucket, nil
+}
+
+func decodeCommandArgs(commandArgs map[string]interface{}, args interface{}) error {
+	if err := mapstructure.Decode(commandArgs, args); err != nil {
+		return err
+	}
+	validate := validator.New()
+	return validate.Struct(args)
+}
@@ -0,0 +1,203 @@
+//go:build integration
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/assert"
+)
+
+// the tests run against MinIO, start it by:
+// docker run -p 9000:9000 minio/minio server /data
+// then run: S3_TEST_ENDPOINT=http://127.0.0.1:9000 go test -tags integration ./src/actionruntime/s3/
+func newMinIOConnector(t *testing.T) (*Connector, map[string]interface{}) {
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT is not set")
+	}
+	accessKeyID := os.Getenv("S3_TEST_ACCESS_KEY_ID")
+	if accessKeyID == "" {
+		accessKeyID = "minioadmin"
+	}
+	secretAccessKey := os.Getenv("S3_TEST_SECRET_ACCESS_KEY")
+	if secretAccessKey == "" {
+		secretAccessKey = "minioadmin"
+	}
+	bucketName := "illa-test-" + time.Now().Format("20060102150405")
+	resourceOptions := map[string]interface{}{
+		"bucketName":      bucketName,
+		"region":          "us-east-1",
+		"endpoint":        true,
+		"baseURL":         endpoint,
+		"accessKeyID":     accessKeyID,
+		"secretAccessKey": secretAccessKey,
+		"forcePathStyle":  true,
+	}
+	connector := &Connector{}
+	client, err := connector.getConnectionWithOptions(resourceOptions)
+	assert.Nil(t, err)
+	_, err = client.CreateBucket(context.Background(), &s3.CreateBucketInput{Bucket: &bucketName})
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		objects, err := client.ListObjectsV2(context.Background(), &s3.ListObjectsV2Input{Bucket: &bucketName})
+		if err == nil {
+			for _, object := range objects.Contents {
+				client.DeleteObject(context.Background(), &s3.DeleteObjectInput{Bucket: &bucketName, Key: object.Key})
+			}
+		}
+		client.DeleteBucket(context.Background(), &s3.DeleteBucketInput{Bucket: &bucketName})
+	})
+	return connector, resourceOptions
+}
+
+func runCommand(t *testing.T, connector *Connector, resourceOptions map[string]interface{}, command string, commandArgs map[string]interface{}) map[string]interface{} {
+	result, err := connector.Run(resourceOptions, map[string]interface{}{"commands": command, "commandArgs": commandArgs}, nil)
+	assert.Nil(t, err)
+	assert.True(t, result.Success)
+	if len(result.Rows) == 0 {
+		return map[string]interface{}{"extra": result.Extra}
+	}
+	return result.Rows[0]
+}
+
+func sendRequest(t *testing.T, method string, url string, headers map[string]string, body []byte) *http.Response {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	assert.Nil(t, err)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	req.ContentLength = int64(len(body))
+	res, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	return res
+}
+
+func TestMinIOPresignedPutAndGet(t *testing.T) {
+	connector, resourceOptions := newMinIOConnector(t)
+	content := []byte("hello presigned")
+
+	presignedPut := runCommand(t, connector, resourceOptions, PRESIGNED_PUT_COMMAND, map[string]interface{}{
+		"objectKey":     "dir/hello world.txt",
+		"expiry":        10,
+		"contentType":   "text/plain",
+		"contentLength": len(content),
+	})
+	res := sendRequest(t, presignedPut["method"].(string), presignedPut["url"].(string), presignedPut["headers"].(map[string]string), content)
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	presignedGet := runCommand(t, connector, resourceOptions, PRESIGNED_GET_COMMAND, map[string]interface{}{
+		"objectKey":           "dir/hello world.txt",
+		"expiry":              10,
+		"responseContentType": "application/octet-stream",
+	})
+	res = sendRequest(t, presignedGet["method"].(string), presignedGet["url"].(string), nil, nil)
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, content, body)
+	assert.Equal(t, "application/octet-stream", res.Header.Get("Content-Type"))
+
+	// the signed content length must be matched
+	presignedPut = runCommand(t, connector, resourceOptions, PRESIGNED_PUT_COMMAND, map[string]interface{}{
+		"objectKey":     "mismatch.txt",
+		"expiry":        10,
+		"contentLength": len(content),
+	})
+	res = sendRequest(t, presignedPut["method"].(string), presignedPut["url"].(string), presignedPut["headers"].(map[string]string), append(content, '!'))
+	res.Body.Close()
+	assert.NotEqual(t, http.StatusOK, res.StatusCode)
+}
+
+func TestMinIOMultipartUpload(t *testing.T) {
+	connector, resourceOptions := newMinIOConnector(t)
+
+	result, err := connector.Run(resourceOptions, map[string]interface{}{
+		"commands":    CREATE_MULTIPART_UPLOAD_COMMAND,
+		"commandArgs": map[string]interface{}{"objectKey": "large.bin", "partCount": 2, "expiry": 10},
+	}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(result.Rows))
+	uploadID := result.Extra["uploadID"].(string)
+
+	// every part except the last one must be at least 5MB
+	partContents := [][]byte{bytes.Repeat([]byte("a"), 5*1024*1024), []byte("tail")}
+	parts := make([]interface{}, 0, len(result.Rows))
+	for i, part := range result.Rows {
+		res := sendRequest(t, part["method"].(string), part["url"].(string), nil, partContents[i])
+		res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		parts = append(parts, map[string]interface{}{"partNumber": part["partNumber"], "eTag": res.Header.Get("ETag")})
+	}
+	// the parts are sorted before complete
+	parts[0], parts[1] = parts[1], parts[0]
+	runCommand(t, connector, resourceOptions, COMPLETE_MULTIPART_UPLOAD_COMMAND, map[string]interface{}{
+		"objectKey": "large.bin",
+		"uploadID":  uploadID,
+		"parts":     parts,
+	})
+
+	head := runCommand(t, connector, resourceOptions, HEAD_OBJECT_COMMAND, map[string]interface{}{"objectKey": "large.bin"})
+	assert.Equal(t, int64(5*1024*1024+4), head["contentLength"])
+
+	// abort a pending upload
+	result, err = connector.Run(resourceOptions, map[string]interface{}{
+		"commands":    CREATE_MULTIPART_UPLOAD_COMMAND,
+		"commandArgs": map[string]interface{}{"objectKey": "aborted.bin", "partCount": 1, "expiry": 10},
+	}, nil)
+	assert.Nil(t, err)
+	runCommand(t, connector, resourceOptions, ABORT_MULTIPART_UPLOAD_COMMAND, map[string]interface{}{
+		"objectKey": "aborted.bin",
+		"uploadID":  result.Extra["uploadID"],
+	})
+}
+
+func TestMinIOCopyAndMove(t *testing.T) {
+	connector, resourceOptions := newMinIOConnector(t)
+	client, _ := connector.getConnectionWithOptions(resourceOptions)
+	bucketName := resourceOptions["bucketName"].(string)
+	sourceKey := "source dir/a+b.txt"
+	_, err := client.PutObject(context.Background(), &s3.PutObjectInput{Bucket: &bucketName, Key: &sourceKey, Body: strings.NewReader("copy me")})
+	assert.Nil(t, err)
+
+	copyResult := runCommand(t, connector, resourceOptions, COPY_COMMAND, map[string]interface{}{"sourceObjectKey": sourceKey, "objectKey": "copied.txt"})
+	assert.Equal(t, "copied.txt", copyResult["key"])
+	runCommand(t, connector, resourceOptions, HEAD_OBJECT_COMMAND, map[string]interface{}{"objectKey": sourceKey})
+
+	moveResult := runCommand(t, connector, resourceOptions, MOVE_COMMAND, map[string]interface{}{"sourceObjectKey": sourceKey, "objectKey": "moved.txt"})
+	assert.Equal(t, true, moveResult["moved"])
+	head := runCommand(t, connector, resourceOptions, HEAD_OBJECT_COMMAND, map[string]interface{}{"objectKey": "moved.txt"})
+	assert.Equal(t, int64(7), head["contentLength"])
+	_, err = connector.Run(resourceOptions, map[string]interface{}{"commands": HEAD_OBJECT_COMMAND, "commandArgs": map[string]interface{}{"objectKey": sourceKey}}, nil)
+	assert.NotNil(t, err)
+}
+
+func TestMinIOTags(t *testing.T) {
+	connector, resourceOptions := newMinIOConnector(t)
+	client, _ := connector.getConnectionWithOptions(resourceOptions)
+	bucketName := resourceOptions["bucketName"].(string)
+	objectKey := "tagged.txt"
+	_, err := client.PutObject(context.Background(), &s3.PutObjectInput{Bucket: &bucketName, Key: &objectKey, Body: strings.NewReader("tagged")})
+	assert.Nil(t, err)
+
+	tags := runCommand(t, connector, resourceOptions, GET_TAGS_COMMAND, map[string]interface{}{"objectKey": objectKey})
+	assert.Equal(t, map[string]string{}, tags["tags"])
+
+	runCommand(t, connector, resourceOptions, PUT_TAGS_COMMAND, map[string]interface{}{"objectKey": objectKey, "tags": map[string]string{"env": "test", "owner": "illa"}})
+	tags = runCommand(t, connector, resourceOptions, GET_TAGS_COMMAND, map[string]interface{}{"objectKey": objectKey})
+	assert.Equal(t, map[string]string{"env": "test", "owner": "illa"}, tags["tags"])
+
+	// put tags replaces all tags
+	runCommand(t, connector, resourceOptions, PUT_TAGS_COMMAND, map[string]interface{}{"objectKey": objectKey, "tags": map[string]string{"env": "prod"}})
+	tags = runCommand(t, connector, resourceOptions, GET_TAGS_COMMAND, map[string]interface{}{"objectKey": objectKey})
+	assert.Equal(t, map[string]string{"env": "prod"}, tags["tags"])
+}
@@ -0,0 +1,167 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+)
+
+// createMultipartUpload starts a multipart upload and presigns the url of every part,
+// the parts are uploaded by client directly, then the upload is completed with the etag of parts.
+func (c *CommandExecutor) createMultipartUpload(ACL string) (common.RuntimeResult, error) {
+	var createMultipartUploadCommandArgs CreateMultipartUploadCommandArgs
+	if err := decodeCommandArgs(c.command.CommandArgs, &createMultipartUploadCommandArgs); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	bucketName, err := c.exportBucketName(createMultipartUploadCommandArgs.BucketName)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// build CreateMultipartUploadInput
+	params := s3.CreateMultipartUploadInput{
+		Bucket: &bucketName,
+		Key:    &createMultipartUploadCommandArgs.ObjectKey,
+	}
+	if createMultipartUploadCommandArgs.ContentType != "" {
+		params.ContentType = &createMultipartUploadCommandArgs.ContentType
+	}
+	if ACL != "" {
+		params.ACL = types.ObjectCannedACL(ACL)
+	}
+	res, err := c.client.CreateMultipartUpload(context.TODO(), &params)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// presign the upload part urls
+	expiryDuration := time.Duration(createMultipartUploadCommandArgs.Expiry) * time.Minute
+	presignClient := s3.NewPresignClient(c.client, s3.WithPresignExpires(expiryDuration))
+	parts := make([]map[string]interface{}, 0, createMultipartUploadCommandArgs.PartCount)
+	for partNumber := int32(1); partNumber <= createMultipartUploadCommandArgs.PartCount; partNumber++ {
+		presignedRequest, err := presignClient.PresignUploadPart(context.TODO(), &s3.UploadPartInput{
+			Bucket:     &bucketName,
+			Key:        &createMultipartUploadCommandArgs.ObjectKey,
+			UploadId:   res.UploadId,
+			PartNumber: partNumber,
+		})
+		if err != nil {
+			c.abortMultipartUploadQuietly(bucketName, createMultipartUploadCommandArgs.ObjectKey, res.UploadId)
+			return common.RuntimeResult{Success: false}, err
+		}
+		parts = append(parts, map[string]interface{}{
+			"partNumber": partNumber,
+			"url":        presignedRequest.URL,
+			"method":     presignedRequest.Method,
+		})
+	}
+
+	return common.RuntimeResult{
+		Success: true,
+		Rows:    parts,
+		Extra: map[string]interface{}{
+			"key":       createMultipartUploadCommandArgs.ObjectKey,
+			"uploadID":  *res.UploadId,
+			"expiresAt": time.Now().UTC().Add(expiryDuration).Format(time.RFC3339),
+		},
+	}, nil
+}
+
+func (c *CommandExecutor) completeMultipartUpload() (common.RuntimeResult, error) {
+	var completeMultipartUploadCommandArgs CompleteMultipartUploadCommandArgs
+	if err := decodeCommandArgs(c.command.CommandArgs, &completeMultipartUploadCommandArgs); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	bucketName, err := c.exportBucketName(completeMultipartUploadCommandArgs.BucketName)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// the parts must be in ascending order
+	sort.Slice(completeMultipartUploadCommandArgs.Parts, func(i, j int) bool {
+		return completeMultipartUploadCommandArgs.Parts[i].PartNumber < completeMultipartUploadCommandArgs.Parts[j].PartNumber
+	})
+	completedParts := make([]types.CompletedPart, 0, len(completeMultipartUploadCommandArgs.Parts))
+	for i := range completeMultipartUploadCommandArgs.Parts {
+		completedParts = append(completedParts, types.CompletedPart{
+			PartNumber: completeMultipartUploadCommandArgs.Parts[i].PartNumber,
+			ETag:       &completeMultipartUploadCommandArgs.Parts[i].ETag,
+		})
+	}
+
+	// build CompleteMultipartUploadInput
+	params := s3.CompleteMultipartUploadInput{
+		Bucket:          &bucketName,
+		Key:             &completeMultipartUploadCommandArgs.ObjectKey,
+		UploadId:        &completeMultipartUploadCommandArgs.UploadID,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completedParts},
+	}
+	res, err := c.client.CompleteMultipartUpload(context.TODO(), &params)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	return common.RuntimeResult{
+		Success: true,
+		Rows: []map[string]interface{}{{
+			"key":       completeMultipartUploadCommandArgs.ObjectKey,
+			"location":  res.Location,
+			"eTag":      res.ETag,
+			"versionID": res.VersionId,
+		}},
+		Extra: nil,
+	}, nil
+}
+
+func (c *CommandExecutor) abortMultipartUpload() (common.RuntimeResult, error) {
+	var abortMultipartUploadCommandArgs AbortMultipartUploadCommandArgs
+	if err := decodeCommandArgs(c.command.CommandArgs, &abortMultipartUploadCommandArgs); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	bucketName, err := c.exportBucketName(abortMultipartUploadCommandArgs.BucketName)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// build AbortMultipartUploadInput
+	params := s3.AbortMultipartUploadInput{
+		Bucket:   &bucketName,
+		Key:      &abortMultipartUploadCommandArgs.ObjectKey,
+		UploadId: &abortMultipartUploadCommandArgs.UploadID,
+	}
+	if _, err := c.client.AbortMultipartUpload(context.TODO(), &params); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	return common.RuntimeResult{
+		Success: true,
+		Rows:    []map[string]interface{}{{"key": abortMultipartUploadCommandArgs.ObjectKey, "uploadID": abortMultipartUploadCommandArgs.UploadID, "aborted": true}},
+		Extra:   nil,
+	}, nil
+}
+
+func (c *CommandExecutor) abortMultipartUploadQuietly(bucketName string, objectKey string, uploadID *string) {
+	_, _ = c.client.AbortMultipartUpload(context.TODO(), &s3.AbortMultipartUploadInput{
+		Bucket:   &bucketName,
+		Key:      &objectKey,
+		UploadId: uploadID,
+	})
+}
@@ -0,0 +1,199 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+)
+
+// copyObject copies the object in server side, the move deletes the source object after copied.
+func (c *CommandExecutor) copyObject(move bool) (common.RuntimeResult, error) {
+	var copyCommandArgs CopyCommandArgs
+	if err := decodeCommandArgs(c.command.CommandArgs, &copyCommandArgs); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	bucketName, err := c.exportBucketName(copyCommandArgs.BucketName)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	sourceBucketName := copyCommandArgs.SourceBucketName
+	if sourceBucketName == "" {
+		sourceBucketName = bucketName
+	}
+
+	// build CopyObjectInput, the copy source is url encoded
+	copySource := sourceBucketName + "/" + escapeObjectKey(copyCommandArgs.SourceObjectKey)
+	params := s3.CopyObjectInput{
+		Bucket:     &bucketName,
+		Key:        &copyCommandArgs.ObjectKey,
+		CopySource: &copySource,
+	}
+	res, err := c.client.CopyObject(context.TODO(), &params)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	copyResult := map[string]interface{}{
+		"sourceKey": copyCommandArgs.SourceObjectKey,
+		"key":       copyCommandArgs.ObjectKey,
+	}
+	if res.CopyObjectResult != nil {
+		copyResult["eTag"] = res.CopyObjectResult.ETag
+		copyResult["lastModified"] = res.CopyObjectResult.LastModified
+	}
+
+	if move {
+		// nothing to delete when moved to itself
+		if sourceBucketName != bucketName || copyCommandArgs.SourceObjectKey != copyCommandArgs.ObjectKey {
+			if _, err := c.client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
+				Bucket: &sourceBucketName,
+				Key:    &copyCommandArgs.SourceObjectKey,
+			}); err != nil {
+				return common.RuntimeResult{Success: false}, err
+			}
+		}
+		copyResult["moved"] = true
+	}
+
+	return common.RuntimeResult{
+		Success: true,
+		Rows:    []map[string]interface{}{copyResult},
+		Extra:   nil,
+	}, nil
+}
+
+func (c *CommandExecutor) headObject() (common.RuntimeResult, error) {
+	var headObjectCommandArgs HeadObjectCommandArgs
+	if err := decodeCommandArgs(c.command.CommandArgs, &headObjectCommandArgs); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	bucketName, err := c.exportBucketName(headObjectCommandArgs.BucketName)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	res, err := c.client.HeadObject(context.TODO(), &s3.HeadObjectInput{
+		Bucket: &bucketName,
+		Key:    &headObjectCommandArgs.ObjectKey,
+	})
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	return common.RuntimeResult{
+		Success: true,
+		Rows: []map[string]interface{}{{
+			"key":                headObjectCommandArgs.ObjectKey,
+			"contentType":        res.ContentType,
+			"contentLength":      res.ContentLength,
+			"contentEncoding":    res.ContentEncoding,
+			"contentDisposition": res.ContentDisposition,
+			"cacheControl":       res.CacheControl,
+			"eTag":               res.ETag,
+			"lastModified":       res.LastModified,
+			"storageClass":       res.StorageClass,
+			"versionID":          res.VersionId,
+			"metadata":           res.Metadata,
+		}},
+		Extra: nil,
+	}, nil
+}
+
+func (c *CommandExecutor) getTags() (common.RuntimeResult, error) {
+	var getTagsCommandArgs GetTagsCommandArgs
+	if err := decodeCommandArgs(c.command.CommandArgs, &getTagsCommandArgs); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	bucketName, err := c.exportBucketName(getTagsCommandArgs.BucketName)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	params := s3.GetObjectTaggingInput{
+		Bucket: &bucketName,
+		Key:    &getTagsCommandArgs.ObjectKey,
+	}
+	if getTagsCommandArgs.VersionID != "" {
+		params.VersionId = &getTagsCommandArgs.VersionID
+	}
+	res, err := c.client.GetObjectTagging(context.TODO(), &params)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	tags := make(map[string]string, len(res.TagSet))
+	for _, tag := range res.TagSet {
+		if tag.Key != nil && tag.Value != nil {
+			tags[*tag.Key] = *tag.Value
+		}
+	}
+
+	return common.RuntimeResult{
+		Success: true,
+		Rows:    []map[string]interface{}{{"key": getTagsCommandArgs.ObjectKey, "tags": tags}},
+		Extra:   nil,
+	}, nil
+}
+
+// putTags replaces all tags of the object
+func (c *CommandExecutor) putTags() (common.RuntimeResult, error) {
+	var putTagsCommandArgs PutTagsCommandArgs
+	if err := decodeCommandArgs(c.command.CommandArgs, &putTagsCommandArgs); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	bucketName, err := c.exportBucketName(putTagsCommandArgs.BucketName)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	tagKeys := make([]string, 0, len(putTagsCommandArgs.Tags))
+	for key := range putTagsCommandArgs.Tags {
+		tagKeys = append(tagKeys, key)
+	}
+	sort.Strings(tagKeys)
+	tagSet := make([]types.Tag, 0, len(tagKeys))
+	for i := range tagKeys {
+		value := putTagsCommandArgs.Tags[tagKeys[i]]
+		tagSet = append(tagSet, types.Tag{Key: &tagKeys[i], Value: &value})
+	}
+	if _, err := c.client.PutObjectTagging(context.TODO(), &s3.PutObjectTaggingInput{
+		Bucket:  &bucketName,
+		Key:     &putTagsCommandArgs.ObjectKey,
+		Tagging: &types.Tagging{TagSet: tagSet},
+	}); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	return common.RuntimeResult{
+		Success: true,
+		Rows:    []map[string]interface{}{{"key": putTagsCommandArgs.ObjectKey, "tags": putTagsCommandArgs.Tags}},
+		Extra:   nil,
+	}, nil
+}
+
+// escapeObjectKey escapes every segment of object key, and keeps the slashes
+func escapeObjectKey(objectKey string) string {
+	segments := strings.Split(objectKey, "/")
+	for i := range segments {
+		// the plus sign is decoded to space by some servers, escape it to keep it literal
+		segments[i] = strings.ReplaceAll(url.PathEscape(segments[i]), "+", "%2B")
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,12 @@
+package s3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeObjectKey(t *testing.T) {
+	assert.Equal(t, "dir/sub%20dir/a%2Bb.txt", escapeObjectKey("dir/sub dir/a+b.txt"))
+	assert.Equal(t, "%E6%96%87%E4%BB%B6/%3F%23.txt", escapeObjectKey("文件/?#.txt"))
+}
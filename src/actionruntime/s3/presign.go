@@ -0,0 +1,115 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+)
+
+func (c *CommandExecutor) presignedGet() (common.RuntimeResult, error) {
+	var presignedGetCommandArgs PresignedGetCommandArgs
+	if err := decodeCommandArgs(c.command.CommandArgs, &presignedGetCommandArgs); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	bucketName, err := c.exportBucketName(presignedGetCommandArgs.BucketName)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// build GetObjectInput, the response headers override the stored content type and disposition
+	params := s3.GetObjectInput{
+		Bucket: &bucketName,
+		Key:    &presignedGetCommandArgs.ObjectKey,
+	}
+	if presignedGetCommandArgs.ResponseContentType != "" {
+		params.ResponseContentType = &presignedGetCommandArgs.ResponseContentType
+	}
+	if presignedGetCommandArgs.ResponseContentDisposition != "" {
+		params.ResponseContentDisposition = &presignedGetCommandArgs.ResponseContentDisposition
+	}
+	expiryDuration := time.Duration(presignedGetCommandArgs.Expiry) * time.Minute
+	presignClient := s3.NewPresignClient(c.client, s3.WithPresignExpires(expiryDuration))
+	presignedRequest, err := presignClient.PresignGetObject(context.TODO(), &params)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	return common.RuntimeResult{
+		Success: true,
+		Rows:    []map[string]interface{}{exportPresignedRequest(presignedGetCommandArgs.ObjectKey, presignedRequest.URL, presignedRequest.Method, nil, expiryDuration)},
+		Extra:   nil,
+	}, nil
+}
+
+func (c *CommandExecutor) presignedPut(ACL string) (common.RuntimeResult, error) {
+	var presignedPutCommandArgs PresignedPutCommandArgs
+	if err := decodeCommandArgs(c.command.CommandArgs, &presignedPutCommandArgs); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	bucketName, err := c.exportBucketName(presignedPutCommandArgs.BucketName)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// build PutObjectInput, the content type and content length are signed, so the upload must match them
+	params := s3.PutObjectInput{
+		Bucket:        &bucketName,
+		Key:           &presignedPutCommandArgs.ObjectKey,
+		ContentLength: presignedPutCommandArgs.ContentLength,
+	}
+	if presignedPutCommandArgs.ContentType != "" {
+		params.ContentType = &presignedPutCommandArgs.ContentType
+	}
+	if ACL != "" {
+		params.ACL = types.ObjectCannedACL(ACL)
+	}
+	expiryDuration := time.Duration(presignedPutCommandArgs.Expiry) * time.Minute
+	presignClient := s3.NewPresignClient(c.client, s3.WithPresignExpires(expiryDuration))
+	presignedRequest, err := presignClient.PresignPutObject(context.TODO(), &params)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	return common.RuntimeResult{
+		Success: true,
+		Rows:    []map[string]interface{}{exportPresignedRequest(presignedPutCommandArgs.ObjectKey, presignedRequest.URL, presignedRequest.Method, presignedRequest.SignedHeader, expiryDuration)},
+		Extra:   nil,
+	}, nil
+}
+
+// exportPresignedRequest exports the presigned url with the headers which should be sent with it
+func exportPresignedRequest(objectKey string, url string, method string, signedHeader http.Header, expiry time.Duration) map[string]interface{} {
+	headers := make(map[string]string, len(signedHeader))
+	for name := range signedHeader {
+		// the host header is sent by client automatically
+		if http.CanonicalHeaderKey(name) == "Host" {
+			continue
+		}
+		headers[name] = signedHeader.Get(name)
+	}
+	return map[string]interface{}{
+		"key":       objectKey,
+		"url":       url,
+		"method":    method,
+		"headers":   headers,
+		"expiresAt": time.Now().UTC().Add(expiry).Format(time.RFC3339),
+	}
+}
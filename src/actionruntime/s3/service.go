@@ -93,9 +93,33 @@ func (s *Connector) GetMetaInfo(resourceOptions map[string]interface{}) (common.
 		return common.MetaInfoResult{Success: false}, err
 	}
 
+	// get top level prefixes, the default bucket of resource only when it is set
+	prefixBuckets := make([]string, 0)
+	if s.ResourceOpts.BucketName != "" {
+		prefixBuckets = append(prefixBuckets, s.ResourceOpts.BucketName)
+	} else {
+		for _, bucket := range buckets.Buckets {
+			if len(prefixBuckets) >= META_INFO_PREFIX_BUCKET_LIMIT {
+				break
+			}
+			if bucket.Name != nil {
+				prefixBuckets = append(prefixBuckets, *bucket.Name)
+			}
+		}
+	}
+	prefixes := make(map[string][]string, len(prefixBuckets))
+	for _, bucketName := range prefixBuckets {
+		bucketPrefixes, err := listPrefixes(s3Client, bucketName)
+		// the bucket may be in other region or not accessible, skip it
+		if err != nil {
+			continue
+		}
+		prefixes[bucketName] = bucketPrefixes
+	}
+
 	return common.MetaInfoResult{
 		Success: true,
-		Schema:  map[string]interface{}{"buckets": buckets.Buckets},
+		Schema:  map[string]interface{}{"buckets": buckets.Buckets, "prefixes": prefixes},
 	}, nil
 }
 
@@ -128,6 +152,26 @@ func (s *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 		result, err = commandExecutor.uploadAnObject(s.ResourceOpts.ACL)
 	case BATCH_UPLOAD_COMMAND:
 		result, err = commandExecutor.uploadMultipleObjects(s.ResourceOpts.ACL)
+	case PRESIGNED_GET_COMMAND:
+		result, err = commandExecutor.presignedGet()
+	case PRESIGNED_PUT_COMMAND:
+		result, err = commandExecutor.presignedPut(s.ResourceOpts.ACL)
+	case CREATE_MULTIPART_UPLOAD_COMMAND:
+		result, err = commandExecutor.createMultipartUpload(s.ResourceOpts.ACL)
+	case COMPLETE_MULTIPART_UPLOAD_COMMAND:
+		result, err = commandExecutor.completeMultipartUpload()
+	case ABORT_MULTIPART_UPLOAD_COMMAND:
+		result, err = commandExecutor.abortMultipartUpload()
+	case COPY_COMMAND:
+		result, err = commandExecutor.copyObject(false)
+	case MOVE_COMMAND:
+		result, err = commandExecutor.copyObject(true)
+	case HEAD_OBJECT_COMMAND:
+		result, err = commandExecutor.headObject()
+	case GET_TAGS_COMMAND:
+		result, err = commandExecutor.getTags()
+	case PUT_TAGS_COMMAND:
+		result, err = commandExecutor.putTags()
 	}
 
 	return result, err
@@ -22,8 +22,22 @@ const (
 	BATCH_DELETE_COMMAND = "batchDelete"
 	UPLOAD_COMMAND       = "upload"
 	BATCH_UPLOAD_COMMAND = "batchUpload"
+
+	PRESIGNED_GET_COMMAND             = "presignedGet"
+	PRESIGNED_PUT_COMMAND             = "presignedPut"
+	CREATE_MULTIPART_UPLOAD_COMMAND   = "createMultipartUpload"
+	COMPLETE_MULTIPART_UPLOAD_COMMAND = "completeMultipartUpload"
+	ABORT_MULTIPART_UPLOAD_COMMAND    = "abortMultipartUpload"
+	COPY_COMMAND                      = "copy"
+	MOVE_COMMAND                      = "move"
+	HEAD_OBJECT_COMMAND               = "headObject"
+	GET_TAGS_COMMAND                  = "getTags"
+	PUT_TAGS_COMMAND                  = "putTags"
 )
 
+// the prefixes of buckets are listed in meta info, the buckets over limit are listed without prefixes
+const META_INFO_PREFIX_BUCKET_LIMIT = 20
+
 type Resource struct {
 	BucketName      string
 	Region          string `validate:"required"`
@@ -32,6 +46,8 @@ type Resource struct {
 	BaseURL         string `validate:"required_unless=Endpoint false"`
 	AccessKeyID     string `validate:"required"`
 	SecretAccessKey string `validate:"required"`
+	// ForcePathStyle addresses the bucket in path instead of host, which is needed by the self hosted storage like MinIO
+	ForcePathStyle bool
 }
 
 var ACLs = map[string]bool{
@@ -45,7 +61,7 @@ var ACLs = map[string]bool{
 }
 
 type Action struct {
-	Commands    string                 `validate:"required,oneof=list read download delete batchDelete upload batchUpload"`
+	Commands    string                 `validate:"required,oneof=list read download delete batchDelete upload batchUpload presignedGet presignedPut createMultipartUpload completeMultipartUpload abortMultipartUpload copy move headObject getTags putTags"`
 	CommandArgs map[string]interface{} `validate:"required"`
 }
 
@@ -85,3 +101,72 @@ type BatchUploadCommandArgs struct {
 	ObjectKeyList  []string `json:"objectKeyList" validate:"required,gt=0,dive,required"`
 	ObjectDataList []string `json:"objectDataList"`
 }
+
+type PresignedGetCommandArgs struct {
+	BucketName                 string `json:"bucketName"`
+	ObjectKey                  string `json:"objectKey" validate:"required"`
+	Expiry                     int64  `json:"expiry" validate:"required,gt=0,lte=10080"`
+	ResponseContentType        string `json:"responseContentType"`
+	ResponseContentDisposition string `json:"responseContentDisposition"`
+}
+
+// PresignedPutCommandArgs constrains the upload by the content type and content length, they are signed and must be sent as the same headers
+type PresignedPutCommandArgs struct {
+	BucketName    string `json:"bucketName"`
+	ObjectKey     string `json:"objectKey" validate:"required"`
+	Expiry        int64  `json:"expiry" validate:"required,gt=0,lte=10080"`
+	ContentType   string `json:"contentType"`
+	ContentLength int64  `json:"contentLength" validate:"gte=0"`
+}
+
+type CreateMultipartUploadCommandArgs struct {
+	BucketName  string `json:"bucketName"`
+	ObjectKey   string `json:"objectKey" validate:"required"`
+	ContentType string `json:"contentType"`
+	PartCount   int32  `json:"partCount" validate:"required,gt=0,lte=10000"`
+	Expiry      int64  `json:"expiry" validate:"required,gt=0,lte=10080"`
+}
+
+type CompletedPart struct {
+	PartNumber int32  `json:"partNumber" validate:"required,gt=0"`
+	ETag       string `json:"eTag" validate:"required"`
+}
+
+type CompleteMultipartUploadCommandArgs struct {
+	BucketName string          `json:"bucketName"`
+	ObjectKey  string          `json:"objectKey" validate:"required"`
+	UploadID   string          `json:"uploadID" validate:"required"`
+	Parts      []CompletedPart `json:"parts" validate:"required,gt=0,dive"`
+}
+
+type AbortMultipartUploadCommandArgs struct {
+	BucketName string `json:"bucketName"`
+	ObjectKey  string `json:"objectKey" validate:"required"`
+	UploadID   string `json:"uploadID" validate:"required"`
+}
+
+// CopyCommandArgs copies the source object in server side, the source bucket is the destination bucket when empty
+type CopyCommandArgs struct {
+	SourceBucketName string `json:"sourceBucketName"`
+	SourceObjectKey  string `json:"sourceObjectKey" validate:"required"`
+	BucketName       string `json:"bucketName"`
+	ObjectKey        string `json:"objectKey" validate:"required"`
+}
+
+type HeadObjectCommandArgs struct {
+	BucketName string `json:"bucketName"`
+	ObjectKey  string `json:"objectKey" validate:"required"`
+}
+
+// GetTagsCommandArgs reads the tags of the latest version when the version id is empty
+type GetTagsCommandArgs struct {
+	BucketName string `json:"bucketName"`
+	ObjectKey  string `json:"objectKey" validate:"required"`
+	VersionID  string `json:"versionID"`
+}
+
+type PutTagsCommandArgs struct {
+	BucketName string            `json:"bucketName"`
+	ObjectKey  string            `json:"objectKey" validate:"required"`
+	Tags       map[string]string `json:"tags" validate:"required"`
+}
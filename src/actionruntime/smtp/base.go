@@ -15,21 +15,143 @@
 package smtp
 
 import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/mitchellh/mapstructure"
-	"gopkg.in/gomail.v2"
 )
 
-func (s *Connector) getConnectionWithOptions(resourceOptions map[string]interface{}) (*gomail.Dialer, error) {
+const DIAL_TIMEOUT = 10 * time.Second
+
+func (s *Connector) getConnectionWithOptions(resourceOptions map[string]interface{}) (*smtp.Client, error) {
 	if err := mapstructure.Decode(resourceOptions, &s.ResourceOpts); err != nil {
 		return nil, err
 	}
 
-	smtpDialer := gomail.NewDialer(s.ResourceOpts.Host, s.ResourceOpts.Port, s.ResourceOpts.Username, s.ResourceOpts.Password)
+	return dial(s.ResourceOpts)
+}
+
+// dial connects to server in the security mode of resource, and authenticates when username is set.
+func dial(resource Resource) (*smtp.Client, error) {
+	security := resource.Security
+	if security == "" || security == SECURITY_AUTO {
+		security = ""
+		if resource.Port == 465 {
+			security = SECURITY_TLS
+		}
+	}
+	tlsConfig := &tls.Config{
+		ServerName:         resource.Host,
+		InsecureSkipVerify: resource.SkipVerify,
+	}
 
-	return smtpDialer, nil
+	address := net.JoinHostPort(resource.Host, strconv.Itoa(resource.Port))
+	conn, err := net.DialTimeout("tcp", address, DIAL_TIMEOUT)
+	if err != nil {
+		return nil, err
+	}
+	if security == SECURITY_TLS {
+		conn = tls.Client(conn, tlsConfig)
+	}
+	client, err := smtp.NewClient(conn, resource.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	// upgrade connection, the starttls mode requires it and the auto mode upgrades when supported
+	if security != SECURITY_TLS && security != SECURITY_NONE {
+		supportStartTLS, _ := client.Extension("STARTTLS")
+		if !supportStartTLS && security == SECURITY_STARTTLS {
+			client.Close()
+			return nil, errors.New("smtp server does not support STARTTLS")
+		}
+		if supportStartTLS {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				client.Close()
+				return nil, err
+			}
+		}
+	}
+
+	if resource.Username != "" {
+		if supportAuth, mechanisms := client.Extension("AUTH"); supportAuth {
+			if err := client.Auth(selectAuth(resource, mechanisms)); err != nil {
+				client.Close()
+				return nil, err
+			}
+		}
+	}
+	return client, nil
+}
+
+func selectAuth(resource Resource, mechanisms string) smtp.Auth {
+	if strings.Contains(mechanisms, "CRAM-MD5") {
+		return smtp.CRAMMD5Auth(resource.Username, resource.Password)
+	}
+	if strings.Contains(mechanisms, "LOGIN") && !strings.Contains(mechanisms, "PLAIN") {
+		return &loginAuth{username: resource.Username, password: resource.Password, host: resource.Host}
+	}
+	return smtp.PlainAuth("", resource.Username, resource.Password, resource.Host)
+}
+
+// loginAuth implements the LOGIN mechanism which is the only one supported by some servers like outlook
+type loginAuth struct {
+	username string
+	password string
+	host     string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	if server.Name != a.host {
+		return "", nil, errors.New("wrong host name")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+	switch strings.ToLower(string(fromServer)) {
+	case "username:":
+		return []byte(a.username), nil
+	case "password:":
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected server challenge: %s", fromServer)
+	}
+}
+
+// sendMessage sends the rendered message on the connected client
+func sendMessage(client *smtp.Client, from string, recipients []string, message []byte) error {
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message); err != nil {
+		writer.Close()
+		return err
+	}
+	return writer.Close()
 }
 
 func attachSizeLimiter(contentLength int64) bool {
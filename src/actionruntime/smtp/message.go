@@ -0,0 +1,189 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package smtp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/mail"
+	"net/url"
+	"strings"
+
+	parser_template "github.com/illacloud/builder-backend/src/utils/parser/template"
+	"gopkg.in/gomail.v2"
+)
+
+// envelope is a rendered email with the sender and recipients of smtp transaction
+type envelope struct {
+	from       string
+	recipients []string
+	message    []byte
+	// recipient is the personalized recipient, it is empty when the email is sent to all recipients
+	recipient string
+}
+
+// buildEnvelopes renders the emails, the cc and bcc are only used when the email is not personalized
+func (s *Connector) buildEnvelopes(context map[string]interface{}) ([]*envelope, error) {
+	if len(s.ActionOpts.Recipients) == 0 {
+		recipients := make([]string, 0, len(s.ActionOpts.To)+len(s.ActionOpts.Cc)+len(s.ActionOpts.Bcc))
+		recipients = append(recipients, s.ActionOpts.To...)
+		recipients = append(recipients, s.ActionOpts.Cc...)
+		recipients = append(recipients, s.ActionOpts.Bcc...)
+		emailEnvelope, err := s.buildEnvelope(s.ActionOpts.To, s.ActionOpts.Cc, s.ActionOpts.Bcc, recipients, context)
+		if err != nil {
+			return nil, err
+		}
+		return []*envelope{emailEnvelope}, nil
+	}
+
+	envelopes := make([]*envelope, 0, len(s.ActionOpts.Recipients))
+	for _, recipient := range s.ActionOpts.Recipients {
+		// the variables of recipient overwrite the action context
+		variables := make(map[string]interface{}, len(context)+len(recipient.Variables))
+		for key, value := range context {
+			variables[key] = value
+		}
+		for key, value := range recipient.Variables {
+			variables[key] = value
+		}
+		emailEnvelope, err := s.buildEnvelope([]string{recipient.Email}, nil, nil, []string{recipient.Email}, variables)
+		if err != nil {
+			return nil, err
+		}
+		emailEnvelope.recipient = recipient.Email
+		envelopes = append(envelopes, emailEnvelope)
+	}
+	return envelopes, nil
+}
+
+func (s *Connector) buildEnvelope(to []string, cc []string, bcc []string, recipients []string, variables map[string]interface{}) (*envelope, error) {
+	subject, err := parser_template.AssembleTemplateWithVariable(s.ActionOpts.Subject, variables)
+	if err != nil {
+		return nil, err
+	}
+	body, err := parser_template.AssembleTemplateWithVariable(s.ActionOpts.Body, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	// build message
+	emailMessage := gomail.NewMessage()
+
+	// set header
+	emailMessage.SetHeader("From", s.ActionOpts.From)
+	emailMessage.SetHeader("To", to...)
+	emailMessage.SetHeader("Subject", subject)
+	if len(bcc) != 0 {
+		emailMessage.SetHeader("Bcc", bcc...)
+	}
+	if len(cc) != 0 {
+		emailMessage.SetHeader("Cc", cc...)
+	}
+	if s.ActionOpts.SetReplyTo {
+		emailMessage.SetHeader("Reply-To", s.ActionOpts.ReplyTo)
+	}
+
+	// set body
+	emailMessage.SetBody(s.ActionOpts.ContentType, body)
+
+	// attach and embed
+	s.attach(emailMessage)
+	s.embedInlineImages(emailMessage)
+
+	// render message, the bcc header is not written
+	var buf bytes.Buffer
+	if _, err := emailMessage.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+
+	// the smtp transaction uses bare addresses
+	from, err := exportAddress(s.ActionOpts.From)
+	if err != nil {
+		return nil, err
+	}
+	recipientAddresses := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		address, err := exportAddress(recipient)
+		if err != nil {
+			return nil, err
+		}
+		recipientAddresses = append(recipientAddresses, address)
+	}
+	return &envelope{from: from, recipients: recipientAddresses, message: buf.Bytes()}, nil
+}
+
+func (s *Connector) attach(emailMessage *gomail.Message) {
+	for _, attach := range s.ActionOpts.Attachment {
+		attachDataBytes, err := base64.StdEncoding.DecodeString(attach.Data)
+		if err != nil {
+			continue
+		}
+		decodedAttachDataString, err := url.QueryUnescape(string(attachDataBytes))
+		if err != nil {
+			continue
+		}
+		contentLength := len(decodedAttachDataString)
+		if attachSizeLimiter(int64(contentLength)) {
+			continue
+		}
+		emailMessage.Attach(attach.Name, gomail.SetCopyFunc(func(w io.Writer) error {
+			_, err := w.Write([]byte(decodedAttachDataString))
+			return err
+		}))
+	}
+}
+
+func (s *Connector) embedInlineImages(emailMessage *gomail.Message) {
+	for _, inlineImage := range s.ActionOpts.InlineImages {
+		// accept data url like data:image/png;base64,iVBORw0KGgo...
+		data := inlineImage.Data
+		contentType := inlineImage.ContentType
+		if strings.HasPrefix(data, "data:") {
+			if separatorIndex := strings.Index(data, ","); separatorIndex != -1 {
+				if contentType == "" {
+					contentType = strings.TrimSuffix(strings.TrimPrefix(data[:separatorIndex], "data:"), ";base64")
+				}
+				data = data[separatorIndex+1:]
+			}
+		}
+		imageData, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			continue
+		}
+		if attachSizeLimiter(int64(len(imageData))) {
+			continue
+		}
+		settings := []gomail.FileSetting{
+			gomail.SetCopyFunc(func(w io.Writer) error {
+				_, err := w.Write(imageData)
+				return err
+			}),
+		}
+		if contentType != "" {
+			settings = append(settings, gomail.SetHeader(map[string][]string{"Content-Type": {contentType}}))
+		}
+		// the content id is the file name
+		emailMessage.Embed(inlineImage.CID, settings...)
+	}
+}
+
+func exportAddress(address string) (string, error) {
+	parsedAddress, err := mail.ParseAddress(address)
+	if err != nil {
+		return "", err
+	}
+	return parsedAddress.Address, nil
+}
@@ -0,0 +1,266 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package smtp
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/textproto"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	OUTBOX_STATUS_QUEUED    = "queued"
+	OUTBOX_STATUS_SENT      = "sent"
+	OUTBOX_STATUS_FAILED    = "failed"
+	OUTBOX_STATUS_NOT_FOUND = "notFound"
+
+	OUTBOX_CLAIM_BATCH_SIZE = 10
+	// the claimed message is claimed again when it is not acknowledged in visibility timeout, like the worker crashed in delivery
+	OUTBOX_VISIBILITY_TIMEOUT = 5 * time.Minute
+	// the failed delivery is retried after 30s, 1m, 2m ... and 1h at most
+	OUTBOX_RETRY_BASE_DELAY = 30 * time.Second
+	OUTBOX_RETRY_MAX_DELAY  = time.Hour
+)
+
+var (
+	errOutboxDisabled         = errors.New("smtp outbox is not enabled")
+	errOutboxResourceNotBound = errors.New("smtp outbox requires the resource of action")
+)
+
+// OutboxStore persists the queued emails, a due message should be claimed only once when multiple workers running.
+type OutboxStore interface {
+	SaveMessage(messageID string, message []byte, ttl time.Duration) error
+	// RetrieveMessage returns nil when message not found
+	RetrieveMessage(messageID string) ([]byte, error)
+	ScheduleMessage(messageID string, dueAt time.Time) error
+	// ClaimDueMessages moves the due messages from queue to processing, the message not acknowledged in visibility timeout
+	// is moved back to queue and claimed again.
+	ClaimDueMessages(now time.Time, visibilityTimeout time.Duration, limit int64) ([]string, error)
+	// AckMessage removes the claimed message from processing after it was sent, failed or scheduled for retry
+	AckMessage(messageID string) error
+}
+
+// OutboxResourceLoader loads the options of smtp resource in delivery, so the credentials are never kept in outbox.
+type OutboxResourceLoader func(teamID int, resourceID int) (map[string]interface{}, error)
+
+type OutboxMessage struct {
+	ID         string     `json:"id"`
+	Status     string     `json:"status"`
+	TeamID     int        `json:"teamID"`
+	ResourceID int        `json:"resourceID"`
+	From       string     `json:"from"`
+	Recipients []string   `json:"recipients"`
+	Recipient  string     `json:"recipient"`
+	Message    []byte     `json:"message"`
+	Attempts   int        `json:"attempts"`
+	LastError  string     `json:"lastError"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+	SentAt     *time.Time `json:"sentAt"`
+}
+
+func (message *OutboxMessage) ExportStatus() map[string]interface{} {
+	return map[string]interface{}{
+		"messageID": message.ID,
+		"status":    message.Status,
+		"recipient": message.Recipient,
+		"attempts":  message.Attempts,
+		"lastError": message.LastError,
+		"createdAt": message.CreatedAt,
+		"sentAt":    message.SentAt,
+	}
+}
+
+type outbox struct {
+	store          OutboxStore
+	resourceLoader OutboxResourceLoader
+	maxAttempts    int
+	retention      time.Duration
+}
+
+var defaultOutbox *outbox
+
+// EnableOutbox enables the outbox, the delivery status is kept in retention after sent or failed
+func EnableOutbox(store OutboxStore, resourceLoader OutboxResourceLoader, maxAttempts int, retention time.Duration) {
+	defaultOutbox = newOutbox(store, resourceLoader, maxAttempts, retention)
+}
+
+func newOutbox(store OutboxStore, resourceLoader OutboxResourceLoader, maxAttempts int, retention time.Duration) *outbox {
+	return &outbox{
+		store:          store,
+		resourceLoader: resourceLoader,
+		maxAttempts:    maxAttempts,
+		retention:      retention,
+	}
+}
+
+// StartOutboxWorker delivers the due messages in every poll interval
+func StartOutboxWorker(pollInterval time.Duration) {
+	if defaultOutbox == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			defaultOutbox.deliverDueMessages()
+		}
+	}()
+}
+
+func (o *outbox) saveMessage(message *OutboxMessage) error {
+	message.UpdatedAt = time.Now().UTC()
+	messageInJSON, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return o.store.SaveMessage(message.ID, messageInJSON, o.retention)
+}
+
+func (o *outbox) retrieveMessage(messageID string) (*OutboxMessage, error) {
+	messageInJSON, err := o.store.RetrieveMessage(messageID)
+	if err != nil || messageInJSON == nil {
+		return nil, err
+	}
+	message := &OutboxMessage{}
+	if err := json.Unmarshal(messageInJSON, message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+func (o *outbox) enqueue(teamID int, resourceID int, emailEnvelope *envelope) (*OutboxMessage, error) {
+	now := time.Now().UTC()
+	message := &OutboxMessage{
+		ID:         uuid.New().String(),
+		Status:     OUTBOX_STATUS_QUEUED,
+		TeamID:     teamID,
+		ResourceID: resourceID,
+		From:       emailEnvelope.from,
+		Recipients: emailEnvelope.recipients,
+		Recipient:  emailEnvelope.recipient,
+		Message:    emailEnvelope.message,
+		CreatedAt:  now,
+	}
+	if err := o.saveMessage(message); err != nil {
+		return nil, err
+	}
+	if err := o.store.ScheduleMessage(message.ID, now); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// deliverDueMessages claims the due messages in small batches, so every batch is delivered in the visibility timeout
+func (o *outbox) deliverDueMessages() {
+	for {
+		messageIDs, err := o.store.ClaimDueMessages(time.Now(), OUTBOX_VISIBILITY_TIMEOUT, OUTBOX_CLAIM_BATCH_SIZE)
+		if err != nil {
+			log.Printf("[ERROR] claim smtp outbox messages failed: %s\n", err.Error())
+			return
+		}
+		for _, messageID := range messageIDs {
+			if err := o.deliver(messageID); err != nil {
+				log.Printf("[ERROR] deliver smtp outbox message %s failed: %s\n", messageID, err.Error())
+			}
+		}
+		if len(messageIDs) < OUTBOX_CLAIM_BATCH_SIZE {
+			return
+		}
+	}
+}
+
+// deliver sends the claimed message and acknowledges it after the result saved, the message is claimed again
+// when the worker stopped before acknowledged.
+func (o *outbox) deliver(messageID string) error {
+	message, err := o.retrieveMessage(messageID)
+	if err != nil {
+		return err
+	}
+	if message == nil || message.Status != OUTBOX_STATUS_QUEUED {
+		return o.store.AckMessage(messageID)
+	}
+	if err := o.attempt(message); err != nil {
+		return err
+	}
+	return o.store.AckMessage(messageID)
+}
+
+// attempt sends the message, and schedules the retry with backoff when failed until max attempts reached
+func (o *outbox) attempt(message *OutboxMessage) error {
+	message.Attempts++
+	errInSend := o.send(message)
+	if errInSend == nil {
+		sentAt := time.Now().UTC()
+		message.Status = OUTBOX_STATUS_SENT
+		message.SentAt = &sentAt
+		message.LastError = ""
+		return o.saveMessage(message)
+	}
+
+	// the permanent failure like unknown recipient is not retried
+	message.LastError = errInSend.Error()
+	var protocolError *textproto.Error
+	isPermanentFailure := errors.As(errInSend, &protocolError) && protocolError.Code >= 500
+	if isPermanentFailure || message.Attempts >= o.maxAttempts {
+		message.Status = OUTBOX_STATUS_FAILED
+		return o.saveMessage(message)
+	}
+	if err := o.saveMessage(message); err != nil {
+		return err
+	}
+	return o.store.ScheduleMessage(message.ID, time.Now().Add(outboxRetryDelay(message.Attempts)))
+}
+
+func outboxRetryDelay(attempts int) time.Duration {
+	delay := OUTBOX_RETRY_BASE_DELAY
+	for i := 1; i < attempts && delay < OUTBOX_RETRY_MAX_DELAY; i++ {
+		delay *= 2
+	}
+	if delay > OUTBOX_RETRY_MAX_DELAY {
+		return OUTBOX_RETRY_MAX_DELAY
+	}
+	return delay
+}
+
+// send loads the resource options in delivery, the changed credentials take effect on the queued messages
+func (o *outbox) send(message *OutboxMessage) error {
+	resourceOptions, err := o.resourceLoader(message.TeamID, message.ResourceID)
+	if err != nil {
+		return err
+	}
+	var resource Resource
+	if err := mapstructure.Decode(resourceOptions, &resource); err != nil {
+		return err
+	}
+	return sendEnvelope(resource, message.From, message.Recipients, message.Message)
+}
+
+func sendEnvelope(resource Resource, from string, recipients []string, message []byte) error {
+	client, err := dial(resource)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	if err := sendMessage(client, from, recipients, message); err != nil {
+		return err
+	}
+	return client.Quit()
+}
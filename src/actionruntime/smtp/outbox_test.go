@@ -0,0 +1,310 @@
+package smtp
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// smtpSink is a minimal smtp server which keeps the received messages, the rejected recipients are answered with 550
+type smtpSink struct {
+	listener           net.Listener
+	username           string
+	password           string
+	rejectedRecipients map[string]bool
+	mutex              sync.Mutex
+	messages           []string
+	recipients         [][]string
+}
+
+func newSMTPSink(t *testing.T, username string, password string) *smtpSink {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	sink := &smtpSink{listener: listener, username: username, password: password, rejectedRecipients: map[string]bool{}}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go sink.serve(conn)
+		}
+	}()
+	return sink
+}
+
+func (sink *smtpSink) port() int {
+	return sink.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (sink *smtpSink) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	reply("220 sink ESMTP")
+	recipients := make([]string, 0)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch {
+		case command == "EHLO" || command == "HELO":
+			reply("250-sink")
+			reply("250 AUTH PLAIN")
+		case command == "AUTH":
+			credentials, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(line, "AUTH PLAIN "))
+			if string(credentials) != "\x00"+sink.username+"\x00"+sink.password {
+				reply("535 authentication failed")
+				continue
+			}
+			reply("235 authenticated")
+		case strings.HasPrefix(strings.ToUpper(line), "MAIL FROM:"):
+			recipients = make([]string, 0)
+			reply("250 OK")
+		case strings.HasPrefix(strings.ToUpper(line), "RCPT TO:"):
+			recipient := strings.Trim(line[len("RCPT TO:"):], "<>")
+			if sink.rejectedRecipients[recipient] {
+				reply("550 no such user")
+				continue
+			}
+			recipients = append(recipients, recipient)
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			sink.mutex.Lock()
+			sink.messages = append(sink.messages, data.String())
+			sink.recipients = append(sink.recipients, recipients)
+			sink.mutex.Unlock()
+			reply("250 OK")
+		case command == "RSET" || command == "NOOP":
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 command not implemented")
+		}
+	}
+}
+
+func (sink *smtpSink) receivedMessages() []string {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	return append([]string{}, sink.messages...)
+}
+
+// memoryOutboxStore keeps the queue and processing set in memory like the redis store
+type memoryOutboxStore struct {
+	messages   map[string][]byte
+	queue      map[string]time.Time
+	processing map[string]time.Time
+}
+
+func newMemoryOutboxStore() *memoryOutboxStore {
+	return &memoryOutboxStore{messages: map[string][]byte{}, queue: map[string]time.Time{}, processing: map[string]time.Time{}}
+}
+
+func (store *memoryOutboxStore) SaveMessage(messageID string, message []byte, ttl time.Duration) error {
+	store.messages[messageID] = message
+	return nil
+}
+
+func (store *memoryOutboxStore) RetrieveMessage(messageID string) ([]byte, error) {
+	return store.messages[messageID], nil
+}
+
+func (store *memoryOutboxStore) ScheduleMessage(messageID string, dueAt time.Time) error {
+	store.queue[messageID] = dueAt
+	return nil
+}
+
+func (store *memoryOutboxStore) ClaimDueMessages(now time.Time, visibilityTimeout time.Duration, limit int64) ([]string, error) {
+	for messageID, deadline := range store.processing {
+		if !deadline.After(now) {
+			delete(store.processing, messageID)
+			if _, isQueued := store.queue[messageID]; !isQueued {
+				store.queue[messageID] = now
+			}
+		}
+	}
+	messageIDs := make([]string, 0)
+	for messageID, dueAt := range store.queue {
+		if int64(len(messageIDs)) >= limit {
+			break
+		}
+		if !dueAt.After(now) {
+			delete(store.queue, messageID)
+			store.processing[messageID] = now.Add(visibilityTimeout)
+			messageIDs = append(messageIDs, messageID)
+		}
+	}
+	return messageIDs, nil
+}
+
+func (store *memoryOutboxStore) AckMessage(messageID string) error {
+	delete(store.processing, messageID)
+	return nil
+}
+
+func newSinkResourceLoader(port int, loadedResources *[]int) OutboxResourceLoader {
+	return func(teamID int, resourceID int) (map[string]interface{}, error) {
+		*loadedResources = append(*loadedResources, resourceID)
+		if resourceID != 7 {
+			return nil, errors.New("resource not found")
+		}
+		return map[string]interface{}{"host": "127.0.0.1", "port": port, "username": "mailer", "password": "s3cret", "security": SECURITY_NONE}, nil
+	}
+}
+
+func newTestEnvelope(recipients ...string) *envelope {
+	return &envelope{from: "noreply@illa.test", recipients: recipients, message: []byte("Subject: hello\r\n\r\nhello outbox\r\n")}
+}
+
+func TestOutboxMessageKeepsOnlyResourceIdentity(t *testing.T) {
+	store := newMemoryOutboxStore()
+	loadedResources := make([]int, 0)
+	testOutbox := newOutbox(store, newSinkResourceLoader(25, &loadedResources), 3, time.Hour)
+
+	message, err := testOutbox.enqueue(1, 7, newTestEnvelope("a@illa.test"))
+	assert.Nil(t, err)
+	storedMessage := string(store.messages[message.ID])
+	assert.Contains(t, storedMessage, `"resourceID":7`)
+	assert.NotContains(t, storedMessage, "s3cret")
+	assert.NotContains(t, storedMessage, "mailer")
+	assert.Equal(t, 0, len(loadedResources))
+}
+
+func TestOutboxDeliversWithResourceLoadedInDelivery(t *testing.T) {
+	sink := newSMTPSink(t, "mailer", "s3cret")
+	store := newMemoryOutboxStore()
+	loadedResources := make([]int, 0)
+	testOutbox := newOutbox(store, newSinkResourceLoader(sink.port(), &loadedResources), 3, time.Hour)
+
+	message, err := testOutbox.enqueue(1, 7, newTestEnvelope("a@illa.test", "b@illa.test"))
+	assert.Nil(t, err)
+	testOutbox.deliverDueMessages()
+
+	assert.Equal(t, []int{7}, loadedResources)
+	assert.Equal(t, 1, len(sink.receivedMessages()))
+	assert.Contains(t, sink.receivedMessages()[0], "hello outbox")
+	assert.Equal(t, []string{"a@illa.test", "b@illa.test"}, sink.recipients[0])
+	deliveredMessage, _ := testOutbox.retrieveMessage(message.ID)
+	assert.Equal(t, OUTBOX_STATUS_SENT, deliveredMessage.Status)
+	assert.Equal(t, 1, deliveredMessage.Attempts)
+	assert.NotNil(t, deliveredMessage.SentAt)
+	// acknowledged after delivered
+	assert.Equal(t, 0, len(store.queue))
+	assert.Equal(t, 0, len(store.processing))
+}
+
+func TestOutboxDoesNotRetryRejectedRecipient(t *testing.T) {
+	sink := newSMTPSink(t, "mailer", "s3cret")
+	sink.rejectedRecipients["unknown@illa.test"] = true
+	store := newMemoryOutboxStore()
+	loadedResources := make([]int, 0)
+	testOutbox := newOutbox(store, newSinkResourceLoader(sink.port(), &loadedResources), 3, time.Hour)
+
+	message, err := testOutbox.enqueue(1, 7, newTestEnvelope("unknown@illa.test"))
+	assert.Nil(t, err)
+	testOutbox.deliverDueMessages()
+
+	failedMessage, _ := testOutbox.retrieveMessage(message.ID)
+	assert.Equal(t, OUTBOX_STATUS_FAILED, failedMessage.Status)
+	assert.Contains(t, failedMessage.LastError, "no such user")
+	assert.Equal(t, 0, len(sink.receivedMessages()))
+	assert.Equal(t, 0, len(store.queue))
+	assert.Equal(t, 0, len(store.processing))
+}
+
+func TestOutboxReschedulesTransientFailure(t *testing.T) {
+	sink := newSMTPSink(t, "mailer", "s3cret")
+	port := sink.port()
+	sink.listener.Close()
+	store := newMemoryOutboxStore()
+	loadedResources := make([]int, 0)
+	testOutbox := newOutbox(store, newSinkResourceLoader(port, &loadedResources), 2, time.Hour)
+
+	message, err := testOutbox.enqueue(1, 7, newTestEnvelope("a@illa.test"))
+	assert.Nil(t, err)
+	testOutbox.deliverDueMessages()
+
+	queuedMessage, _ := testOutbox.retrieveMessage(message.ID)
+	assert.Equal(t, OUTBOX_STATUS_QUEUED, queuedMessage.Status)
+	assert.Equal(t, 1, queuedMessage.Attempts)
+	assert.NotEqual(t, "", queuedMessage.LastError)
+	assert.True(t, store.queue[message.ID].After(time.Now().Add(OUTBOX_RETRY_BASE_DELAY-time.Second)))
+	assert.Equal(t, 0, len(store.processing))
+
+	// failed when max attempts reached
+	store.queue[message.ID] = time.Now()
+	testOutbox.deliverDueMessages()
+	failedMessage, _ := testOutbox.retrieveMessage(message.ID)
+	assert.Equal(t, OUTBOX_STATUS_FAILED, failedMessage.Status)
+	assert.Equal(t, 2, failedMessage.Attempts)
+	assert.Equal(t, 0, len(store.queue))
+}
+
+func TestOutboxFailsWhenResourceRemoved(t *testing.T) {
+	store := newMemoryOutboxStore()
+	loadedResources := make([]int, 0)
+	testOutbox := newOutbox(store, newSinkResourceLoader(25, &loadedResources), 1, time.Hour)
+
+	message, err := testOutbox.enqueue(1, 8, newTestEnvelope("a@illa.test"))
+	assert.Nil(t, err)
+	testOutbox.deliverDueMessages()
+
+	failedMessage, _ := testOutbox.retrieveMessage(message.ID)
+	assert.Equal(t, OUTBOX_STATUS_FAILED, failedMessage.Status)
+	assert.Equal(t, "resource not found", failedMessage.LastError)
+}
+
+func TestValidateActionTemplateRejectsOutboxWhenDisabled(t *testing.T) {
+	connector := &Connector{}
+	_, err := connector.ValidateActionTemplate(map[string]interface{}{"from": "noreply@illa.test", "useOutbox": true})
+	assert.Equal(t, errOutboxDisabled, err)
+	_, err = connector.ValidateActionTemplate(map[string]interface{}{"operation": OPERATION_STATUS, "messageIDs": []string{"1"}})
+	assert.Equal(t, errOutboxDisabled, err)
+	_, err = connector.ValidateActionTemplate(map[string]interface{}{"from": "noreply@illa.test"})
+	assert.Nil(t, err)
+}
+
+func TestRetrieveOutboxStatusOnlyForTeam(t *testing.T) {
+	store := newMemoryOutboxStore()
+	loadedResources := make([]int, 0)
+	testOutbox := newOutbox(store, newSinkResourceLoader(25, &loadedResources), 3, time.Hour)
+	defaultOutbox = testOutbox
+	defer func() { defaultOutbox = nil }()
+
+	message, err := testOutbox.enqueue(1, 7, newTestEnvelope("a@illa.test"))
+	assert.Nil(t, err)
+
+	result, err := retrieveOutboxStatus(1, []string{message.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, OUTBOX_STATUS_QUEUED, result.Rows[0]["status"])
+
+	result, err = retrieveOutboxStatus(2, []string{message.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, []map[string]interface{}{{"messageID": message.ID, "status": OUTBOX_STATUS_NOT_FOUND}}, result.Rows)
+}
@@ -15,20 +15,22 @@
 package smtp
 
 import (
-	"encoding/base64"
-	"errors"
-	"io"
-	"net/url"
-
 	"github.com/go-playground/validator/v10"
 	"github.com/illacloud/builder-backend/src/actionruntime/common"
 	"github.com/mitchellh/mapstructure"
-	"gopkg.in/gomail.v2"
 )
 
 type Connector struct {
 	ResourceOpts Resource
 	ActionOpts   Action
+	teamID       int
+	resourceID   int
+}
+
+// SetResourceIdentity keeps the resource of action, the queued emails are delivered with the resource loaded then
+func (s *Connector) SetResourceIdentity(teamID int, resourceID int) {
+	s.teamID = teamID
+	s.resourceID = resourceID
 }
 
 func (s *Connector) ValidateResourceOptions(resourceOptions map[string]interface{}) (common.ValidateResult, error) {
@@ -46,22 +48,26 @@ func (s *Connector) ValidateResourceOptions(resourceOptions map[string]interface
 }
 
 func (s *Connector) ValidateActionTemplate(actionOptions map[string]interface{}) (common.ValidateResult, error) {
+	// format smtp action
+	var action Action
+	if err := mapstructure.Decode(actionOptions, &action); err != nil {
+		return common.ValidateResult{Valid: false}, err
+	}
+
+	// the outbox is only enabled in the server which runs the delivery worker
+	if (action.UseOutbox || action.Operation == OPERATION_STATUS) && defaultOutbox == nil {
+		return common.ValidateResult{Valid: false}, errOutboxDisabled
+	}
 	return common.ValidateResult{Valid: true}, nil
 }
 
 func (s *Connector) TestConnection(resourceOptions map[string]interface{}) (common.ConnectionResult, error) {
-	// get smtp dialer
-	smtpDialer, err := s.getConnectionWithOptions(resourceOptions)
+	// dials and authenticates to an SMTP server
+	smtpClient, err := s.getConnectionWithOptions(resourceOptions)
 	if err != nil {
 		return common.ConnectionResult{Success: false}, err
 	}
-
-	// Dial dials and authenticates to an SMTP server
-	sendCloser, err := smtpDialer.Dial()
-	if err != nil {
-		return common.ConnectionResult{Success: false}, err
-	}
-	defer sendCloser.Close()
+	defer smtpClient.Close()
 
 	return common.ConnectionResult{Success: true}, nil
 }
@@ -74,10 +80,9 @@ func (s *Connector) GetMetaInfo(resourceOptions map[string]interface{}) (common.
 }
 
 func (s *Connector) Run(resourceOptions map[string]interface{}, actionOptions map[string]interface{}, rawActionOptions map[string]interface{}) (common.RuntimeResult, error) {
-	// get smtp dialer
-	smtpDialer, err := s.getConnectionWithOptions(resourceOptions)
-	if err != nil {
-		return common.RuntimeResult{Success: false}, errors.New("failed to get smtp dialer")
+	// format smtp resource
+	if err := mapstructure.Decode(resourceOptions, &s.ResourceOpts); err != nil {
+		return common.RuntimeResult{Success: false}, err
 	}
 
 	// format smtp action
@@ -90,55 +95,105 @@ func (s *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 	if err := validate.Struct(s.ActionOpts); err != nil {
 		return common.RuntimeResult{Success: false}, err
 	}
+	if err := s.ActionOpts.validateOperation(); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
 
-	// build message
-	emailMessage := gomail.NewMessage()
+	// look up delivery status
+	if s.ActionOpts.Operation == OPERATION_STATUS {
+		return retrieveOutboxStatus(s.teamID, s.ActionOpts.MessageIDs)
+	}
 
-	// set header
-	emailMessage.SetHeader("From", s.ActionOpts.From)
-	emailMessage.SetHeader("To", s.ActionOpts.To...)
-	emailMessage.SetHeader("Subject", s.ActionOpts.Subject)
-	if len(s.ActionOpts.Bcc) != 0 {
-		emailMessage.SetHeader("Bcc", s.ActionOpts.Bcc...)
+	// render emails with context
+	context, _ := rawActionOptions["context"].(map[string]interface{})
+	envelopes, err := s.buildEnvelopes(context)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
 	}
-	if len(s.ActionOpts.Cc) != 0 {
-		emailMessage.SetHeader("Cc", s.ActionOpts.Cc...)
+
+	if s.ActionOpts.UseOutbox {
+		return s.enqueueEnvelopes(envelopes)
 	}
-	if s.ActionOpts.SetReplyTo {
-		emailMessage.SetHeader("Reply-To", s.ActionOpts.ReplyTo)
+	return s.sendEnvelopes(envelopes)
+}
+
+func (s *Connector) sendEnvelopes(envelopes []*envelope) (common.RuntimeResult, error) {
+	// get smtp client
+	smtpClient, err := dial(s.ResourceOpts)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
 	}
+	defer smtpClient.Close()
 
-	// set body
-	emailMessage.SetBody(s.ActionOpts.ContentType, s.ActionOpts.Body)
+	// the email sent to all recipients
+	if len(envelopes) == 1 && envelopes[0].recipient == "" {
+		if err := sendMessage(smtpClient, envelopes[0].from, envelopes[0].recipients, envelopes[0].message); err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		smtpClient.Quit()
+		return common.RuntimeResult{
+			Success: true,
+			Rows:    []map[string]interface{}{{"message": "email sent successfully"}},
+		}, nil
+	}
 
-	// attach
-	if len(s.ActionOpts.Attachment) != 0 {
-		for _, attach := range s.ActionOpts.Attachment {
-			attachDataBytes, err := base64.StdEncoding.DecodeString(attach.Data)
-			if err != nil {
-				continue
+	// the personalized emails, a rejected recipient does not stop others
+	rows := make([]map[string]interface{}, 0, len(envelopes))
+	var lastErr error
+	sentCount := 0
+	for _, emailEnvelope := range envelopes {
+		if err := sendMessage(smtpClient, emailEnvelope.from, emailEnvelope.recipients, emailEnvelope.message); err != nil {
+			lastErr = err
+			rows = append(rows, map[string]interface{}{"recipient": emailEnvelope.recipient, "status": OUTBOX_STATUS_FAILED, "error": err.Error()})
+			if errInReset := smtpClient.Reset(); errInReset != nil {
+				return common.RuntimeResult{Success: false, Rows: rows}, errInReset
 			}
-			decodedAttachDataString, err := url.QueryUnescape(string(attachDataBytes))
-			if err != nil {
-				continue
-			}
-			contentLength := len(decodedAttachDataString)
-			if attachSizeLimiter(int64(contentLength)) {
-				continue
-			}
-			emailMessage.Attach(attach.Name, gomail.SetCopyFunc(func(w io.Writer) error {
-				_, err := w.Write([]byte(decodedAttachDataString))
-				return err
-			}))
+			continue
 		}
+		sentCount++
+		rows = append(rows, map[string]interface{}{"recipient": emailEnvelope.recipient, "status": OUTBOX_STATUS_SENT})
+	}
+	smtpClient.Quit()
+	if sentCount == 0 {
+		return common.RuntimeResult{Success: false, Rows: rows}, lastErr
 	}
+	return common.RuntimeResult{Success: true, Rows: rows}, nil
+}
 
-	if err := smtpDialer.DialAndSend(emailMessage); err != nil {
-		return common.RuntimeResult{Success: false}, err
+func (s *Connector) enqueueEnvelopes(envelopes []*envelope) (common.RuntimeResult, error) {
+	if defaultOutbox == nil {
+		return common.RuntimeResult{Success: false}, errOutboxDisabled
 	}
+	if s.resourceID == 0 {
+		return common.RuntimeResult{Success: false}, errOutboxResourceNotBound
+	}
+	rows := make([]map[string]interface{}, 0, len(envelopes))
+	for _, emailEnvelope := range envelopes {
+		message, err := defaultOutbox.enqueue(s.teamID, s.resourceID, emailEnvelope)
+		if err != nil {
+			return common.RuntimeResult{Success: false, Rows: rows}, err
+		}
+		rows = append(rows, message.ExportStatus())
+	}
+	return common.RuntimeResult{Success: true, Rows: rows}, nil
+}
 
-	return common.RuntimeResult{
-		Success: true,
-		Rows:    []map[string]interface{}{{"message": "email sent successfully"}},
-	}, nil
+// retrieveOutboxStatus returns the status of messages enqueued by the team, the messages of other teams are not found.
+func retrieveOutboxStatus(teamID int, messageIDs []string) (common.RuntimeResult, error) {
+	if defaultOutbox == nil {
+		return common.RuntimeResult{Success: false}, errOutboxDisabled
+	}
+	rows := make([]map[string]interface{}, 0, len(messageIDs))
+	for _, messageID := range messageIDs {
+		message, err := defaultOutbox.retrieveMessage(messageID)
+		if err != nil {
+			return common.RuntimeResult{Success: false}, err
+		}
+		if message == nil || message.TeamID != teamID {
+			rows = append(rows, map[string]interface{}{"messageID": messageID, "status": OUTBOX_STATUS_NOT_FOUND})
+			continue
+		}
+		rows = append(rows, message.ExportStatus())
+	}
+	return common.RuntimeResult{Success: true, Rows: rows}, nil
 }
@@ -14,24 +14,61 @@
 
 package smtp
 
+import "errors"
+
+const (
+	// SECURITY_AUTO uses implicit tls on port 465, and upgrades connection by STARTTLS when the server supports it on other ports
+	SECURITY_AUTO     = "auto"
+	SECURITY_NONE     = "none"
+	SECURITY_STARTTLS = "starttls"
+	SECURITY_TLS      = "tls"
+)
+
+const (
+	OPERATION_SEND   = "send"
+	OPERATION_STATUS = "status"
+)
+
 type Resource struct {
-	Host     string `validate:"required"`
-	Port     int    `validate:"gt=0"`
-	Username string
-	Password string
+	Host       string `validate:"required"`
+	Port       int    `validate:"gt=0"`
+	Username   string
+	Password   string
+	Security   string `validate:"omitempty,oneof=auto none starttls tls"`
+	SkipVerify bool
 }
 
+// Action sends the email, the subject and body are templates rendered with the action context and the variables of every recipient.
+// When recipients are set, every recipient receives a personalized email, otherwise one email is sent to all of to, cc and bcc.
+// The status operation looks up the delivery status of emails queued in outbox.
 type Action struct {
-	From        string   `validate:"required"`
-	To          []string `validate:"required,gt=0,dive,required"`
-	Bcc         []string
-	Cc          []string
-	SetReplyTo  bool
-	ReplyTo     string `validate:"required_unless=SetReplyTo false"`
-	Subject     string `validate:"required"`
-	ContentType string `validate:"required,oneof=text/plain text/html"`
-	Body        string `validate:"required"`
-	Attachment  []Attachment
+	Operation    string `validate:"omitempty,oneof=send status"`
+	From         string `validate:"required_unless=Operation status"`
+	To           []string
+	Bcc          []string
+	Cc           []string
+	SetReplyTo   bool
+	ReplyTo      string `validate:"required_unless=SetReplyTo false"`
+	Subject      string `validate:"required_unless=Operation status"`
+	ContentType  string `validate:"required_unless=Operation status,omitempty,oneof=text/plain text/html"`
+	Body         string `validate:"required_unless=Operation status"`
+	Attachment   []Attachment
+	InlineImages []InlineImage `validate:"dive"`
+	Recipients   []Recipient   `validate:"dive"`
+	UseOutbox    bool
+	MessageIDs   []string
+}
+
+type Recipient struct {
+	Email     string `validate:"required"`
+	Variables map[string]interface{}
+}
+
+// InlineImage is referenced in html body by cid, like <img src="cid:logo">, the data is base64 encoded
+type InlineImage struct {
+	CID         string `validate:"required"`
+	Data        string `validate:"required"`
+	ContentType string
 }
 
 type Attachment struct {
@@ -39,3 +76,21 @@ type Attachment struct {
 	Name        string
 	ContentType string
 }
+
+func (action *Action) validateOperation() error {
+	if action.Operation == OPERATION_STATUS {
+		if len(action.MessageIDs) == 0 {
+			return errors.New("message ids are required in status operation")
+		}
+		return nil
+	}
+	if len(action.To) == 0 && len(action.Recipients) == 0 {
+		return errors.New("to or recipients is required")
+	}
+	for _, to := range action.To {
+		if to == "" {
+			return errors.New("to contains empty address")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+// Copyright 2022 The ILLA Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snowflake
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	sf "github.com/snowflakedb/gosnowflake"
+)
+
+const (
+	QUERY_STATUS_SUCCESS = "SUCCESS"
+	QUERY_STATUS_RUNNING = "RUNNING"
+	QUERY_STATUS_FAILED  = "FAILED"
+)
+
+// submitQuery returns once snowflake accepted the query, the connection must keep session alive,
+// otherwise the query is aborted when the session closed.
+func (s *Connector) submitQuery(db *sql.DB, query string, args []interface{}) (common.RuntimeResult, error) {
+	queryIDChan := make(chan string, 1)
+	ctx, cancel := context.WithCancel(sf.WithQueryIDChan(sf.WithAsyncMode(context.Background()), queryIDChan))
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		cancel()
+		return common.RuntimeResult{Success: false}, err
+	}
+	queryID := ""
+	select {
+	case queryID = <-queryIDChan:
+	default:
+	}
+	// stop polling the result in driver, the query keeps running on server
+	cancel()
+	rows.Close()
+	if queryID == "" {
+		return common.RuntimeResult{Success: false}, errors.New("snowflake did not return query id")
+	}
+
+	return common.RuntimeResult{
+		Success: true,
+		Rows:    []map[string]interface{}{{"queryID": queryID}},
+		Extra:   map[string]interface{}{"queryID": queryID},
+	}, nil
+}
+
+func (s *Connector) queryStatus(db *sql.DB) (common.RuntimeResult, error) {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	defer conn.Close()
+
+	var queryStatus *sf.SnowflakeQueryStatus
+	var errInGetStatus error
+	errInRaw := conn.Raw(func(driverConn interface{}) error {
+		snowflakeConn, ok := driverConn.(sf.SnowflakeConnection)
+		if !ok {
+			return errors.New("unsupported snowflake connection")
+		}
+		queryStatus, errInGetStatus = snowflakeConn.GetQueryStatus(ctx, s.actionOptions.QueryID)
+		return nil
+	})
+	if errInRaw != nil {
+		return common.RuntimeResult{Success: false}, errInRaw
+	}
+
+	// the driver reports running and failed query by error
+	status := QUERY_STATUS_SUCCESS
+	errorMessage := ""
+	if errInGetStatus != nil {
+		var snowflakeError *sf.SnowflakeError
+		if !errors.As(errInGetStatus, &snowflakeError) {
+			return common.RuntimeResult{Success: false}, errInGetStatus
+		}
+		switch {
+		case snowflakeError.Number == sf.ErrQueryIsRunning:
+			status = QUERY_STATUS_RUNNING
+		case snowflakeError.Number == sf.ErrQueryReportedError || queryStatus != nil:
+			status = QUERY_STATUS_FAILED
+			errorMessage = snowflakeError.Error()
+		default:
+			return common.RuntimeResult{Success: false}, errInGetStatus
+		}
+	}
+
+	row := map[string]interface{}{
+		"queryID":      s.actionOptions.QueryID,
+		"status":       status,
+		"errorMessage": errorMessage,
+	}
+	if queryStatus != nil {
+		row["startTime"] = queryStatus.StartTime
+		row["endTime"] = queryStatus.EndTime
+		row["scanBytes"] = queryStatus.ScanBytes
+		row["producedRows"] = queryStatus.ProducedRows
+	}
+	return common.RuntimeResult{
+		Success: true,
+		Rows:    []map[string]interface{}{row},
+		Extra:   map[string]interface{}{"queryID": s.actionOptions.QueryID, "status": status},
+	}, nil
+}
+
+// queryResult pages through the result of completed query, the result is fetched by query id in its own order and paged on client,
+// since RESULT_SCAN with LIMIT and OFFSET does not keep the order between pages. One more row is kept for reporting hasMore.
+func (s *Connector) queryResult(db *sql.DB) (common.RuntimeResult, error) {
+	limit := s.actionOptions.exportLimit()
+	rows, err := db.QueryContext(sf.WithFetchResultByID(context.Background(), s.actionOptions.QueryID), "")
+	if err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	defer rows.Close()
+	page := newResultPage(s.actionOptions.Offset, limit)
+	if err := common.RetrieveToStream(rows, page); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	hasMore := len(page.rows) > limit
+	if hasMore {
+		page.rows = page.rows[:limit]
+	}
+	return common.RuntimeResult{
+		Success: true,
+		Rows:    page.rows,
+		Extra: map[string]interface{}{
+			"queryID":    s.actionOptions.QueryID,
+			"offset":     s.actionOptions.Offset,
+			"limit":      limit,
+			"hasMore":    hasMore,
+			"nextOffset": s.actionOptions.Offset + len(page.rows),
+		},
+	}, nil
+}
+
+// resultPage is the result stream keeping one page of rows, the rows before offset are skipped.
+type resultPage struct {
+	offset  int
+	limit   int
+	skipped int
+	rows    []map[string]interface{}
+}
+
+func newResultPage(offset int, limit int) *resultPage {
+	return &resultPage{
+		offset: offset,
+		limit:  limit,
+		rows:   make([]map[string]interface{}, 0),
+	}
+}
+
+func (p *resultPage) WriteRow(row map[string]interface{}) error {
+	if p.skipped < p.offset {
+		p.skipped++
+		return nil
+	}
+	p.rows = append(p.rows, row)
+	if len(p.rows) > p.limit {
+		return common.ErrResultStreamLimitReached
+	}
+	return nil
+}
+
+func (p *resultPage) ExportExtra() map[string]interface{} {
+	return map[string]interface{}{}
+}
+
+func (s *Connector) cancelQuery(db *sql.DB) (common.RuntimeResult, error) {
+	var message string
+	if err := db.QueryRow(fmt.Sprintf("SELECT SYSTEM$CANCEL_QUERY('%s')", s.actionOptions.QueryID)).Scan(&message); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	return common.RuntimeResult{
+		Success: true,
+		Rows:    []map[string]interface{}{},
+		Extra:   map[string]interface{}{"queryID": s.actionOptions.QueryID, "message": message},
+	}, nil
+}
@@ -0,0 +1,33 @@
+package snowflake
+
+import (
+	"testing"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeResultRows(page *resultPage, total int) error {
+	for i := 0; i < total; i++ {
+		if err := page.WriteRow(map[string]interface{}{"ID": i}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestResultPageSkipsOffsetAndKeepsOneMoreRow(t *testing.T) {
+	page := newResultPage(3, 2)
+	assert.Equal(t, common.ErrResultStreamLimitReached, writeResultRows(page, 10))
+	assert.Equal(t, []map[string]interface{}{{"ID": 3}, {"ID": 4}, {"ID": 5}}, page.rows)
+
+	// the last page
+	page = newResultPage(8, 5)
+	assert.Nil(t, writeResultRows(page, 10))
+	assert.Equal(t, []map[string]interface{}{{"ID": 8}, {"ID": 9}}, page.rows)
+
+	// offset beyond the result
+	page = newResultPage(20, 5)
+	assert.Nil(t, writeResultRows(page, 10))
+	assert.Equal(t, 0, len(page.rows))
+}
@@ -35,6 +35,14 @@ const (
 )
 
 func (s *Connector) getConnectionWithOptions(resourceOptions map[string]interface{}) (*sql.DB, error) {
+	config, err := s.exportConfigWithOptions(resourceOptions)
+	if err != nil {
+		return nil, err
+	}
+	return openConnection(config)
+}
+
+func (s *Connector) exportConfigWithOptions(resourceOptions map[string]interface{}) (*sf.Config, error) {
 	if err := mapstructure.Decode(resourceOptions, &s.resourceOptions); err != nil {
 		return nil, err
 	}
@@ -71,7 +79,16 @@ func (s *Connector) getConnectionWithOptions(resourceOptions map[string]interfac
 		return nil, errors.New("unsupported authentication method")
 	}
 
-	dsn, err := sf.DSN(&config)
+	return &config, nil
+}
+
+func openConnection(config *sf.Config) (*sql.DB, error) {
+	// the dsn does not carry KeepSessionAlive, so open by connector
+	if config.KeepSessionAlive {
+		return sql.OpenDB(sf.NewConnector(sf.SnowflakeDriver{}, *config)), nil
+	}
+
+	dsn, err := sf.DSN(config)
 	if err != nil {
 		return nil, err
 	}
@@ -28,6 +28,33 @@ import (
 type Connector struct {
 	resourceOptions Resource
 	actionOptions   Action
+	resultStream    common.ResultStream
+}
+
+func (s *Connector) SetResultStream(stream common.ResultStream) {
+	s.resultStream = stream
+}
+
+func (s *Connector) IsReadOnlyAction(actionOptions map[string]interface{}) bool {
+	operation, _ := actionOptions["operation"].(string)
+	if operation != "" && operation != OPERATION_QUERY {
+		return false
+	}
+	mode, _ := actionOptions["mode"].(string)
+	query, _ := actionOptions["query"].(string)
+	return common.IsReadOnlySQLQuery(mode, query)
+}
+
+// IsWriteAction treats the query and submit operations by their query, the status, result and cancel operations
+// only poll the submitted query so they are not writes.
+func (s *Connector) IsWriteAction(actionOptions map[string]interface{}) bool {
+	operation, _ := actionOptions["operation"].(string)
+	if operation != "" && operation != OPERATION_QUERY && operation != OPERATION_SUBMIT {
+		return false
+	}
+	mode, _ := actionOptions["mode"].(string)
+	query, _ := actionOptions["query"].(string)
+	return common.IsWriteSQLQuery(mode, query)
 }
 
 func (s *Connector) ValidateResourceOptions(resourceOptions map[string]interface{}) (common.ValidateResult, error) {
@@ -56,6 +83,9 @@ func (s *Connector) ValidateActionTemplate(actionOptions map[string]interface{})
 	if err := validate.Struct(s.actionOptions); err != nil {
 		return common.ValidateResult{Valid: false}, err
 	}
+	if err := s.actionOptions.validateOperation(); err != nil {
+		return common.ValidateResult{Valid: false}, err
+	}
 
 	return common.ValidateResult{Valid: true}, nil
 }
@@ -98,16 +128,36 @@ func (s *Connector) GetMetaInfo(resourceOptions map[string]interface{}) (common.
 }
 
 func (s *Connector) Run(resourceOptions map[string]interface{}, actionOptions map[string]interface{}, rawActionOptions map[string]interface{}) (common.RuntimeResult, error) {
-	// get snowflake connection
-	db, err := s.getConnectionWithOptions(resourceOptions)
+	// format query
+	if err := mapstructure.Decode(actionOptions, &s.actionOptions); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	if err := s.actionOptions.validateOperation(); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+
+	// get snowflake connection, the action can override warehouse and role in allowed set
+	config, err := s.exportConfigWithOptions(resourceOptions)
+	if err != nil {
+		return common.RuntimeResult{Success: false}, errors.New("failed to get snowflake connection")
+	}
+	if err := s.actionOptions.applyOverride(config, &s.resourceOptions); err != nil {
+		return common.RuntimeResult{Success: false}, err
+	}
+	config.KeepSessionAlive = s.actionOptions.Operation == OPERATION_SUBMIT
+	db, err := openConnection(config)
 	if err != nil {
 		return common.RuntimeResult{Success: false}, errors.New("failed to get snowflake connection")
 	}
 	defer db.Close()
 
-	// format query
-	if err := mapstructure.Decode(actionOptions, &s.actionOptions); err != nil {
-		return common.RuntimeResult{Success: false}, err
+	switch s.actionOptions.Operation {
+	case OPERATION_STATUS:
+		return s.queryStatus(db)
+	case OPERATION_RESULT:
+		return s.queryResult(db)
+	case OPERATION_CANCEL:
+		return s.cancelQuery(db)
 	}
 
 	// set context field
@@ -130,6 +180,14 @@ func (s *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 		return queryResult, errInEscapeSQL
 	}
 
+	// submit query asynchronously
+	if s.actionOptions.Operation == OPERATION_SUBMIT {
+		if !s.actionOptions.IsSafeMode() {
+			sqlArgs = nil
+		}
+		return s.submitQuery(db, escapedSQL, sqlArgs)
+	}
+
 	// check if m.Action.Query is select query
 	isSelectQuery := false
 
@@ -145,7 +203,7 @@ func (s *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 		if err != nil {
 			return queryResult, err
 		}
-		mapRes, err := common.RetrieveToMap(rows)
+		mapRes, err := common.RetrieveToMapOrStream(rows, s.resultStream)
 		if err != nil {
 			return queryResult, err
 		}
@@ -157,7 +215,7 @@ func (s *Connector) Run(resourceOptions map[string]interface{}, actionOptions ma
 		if err != nil {
 			return queryResult, err
 		}
-		mapRes, err := common.RetrieveToMap(rows)
+		mapRes, err := common.RetrieveToMapOrStream(rows, s.resultStream)
 		if err != nil {
 			return queryResult, err
 		}
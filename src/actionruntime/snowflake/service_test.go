@@ -0,0 +1,28 @@
+package snowflake
+
+import (
+	"testing"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectorIsWriteAction(t *testing.T) {
+	connector := &Connector{}
+	write := map[string]interface{}{"mode": common.MODE_SQL, "query": "insert into t values (1)"}
+	read := map[string]interface{}{"mode": common.MODE_SQL, "query": "select * from t"}
+	assert.True(t, connector.IsWriteAction(write))
+	assert.False(t, connector.IsReadOnlyAction(write))
+	assert.False(t, connector.IsWriteAction(read))
+	assert.True(t, connector.IsReadOnlyAction(read))
+
+	write["operation"] = OPERATION_SUBMIT
+	assert.True(t, connector.IsWriteAction(write))
+
+	// polling the submitted query neither reads from cache nor invalidates it
+	for _, operation := range []string{OPERATION_STATUS, OPERATION_RESULT, OPERATION_CANCEL} {
+		polling := map[string]interface{}{"mode": common.MODE_SQL, "query": "insert into t values (1)", "operation": operation, "queryID": "01b2c3d4-0000-1111-0000-000000000001"}
+		assert.False(t, connector.IsWriteAction(polling))
+		assert.False(t, connector.IsReadOnlyAction(polling))
+	}
+}
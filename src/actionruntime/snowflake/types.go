@@ -16,8 +16,12 @@ package snowflake
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	sf "github.com/snowflakedb/gosnowflake"
 )
 
 const (
@@ -25,6 +29,21 @@ const (
 	FIELD_QUERY   = "query"
 )
 
+// the submit operation runs query asynchronously, then the status, result and cancel operations use the returned query id.
+const (
+	OPERATION_QUERY  = "query"
+	OPERATION_SUBMIT = "submit"
+	OPERATION_STATUS = "status"
+	OPERATION_RESULT = "result"
+	OPERATION_CANCEL = "cancel"
+)
+
+const (
+	DEFAULT_RESULT_LIMIT = 1000
+)
+
+var queryIDPattern = regexp.MustCompile(`^[0-9a-fA-F-]+$`)
+
 type Resource struct {
 	AccountName    string `validate:"required"`
 	Warehouse      string `validate:"required"`
@@ -33,13 +52,67 @@ type Resource struct {
 	Role           string
 	Authentication string            `validate:"oneof=basic key"`
 	AuthContent    map[string]string `validate:"required"`
+	// the warehouses and roles can be used by action besides the default ones
+	AllowedWarehouses []string
+	AllowedRoles      []string
 }
 
 type Action struct {
-	Mode     string `validate:"oneof=gui sql sql-safe"`
-	Query    string
-	RawQuery string
-	Context  map[string]interface{}
+	Mode      string `validate:"oneof=gui sql sql-safe"`
+	Operation string `validate:"omitempty,oneof=query submit status result cancel"`
+	Query     string
+	QueryID   string
+	Offset    int `validate:"gte=0"`
+	Limit     int `validate:"gte=0"`
+	Warehouse string
+	Role      string
+	RawQuery  string
+	Context   map[string]interface{}
+}
+
+func (q *Action) IsQueryIDOperation() bool {
+	return q.Operation == OPERATION_STATUS || q.Operation == OPERATION_RESULT || q.Operation == OPERATION_CANCEL
+}
+
+func (q *Action) validateOperation() error {
+	if q.IsQueryIDOperation() && !queryIDPattern.MatchString(q.QueryID) {
+		return fmt.Errorf("valid query id is required in %s operation", q.Operation)
+	}
+	return nil
+}
+
+func (q *Action) exportLimit() int {
+	if q.Limit <= 0 {
+		return DEFAULT_RESULT_LIMIT
+	}
+	return q.Limit
+}
+
+// applyOverride sets the action warehouse and role to config, they must be the resource default or in the allowed set.
+func (q *Action) applyOverride(config *sf.Config, resource *Resource) error {
+	if q.Warehouse != "" && q.Warehouse != resource.Warehouse {
+		if !containsFold(resource.AllowedWarehouses, q.Warehouse) {
+			return fmt.Errorf("warehouse %s is not allowed by resource", q.Warehouse)
+		}
+		config.Warehouse = q.Warehouse
+	}
+	if q.Role != "" && q.Role != resource.Role {
+		if !containsFold(resource.AllowedRoles, q.Role) {
+			return fmt.Errorf("role %s is not allowed by resource", q.Role)
+		}
+		config.Role = q.Role
+	}
+	return nil
+}
+
+// containsFold compares case-insensitively, since the unquoted snowflake identifiers are case-insensitive.
+func containsFold(items []string, target string) bool {
+	for _, item := range items {
+		if strings.EqualFold(item, target) {
+			return true
+		}
+	}
+	return false
 }
 
 func (q *Action) IsSafeMode() bool {
@@ -0,0 +1,49 @@
+package snowflake
+
+import (
+	"testing"
+
+	sf "github.com/snowflakedb/gosnowflake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateOperation(t *testing.T) {
+	for _, operation := range []string{OPERATION_STATUS, OPERATION_RESULT, OPERATION_CANCEL} {
+		action := &Action{Operation: operation, QueryID: "01b2c3d4-0000-1111-0000-000000000001"}
+		assert.Nil(t, action.validateOperation())
+		action.QueryID = ""
+		assert.NotNil(t, action.validateOperation())
+		// the query id is interpolated in cancel statement
+		action.QueryID = "01b2c3d4'); DROP TABLE t; --"
+		assert.NotNil(t, action.validateOperation())
+	}
+	assert.Nil(t, (&Action{Operation: OPERATION_SUBMIT}).validateOperation())
+	assert.Nil(t, (&Action{}).validateOperation())
+}
+
+func TestApplyOverride(t *testing.T) {
+	resource := &Resource{
+		Warehouse:         "COMPUTE_WH",
+		Role:              "ANALYST",
+		AllowedWarehouses: []string{"REPORTING_WH"},
+		AllowedRoles:      []string{"reporter"},
+	}
+
+	// the resource default is kept
+	config := &sf.Config{Warehouse: "COMPUTE_WH", Role: "ANALYST"}
+	assert.Nil(t, (&Action{}).applyOverride(config, resource))
+	assert.Equal(t, "COMPUTE_WH", config.Warehouse)
+	assert.Equal(t, "ANALYST", config.Role)
+	assert.Nil(t, (&Action{Warehouse: "COMPUTE_WH", Role: "ANALYST"}).applyOverride(config, resource))
+
+	// the allowed override is compared case-insensitively
+	assert.Nil(t, (&Action{Warehouse: "reporting_wh", Role: "REPORTER"}).applyOverride(config, resource))
+	assert.Equal(t, "reporting_wh", config.Warehouse)
+	assert.Equal(t, "REPORTER", config.Role)
+
+	config = &sf.Config{Warehouse: "COMPUTE_WH", Role: "ANALYST"}
+	assert.NotNil(t, (&Action{Warehouse: "ADMIN_WH"}).applyOverride(config, resource))
+	assert.NotNil(t, (&Action{Role: "ACCOUNTADMIN"}).applyOverride(config, resource))
+	assert.Equal(t, "COMPUTE_WH", config.Warehouse)
+	assert.Equal(t, "ANALYST", config.Role)
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	ACTION_RESULT_CACHE_KEY_PREFIX         = "action_result_cache:"
+	ACTION_RESULT_CACHE_VERSION_KEY_PREFIX = "action_result_cache_version:"
+
+	// the version key must outlive every result cached under it, otherwise the version restarts from 0 and
+	// the stale results of old version 0 would be hit again. so the result ttl is capped and the version
+	// key expiration is refreshed on every write.
+	ACTION_RESULT_CACHE_MAX_TTL     = 24 * time.Hour
+	ACTION_RESULT_CACHE_VERSION_TTL = ACTION_RESULT_CACHE_MAX_TTL + time.Hour
+)
+
+// ActionResultCache caches the results of read only actions.
+// Every resource has a version number which is a part of the cache key, the write action increases the version,
+// so all cached results of the resource are invalidated at once and expired by their ttl later.
+type ActionResultCache struct {
+	logger  *zap.SugaredLogger
+	cache   *redis.Client
+	context context.Context
+}
+
+func NewActionResultCache(cache *redis.Client, logger *zap.SugaredLogger) *ActionResultCache {
+	return &ActionResultCache{
+		logger:  logger,
+		cache:   cache,
+		context: context.Background(),
+	}
+}
+
+// BuildActionResultFingerprint hashes the action template (which contains the escaped query) and
+// raw template (which contains the bound arguments) into the cache key fingerprint.
+func BuildActionResultFingerprint(template map[string]interface{}, rawTemplate map[string]interface{}) string {
+	// json.Marshal sorts the map keys, so the same template always get the same fingerprint
+	templateInJSON, _ := json.Marshal(template)
+	rawTemplateInJSON, _ := json.Marshal(rawTemplate)
+	hash := sha256.New()
+	hash.Write(templateInJSON)
+	hash.Write([]byte{0})
+	hash.Write(rawTemplateInJSON)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (c *ActionResultCache) buildVersionKey(resourceID int) string {
+	return ACTION_RESULT_CACHE_VERSION_KEY_PREFIX + strconv.Itoa(resourceID)
+}
+
+func (c *ActionResultCache) buildResultKey(resourceID int, version int64, fingerprint string) string {
+	return ACTION_RESULT_CACHE_KEY_PREFIX + strconv.Itoa(resourceID) + ":" + strconv.FormatInt(version, 10) + ":" + fingerprint
+}
+
+func (c *ActionResultCache) RetrieveResourceVersion(resourceID int) (int64, error) {
+	version, errInGet := c.cache.Get(c.context, c.buildVersionKey(resourceID)).Int64()
+	if errInGet == redis.Nil {
+		return 0, nil
+	} else if errInGet != nil {
+		return 0, errInGet
+	}
+	return version, nil
+}
+
+func (c *ActionResultCache) InvalidateResource(resourceID int) error {
+	versionKey := c.buildVersionKey(resourceID)
+	_, errInExec := c.cache.TxPipelined(c.context, func(pipe redis.Pipeliner) error {
+		pipe.Incr(c.context, versionKey)
+		pipe.Expire(c.context, versionKey, ACTION_RESULT_CACHE_VERSION_TTL)
+		return nil
+	})
+	return errInExec
+}
+
+// RetrieveActionResult returns nil when cache missed
+func (c *ActionResultCache) RetrieveActionResult(resourceID int, version int64, fingerprint string) ([]byte, error) {
+	result, errInGet := c.cache.Get(c.context, c.buildResultKey(resourceID, version, fingerprint)).Bytes()
+	if errInGet == redis.Nil {
+		return nil, nil
+	} else if errInGet != nil {
+		return nil, errInGet
+	}
+	return result, nil
+}
+
+func (c *ActionResultCache) SetActionResult(resourceID int, version int64, fingerprint string, result []byte, ttl time.Duration) error {
+	if ttl > ACTION_RESULT_CACHE_MAX_TTL {
+		ttl = ACTION_RESULT_CACHE_MAX_TTL
+	}
+	versionKey := c.buildVersionKey(resourceID)
+	_, errInExec := c.cache.TxPipelined(c.context, func(pipe redis.Pipeliner) error {
+		pipe.Set(c.context, c.buildResultKey(resourceID, version, fingerprint), result, ttl)
+		// create the version key when it not exists, then keep it alive longer than the result
+		pipe.SetNX(c.context, versionKey, 0, 0)
+		pipe.Expire(c.context, versionKey, ACTION_RESULT_CACHE_VERSION_TTL)
+		return nil
+	})
+	return errInExec
+}
@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	redis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	ACTION_RESULT_PAGE_KEY_PREFIX      = "action_result_page:"
+	ACTION_RESULT_PAGE_META_KEY_SUFFIX = ":meta"
+	ACTION_RESULT_PAGE_ROWS_KEY_SUFFIX = ":rows"
+
+	ACTION_RESULT_PAGE_META_FIELD_TEAM_ID   = "teamID"
+	ACTION_RESULT_PAGE_META_FIELD_ACTION_ID = "actionID"
+	ACTION_RESULT_PAGE_META_FIELD_PAGE_SIZE = "pageSize"
+)
+
+var ErrActionResultPageExpired = errors.New("action result page expired or not exists")
+
+// ActionResultPageCache spools the rows after the first page of a paginated action result,
+// the next pages can be fetched by continuation token without re-running the action.
+type ActionResultPageCache struct {
+	logger  *zap.SugaredLogger
+	cache   *redis.Client
+	context context.Context
+}
+
+type ActionResultPageMeta struct {
+	TeamID   int
+	ActionID int
+	PageSize int
+}
+
+type ActionResultContinuationToken struct {
+	ResultSetID string `json:"id"`
+	Offset      int    `json:"offset"`
+}
+
+func NewActionResultContinuationToken(resultSetID string, offset int) *ActionResultContinuationToken {
+	return &ActionResultContinuationToken{
+		ResultSetID: resultSetID,
+		Offset:      offset,
+	}
+}
+
+func NewActionResultContinuationTokenByString(token string) (*ActionResultContinuationToken, error) {
+	tokenInJSON, errInDecode := base64.RawURLEncoding.DecodeString(token)
+	if errInDecode != nil {
+		return nil, errors.New("invalid continuation token")
+	}
+	continuationToken := &ActionResultContinuationToken{}
+	if errInUnmarshal := json.Unmarshal(tokenInJSON, continuationToken); errInUnmarshal != nil {
+		return nil, errors.New("invalid continuation token")
+	}
+	if continuationToken.ResultSetID == "" || continuationToken.Offset < 0 {
+		return nil, errors.New("invalid continuation token")
+	}
+	return continuationToken, nil
+}
+
+func (token *ActionResultContinuationToken) ExportToString() string {
+	tokenInJSON, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(tokenInJSON)
+}
+
+func NewActionResultPageCache(cache *redis.Client, logger *zap.SugaredLogger) *ActionResultPageCache {
+	return &ActionResultPageCache{
+		logger:  logger,
+		cache:   cache,
+		context: context.Background(),
+	}
+}
+
+func (c *ActionResultPageCache) buildMetaKey(resultSetID string) string {
+	return ACTION_RESULT_PAGE_KEY_PREFIX + resultSetID + ACTION_RESULT_PAGE_META_KEY_SUFFIX
+}
+
+func (c *ActionResultPageCache) buildRowsKey(resultSetID string) string {
+	return ACTION_RESULT_PAGE_KEY_PREFIX + resultSetID + ACTION_RESULT_PAGE_ROWS_KEY_SUFFIX
+}
+
+func (c *ActionResultPageCache) CreateResultSet(meta *ActionResultPageMeta, ttl time.Duration) (string, error) {
+	resultSetID := strings.ReplaceAll(uuid.New().String(), "-", "")
+	metaKey := c.buildMetaKey(resultSetID)
+	pipe := c.cache.TxPipeline()
+	pipe.HSet(c.context, metaKey,
+		ACTION_RESULT_PAGE_META_FIELD_TEAM_ID, meta.TeamID,
+		ACTION_RESULT_PAGE_META_FIELD_ACTION_ID, meta.ActionID,
+		ACTION_RESULT_PAGE_META_FIELD_PAGE_SIZE, meta.PageSize,
+	)
+	pipe.Expire(c.context, metaKey, ttl)
+	if _, errInExec := pipe.Exec(c.context); errInExec != nil {
+		return "", errInExec
+	}
+	return resultSetID, nil
+}
+
+func (c *ActionResultPageCache) AppendRows(resultSetID string, rows []map[string]interface{}, ttl time.Duration) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	rowsInJSON := make([]interface{}, 0, len(rows))
+	for _, row := range rows {
+		rowInJSON, errInMarshal := json.Marshal(row)
+		if errInMarshal != nil {
+			return errInMarshal
+		}
+		rowsInJSON = append(rowsInJSON, rowInJSON)
+	}
+	rowsKey := c.buildRowsKey(resultSetID)
+	pipe := c.cache.TxPipeline()
+	pipe.RPush(c.context, rowsKey, rowsInJSON...)
+	pipe.Expire(c.context, rowsKey, ttl)
+	_, errInExec := pipe.Exec(c.context)
+	return errInExec
+}
+
+func (c *ActionResultPageCache) RetrieveResultSetMeta(resultSetID string) (*ActionResultPageMeta, error) {
+	metaInMap, errInGet := c.cache.HGetAll(c.context, c.buildMetaKey(resultSetID)).Result()
+	if errInGet != nil {
+		return nil, errInGet
+	}
+	if len(metaInMap) == 0 {
+		return nil, ErrActionResultPageExpired
+	}
+	meta := &ActionResultPageMeta{}
+	meta.TeamID, _ = strconv.Atoi(metaInMap[ACTION_RESULT_PAGE_META_FIELD_TEAM_ID])
+	meta.ActionID, _ = strconv.Atoi(metaInMap[ACTION_RESULT_PAGE_META_FIELD_ACTION_ID])
+	meta.PageSize, _ = strconv.Atoi(metaInMap[ACTION_RESULT_PAGE_META_FIELD_PAGE_SIZE])
+	return meta, nil
+}
+
+// RetrievePage returns the rows in [offset, offset+limit) and the count of all spooled rows
+func (c *ActionResultPageCache) RetrievePage(resultSetID string, offset int, limit int) ([]map[string]interface{}, int, error) {
+	rowsKey := c.buildRowsKey(resultSetID)
+	pipe := c.cache.Pipeline()
+	rangeCmd := pipe.LRange(c.context, rowsKey, int64(offset), int64(offset+limit-1))
+	lenCmd := pipe.LLen(c.context, rowsKey)
+	if _, errInExec := pipe.Exec(c.context); errInExec != nil && errInExec != redis.Nil {
+		return nil, 0, errInExec
+	}
+	rows := make([]map[string]interface{}, 0, len(rangeCmd.Val()))
+	for _, rowInJSON := range rangeCmd.Val() {
+		row := make(map[string]interface{})
+		if errInUnmarshal := json.Unmarshal([]byte(rowInJSON), &row); errInUnmarshal != nil {
+			return nil, 0, errInUnmarshal
+		}
+		rows = append(rows, row)
+	}
+	return rows, int(lenCmd.Val()), nil
+}
@@ -6,12 +6,27 @@ import (
 )
 
 type Cache struct {
-	IPZoneCache *IPZoneCache
+	IPZoneCache           *IPZoneCache
+	ActionResultPageCache *ActionResultPageCache
+	ActionResultCache     *ActionResultCache
+	ResourceMetaInfoCache *ResourceMetaInfoCache
+	SMTPOutboxCache       *SMTPOutboxCache
+	ResourceOAuth2Cache   *ResourceOAuth2Cache
 }
 
 func NewCache(redisDriver *redis.Client, logger *zap.SugaredLogger) *Cache {
 	ipZoneCache := NewIPZoneCache(redisDriver, logger)
+	actionResultPageCache := NewActionResultPageCache(redisDriver, logger)
+	actionResultCache := NewActionResultCache(redisDriver, logger)
+	resourceMetaInfoCache := NewResourceMetaInfoCache(redisDriver, logger)
+	smtpOutboxCache := NewSMTPOutboxCache(redisDriver, logger)
+	resourceOAuth2Cache := NewResourceOAuth2Cache(redisDriver, logger)
 	return &Cache{
-		IPZoneCache: ipZoneCache,
+		IPZoneCache:           ipZoneCache,
+		ActionResultPageCache: actionResultPageCache,
+		ActionResultCache:     actionResultCache,
+		ResourceMetaInfoCache: resourceMetaInfoCache,
+		SMTPOutboxCache:       smtpOutboxCache,
+		ResourceOAuth2Cache:   resourceOAuth2Cache,
 	}
 }
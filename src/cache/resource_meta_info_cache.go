@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const RESOURCE_META_INFO_CACHE_KEY_PREFIX = "resource_meta_info_cache:"
+
+// ResourceMetaInfoCache caches the meta info of resources, like tables and inferred collection schemas.
+// The update time of resource is a part of the cache key, so the meta info is fetched again after the resource updated.
+type ResourceMetaInfoCache struct {
+	logger  *zap.SugaredLogger
+	cache   *redis.Client
+	context context.Context
+}
+
+func NewResourceMetaInfoCache(cache *redis.Client, logger *zap.SugaredLogger) *ResourceMetaInfoCache {
+	return &ResourceMetaInfoCache{
+		logger:  logger,
+		cache:   cache,
+		context: context.Background(),
+	}
+}
+
+func (c *ResourceMetaInfoCache) buildKey(resourceID int, updatedAt time.Time) string {
+	return RESOURCE_META_INFO_CACHE_KEY_PREFIX + strconv.Itoa(resourceID) + ":" + strconv.FormatInt(updatedAt.UnixNano(), 10)
+}
+
+// RetrieveMetaInfo returns nil when cache missed
+func (c *ResourceMetaInfoCache) RetrieveMetaInfo(resourceID int, updatedAt time.Time) ([]byte, error) {
+	metaInfo, errInGet := c.cache.Get(c.context, c.buildKey(resourceID, updatedAt)).Bytes()
+	if errInGet == redis.Nil {
+		return nil, nil
+	} else if errInGet != nil {
+		return nil, errInGet
+	}
+	return metaInfo, nil
+}
+
+func (c *ResourceMetaInfoCache) SetMetaInfo(resourceID int, updatedAt time.Time, metaInfo []byte, ttl time.Duration) error {
+	return c.cache.Set(c.context, c.buildKey(resourceID, updatedAt), metaInfo, ttl).Err()
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	RESOURCE_OAUTH2_REFRESH_LOCK_KEY_PREFIX = "resource_oauth2:refresh_lock:resource:"
+	RESOURCE_OAUTH2_REFRESH_LOCK_TTL        = time.Minute
+	RESOURCE_OAUTH2_REFRESHER_LOCK_KEY      = "resource_oauth2:refresher_lock"
+)
+
+// ResourceOAuth2Cache holds the refresh lock of oauth2 resources, the providers rotating refresh token only accept one refresh at same time.
+type ResourceOAuth2Cache struct {
+	logger  *zap.SugaredLogger
+	cache   *redis.Client
+	context context.Context
+}
+
+func NewResourceOAuth2Cache(cache *redis.Client, logger *zap.SugaredLogger) *ResourceOAuth2Cache {
+	return &ResourceOAuth2Cache{
+		logger:  logger,
+		cache:   cache,
+		context: context.Background(),
+	}
+}
+
+func (c *ResourceOAuth2Cache) buildRefreshLockKey(resourceID int) string {
+	return RESOURCE_OAUTH2_REFRESH_LOCK_KEY_PREFIX + strconv.Itoa(resourceID)
+}
+
+func (c *ResourceOAuth2Cache) TryLockRefresh(resourceID int) (bool, error) {
+	return c.cache.SetNX(c.context, c.buildRefreshLockKey(resourceID), 1, RESOURCE_OAUTH2_REFRESH_LOCK_TTL).Result()
+}
+
+func (c *ResourceOAuth2Cache) UnlockRefresh(resourceID int) error {
+	return c.cache.Del(c.context, c.buildRefreshLockKey(resourceID)).Err()
+}
+
+// TryLockRefresher makes only one instance scan the expiring tokens in period, the lock is released by expiry.
+func (c *ResourceOAuth2Cache) TryLockRefresher(period time.Duration) (bool, error) {
+	return c.cache.SetNX(c.context, RESOURCE_OAUTH2_REFRESHER_LOCK_KEY, 1, period).Result()
+}
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	SMTP_OUTBOX_MESSAGE_KEY_PREFIX = "smtp_outbox:message:"
+	SMTP_OUTBOX_QUEUE_KEY          = "smtp_outbox:queue"
+	SMTP_OUTBOX_PROCESSING_KEY     = "smtp_outbox:processing"
+)
+
+// claimDueMessagesScript moves the expired claims back to queue first, then moves the due messages to processing with
+// the visibility deadline as score. The message rescheduled but not acknowledged keeps its due time in queue.
+var claimDueMessagesScript = redis.NewScript(`
+local expired = redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', ARGV[1])
+for _, messageID in ipairs(expired) do
+	redis.call('ZREM', KEYS[2], messageID)
+	redis.call('ZADD', KEYS[1], 'NX', ARGV[1], messageID)
+end
+local messageIDs = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[3])
+for _, messageID in ipairs(messageIDs) do
+	redis.call('ZREM', KEYS[1], messageID)
+	redis.call('ZADD', KEYS[2], ARGV[2], messageID)
+end
+return messageIDs
+`)
+
+// SMTPOutboxCache keeps the queued emails of smtp outbox, the due time of messages are scores of a sorted set,
+// and the claimed messages are kept in processing set until acknowledged.
+type SMTPOutboxCache struct {
+	logger  *zap.SugaredLogger
+	cache   *redis.Client
+	context context.Context
+}
+
+func NewSMTPOutboxCache(cache *redis.Client, logger *zap.SugaredLogger) *SMTPOutboxCache {
+	return &SMTPOutboxCache{
+		logger:  logger,
+		cache:   cache,
+		context: context.Background(),
+	}
+}
+
+func (c *SMTPOutboxCache) SaveMessage(messageID string, message []byte, ttl time.Duration) error {
+	return c.cache.Set(c.context, SMTP_OUTBOX_MESSAGE_KEY_PREFIX+messageID, message, ttl).Err()
+}
+
+// RetrieveMessage returns nil when cache missed
+func (c *SMTPOutboxCache) RetrieveMessage(messageID string) ([]byte, error) {
+	message, errInGet := c.cache.Get(c.context, SMTP_OUTBOX_MESSAGE_KEY_PREFIX+messageID).Bytes()
+	if errInGet == redis.Nil {
+		return nil, nil
+	} else if errInGet != nil {
+		return nil, errInGet
+	}
+	return message, nil
+}
+
+func (c *SMTPOutboxCache) ScheduleMessage(messageID string, dueAt time.Time) error {
+	return c.cache.ZAdd(c.context, SMTP_OUTBOX_QUEUE_KEY, redis.Z{Score: float64(dueAt.UnixMilli()), Member: messageID}).Err()
+}
+
+// ClaimDueMessages moves the due messages to processing atomically, so a message is claimed by only one worker
+func (c *SMTPOutboxCache) ClaimDueMessages(now time.Time, visibilityTimeout time.Duration, limit int64) ([]string, error) {
+	keys := []string{SMTP_OUTBOX_QUEUE_KEY, SMTP_OUTBOX_PROCESSING_KEY}
+	messageIDs, errInClaim := claimDueMessagesScript.Run(c.context, c.cache, keys, now.UnixMilli(), now.Add(visibilityTimeout).UnixMilli(), limit).StringSlice()
+	if errInClaim == redis.Nil {
+		return []string{}, nil
+	}
+	return messageIDs, errInClaim
+}
+
+func (c *SMTPOutboxCache) AckMessage(messageID string) error {
+	return c.cache.ZRem(c.context, SMTP_OUTBOX_PROCESSING_KEY, messageID).Err()
+}
@@ -0,0 +1,86 @@
+//go:build integration
+
+package cache
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// the tests run against redis, start it by:
+// docker run -p 6379:6379 redis
+// then run: REDIS_TEST_ADDR=127.0.0.1:6379 go test -tags integration ./src/cache/
+func newTestSMTPOutboxCache(t *testing.T) (*SMTPOutboxCache, *redis.Client) {
+	addr := os.Getenv("REDIS_TEST_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_TEST_ADDR is not set")
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	client.Del(context.Background(), SMTP_OUTBOX_QUEUE_KEY, SMTP_OUTBOX_PROCESSING_KEY)
+	t.Cleanup(func() {
+		client.Del(context.Background(), SMTP_OUTBOX_QUEUE_KEY, SMTP_OUTBOX_PROCESSING_KEY)
+		client.Close()
+	})
+	return NewSMTPOutboxCache(client, zap.NewNop().Sugar()), client
+}
+
+func TestSMTPOutboxCacheClaimsDueMessagesOnce(t *testing.T) {
+	outboxCache, _ := newTestSMTPOutboxCache(t)
+	now := time.Now()
+	assert.Nil(t, outboxCache.ScheduleMessage("due-1", now.Add(-time.Second)))
+	assert.Nil(t, outboxCache.ScheduleMessage("due-2", now))
+	assert.Nil(t, outboxCache.ScheduleMessage("later", now.Add(time.Minute)))
+
+	messageIDs, err := outboxCache.ClaimDueMessages(now, time.Minute, 10)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"due-1", "due-2"}, messageIDs)
+
+	// the claimed messages are invisible until the visibility timeout elapsed
+	messageIDs, err = outboxCache.ClaimDueMessages(now, time.Minute, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(messageIDs))
+
+	messageIDs, err = outboxCache.ClaimDueMessages(now, time.Minute, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(messageIDs))
+}
+
+func TestSMTPOutboxCacheClaimsUnacknowledgedMessageAgain(t *testing.T) {
+	outboxCache, client := newTestSMTPOutboxCache(t)
+	now := time.Now()
+	assert.Nil(t, outboxCache.ScheduleMessage("acked", now))
+	assert.Nil(t, outboxCache.ScheduleMessage("crashed", now))
+	messageIDs, err := outboxCache.ClaimDueMessages(now, time.Minute, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(messageIDs))
+	assert.Nil(t, outboxCache.AckMessage("acked"))
+
+	messageIDs, err = outboxCache.ClaimDueMessages(now.Add(2*time.Minute), time.Minute, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"crashed"}, messageIDs)
+	processingCount, _ := client.ZCard(context.Background(), SMTP_OUTBOX_PROCESSING_KEY).Result()
+	assert.Equal(t, int64(1), processingCount)
+}
+
+func TestSMTPOutboxCacheKeepsRescheduledDueTime(t *testing.T) {
+	outboxCache, _ := newTestSMTPOutboxCache(t)
+	now := time.Now()
+	assert.Nil(t, outboxCache.ScheduleMessage("retried", now))
+	_, err := outboxCache.ClaimDueMessages(now, time.Minute, 10)
+	assert.Nil(t, err)
+
+	// rescheduled for retry, but the worker stopped before acknowledged
+	assert.Nil(t, outboxCache.ScheduleMessage("retried", now.Add(time.Hour)))
+	messageIDs, err := outboxCache.ClaimDueMessages(now.Add(2*time.Minute), time.Minute, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(messageIDs))
+	messageIDs, err = outboxCache.ClaimDueMessages(now.Add(2*time.Hour), time.Minute, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"retried"}, messageIDs)
+}
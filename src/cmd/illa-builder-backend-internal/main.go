@@ -1,8 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/illacloud/builder-backend/src/actionruntime/plugin"
 	"github.com/illacloud/builder-backend/src/controller"
 	"github.com/illacloud/builder-backend/src/drive"
 	"github.com/illacloud/builder-backend/src/driver/awss3"
@@ -20,19 +27,23 @@ import (
 	"go.uber.org/zap"
 )
 
+const SERVER_SHUTDOWN_TIMEOUT = 10 * time.Second
+
 type Server struct {
-	engine *gin.Engine
-	router *internalrouter.Router
-	logger *zap.SugaredLogger
-	config *config.Config
+	engine  *gin.Engine
+	router  *internalrouter.Router
+	logger  *zap.SugaredLogger
+	config  *config.Config
+	plugins []*plugin.Plugin
 }
 
-func NewServer(config *config.Config, engine *gin.Engine, router *internalrouter.Router, logger *zap.SugaredLogger) *Server {
+func NewServer(config *config.Config, engine *gin.Engine, router *internalrouter.Router, logger *zap.SugaredLogger, plugins []*plugin.Plugin) *Server {
 	return &Server{
-		engine: engine,
-		config: config,
-		router: router,
-		logger: logger,
+		engine:  engine,
+		config:  config,
+		router:  router,
+		logger:  logger,
+		plugins: plugins,
 	}
 }
 
@@ -55,6 +66,15 @@ func initDrive(globalConfig *config.Config, logger *zap.SugaredLogger) *drive.Dr
 	return nil
 }
 
+func initPlugins(globalConfig *config.Config, logger *zap.SugaredLogger) []*plugin.Plugin {
+	if !globalConfig.IsPluginEnabled() {
+		return nil
+	}
+	plugins := plugin.LoadPlugins(globalConfig.GetPluginDirectory(), globalConfig.GetPluginHealthCheckInterval(), globalConfig.GetPluginCallTimeout())
+	logger.Infow("connector plugins loaded", "count", len(plugins))
+	return plugins
+}
+
 func initServer() (*Server, error) {
 	globalConfig := config.GetInstance()
 	engine := gin.New()
@@ -67,6 +87,9 @@ func initServer() (*Server, error) {
 	storage := initStorage(globalConfig, sugaredLogger)
 	drive := initDrive(globalConfig, sugaredLogger)
 
+	// init connector plugins
+	plugins := initPlugins(globalConfig, sugaredLogger)
+
 	// init attribute group
 	attrg, errInNewAttributeGroup := accesscontrol.NewRawAttributeGroup()
 	if errInNewAttributeGroup != nil {
@@ -76,7 +99,7 @@ func initServer() (*Server, error) {
 	// init controller
 	c := controller.NewControllerForBackend(storage, nil, drive, validator, attrg)
 	router := internalrouter.NewRouter(c)
-	server := NewServer(globalConfig, engine, router, sugaredLogger)
+	server := NewServer(globalConfig, engine, router, sugaredLogger, plugins)
 	return server, nil
 }
 
@@ -92,11 +115,29 @@ func (server *Server) Start() {
 	server.router.RegisterRouters(server.engine)
 
 	// run
-	err := server.engine.Run(server.config.ServerHost + ":" + server.config.InternalServerPort)
-	if err != nil {
-		server.logger.Errorw("Error in startup", "err", err)
-		os.Exit(2)
+	httpServer := &http.Server{
+		Addr:    server.config.ServerHost + ":" + server.config.InternalServerPort,
+		Handler: server.engine,
+	}
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			server.logger.Errorw("Error in startup", "err", err)
+			plugin.StopPlugins(server.plugins)
+			os.Exit(2)
+		}
+	}()
+
+	// shutdown on signal, the connector plugin processes should be stopped with server, or they will be orphaned
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	server.logger.Infow("Shutting down illa-builder-backend-internal...")
+	ctx, cancel := context.WithTimeout(context.Background(), SERVER_SHUTDOWN_TIMEOUT)
+	defer cancel()
+	if err := httpServer.Shutdown(ctx); err != nil {
+		server.logger.Errorw("Error in shutdown", "err", err)
 	}
+	plugin.StopPlugins(server.plugins)
 }
 
 func main() {
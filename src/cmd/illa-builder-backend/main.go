@@ -1,8 +1,16 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/illacloud/builder-backend/src/actionruntime/plugin"
+	"github.com/illacloud/builder-backend/src/actionruntime/smtp"
 	"github.com/illacloud/builder-backend/src/cache"
 	"github.com/illacloud/builder-backend/src/controller"
 	"github.com/illacloud/builder-backend/src/drive"
@@ -22,19 +30,23 @@ import (
 	"go.uber.org/zap"
 )
 
+const SERVER_SHUTDOWN_TIMEOUT = 10 * time.Second
+
 type Server struct {
-	engine *gin.Engine
-	router *router.Router
-	logger *zap.SugaredLogger
-	config *config.Config
+	engine  *gin.Engine
+	router  *router.Router
+	logger  *zap.SugaredLogger
+	config  *config.Config
+	plugins []*plugin.Plugin
 }
 
-func NewServer(config *config.Config, engine *gin.Engine, router *router.Router, logger *zap.SugaredLogger) *Server {
+func NewServer(config *config.Config, engine *gin.Engine, router *router.Router, logger *zap.SugaredLogger, plugins []*plugin.Plugin) *Server {
 	return &Server{
-		engine: engine,
-		config: config,
-		router: router,
-		logger: logger,
+		engine:  engine,
+		config:  config,
+		router:  router,
+		logger:  logger,
+		plugins: plugins,
 	}
 }
 
@@ -65,6 +77,28 @@ func initDrive(globalConfig *config.Config, logger *zap.SugaredLogger) *drive.Dr
 	return nil
 }
 
+func initPlugins(globalConfig *config.Config, logger *zap.SugaredLogger) []*plugin.Plugin {
+	if !globalConfig.IsPluginEnabled() {
+		return nil
+	}
+	plugins := plugin.LoadPlugins(globalConfig.GetPluginDirectory(), globalConfig.GetPluginHealthCheckInterval(), globalConfig.GetPluginCallTimeout())
+	logger.Infow("connector plugins loaded", "count", len(plugins))
+	return plugins
+}
+
+// initSMTPOutbox enables the outbox with the delivery worker, the queued emails load the resource options in delivery
+func initSMTPOutbox(globalConfig *config.Config, cache *cache.Cache, storage *storage.Storage) {
+	resourceLoader := func(teamID int, resourceID int) (map[string]interface{}, error) {
+		resource, errInRetrieveResource := storage.ResourceStorage.RetrieveByTeamIDAndResourceID(teamID, resourceID)
+		if errInRetrieveResource != nil {
+			return nil, errInRetrieveResource
+		}
+		return resource.ExportOptionsInMap(), nil
+	}
+	smtp.EnableOutbox(cache.SMTPOutboxCache, resourceLoader, globalConfig.GetSMTPOutboxMaxAttempts(), globalConfig.GetSMTPOutboxRetention())
+	smtp.StartOutboxWorker(globalConfig.GetSMTPOutboxPollInterval())
+}
+
 func initServer() (*Server, error) {
 	globalConfig := config.GetInstance()
 	engine := gin.New()
@@ -78,6 +112,12 @@ func initServer() (*Server, error) {
 	cache := initCache(globalConfig, sugaredLogger)
 	drive := initDrive(globalConfig, sugaredLogger)
 
+	// init connector plugins
+	plugins := initPlugins(globalConfig, sugaredLogger)
+
+	// init smtp outbox
+	initSMTPOutbox(globalConfig, cache, storage)
+
 	// init attribute group
 	attrg, errInNewAttributeGroup := accesscontrol.NewRawAttributeGroup()
 	if errInNewAttributeGroup != nil {
@@ -86,8 +126,11 @@ func initServer() (*Server, error) {
 
 	// init controller
 	c := controller.NewControllerForBackend(storage, cache, drive, validator, attrg)
+
+	// init oauth2 token refresher
+	c.StartOAuth2TokenRefresher(globalConfig.GetOAuth2RefreshInterval(), globalConfig.GetOAuth2RefreshBeforeExpiry())
 	router := router.NewRouter(c)
-	server := NewServer(globalConfig, engine, router, sugaredLogger)
+	server := NewServer(globalConfig, engine, router, sugaredLogger, plugins)
 	return server, nil
 
 }
@@ -104,11 +147,29 @@ func (server *Server) Start() {
 	server.router.RegisterRouters(server.engine)
 
 	// run
-	err := server.engine.Run(server.config.ServerHost + ":" + server.config.ServerPort)
-	if err != nil {
-		server.logger.Errorw("Error in startup", "err", err)
-		os.Exit(2)
+	httpServer := &http.Server{
+		Addr:    server.config.ServerHost + ":" + server.config.ServerPort,
+		Handler: server.engine,
+	}
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			server.logger.Errorw("Error in startup", "err", err)
+			plugin.StopPlugins(server.plugins)
+			os.Exit(2)
+		}
+	}()
+
+	// shutdown on signal, the connector plugin processes should be stopped with server, or they will be orphaned
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	server.logger.Infow("Shutting down illa-builder-backend...")
+	ctx, cancel := context.WithTimeout(context.Background(), SERVER_SHUTDOWN_TIMEOUT)
+	defer cancel()
+	if err := httpServer.Shutdown(ctx); err != nil {
+		server.logger.Errorw("Error in shutdown", "err", err)
 	}
+	plugin.StopPlugins(server.plugins)
 }
 
 func main() {
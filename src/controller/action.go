@@ -2,19 +2,17 @@ package controller
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"log"
 	"net/http"
-	"regexp"
-	"strconv"
-	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
 	"github.com/illacloud/builder-backend/src/model"
 	"github.com/illacloud/builder-backend/src/request"
 	"github.com/illacloud/builder-backend/src/response"
 	"github.com/illacloud/builder-backend/src/utils/accesscontrol"
+	"github.com/illacloud/builder-backend/src/utils/config"
 	"github.com/illacloud/builder-backend/src/utils/illaresourcemanagersdk"
 )
 
@@ -234,6 +232,7 @@ func (controller *Controller) DeleteAction(c *gin.Context) {
 		controller.FeedbackBadRequest(c, ERROR_FLAG_CAN_NOT_DELETE_ACTION, "delete action error: "+errInDelete.Error())
 		return
 	}
+	controller.deleteActionResumeToken(teamID, model.ACTION_RESUME_TOKEN_KIND_ACTION, actionID)
 
 	// feedback
 	controller.FeedbackOK(c, response.NewDeleteActionResponse(actionID))
@@ -337,6 +336,47 @@ func (controller *Controller) RunAction(c *gin.Context) {
 		return
 	}
 
+	// get action, resource and assembly action
+	actionAssemblyLine, action, resource, assembled := controller.assembleActionForRun(c, teamID, appID, actionID, userID, userAuthToken, runActionRequest)
+	if !assembled {
+		return
+	}
+
+	// attach result stream when client asked for streaming or paginated result
+	resultStream, errInNewResultStream := controller.NewActionResultStreamByRequest(c, teamID, actionID)
+	if errInNewResultStream != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_VALIDATE_REQUEST_PARAM_FAILED, "invalid result stream param: "+errInNewResultStream.Error())
+		return
+	}
+	streamableActionAssemblyLine, isStreamable := actionAssemblyLine.(common.StreamableDataConnector)
+	if resultStream != nil && isStreamable {
+		streamableActionAssemblyLine.SetResultStream(resultStream)
+	}
+
+	// run
+	log.Printf("[DUMP]action: %+v\n", action)
+	log.Printf("[DUMP] resource.ExportOptionsInMap(): %+v, action.ExportTemplateInMap(): %+v\n", resource.ExportOptionsInMap(), action.ExportTemplateInMap())
+	actionRunResult, errInRunAction := controller.runActionWithResultCache(c.Request.Context(), actionAssemblyLine, action, resource, resultStream != nil)
+	if resultStream != nil && (errInRunAction == nil || resultStream.HasStarted()) {
+		if errInRunAction == nil && !isStreamable {
+			errInRunAction = writeRowsToActionResultStream(resultStream, actionRunResult.Rows)
+			actionRunResult.Rows = nil
+		}
+		resultStream.Feedback(c, actionRunResult, errInRunAction)
+		return
+	}
+	if errInRunAction != nil {
+		controller.FeedbackRunActionError(c, ERROR_FLAG_EXECUTE_ACTION_FAILED, "run action error: ", errInRunAction, actionRunResult.Extra)
+		return
+	}
+
+	// feedback
+	c.JSON(http.StatusOK, capActionRunResultRows(actionRunResult, config.GetInstance().GetActionResultMaxRows()))
+}
+
+// assembleActionForRun fetch action and resource, then build the action connector with validated template.
+// It returns false when the error has already been feedback to client.
+func (controller *Controller) assembleActionForRun(c *gin.Context, teamID int, appID int, actionID int, userID int, userAuthToken string, runActionRequest *request.RunActionRequest) (common.DataConnector, *model.Action, *model.Resource, bool) {
 	// get action
 	action := model.NewAction()
 	fmt.Printf("[RetrieveActionsByTeamIDActionID] teamID: %d, actionID: %d\n", teamID, actionID)
@@ -347,7 +387,7 @@ func (controller *Controller) RunAction(c *gin.Context) {
 		app, errInRetrieveApp := controller.Storage.AppStorage.RetrieveAppByTeamIDAndAppID(teamID, appID)
 		if errInRetrieveApp != nil {
 			controller.FeedbackBadRequest(c, ERROR_FLAG_CAN_NOT_GET_APP, "get app failed: "+errInRetrieveApp.Error())
-			return
+			return nil, nil, nil, false
 		}
 		action = model.NewAcitonByRunActionRequest(app, userID, runActionRequest)
 	} else {
@@ -356,7 +396,7 @@ func (controller *Controller) RunAction(c *gin.Context) {
 		action, errInRetrieveAction = controller.Storage.ActionStorage.RetrieveActionByTeamIDActionID(teamID, actionID)
 		if errInRetrieveAction != nil {
 			controller.FeedbackBadRequest(c, ERROR_FLAG_CAN_NOT_GET_ACTION, "get action failed: "+errInRetrieveAction.Error())
-			return
+			return nil, nil, nil, false
 		}
 	}
 
@@ -369,7 +409,7 @@ func (controller *Controller) RunAction(c *gin.Context) {
 	actionAssemblyLine, errInBuild := actionFactory.Build()
 	if errInBuild != nil {
 		controller.FeedbackBadRequest(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, "validate action type error: "+errInBuild.Error())
-		return
+		return nil, nil, nil, false
 	}
 
 	// get resource
@@ -380,14 +420,14 @@ func (controller *Controller) RunAction(c *gin.Context) {
 		resource, errInRetrieveResource = controller.Storage.ResourceStorage.RetrieveByTeamIDAndResourceID(teamID, action.ExportResourceID())
 		if errInRetrieveResource != nil {
 			controller.FeedbackBadRequest(c, ERROR_FLAG_CAN_NOT_GET_RESOURCE, "get resource failed: "+errInRetrieveResource.Error())
-			return
+			return nil, nil, nil, false
 		}
 		// resource option validate only happend in create or update phrase
 		// note that validate will set resprce options to actionAssemblyLine
-		_, errInValidateResourceOptions := actionAssemblyLine.ValidateResourceOptions(resource.ExportOptionsInMap())
+		_, errInValidateResourceOptions := common.ValidateResourceOptions(action.ExportType(), actionAssemblyLine, resource.ExportOptionsInMap())
 		if errInValidateResourceOptions != nil {
-			controller.FeedbackBadRequest(c, ERROR_FLAG_VALIDATE_RESOURCE_FAILED, "validate resource failed: "+errInValidateResourceOptions.Error())
-			return
+			controller.FeedbackValidateError(c, ERROR_FLAG_VALIDATE_RESOURCE_FAILED, "validate resource failed: ", errInValidateResourceOptions)
+			return nil, nil, nil, false
 		}
 	} else {
 		// process virtual resource action
@@ -397,40 +437,11 @@ func (controller *Controller) RunAction(c *gin.Context) {
 	// check action template
 	fmt.Printf("[DUMP] action.ExportTemplateInMap(): %+v\n", action.ExportTemplateInMap())
 	fmt.Printf("[DUMP] action.ExportRawTemplateInMap(): %+v\n", action.ExportRawTemplateInMap())
-	_, errInValidate := actionAssemblyLine.ValidateActionTemplate(action.ExportTemplateInMap())
+	_, errInValidate := common.ValidateActionTemplate(action.ExportType(), actionAssemblyLine, action.ExportTemplateInMap())
 	if errInValidate != nil {
-		controller.FeedbackBadRequest(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, "validate action template error: "+errInValidate.Error())
-		return
-	}
-
-	// run
-	log.Printf("[DUMP]action: %+v\n", action)
-	log.Printf("[DUMP] resource.ExportOptionsInMap(): %+v, action.ExportTemplateInMap(): %+v\n", resource.ExportOptionsInMap(), action.ExportTemplateInMap())
-	actionRunResult, errInRunAction := actionAssemblyLine.Run(resource.ExportOptionsInMap(), action.ExportTemplateInMap(), action.ExportRawTemplateInMap())
-	if errInRunAction != nil {
-		if strings.HasPrefix(errInRunAction.Error(), "Error 1064:") {
-			lineNumber, _ := strconv.Atoi(errInRunAction.Error()[len(errInRunAction.Error())-1:])
-			message := ""
-			regexp, _ := regexp.Compile(`to use`)
-			match := regexp.FindStringIndex(errInRunAction.Error())
-			if len(match) == 2 {
-				message = errInRunAction.Error()[match[1]:]
-			}
-			c.JSON(http.StatusBadRequest, gin.H{
-				"errorCode":    400,
-				"errorFlag":    ERROR_FLAG_EXECUTE_ACTION_FAILED,
-				"errorMessage": errors.New("SQL syntax error").Error(),
-				"errorData": map[string]interface{}{
-					"lineNumber": lineNumber,
-					"message":    "SQL syntax error" + message,
-				},
-			})
-			return
-		}
-		controller.FeedbackBadRequest(c, ERROR_FLAG_EXECUTE_ACTION_FAILED, "run action error: "+errInRunAction.Error())
-		return
+		controller.FeedbackValidateError(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, "validate action template error: ", errInValidate)
+		return nil, nil, nil, false
 	}
 
-	// feedback
-	c.JSON(http.StatusOK, actionRunResult)
+	return actionAssemblyLine, action, resource, true
 }
@@ -0,0 +1,229 @@
+package controller
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/illacloud/builder-backend/src/request"
+	"github.com/illacloud/builder-backend/src/utils/accesscontrol"
+	"github.com/illacloud/builder-backend/src/utils/config"
+	"github.com/illacloud/builder-backend/src/utils/illadrivesdk"
+	"github.com/illacloud/builder-backend/src/utils/resultexporter"
+)
+
+const (
+	ACTION_EXPORT_TEMP_FILE_PATTERN = "illa-action-export-*"
+	ACTION_EXPORT_FIELD_FILE_NAME   = "fileName"
+	ACTION_EXPORT_FIELD_FORMAT      = "format"
+)
+
+// exportActionResultStream feeds the action result rows into a result exporter
+type exportActionResultStream struct {
+	exporter  resultexporter.ResultExporter
+	maxRows   int
+	rowCount  int
+	truncated bool
+}
+
+func newExportActionResultStream(exporter resultexporter.ResultExporter, maxRows int) *exportActionResultStream {
+	return &exportActionResultStream{
+		exporter: exporter,
+		maxRows:  maxRows,
+	}
+}
+
+func (stream *exportActionResultStream) WriteRow(row map[string]interface{}) error {
+	if stream.rowCount >= stream.maxRows {
+		stream.truncated = true
+		return common.ErrResultStreamLimitReached
+	}
+	if err := stream.exporter.WriteRow(row); err != nil {
+		return err
+	}
+	stream.rowCount++
+	return nil
+}
+
+func (stream *exportActionResultStream) ExportExtra() map[string]interface{} {
+	return map[string]interface{}{
+		common.RESULT_STREAM_FIELD_TRUNCATED: stream.truncated,
+		common.RESULT_STREAM_FIELD_MAX_ROWS:  stream.maxRows,
+		common.RESULT_STREAM_FIELD_ROW_COUNT: stream.rowCount,
+	}
+}
+
+// exportResponseWriter sends the download headers with the first write,
+// so the errors occurred before that can still feedback as a normal bad request.
+type exportResponseWriter struct {
+	c           *gin.Context
+	contentType string
+	fileName    string
+	started     bool
+}
+
+func (writer *exportResponseWriter) start() {
+	writer.c.Header("Content-Type", writer.contentType)
+	writer.c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": writer.fileName}))
+	writer.c.Header("Cache-Control", "no-cache")
+	writer.c.Status(http.StatusOK)
+	writer.started = true
+}
+
+func (writer *exportResponseWriter) Write(p []byte) (int, error) {
+	if !writer.started {
+		writer.start()
+	}
+	return writer.c.Writer.Write(p)
+}
+
+func (controller *Controller) ExportActionResult(c *gin.Context) {
+	// fetch needed param
+	teamID, errInGetTeamID := controller.GetMagicIntParamFromRequest(c, PARAM_TEAM_ID)
+	appID, errInGetAppID := controller.GetMagicIntParamFromRequest(c, PARAM_APP_ID)
+	actionID, errInGetActionID := controller.GetMagicIntParamFromRequest(c, PARAM_ACTION_ID)
+	userAuthToken, errInGetAuthToken := controller.GetUserAuthTokenFromHeader(c)
+	userID, errInGetUserID := controller.GetUserIDFromAuth(c)
+	if errInGetTeamID != nil || errInGetAppID != nil || errInGetActionID != nil || errInGetAuthToken != nil || errInGetUserID != nil {
+		return
+	}
+
+	// validate
+	canManage, errInCheckAttr := controller.AttributeGroup.CanManage(
+		teamID,
+		userAuthToken,
+		accesscontrol.UNIT_TYPE_ACTION,
+		actionID,
+		accesscontrol.ACTION_MANAGE_RUN_ACTION,
+	)
+	if errInCheckAttr != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_ACCESS_DENIED, "error in check attribute: "+errInCheckAttr.Error())
+		return
+	}
+	if !canManage {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_ACCESS_DENIED, "you can not access this attribute due to access control policy.")
+		return
+	}
+
+	// parse request body
+	exportActionRequest := request.NewExportActionRequest()
+	if err := json.NewDecoder(c.Request.Body).Decode(&exportActionRequest); err != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_PARSE_REQUEST_BODY_FAILED, "parse request body error: "+err.Error())
+		return
+	}
+	validate := validator.New()
+	if exportActionRequest.Export == nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, "validate request body error: missing export option")
+		return
+	}
+	if err := validate.Struct(exportActionRequest.Export); err != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, "validate request body error: "+err.Error())
+		return
+	}
+	if exportActionRequest.IsExportToDrive() && !config.GetInstance().IsCloudMode() {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, "export to ILLA Drive is only available on ILLA Cloud")
+		return
+	}
+
+	// get action, resource and assembly action
+	actionAssemblyLine, action, resource, assembled := controller.assembleActionForRun(c, teamID, appID, actionID, userID, userAuthToken, exportActionRequest.ExportRunActionRequest())
+	if !assembled {
+		return
+	}
+
+	// init export output, the drive destination needs the file size before upload, so buffer it in a temp file
+	var output io.Writer
+	var responseWriter *exportResponseWriter
+	var tempFile *os.File
+	if exportActionRequest.IsExportToDrive() {
+		var errInCreateTempFile error
+		tempFile, errInCreateTempFile = os.CreateTemp("", ACTION_EXPORT_TEMP_FILE_PATTERN)
+		if errInCreateTempFile != nil {
+			controller.FeedbackInternalServerError(c, ERROR_FLAG_EXECUTE_ACTION_FAILED, "create export file failed: "+errInCreateTempFile.Error())
+			return
+		}
+		defer os.Remove(tempFile.Name())
+		defer tempFile.Close()
+		output = tempFile
+	} else {
+		responseWriter = &exportResponseWriter{c: c}
+		output = responseWriter
+	}
+	exporter, errInNewExporter := resultexporter.NewResultExporter(exportActionRequest.ExportFormat(), output)
+	if errInNewExporter != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, errInNewExporter.Error())
+		return
+	}
+	fileName := exportActionRequest.ExportFileName(exporter.ExportFileExtension())
+	if responseWriter != nil {
+		responseWriter.contentType = exporter.ExportContentType()
+		responseWriter.fileName = fileName
+	}
+
+	// run
+	exportStream := newExportActionResultStream(exporter, config.GetInstance().GetActionExportMaxRows())
+	streamableActionAssemblyLine, isStreamable := actionAssemblyLine.(common.StreamableDataConnector)
+	if isStreamable {
+		streamableActionAssemblyLine.SetResultStream(exportStream)
+	}
+	bindActionResource(actionAssemblyLine, action.ExportTeamID(), action.ExportResourceID())
+	actionRunResult, errInRunAction := actionAssemblyLine.Run(resource.ExportOptionsInMap(), action.ExportTemplateInMap(), action.ExportRawTemplateInMap())
+	errInRunAction = common.NormalizeRunError(actionAssemblyLine, errInRunAction)
+	if errInRunAction == nil && !isStreamable {
+		errInRunAction = writeRowsToActionResultStream(exportStream, actionRunResult.Rows)
+	}
+	if errInRunAction == nil {
+		errInRunAction = exporter.Close()
+	}
+	if errInRunAction != nil {
+		// the download has been started, we can only abort the connection.
+		if responseWriter != nil && responseWriter.started {
+			log.Printf("[ERROR] export action result failed after response started: %s\n", errInRunAction.Error())
+			c.Abort()
+			return
+		}
+		controller.FeedbackRunActionError(c, ERROR_FLAG_EXECUTE_ACTION_FAILED, "export action result error: ", errInRunAction, nil)
+		return
+	}
+
+	// browser download finished
+	if responseWriter != nil {
+		if !responseWriter.started {
+			responseWriter.start()
+		}
+		return
+	}
+
+	// upload to drive
+	fileInfo, errInStat := tempFile.Stat()
+	if errInStat != nil {
+		controller.FeedbackInternalServerError(c, ERROR_FLAG_EXECUTE_ACTION_FAILED, "read export file failed: "+errInStat.Error())
+		return
+	}
+	if _, errInSeek := tempFile.Seek(0, io.SeekStart); errInSeek != nil {
+		controller.FeedbackInternalServerError(c, ERROR_FLAG_EXECUTE_ACTION_FAILED, "read export file failed: "+errInSeek.Error())
+		return
+	}
+	driveAPI := illadrivesdk.NewIllaDriveRestAPI(teamID, userID, accesscontrol.UNIT_TYPE_APP, appID)
+	driveFile, errInUpload := driveAPI.UploadFile(exportActionRequest.ExportOverwriteDuplicate(), exportActionRequest.ExportDrivePath(), fileName, fileInfo.Size(), exporter.ExportContentType(), tempFile)
+	if errInUpload != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_EXECUTE_ACTION_FAILED, "upload export file to drive failed: "+errInUpload.Error())
+		return
+	}
+
+	// feedback
+	extra := exportStream.ExportExtra()
+	extra[ACTION_EXPORT_FIELD_FILE_NAME] = fileName
+	extra[ACTION_EXPORT_FIELD_FORMAT] = exportActionRequest.ExportFormat()
+	c.JSON(http.StatusOK, common.RuntimeResult{
+		Success: true,
+		Rows:    []map[string]interface{}{driveFile},
+		Extra:   extra,
+	})
+}
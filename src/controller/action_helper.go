@@ -2,6 +2,7 @@ package controller
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
 	"github.com/illacloud/builder-backend/src/model"
 	"github.com/illacloud/builder-backend/src/request"
 	"github.com/illacloud/builder-backend/src/utils/illaresourcemanagersdk"
@@ -22,9 +23,9 @@ func (controller *Controller) ValidateActionTemplate(c *gin.Context, action *mod
 	}
 
 	// check template
-	_, errInValidate := actionAssemblyLine.ValidateActionTemplate(action.ExportTemplateInMap())
+	_, errInValidate := common.ValidateActionTemplate(action.ExportType(), actionAssemblyLine, action.ExportTemplateInMap())
 	if errInValidate != nil {
-		controller.FeedbackBadRequest(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, "validate action template error: "+errInValidate.Error())
+		controller.FeedbackValidateError(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, "validate action template error: ", errInValidate)
 		return errInValidate
 	}
 	return nil
@@ -44,9 +45,9 @@ func (controller *Controller) ValidateFlowActionTemplate(c *gin.Context, flowAct
 	}
 
 	// check template
-	_, errInValidate := actionAssemblyLine.ValidateActionTemplate(flowAction.ExportTemplateInMap())
+	_, errInValidate := common.ValidateActionTemplate(flowAction.ExportType(), actionAssemblyLine, flowAction.ExportTemplateInMap())
 	if errInValidate != nil {
-		controller.FeedbackBadRequest(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, "validate action template error: "+errInValidate.Error())
+		controller.FeedbackValidateError(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, "validate action template error: ", errInValidate)
 		return errInValidate
 	}
 	return nil
@@ -0,0 +1,14 @@
+package controller
+
+import (
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+)
+
+// bindActionResource tells the resource bound connectors which resource they run with.
+func bindActionResource(actionAssemblyLine common.DataConnector, teamID int, resourceID int) {
+	resourceBoundConnector, isResourceBound := actionAssemblyLine.(common.ResourceBoundDataConnector)
+	if !isResourceBound {
+		return
+	}
+	resourceBoundConnector.SetResourceIdentity(teamID, resourceID)
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/illacloud/builder-backend/src/cache"
+	"github.com/illacloud/builder-backend/src/model"
+	"github.com/illacloud/builder-backend/src/utils/config"
+)
+
+const ACTION_RESULT_FIELD_CACHE_HIT = "cacheHit"
+
+// runActionWithResultCache runs the action with retry policy and caches the result when the action enabled cache and only reads data,
+// the write actions run against the same resource will invalidate the cached results. The actions of connectors which can not tell
+// reads from writes are neither cached nor invalidate anything.
+// The cache and retry are skipped when the result rows were written to a result stream.
+// The resumable actions continue from the resume token persisted by last succeeded run.
+func (controller *Controller) runActionWithResultCache(ctx context.Context, actionAssemblyLine common.DataConnector, action *model.Action, resource *model.Resource, isResultStreaming bool) (common.RuntimeResult, error) {
+	retryPolicy := action.ExportRetryPolicy()
+	if isResultStreaming {
+		retryPolicy = nil
+	}
+	controller.loadActionResumeToken(actionAssemblyLine, action.ExportTeamID(), model.ACTION_RESUME_TOKEN_KIND_ACTION, action.ExportID())
+	bindActionResource(actionAssemblyLine, action.ExportTeamID(), action.ExportResourceID())
+	run := func(isIdempotent bool) (common.RuntimeResult, error) {
+		actionRunResult, _, errInRunAction := model.RunWithRetryPolicy(ctx, retryPolicy, isIdempotent, func() (common.RuntimeResult, error) {
+			actionRunResult, errInRunAction := actionAssemblyLine.Run(resource.ExportOptionsInMap(), action.ExportTemplateInMap(), action.ExportRawTemplateInMap())
+			return actionRunResult, common.NormalizeRunError(actionAssemblyLine, errInRunAction)
+		})
+		if errInRunAction == nil {
+			controller.saveActionResumeToken(actionAssemblyLine, action.ExportTeamID(), model.ACTION_RESUME_TOKEN_KIND_ACTION, action.ExportID())
+		}
+		return actionRunResult, errInRunAction
+	}
+	cacheableActionAssemblyLine, isCacheable := actionAssemblyLine.(common.CacheableDataConnector)
+	isReadOnly := isCacheable && cacheableActionAssemblyLine.IsReadOnlyAction(action.ExportTemplateInMap())
+	isWrite := isCacheable && cacheableActionAssemblyLine.IsWriteAction(action.ExportTemplateInMap())
+	isIdempotent := !isCacheable || isReadOnly
+	if controller.Cache == nil || action.IsVirtualAction() {
+		return run(isIdempotent)
+	}
+	resourceID := action.ExportResourceID()
+	resultCache := controller.Cache.ActionResultCache
+
+	// known write action, invalidate cached results of this resource after run
+	if isWrite {
+		actionRunResult, errInRunAction := run(false)
+		if errInInvalidate := resultCache.InvalidateResource(resourceID); errInInvalidate != nil {
+			log.Printf("[ERROR] invalidate action result cache of resource %d failed: %s\n", resourceID, errInInvalidate.Error())
+		}
+		return actionRunResult, errInRunAction
+	}
+	if !isReadOnly || isResultStreaming || !action.IsCacheEnabled() {
+		return run(isIdempotent)
+	}
+
+	// the version should be fetched before run, so a result read before invalidation will not be cached under the new version
+	fingerprint := cache.BuildActionResultFingerprint(action.ExportTemplateInMap(), action.ExportRawTemplateInMap())
+	version, errInRetrieveVersion := resultCache.RetrieveResourceVersion(resourceID)
+	if errInRetrieveVersion != nil {
+		log.Printf("[ERROR] retrieve action result cache version of resource %d failed: %s\n", resourceID, errInRetrieveVersion.Error())
+		return run(true)
+	}
+	cachedResult, errInRetrieveCache := resultCache.RetrieveActionResult(resourceID, version, fingerprint)
+	if errInRetrieveCache != nil {
+		log.Printf("[ERROR] retrieve action result cache failed: %s\n", errInRetrieveCache.Error())
+	}
+	if cachedResult != nil {
+		actionRunResult := common.RuntimeResult{}
+		if errInUnmarshal := json.Unmarshal(cachedResult, &actionRunResult); errInUnmarshal == nil {
+			if actionRunResult.Extra == nil {
+				actionRunResult.Extra = make(map[string]interface{})
+			}
+			actionRunResult.Extra[ACTION_RESULT_FIELD_CACHE_HIT] = true
+			actionRunResult.Extra[model.RUNTIME_RESULT_FIELD_ATTEMPTS] = 0
+			return actionRunResult, nil
+		}
+	}
+
+	// cache missed
+	actionRunResult, errInRunAction := run(true)
+	if errInRunAction != nil || !actionRunResult.Success {
+		return actionRunResult, errInRunAction
+	}
+	actionRunResultInJSON, errInMarshal := json.Marshal(actionRunResult)
+	if errInMarshal == nil {
+		ttl := action.ExportCacheTTL(config.GetInstance().GetActionResultCacheTTL())
+		if errInSetCache := resultCache.SetActionResult(resourceID, version, fingerprint, actionRunResultInJSON, ttl); errInSetCache != nil {
+			log.Printf("[ERROR] set action result cache failed: %s\n", errInSetCache.Error())
+		}
+	}
+	if actionRunResult.Extra == nil {
+		actionRunResult.Extra = make(map[string]interface{})
+	}
+	actionRunResult.Extra[ACTION_RESULT_FIELD_CACHE_HIT] = false
+	return actionRunResult, nil
+}
+
+// isIdempotentAction treats the non-select sql actions as non-idempotent
+func isIdempotentAction(actionAssemblyLine common.DataConnector, actionTemplate map[string]interface{}) bool {
+	cacheableActionAssemblyLine, isCacheable := actionAssemblyLine.(common.CacheableDataConnector)
+	return !isCacheable || cacheableActionAssemblyLine.IsReadOnlyAction(actionTemplate)
+}
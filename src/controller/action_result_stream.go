@@ -0,0 +1,354 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/illacloud/builder-backend/src/cache"
+	"github.com/illacloud/builder-backend/src/utils/accesscontrol"
+	"github.com/illacloud/builder-backend/src/utils/config"
+)
+
+const (
+	ACTION_RESULT_FORMAT_NDJSON       = "ndjson"
+	ACTION_RESULT_CONTENT_TYPE_NDJSON = "application/x-ndjson"
+
+	ACTION_RESULT_NDJSON_LINE_TYPE_ROW    = "row"
+	ACTION_RESULT_NDJSON_LINE_TYPE_RESULT = "result"
+	ACTION_RESULT_NDJSON_LINE_TYPE_ERROR  = "error"
+
+	ACTION_RESULT_NDJSON_FLUSH_ROWS = 100
+	ACTION_RESULT_SPOOL_BATCH_ROWS  = 500
+)
+
+// actionResultStream is a common.ResultStream which can feedback itself to client after the action finished
+type actionResultStream interface {
+	common.ResultStream
+	HasStarted() bool
+	Feedback(c *gin.Context, actionRunResult common.RuntimeResult, errInRunAction error)
+}
+
+// NewActionResultStreamByRequest returns nil when client did not ask for a streaming or paginated result
+func (controller *Controller) NewActionResultStreamByRequest(c *gin.Context, teamID int, actionID int) (actionResultStream, error) {
+	maxRows := config.GetInstance().GetActionResultMaxRows()
+	resultFormat := c.Query(PARAM_RESULT_FORMAT)
+	if resultFormat == ACTION_RESULT_FORMAT_NDJSON || strings.Contains(c.GetHeader("Accept"), ACTION_RESULT_CONTENT_TYPE_NDJSON) {
+		return newNDJSONActionResultStream(c, maxRows), nil
+	}
+	pageSizeInString := c.Query(PARAM_PAGE_SIZE)
+	if pageSizeInString == "" {
+		return nil, nil
+	}
+	pageSize, errInConvert := strconv.Atoi(pageSizeInString)
+	if errInConvert != nil || pageSize <= 0 {
+		return nil, errors.New("invalid page size: " + pageSizeInString)
+	}
+	if controller.Cache == nil {
+		return nil, errors.New("paginated action result is not supported on this server")
+	}
+	if pageSize > maxRows {
+		pageSize = maxRows
+	}
+	meta := &cache.ActionResultPageMeta{
+		TeamID:   teamID,
+		ActionID: actionID,
+		PageSize: pageSize,
+	}
+	return newPagedActionResultStream(controller.Cache.ActionResultPageCache, meta, maxRows), nil
+}
+
+// writeRowsToActionResultStream feeds the rows of connectors which not implemented common.StreamableDataConnector
+func writeRowsToActionResultStream(stream common.ResultStream, rows []map[string]interface{}) error {
+	for _, row := range rows {
+		if err := stream.WriteRow(row); err != nil {
+			if err == common.ErrResultStreamLimitReached {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// capActionRunResultRows truncates the rows which were not written to a result stream, and reports it in extra like the result streams do.
+func capActionRunResultRows(actionRunResult common.RuntimeResult, maxRows int) common.RuntimeResult {
+	if len(actionRunResult.Rows) <= maxRows {
+		return actionRunResult
+	}
+	actionRunResult.Rows = actionRunResult.Rows[:maxRows]
+	if actionRunResult.Extra == nil {
+		actionRunResult.Extra = make(map[string]interface{})
+	}
+	actionRunResult.Extra[common.RESULT_STREAM_FIELD_TRUNCATED] = true
+	actionRunResult.Extra[common.RESULT_STREAM_FIELD_MAX_ROWS] = maxRows
+	actionRunResult.Extra[common.RESULT_STREAM_FIELD_ROW_COUNT] = maxRows
+	return actionRunResult
+}
+
+func mergeActionResultStreamExtra(actionRunResult common.RuntimeResult, stream actionResultStream) common.RuntimeResult {
+	if actionRunResult.Extra == nil {
+		actionRunResult.Extra = make(map[string]interface{})
+	}
+	for key, value := range stream.ExportExtra() {
+		actionRunResult.Extra[key] = value
+	}
+	return actionRunResult
+}
+
+type ndjsonActionResultLine struct {
+	Type         string                 `json:"type"`
+	Data         map[string]interface{} `json:"data,omitempty"`
+	Success      bool                   `json:"success,omitempty"`
+	Extra        map[string]interface{} `json:"extra,omitempty"`
+	ErrorMessage string                 `json:"errorMessage,omitempty"`
+	ErrorData    *common.ConnectorError `json:"errorData,omitempty"`
+}
+
+// ndjsonActionResultStream writes every row as a json line to response body, the http header will be sent with first row,
+// so the errors occurred before that can still feedback as a normal bad request.
+type ndjsonActionResultStream struct {
+	c         *gin.Context
+	encoder   *json.Encoder
+	maxRows   int
+	rowCount  int
+	truncated bool
+	started   bool
+}
+
+func newNDJSONActionResultStream(c *gin.Context, maxRows int) *ndjsonActionResultStream {
+	return &ndjsonActionResultStream{
+		c:       c,
+		encoder: json.NewEncoder(c.Writer),
+		maxRows: maxRows,
+	}
+}
+
+func (stream *ndjsonActionResultStream) start() {
+	stream.c.Header("Content-Type", ACTION_RESULT_CONTENT_TYPE_NDJSON)
+	stream.c.Header("Cache-Control", "no-cache")
+	stream.c.Header("X-Content-Type-Options", "nosniff")
+	stream.c.Status(http.StatusOK)
+	stream.started = true
+}
+
+func (stream *ndjsonActionResultStream) WriteRow(row map[string]interface{}) error {
+	if stream.rowCount >= stream.maxRows {
+		stream.truncated = true
+		return common.ErrResultStreamLimitReached
+	}
+	if !stream.started {
+		stream.start()
+	}
+	if err := stream.encoder.Encode(&ndjsonActionResultLine{Type: ACTION_RESULT_NDJSON_LINE_TYPE_ROW, Data: row}); err != nil {
+		return err
+	}
+	stream.rowCount++
+	if stream.rowCount%ACTION_RESULT_NDJSON_FLUSH_ROWS == 0 {
+		stream.c.Writer.Flush()
+	}
+	return nil
+}
+
+func (stream *ndjsonActionResultStream) ExportExtra() map[string]interface{} {
+	return map[string]interface{}{
+		common.RESULT_STREAM_FIELD_TRUNCATED: stream.truncated,
+		common.RESULT_STREAM_FIELD_MAX_ROWS:  stream.maxRows,
+		common.RESULT_STREAM_FIELD_ROW_COUNT: stream.rowCount,
+	}
+}
+
+func (stream *ndjsonActionResultStream) HasStarted() bool {
+	return stream.started
+}
+
+func (stream *ndjsonActionResultStream) Feedback(c *gin.Context, actionRunResult common.RuntimeResult, errInRunAction error) {
+	if !stream.started {
+		stream.start()
+	}
+	trailer := &ndjsonActionResultLine{Type: ACTION_RESULT_NDJSON_LINE_TYPE_RESULT}
+	if errInRunAction != nil {
+		trailer.Type = ACTION_RESULT_NDJSON_LINE_TYPE_ERROR
+		trailer.ErrorMessage = "run action error: " + errInRunAction.Error()
+		if connectorError, isConnectorError := common.AsConnectorError(errInRunAction); isConnectorError {
+			trailer.ErrorMessage = connectorError.ExportFeedbackMessage(trailer.ErrorMessage)
+			trailer.ErrorData = connectorError
+		}
+		trailer.Extra = actionRunResult.Extra
+	} else {
+		actionRunResult = mergeActionResultStreamExtra(actionRunResult, stream)
+		trailer.Success = actionRunResult.Success
+		trailer.Extra = actionRunResult.Extra
+	}
+	stream.encoder.Encode(trailer)
+	c.Writer.Flush()
+}
+
+// pagedActionResultStream keeps the first page in memory and spools the rest rows to redis,
+// the next pages can be fetched by continuation token.
+type pagedActionResultStream struct {
+	pageCache   *cache.ActionResultPageCache
+	meta        *cache.ActionResultPageMeta
+	maxRows     int
+	rowCount    int
+	truncated   bool
+	firstPage   []map[string]interface{}
+	buffer      []map[string]interface{}
+	resultSetID string
+}
+
+func newPagedActionResultStream(pageCache *cache.ActionResultPageCache, meta *cache.ActionResultPageMeta, maxRows int) *pagedActionResultStream {
+	return &pagedActionResultStream{
+		pageCache: pageCache,
+		meta:      meta,
+		maxRows:   maxRows,
+		firstPage: make([]map[string]interface{}, 0, meta.PageSize),
+		buffer:    make([]map[string]interface{}, 0, ACTION_RESULT_SPOOL_BATCH_ROWS),
+	}
+}
+
+func (stream *pagedActionResultStream) WriteRow(row map[string]interface{}) error {
+	if stream.rowCount >= stream.maxRows {
+		stream.truncated = true
+		return common.ErrResultStreamLimitReached
+	}
+	stream.rowCount++
+	if len(stream.firstPage) < stream.meta.PageSize {
+		stream.firstPage = append(stream.firstPage, row)
+		return nil
+	}
+	stream.buffer = append(stream.buffer, row)
+	if len(stream.buffer) >= ACTION_RESULT_SPOOL_BATCH_ROWS {
+		return stream.spool()
+	}
+	return nil
+}
+
+func (stream *pagedActionResultStream) spool() error {
+	if len(stream.buffer) == 0 {
+		return nil
+	}
+	ttl := config.GetInstance().GetActionResultPageTTL()
+	if stream.resultSetID == "" {
+		resultSetID, errInCreate := stream.pageCache.CreateResultSet(stream.meta, ttl)
+		if errInCreate != nil {
+			return errInCreate
+		}
+		stream.resultSetID = resultSetID
+	}
+	if errInAppend := stream.pageCache.AppendRows(stream.resultSetID, stream.buffer, ttl); errInAppend != nil {
+		return errInAppend
+	}
+	stream.buffer = stream.buffer[:0]
+	return nil
+}
+
+func (stream *pagedActionResultStream) ExportExtra() map[string]interface{} {
+	extra := map[string]interface{}{
+		common.RESULT_STREAM_FIELD_TRUNCATED: stream.truncated,
+		common.RESULT_STREAM_FIELD_MAX_ROWS:  stream.maxRows,
+		common.RESULT_STREAM_FIELD_ROW_COUNT: stream.rowCount,
+		common.RESULT_STREAM_FIELD_HAS_MORE:  stream.resultSetID != "",
+	}
+	if stream.resultSetID != "" {
+		extra[common.RESULT_STREAM_FIELD_CONTINUATION_TOKEN] = cache.NewActionResultContinuationToken(stream.resultSetID, 0).ExportToString()
+	}
+	return extra
+}
+
+func (stream *pagedActionResultStream) HasStarted() bool {
+	return false
+}
+
+func (stream *pagedActionResultStream) Feedback(c *gin.Context, actionRunResult common.RuntimeResult, errInRunAction error) {
+	if errInRunAction != nil {
+		c.JSON(http.StatusBadRequest, newRunActionErrorFeedback(ERROR_FLAG_EXECUTE_ACTION_FAILED, "run action error: "+errInRunAction.Error(), errInRunAction, actionRunResult.Extra))
+		return
+	}
+	if errInSpool := stream.spool(); errInSpool != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"errorCode":    400,
+			"errorFlag":    ERROR_FLAG_EXECUTE_ACTION_FAILED,
+			"errorMessage": "save action result pages error: " + errInSpool.Error(),
+		})
+		return
+	}
+	actionRunResult = mergeActionResultStreamExtra(actionRunResult, stream)
+	actionRunResult.Rows = stream.firstPage
+	c.JSON(http.StatusOK, actionRunResult)
+}
+
+func (controller *Controller) GetActionResultPage(c *gin.Context) {
+	// fetch needed param
+	teamID, errInGetTeamID := controller.GetMagicIntParamFromRequest(c, PARAM_TEAM_ID)
+	actionID, errInGetActionID := controller.GetMagicIntParamFromRequest(c, PARAM_ACTION_ID)
+	userAuthToken, errInGetAuthToken := controller.GetUserAuthTokenFromHeader(c)
+	continuationTokenInString, errInGetContinuationToken := controller.GetStringParamFromRequest(c, PARAM_CONTINUATION)
+	if errInGetTeamID != nil || errInGetActionID != nil || errInGetAuthToken != nil || errInGetContinuationToken != nil {
+		return
+	}
+
+	// validate
+	canManage, errInCheckAttr := controller.AttributeGroup.CanManage(
+		teamID,
+		userAuthToken,
+		accesscontrol.UNIT_TYPE_ACTION,
+		actionID,
+		accesscontrol.ACTION_MANAGE_RUN_ACTION,
+	)
+	if errInCheckAttr != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_ACCESS_DENIED, "error in check attribute: "+errInCheckAttr.Error())
+		return
+	}
+	if !canManage {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_ACCESS_DENIED, "you can not access this attribute due to access control policy.")
+		return
+	}
+	if controller.Cache == nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_CAN_NOT_GET_ACTION_RESULT, "paginated action result is not supported on this server")
+		return
+	}
+
+	// decode token and check result set owner
+	continuationToken, errInDecodeToken := cache.NewActionResultContinuationTokenByString(continuationTokenInString)
+	if errInDecodeToken != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_VALIDATE_REQUEST_PARAM_FAILED, errInDecodeToken.Error())
+		return
+	}
+	pageCache := controller.Cache.ActionResultPageCache
+	meta, errInRetrieveMeta := pageCache.RetrieveResultSetMeta(continuationToken.ResultSetID)
+	if errInRetrieveMeta != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_CAN_NOT_GET_ACTION_RESULT, "get action result failed: "+errInRetrieveMeta.Error())
+		return
+	}
+	if meta.TeamID != teamID || meta.ActionID != actionID {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_ACCESS_DENIED, "continuation token does not belong to this action.")
+		return
+	}
+
+	// fetch page
+	rows, total, errInRetrievePage := pageCache.RetrievePage(continuationToken.ResultSetID, continuationToken.Offset, meta.PageSize)
+	if errInRetrievePage != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_CAN_NOT_GET_ACTION_RESULT, "get action result failed: "+errInRetrievePage.Error())
+		return
+	}
+	nextOffset := continuationToken.Offset + len(rows)
+	hasMore := nextOffset < total
+	extra := map[string]interface{}{
+		common.RESULT_STREAM_FIELD_HAS_MORE: hasMore,
+	}
+	if hasMore {
+		extra[common.RESULT_STREAM_FIELD_CONTINUATION_TOKEN] = cache.NewActionResultContinuationToken(continuationToken.ResultSetID, nextOffset).ExportToString()
+	}
+
+	// feedback
+	c.JSON(http.StatusOK, common.RuntimeResult{
+		Success: true,
+		Rows:    rows,
+		Extra:   extra,
+	})
+}
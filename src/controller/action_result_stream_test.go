@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/illacloud/builder-backend/src/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTestRows(count int) []map[string]interface{} {
+	rows := make([]map[string]interface{}, 0, count)
+	for i := 0; i < count; i++ {
+		rows = append(rows, map[string]interface{}{"id": i})
+	}
+	return rows
+}
+
+func TestCapActionRunResultRows(t *testing.T) {
+	actionRunResult := capActionRunResultRows(common.RuntimeResult{Success: true, Rows: buildTestRows(5)}, 3)
+	assert.Equal(t, 3, len(actionRunResult.Rows))
+	assert.Equal(t, true, actionRunResult.Extra[common.RESULT_STREAM_FIELD_TRUNCATED])
+	assert.Equal(t, 3, actionRunResult.Extra[common.RESULT_STREAM_FIELD_MAX_ROWS])
+
+	// the result under limit is not changed
+	actionRunResult = capActionRunResultRows(common.RuntimeResult{Success: true, Rows: buildTestRows(3)}, 3)
+	assert.Equal(t, 3, len(actionRunResult.Rows))
+	assert.Nil(t, actionRunResult.Extra)
+}
+
+func TestPagedActionResultStreamMaxRows(t *testing.T) {
+	meta := &cache.ActionResultPageMeta{TeamID: 1, ActionID: 2, PageSize: 10}
+	stream := newPagedActionResultStream(nil, meta, 4)
+	assert.Nil(t, writeRowsToActionResultStream(stream, buildTestRows(6)))
+	assert.Equal(t, 4, len(stream.firstPage))
+	extra := stream.ExportExtra()
+	assert.Equal(t, true, extra[common.RESULT_STREAM_FIELD_TRUNCATED])
+	assert.Equal(t, 4, extra[common.RESULT_STREAM_FIELD_ROW_COUNT])
+	assert.Equal(t, false, extra[common.RESULT_STREAM_FIELD_HAS_MORE])
+}
+
+func TestNDJSONActionResultStreamMaxRows(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	stream := newNDJSONActionResultStream(c, 2)
+	assert.Nil(t, writeRowsToActionResultStream(stream, buildTestRows(3)))
+	stream.Feedback(c, common.RuntimeResult{Success: true}, nil)
+
+	assert.Equal(t, ACTION_RESULT_CONTENT_TYPE_NDJSON, recorder.Header().Get("Content-Type"))
+	lines := make([]ndjsonActionResultLine, 0)
+	scanner := bufio.NewScanner(recorder.Body)
+	for scanner.Scan() {
+		var line ndjsonActionResultLine
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	assert.Equal(t, 3, len(lines))
+	assert.Equal(t, ACTION_RESULT_NDJSON_LINE_TYPE_ROW, lines[1].Type)
+	assert.Equal(t, ACTION_RESULT_NDJSON_LINE_TYPE_RESULT, lines[2].Type)
+	assert.Equal(t, true, lines[2].Extra[common.RESULT_STREAM_FIELD_TRUNCATED])
+}
@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"log"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/illacloud/builder-backend/src/model"
+)
+
+// loadActionResumeToken sets the persisted resume token to the resumable connectors before run.
+func (controller *Controller) loadActionResumeToken(actionAssemblyLine common.DataConnector, teamID int, actionKind int, actionID int) {
+	resumableConnector, isResumable := actionAssemblyLine.(common.ResumableDataConnector)
+	if !isResumable {
+		return
+	}
+	if !model.DoesActionHasBeenCreated(actionID) {
+		return
+	}
+	resumeToken, errInRetrieve := controller.Storage.ActionResumeTokenStorage.RetrieveResumeToken(teamID, actionKind, actionID)
+	if errInRetrieve != nil {
+		log.Printf("[ERROR] retrieve action resume token failed: %s\n", errInRetrieve.Error())
+		return
+	}
+	resumableConnector.SetResumeToken(resumeToken)
+}
+
+// saveActionResumeToken persists the resume token of resumable connectors after run succeeded, the actions not saved yet have no token.
+func (controller *Controller) saveActionResumeToken(actionAssemblyLine common.DataConnector, teamID int, actionKind int, actionID int) {
+	resumableConnector, isResumable := actionAssemblyLine.(common.ResumableDataConnector)
+	if !isResumable || !model.DoesActionHasBeenCreated(actionID) {
+		return
+	}
+	resumeToken := resumableConnector.ExportResumeToken()
+	if resumeToken == "" {
+		return
+	}
+	actionResumeToken := model.NewActionResumeToken(teamID, actionKind, actionID, resumeToken)
+	if errInUpsert := controller.Storage.ActionResumeTokenStorage.Upsert(actionResumeToken); errInUpsert != nil {
+		log.Printf("[ERROR] save action resume token failed: %s\n", errInUpsert.Error())
+	}
+}
+
+// deleteActionResumeToken deletes the resume token of deleted action, the failure is only logged since the token is useless.
+func (controller *Controller) deleteActionResumeToken(teamID int, actionKind int, actionID int) {
+	if errInDelete := controller.Storage.ActionResumeTokenStorage.DeleteByAction(teamID, actionKind, actionID); errInDelete != nil {
+		log.Printf("[ERROR] delete action resume token failed: %s\n", errInDelete.Error())
+	}
+}
@@ -175,6 +175,7 @@ func (controller *Controller) DeleteApp(c *gin.Context) {
 	// delete app related states and action
 	_ = controller.Storage.TreeStateStorage.DeleteAllTypeTreeStatesByApp(teamID, appID)
 	_ = controller.Storage.KVStateStorage.DeleteAllTypeKVStatesByApp(teamID, appID)
+	_ = controller.Storage.ActionResumeTokenStorage.DeleteByApp(teamID, appID)
 	_ = controller.Storage.ActionStorage.DeleteActionsByApp(teamID, appID)
 	_ = controller.Storage.SetStateStorage.DeleteAllTypeSetStatesByApp(teamID, appID)
 	_ = controller.Storage.AppSnapshotStorage.DeleteAllAppSnapshotByTeamIDAndAppID(teamID, appID)
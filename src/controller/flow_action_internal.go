@@ -6,11 +6,9 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"regexp"
-	"strconv"
-	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
 	"github.com/illacloud/builder-backend/src/model"
 	"github.com/illacloud/builder-backend/src/request"
 	"github.com/illacloud/builder-backend/src/response"
@@ -247,9 +245,9 @@ func (controller *Controller) RunFlowActionInternal(c *gin.Context) {
 		}
 		// resource option validate only happend in create or update phrase
 		// note that validate will set resprce options to flowActionAssemblyLine
-		_, errInValidateResourceOptions := flowActionAssemblyLine.ValidateResourceOptions(resource.ExportOptionsInMap())
+		_, errInValidateResourceOptions := common.ValidateResourceOptions(flowAction.ExportType(), flowActionAssemblyLine, resource.ExportOptionsInMap())
 		if errInValidateResourceOptions != nil {
-			controller.FeedbackBadRequest(c, ERROR_FLAG_VALIDATE_RESOURCE_FAILED, "validate resource failed: "+errInValidateResourceOptions.Error())
+			controller.FeedbackValidateError(c, ERROR_FLAG_VALIDATE_RESOURCE_FAILED, "validate resource failed: ", errInValidateResourceOptions)
 			return
 		}
 	} else {
@@ -261,39 +259,27 @@ func (controller *Controller) RunFlowActionInternal(c *gin.Context) {
 	// check flowAction template
 	fmt.Printf("[DUMP] flowAction.ExportTemplateInMap(): %+v\n", flowAction.ExportTemplateInMap())
 	fmt.Printf("[DUMP] flowAction.ExportRawTemplateInMap(): %+v\n", flowAction.ExportRawTemplateInMap())
-	_, errInValidateActionTemplate := flowActionAssemblyLine.ValidateActionTemplate(flowAction.ExportTemplateInMap())
+	_, errInValidateActionTemplate := common.ValidateActionTemplate(flowAction.ExportType(), flowActionAssemblyLine, flowAction.ExportTemplateInMap())
 	if errInValidateActionTemplate != nil {
-		controller.FeedbackBadRequest(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, "validate flowAction template error: "+errInValidateActionTemplate.Error())
+		controller.FeedbackValidateError(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, "validate flowAction template error: ", errInValidateActionTemplate)
 		return
 	}
 
 	// run
 	log.Printf("[DUMP]flowAction: %+v\n", flowAction)
 	log.Printf("[DUMP] resource.ExportOptionsInMap(): %+v, flowAction.ExportTemplateInMap(): %+v\n", resource.ExportOptionsInMap(), flowAction.ExportTemplateInMap())
-	flowActionRunResult, errInRunAction := flowActionAssemblyLine.Run(resource.ExportOptionsInMap(), flowAction.ExportTemplateInMap(), flowAction.ExportRawTemplateInMap())
+	isIdempotent := isIdempotentAction(flowActionAssemblyLine, flowAction.ExportTemplateInMap())
+	controller.loadActionResumeToken(flowActionAssemblyLine, flowAction.ExportTeamID(), model.ACTION_RESUME_TOKEN_KIND_FLOW_ACTION, flowAction.ExportID())
+	bindActionResource(flowActionAssemblyLine, flowAction.ExportTeamID(), flowAction.ExportResourceID())
+	flowActionRunResult, _, errInRunAction := model.RunWithRetryPolicy(c.Request.Context(), flowAction.ExportRetryPolicy(), isIdempotent, func() (common.RuntimeResult, error) {
+		flowActionRunResult, errInRunFlowAction := flowActionAssemblyLine.Run(resource.ExportOptionsInMap(), flowAction.ExportTemplateInMap(), flowAction.ExportRawTemplateInMap())
+		return flowActionRunResult, common.NormalizeRunError(flowActionAssemblyLine, errInRunFlowAction)
+	})
 	if errInRunAction != nil {
-		if strings.HasPrefix(errInRunAction.Error(), "Error 1064:") {
-			lineNumber, _ := strconv.Atoi(errInRunAction.Error()[len(errInRunAction.Error())-1:])
-			message := ""
-			regexp, _ := regexp.Compile(`to use`)
-			match := regexp.FindStringIndex(errInRunAction.Error())
-			if len(match) == 2 {
-				message = errInRunAction.Error()[match[1]:]
-			}
-			c.JSON(http.StatusBadRequest, gin.H{
-				"errorCode":    400,
-				"errorFlag":    ERROR_FLAG_EXECUTE_FLOW_ACTION_FAILED,
-				"errorMessage": errors.New("SQL syntax error").Error(),
-				"errorData": map[string]interface{}{
-					"lineNumber": lineNumber,
-					"message":    "SQL syntax error" + message,
-				},
-			})
-			return
-		}
-		controller.FeedbackBadRequest(c, ERROR_FLAG_EXECUTE_FLOW_ACTION_FAILED, "run flowAction error: "+errInRunAction.Error())
+		controller.FeedbackRunActionError(c, ERROR_FLAG_EXECUTE_FLOW_ACTION_FAILED, "run flowAction error: ", errInRunAction, flowActionRunResult.Extra)
 		return
 	}
+	controller.saveActionResumeToken(flowActionAssemblyLine, flowAction.ExportTeamID(), model.ACTION_RESUME_TOKEN_KIND_FLOW_ACTION, flowAction.ExportID())
 
 	// feedback
 	c.JSON(http.StatusOK, flowActionRunResult)
@@ -2,12 +2,9 @@ package controller
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
-	"regexp"
-	"strconv"
-	"strings"
 
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
 	"github.com/illacloud/builder-backend/src/model"
 	"github.com/illacloud/builder-backend/src/request"
 	"github.com/illacloud/builder-backend/src/utils/accesscontrol"
@@ -83,9 +80,9 @@ func (controller *Controller) RunPublicAction(c *gin.Context) {
 		}
 		// resource option validate only happend in create or update phrase
 		// note that validate will set resprce options to actionAssemblyLine
-		_, errInValidateResourceOptions := actionAssemblyLine.ValidateResourceOptions(resource.ExportOptionsInMap())
+		_, errInValidateResourceOptions := common.ValidateResourceOptions(action.ExportType(), actionAssemblyLine, resource.ExportOptionsInMap())
 		if errInValidateResourceOptions != nil {
-			controller.FeedbackBadRequest(c, ERROR_FLAG_VALIDATE_RESOURCE_FAILED, "validate resource failed: "+errInValidateResourceOptions.Error())
+			controller.FeedbackValidateError(c, ERROR_FLAG_VALIDATE_RESOURCE_FAILED, "validate resource failed: ", errInValidateResourceOptions)
 			return
 		}
 	} else {
@@ -94,35 +91,16 @@ func (controller *Controller) RunPublicAction(c *gin.Context) {
 	}
 
 	// check action template
-	_, errInValidate := actionAssemblyLine.ValidateActionTemplate(action.ExportTemplateInMap())
+	_, errInValidate := common.ValidateActionTemplate(action.ExportType(), actionAssemblyLine, action.ExportTemplateInMap())
 	if errInValidate != nil {
-		controller.FeedbackBadRequest(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, "validate action template error: "+errInValidate.Error())
+		controller.FeedbackValidateError(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, "validate action template error: ", errInValidate)
 		return
 	}
 
 	// run
-	actionRunResult, errInRunAction := actionAssemblyLine.Run(resource.ExportOptionsInMap(), action.ExportTemplateInMap(), action.ExportRawTemplateInMap())
+	actionRunResult, errInRunAction := controller.runActionWithResultCache(c.Request.Context(), actionAssemblyLine, action, resource, false)
 	if errInRunAction != nil {
-		if strings.HasPrefix(errInRunAction.Error(), "Error 1064:") {
-			lineNumber, _ := strconv.Atoi(errInRunAction.Error()[len(errInRunAction.Error())-1:])
-			message := ""
-			regexp, _ := regexp.Compile(`to use`)
-			match := regexp.FindStringIndex(errInRunAction.Error())
-			if len(match) == 2 {
-				message = errInRunAction.Error()[match[1]:]
-			}
-			c.JSON(http.StatusBadRequest, gin.H{
-				"errorCode":    400,
-				"errorFlag":    ERROR_FLAG_EXECUTE_ACTION_FAILED,
-				"errorMessage": errors.New("SQL syntax error").Error(),
-				"errorData": map[string]interface{}{
-					"lineNumber": lineNumber,
-					"message":    "SQL syntax error" + message,
-				},
-			})
-			return
-		}
-		controller.FeedbackBadRequest(c, ERROR_FLAG_EXECUTE_ACTION_FAILED, "run action error: "+errInRunAction.Error())
+		controller.FeedbackRunActionError(c, ERROR_FLAG_EXECUTE_ACTION_FAILED, "run action error: ", errInRunAction, actionRunResult.Extra)
 		return
 	}
 
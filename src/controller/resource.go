@@ -6,11 +6,13 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
 	"github.com/illacloud/builder-backend/src/model"
 	"github.com/illacloud/builder-backend/src/request"
 	"github.com/illacloud/builder-backend/src/response"
 	"github.com/illacloud/builder-backend/src/utils/accesscontrol"
 	"github.com/illacloud/builder-backend/src/utils/auditlogger"
+	"github.com/illacloud/builder-backend/src/utils/resourcelist"
 )
 
 func (controller *Controller) GetAllResources(c *gin.Context) {
@@ -50,6 +52,75 @@ func (controller *Controller) GetAllResources(c *gin.Context) {
 	return
 }
 
+// GetResourceTypes feedback the resource types supported by this server and the capabilities of them
+func (controller *Controller) GetResourceTypes(c *gin.Context) {
+	// fetch needed param
+	teamID, errInGetTeamID := controller.GetMagicIntParamFromRequest(c, PARAM_TEAM_ID)
+	userAuthToken, errInGetAuthToken := controller.GetUserAuthTokenFromHeader(c)
+	if errInGetTeamID != nil || errInGetAuthToken != nil {
+		return
+	}
+
+	// validate
+	canAccess, errInCheckAttr := controller.AttributeGroup.CanAccess(
+		teamID,
+		userAuthToken,
+		accesscontrol.UNIT_TYPE_RESOURCE,
+		accesscontrol.DEFAULT_UNIT_ID,
+		accesscontrol.ACTION_ACCESS_VIEW,
+	)
+	if errInCheckAttr != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_ACCESS_DENIED, "error in check attribute: "+errInCheckAttr.Error())
+		return
+	}
+	if !canAccess {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_ACCESS_DENIED, "you can not access this attribute due to access control policy.")
+		return
+	}
+
+	// feedback
+	controller.FeedbackOK(c, response.NewGetResourceTypesResponse(common.RetrieveAllConnectorDescriptors()))
+}
+
+// GetResourceTypeSchema feedback the json schemas of resource options and action template for the resource type
+func (controller *Controller) GetResourceTypeSchema(c *gin.Context) {
+	// fetch needed param
+	teamID, errInGetTeamID := controller.GetMagicIntParamFromRequest(c, PARAM_TEAM_ID)
+	resourceType, errInGetResourceType := controller.GetStringParamFromRequest(c, PARAM_RESOURCE_TYPE)
+	userAuthToken, errInGetAuthToken := controller.GetUserAuthTokenFromHeader(c)
+	if errInGetTeamID != nil || errInGetResourceType != nil || errInGetAuthToken != nil {
+		return
+	}
+
+	// validate
+	canAccess, errInCheckAttr := controller.AttributeGroup.CanAccess(
+		teamID,
+		userAuthToken,
+		accesscontrol.UNIT_TYPE_RESOURCE,
+		accesscontrol.DEFAULT_UNIT_ID,
+		accesscontrol.ACTION_ACCESS_VIEW,
+	)
+	if errInCheckAttr != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_ACCESS_DENIED, "error in check attribute: "+errInCheckAttr.Error())
+		return
+	}
+	if !canAccess {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_ACCESS_DENIED, "you can not access this attribute due to access control policy.")
+		return
+	}
+
+	// retrieve connector
+	resourceTypeID := resourcelist.GetResourceNameMappedID(resourceType)
+	descriptor, hit := common.RetrieveConnectorDescriptor(resourceTypeID)
+	if resourcelist.GetResourceIDMappedType(resourceTypeID) != resourceType || !hit {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_VALIDATE_REQUEST_PARAM_FAILED, "unsupported resource type: "+resourceType)
+		return
+	}
+
+	// feedback
+	controller.FeedbackOK(c, response.NewGetResourceTypeSchemaResponse(resourceType, descriptor))
+}
+
 func (controller *Controller) CreateResource(c *gin.Context) {
 	// fetch needed param
 	teamID, errInGetTeamID := controller.GetMagicIntParamFromRequest(c, PARAM_TEAM_ID)
@@ -387,8 +458,9 @@ func (controller *Controller) GetMetaInfo(c *gin.Context) {
 		return
 	}
 
-	// fetch meta info
-	resourceMetaInfo, errInGetMetaInfo := controller.GetResourceMetaInfo(c, resource)
+	// fetch meta info, "?refresh=true" skips the cached meta info
+	refresh := c.Query(PARAM_REFRESH) == "true"
+	resourceMetaInfo, errInGetMetaInfo := controller.GetResourceMetaInfoWithCache(c, resource, refresh)
 	if errInGetMetaInfo != nil {
 		return
 	}
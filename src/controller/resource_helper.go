@@ -23,9 +23,9 @@ func (controller *Controller) ValidateResourceConternt(c *gin.Context, resource
 	}
 
 	// check template
-	_, errInValidate := resourceAssemblyLine.ValidateResourceOptions(resource.ExportOptionsInMap())
+	_, errInValidate := common.ValidateResourceOptions(resource.ExportType(), resourceAssemblyLine, resource.ExportOptionsInMap())
 	if errInValidate != nil {
-		controller.FeedbackBadRequest(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, "validate resource option error: "+errInValidate.Error())
+		controller.FeedbackValidateError(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, "validate resource option error: ", errInValidate)
 		return errInValidate
 	}
 	return nil
@@ -45,9 +45,9 @@ func (controller *Controller) TestResourceConnection(c *gin.Context, resource *m
 	}
 
 	// check template
-	_, errInValidate := resourceAssemblyLine.ValidateResourceOptions(resource.ExportOptionsInMap())
+	_, errInValidate := common.ValidateResourceOptions(resource.ExportType(), resourceAssemblyLine, resource.ExportOptionsInMap())
 	if errInValidate != nil {
-		controller.FeedbackBadRequest(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, "validate resource option error: "+errInValidate.Error())
+		controller.FeedbackValidateError(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, "validate resource option error: ", errInValidate)
 		return errInValidate
 	}
 
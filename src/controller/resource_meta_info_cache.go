@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/illacloud/builder-backend/src/model"
+	"github.com/illacloud/builder-backend/src/utils/config"
+)
+
+// GetResourceMetaInfoWithCache feedbacks the cached meta info when it is not expired and the resource not updated,
+// the refresh skips the cached one and fetches from resource again.
+// Only the connectors opted in the meta info cache are cached, the others always fetch from resource.
+func (controller *Controller) GetResourceMetaInfoWithCache(c *gin.Context, resource *model.Resource, refresh bool) (*common.MetaInfoResult, error) {
+	if controller.Cache == nil || !isMetaInfoCacheEnabled(resource.ExportType()) {
+		return controller.GetResourceMetaInfo(c, resource)
+	}
+	metaInfoCache := controller.Cache.ResourceMetaInfoCache
+	resourceID := resource.ExportID()
+	updatedAt := resource.ExportUpdatedAt()
+
+	if !refresh {
+		cachedMetaInfo, errInRetrieveCache := metaInfoCache.RetrieveMetaInfo(resourceID, updatedAt)
+		if errInRetrieveCache != nil {
+			log.Printf("[ERROR] retrieve resource meta info cache failed: %s\n", errInRetrieveCache.Error())
+		}
+		if cachedMetaInfo != nil {
+			resourceMetaInfo := &common.MetaInfoResult{}
+			if errInUnmarshal := json.Unmarshal(cachedMetaInfo, resourceMetaInfo); errInUnmarshal == nil {
+				return resourceMetaInfo, nil
+			}
+		}
+	}
+
+	// cache missed
+	resourceMetaInfo, errInGetMetaInfo := controller.GetResourceMetaInfo(c, resource)
+	if errInGetMetaInfo != nil || resourceMetaInfo == nil || !resourceMetaInfo.Success {
+		return resourceMetaInfo, errInGetMetaInfo
+	}
+	resourceMetaInfoInJSON, errInMarshal := json.Marshal(resourceMetaInfo)
+	if errInMarshal == nil {
+		if errInSetCache := metaInfoCache.SetMetaInfo(resourceID, updatedAt, resourceMetaInfoInJSON, config.GetInstance().GetResourceMetaInfoCacheTTL()); errInSetCache != nil {
+			log.Printf("[ERROR] set resource meta info cache failed: %s\n", errInSetCache.Error())
+		}
+	}
+	return resourceMetaInfo, nil
+}
+
+func isMetaInfoCacheEnabled(resourceType int) bool {
+	descriptor, hit := common.RetrieveConnectorDescriptor(resourceType)
+	return hit && descriptor.Capability.MetaInfoCache
+}
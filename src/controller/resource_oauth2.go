@@ -0,0 +1,201 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/illacloud/builder-backend/src/model"
+	"github.com/illacloud/builder-backend/src/request"
+	"github.com/illacloud/builder-backend/src/response"
+	"github.com/illacloud/builder-backend/src/utils/accesscontrol"
+	"github.com/illacloud/builder-backend/src/utils/config"
+	"github.com/illacloud/builder-backend/src/utils/oauthgeneric"
+)
+
+func (controller *Controller) CreateOAuth2AuthorizeURL(c *gin.Context) {
+	// fetch needed params
+	teamID, errInGetTeamID := controller.GetMagicIntParamFromRequest(c, PARAM_TEAM_ID)
+	resourceID, errInGetResourceID := controller.GetMagicIntParamFromRequest(c, PARAM_RESOURCE_ID)
+	userID, errInGetUserID := controller.GetUserIDFromAuth(c)
+	userAuthToken, errInGetAuthToken := controller.GetUserAuthTokenFromHeader(c)
+	if errInGetTeamID != nil || errInGetResourceID != nil || errInGetUserID != nil || errInGetAuthToken != nil {
+		return
+	}
+
+	// validate
+	canManage, errInCheckAttr := controller.AttributeGroup.CanManage(
+		teamID,
+		userAuthToken,
+		accesscontrol.UNIT_TYPE_RESOURCE,
+		resourceID,
+		accesscontrol.ACTION_MANAGE_EDIT_RESOURCE,
+	)
+	if errInCheckAttr != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_ACCESS_DENIED, "error in check attribute: "+errInCheckAttr.Error())
+		return
+	}
+	if !canManage {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_ACCESS_DENIED, "you can not access this attribute due to access control policy.")
+		return
+	}
+
+	// parse request body
+	createOAuth2AuthorizeURLRequest := request.NewCreateOAuth2AuthorizeURLRequest()
+	if err := json.NewDecoder(c.Request.Body).Decode(&createOAuth2AuthorizeURLRequest); err != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_PARSE_REQUEST_BODY_FAILED, "parse request body error: "+err.Error())
+		return
+	}
+
+	// validate request body fields
+	validate := validator.New()
+	if err := validate.Struct(createOAuth2AuthorizeURLRequest); err != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, "validate request body error: "+err.Error())
+		return
+	}
+
+	// get resource
+	resource, errInRetrieveResource := controller.Storage.ResourceStorage.RetrieveByTeamIDAndResourceID(teamID, resourceID)
+	if errInRetrieveResource != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_CAN_NOT_GET_RESOURCE, "get resources error: "+errInRetrieveResource.Error())
+		return
+	}
+
+	// validate redirect url, the exchange redirects user back to it
+	if errInValidateRedirectURL := validateOAuth2RedirectURL(c, createOAuth2AuthorizeURLRequest.ExportRedirectURL()); errInValidateRedirectURL != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, "validate redirect url error: "+errInValidateRedirectURL.Error())
+		return
+	}
+
+	// get oauth2 provider
+	provider, errInExportProvider := exportResourceOAuth2Provider(resource)
+	if errInExportProvider != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_CAN_NOT_AUTHORIZE_OAUTH2, errInExportProvider.Error())
+		return
+	}
+
+	// generate state, the code verifier is carried by state and used in exchange
+	codeVerifier := ""
+	if provider.UsePKCE {
+		var errInGenerateCodeVerifier error
+		codeVerifier, errInGenerateCodeVerifier = oauthgeneric.GenerateCodeVerifier()
+		if errInGenerateCodeVerifier != nil {
+			controller.FeedbackBadRequest(c, ERROR_FLAG_CAN_NOT_AUTHORIZE_OAUTH2, "generate code verifier error: "+errInGenerateCodeVerifier.Error())
+			return
+		}
+	}
+	state, errInGenerateState := model.GenerateOAuth2State(teamID, userID, resourceID, createOAuth2AuthorizeURLRequest.ExportRedirectURL(), codeVerifier)
+	if errInGenerateState != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_CAN_NOT_AUTHORIZE_OAUTH2, "generate state error: "+errInGenerateState.Error())
+		return
+	}
+
+	// feedback
+	controller.FeedbackOK(c, response.NewCreateOAuth2AuthorizeURLResponse(provider.ExportAuthorizeURL(state, codeVerifier)))
+	return
+}
+
+func (controller *Controller) RefreshOAuth2Token(c *gin.Context) {
+	// fetch needed params
+	teamID, errInGetTeamID := controller.GetMagicIntParamFromRequest(c, PARAM_TEAM_ID)
+	resourceID, errInGetResourceID := controller.GetMagicIntParamFromRequest(c, PARAM_RESOURCE_ID)
+	userID, errInGetUserID := controller.GetUserIDFromAuth(c)
+	userAuthToken, errInGetAuthToken := controller.GetUserAuthTokenFromHeader(c)
+	if errInGetTeamID != nil || errInGetResourceID != nil || errInGetUserID != nil || errInGetAuthToken != nil {
+		return
+	}
+
+	// validate
+	canManage, errInCheckAttr := controller.AttributeGroup.CanManage(
+		teamID,
+		userAuthToken,
+		accesscontrol.UNIT_TYPE_RESOURCE,
+		resourceID,
+		accesscontrol.ACTION_MANAGE_EDIT_RESOURCE,
+	)
+	if errInCheckAttr != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_ACCESS_DENIED, "error in check attribute: "+errInCheckAttr.Error())
+		return
+	}
+	if !canManage {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_ACCESS_DENIED, "you can not access this attribute due to access control policy.")
+		return
+	}
+
+	// refresh access token
+	resource, errInRefresh := controller.refreshResourceOAuth2Token(teamID, resourceID, userID)
+	if errInRefresh != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_CAN_NOT_REFRESH_OAUTH2, "refresh oauth2 token error: "+errInRefresh.Error())
+		return
+	}
+
+	// feedback
+	controller.FeedbackOK(c, response.NewUpdateResourceResponse(resource))
+	return
+}
+
+func exportResourceOAuth2Provider(resource *model.Resource) (*oauthgeneric.Provider, error) {
+	resourceOAuth2Option, errInExportOption := model.NewResourceOAuth2OptionByResource(resource)
+	if errInExportOption != nil {
+		return nil, errInExportOption
+	}
+	return resourceOAuth2Option.ExportProvider()
+}
+
+func validateOAuth2RedirectURL(c *gin.Context, redirectURL string) error {
+	return oauthgeneric.ValidateRedirectURL(redirectURL, config.GetInstance().GetFrontendOrigins(), c.Request.Host)
+}
+
+// refreshResourceOAuth2Token refreshes the token by request, the refresh is rejected when other instance is refreshing.
+func (controller *Controller) refreshResourceOAuth2Token(teamID int, resourceID int, userID int) (*model.Resource, error) {
+	return controller.refreshLockedResourceOAuth2Token(teamID, resourceID, userID, func(model.ResourceOAuth2Option) bool {
+		return true
+	})
+}
+
+// refreshLockedResourceOAuth2Token reads the resource after locked, so the token rotated by other instance is used for refreshing.
+// Only the token fields are written back, the options edited by user in the meantime are kept.
+func (controller *Controller) refreshLockedResourceOAuth2Token(teamID int, resourceID int, userID int, needRefresh func(model.ResourceOAuth2Option) bool) (*model.Resource, error) {
+	if controller.Cache != nil {
+		locked, errInLock := controller.Cache.ResourceOAuth2Cache.TryLockRefresh(resourceID)
+		if errInLock != nil {
+			return nil, errInLock
+		}
+		if !locked {
+			return nil, errors.New("the oauth2 token is refreshing")
+		}
+		defer controller.Cache.ResourceOAuth2Cache.UnlockRefresh(resourceID)
+	}
+
+	resource, errInRetrieveResource := controller.Storage.ResourceStorage.RetrieveByTeamIDAndResourceID(teamID, resourceID)
+	if errInRetrieveResource != nil {
+		return nil, errInRetrieveResource
+	}
+	resourceOAuth2Option, errInExportOption := model.NewResourceOAuth2OptionByResource(resource)
+	if errInExportOption != nil {
+		return nil, errInExportOption
+	}
+	if !needRefresh(resourceOAuth2Option) {
+		return resource, nil
+	}
+	provider, errInExportProvider := resourceOAuth2Option.ExportProvider()
+	if errInExportProvider != nil {
+		return nil, errInExportProvider
+	}
+	refreshToken, errInExportRefreshToken := resourceOAuth2Option.ExportRefreshToken()
+	if errInExportRefreshToken != nil {
+		return nil, errInExportRefreshToken
+	}
+	token, errInRefreshToken := provider.RefreshOAuthToken(refreshToken)
+	if errInRefreshToken != nil {
+		return nil, errInRefreshToken
+	}
+	if errInUpdateToken := resourceOAuth2Option.UpdateByToken(token); errInUpdateToken != nil {
+		return nil, errInUpdateToken
+	}
+	if errInUpdate := controller.Storage.ResourceStorage.UpdateOptionsContent(teamID, resourceID, resourceOAuth2Option.ExportTokenContentKey(), resourceOAuth2Option.ExportTokenContent(), userID); errInUpdate != nil {
+		return nil, errInUpdate
+	}
+	return controller.Storage.ResourceStorage.RetrieveByTeamIDAndResourceID(teamID, resourceID)
+}
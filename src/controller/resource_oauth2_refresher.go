@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"log"
+	"time"
+
+	"github.com/illacloud/builder-backend/src/model"
+)
+
+// StartOAuth2TokenRefresher refreshes the oauth2 tokens of resources which will expire in beforeExpiry period.
+// Only one instance scans in each interval, and only the expiring tokens are retrieved.
+func (controller *Controller) StartOAuth2TokenRefresher(interval time.Duration, beforeExpiry time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if controller.Cache != nil {
+				locked, errInLock := controller.Cache.ResourceOAuth2Cache.TryLockRefresher(interval)
+				if errInLock != nil {
+					log.Printf("[OAUTH2] lock oauth2 token refresher failed: %s\n", errInLock.Error())
+					continue
+				}
+				if !locked {
+					continue
+				}
+			}
+			controller.refreshExpiringOAuth2Tokens(beforeExpiry)
+		}
+	}()
+}
+
+func (controller *Controller) refreshExpiringOAuth2Tokens(beforeExpiry time.Duration) {
+	resources, errInRetrieveResources := controller.Storage.ResourceStorage.RetrieveOAuth2ResourcesExpiringBefore(time.Now().Add(beforeExpiry))
+	if errInRetrieveResources != nil {
+		log.Printf("[OAUTH2] retrieve expiring oauth2 resources failed: %s\n", errInRetrieveResources.Error())
+		return
+	}
+	for _, resource := range resources {
+		// the token may be refreshed by request after retrieved, so check it again after locked
+		_, errInRefresh := controller.refreshLockedResourceOAuth2Token(resource.TeamID, resource.ExportID(), model.SYSTEM_USER_ID, func(resourceOAuth2Option model.ResourceOAuth2Option) bool {
+			return resourceOAuth2Option.NeedRefresh(beforeExpiry)
+		})
+		if errInRefresh != nil {
+			log.Printf("[OAUTH2] refresh token of resource %d failed: %s\n", resource.ExportID(), errInRefresh.Error())
+		}
+	}
+}
@@ -2,7 +2,6 @@ package controller
 
 import (
 	"encoding/json"
-	"fmt"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -10,7 +9,6 @@ import (
 	"github.com/illacloud/builder-backend/src/request"
 	"github.com/illacloud/builder-backend/src/response"
 	"github.com/illacloud/builder-backend/src/utils/accesscontrol"
-	"github.com/illacloud/builder-backend/src/utils/oauthgoogle"
 )
 
 func (controller *Controller) CreateGoogleOAuthToken(c *gin.Context) {
@@ -80,15 +78,26 @@ func (controller *Controller) CreateGoogleOAuthToken(c *gin.Context) {
 		return
 	}
 
-	// generate access token
-	token, err := model.GenerateGoogleSheetsOAuth2Token(teamID, userID, resourceID, createOAuthTokenRequest)
+	// validate redirect url, the exchange redirects user back to it
+	if errInValidateRedirectURL := validateOAuth2RedirectURL(c, createOAuthTokenRequest.ExportRedirectURL()); errInValidateRedirectURL != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_VALIDATE_REQUEST_BODY_FAILED, "validate redirect url error: "+errInValidateRedirectURL.Error())
+		return
+	}
+
+	// generate access token, it is the state of generic oauth2 authorization
+	accessType := model.GOOGLE_SHEETS_OAUTH2_ACCESS_TYPE_READ_ONLY
+	if createOAuthTokenRequest.IsReadAndWrite() {
+		accessType = model.GOOGLE_SHEETS_OAUTH2_ACCESS_TYPE_READ_AND_WRITE
+	}
+	token, err := model.GenerateGoogleSheetsOAuth2State(teamID, userID, resourceID, accessType, createOAuthTokenRequest.ExportRedirectURL())
 	if err != nil {
 		controller.FeedbackBadRequest(c, ERROR_FLAG_CAN_NOT_CREATE_TOKEN, "generate token error: "+err.Error())
 		return
 	}
+	provider := model.NewGoogleSheetsOAuth2Provider(createOAuthTokenRequest.IsReadAndWrite())
 
 	// feedback
-	controller.FeedbackOK(c, response.NewCreateOAuthTokenResponse(token))
+	controller.FeedbackOK(c, response.NewCreateOAuthTokenResponse(token, provider.ExportAuthorizeURL(token, "")))
 	return
 }
 
@@ -191,48 +200,21 @@ func (controller *Controller) RefreshGoogleSheetsOAuth(c *gin.Context) {
 		controller.FeedbackBadRequest(c, ERROR_FLAG_CAN_NOT_GET_RESOURCE, "get resources error: "+errInRetrieveResource.Error())
 		return
 	}
-	fmt.Printf("[DUMP] RefreshGoogleSheetsOAuth.resource: %+v\n", resource)
 
-	// check resource type for create OAuth token
+	// check resource type for refresh OAuth token
 	if !resource.CanCreateOAuthToken() {
 		controller.FeedbackBadRequest(c, ERROR_FLAG_CAN_NOT_REFRESH_TOKEN, "unsupported resource type")
 		return
 	}
 
-	// new resource option
-	resourceOptionGoogleSheets, errInNewGoogleSheetResourceOption := model.NewResourceOptionGoogleSheetsByResource(resource)
-	if errInNewGoogleSheetResourceOption != nil {
-		controller.FeedbackBadRequest(c, ERROR_FLAG_CAN_NOT_REFRESH_TOKEN, "unsupported resource type: "+errInNewGoogleSheetResourceOption.Error())
-		return
-	}
-
-	// validate resource option
-	if !resourceOptionGoogleSheets.IsAvaliableAuthenticationMethod() {
-		controller.FeedbackBadRequest(c, ERROR_FLAG_CAN_NOT_REFRESH_TOKEN, "unsupported authentication type")
-		return
-	}
-
-	fmt.Printf("[DUMP] RefreshGoogleSheetsOAuth.resourceOptionGoogleSheets: %+v\n", resourceOptionGoogleSheets)
-
 	// refresh access token
-	refreshTokenResponse, errInRefreshOAuthToken := oauthgoogle.RefreshOAuthToken(resourceOptionGoogleSheets.ExportRefreshToken())
-	if errInRefreshOAuthToken != nil {
-		controller.FeedbackBadRequest(c, ERROR_FLAG_CAN_NOT_REFRESH_GOOGLE_SHEETS, "fresh google sheets oauth token error: "+errInRefreshOAuthToken.Error())
-		return
-	}
-	fmt.Printf("[DUMP] RefreshGoogleSheetsOAuth.refreshTokenResponse: %+v\n", refreshTokenResponse)
-
-	resourceOptionGoogleSheets.SetAccessToken(refreshTokenResponse.ExportAccessToken())
-	resource.UpdateGoogleSheetOAuth2Options(userID, resourceOptionGoogleSheets)
-
-	// update resource
-	errInUpdateResource := controller.Storage.ResourceStorage.UpdateWholeResource(resource)
-	if errInUpdateResource != nil {
-		controller.FeedbackBadRequest(c, ERROR_FLAG_CAN_NOT_UPDATE_RESOURCE, "update resources error: "+errInUpdateResource.Error())
+	refreshedResource, errInRefresh := controller.refreshResourceOAuth2Token(teamID, resourceID, userID)
+	if errInRefresh != nil {
+		controller.FeedbackBadRequest(c, ERROR_FLAG_CAN_NOT_REFRESH_GOOGLE_SHEETS, "fresh google sheets oauth token error: "+errInRefresh.Error())
 		return
 	}
 
 	// feedback
-	controller.FeedbackOK(c, response.NewUpdateResourceResponse(resource))
+	controller.FeedbackOK(c, response.NewUpdateResourceResponse(refreshedResource))
 	return
 }
@@ -7,8 +7,10 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
 	"github.com/illacloud/builder-backend/src/response"
 	"github.com/illacloud/builder-backend/src/utils/idconvertor"
+	"github.com/illacloud/builder-backend/src/utils/jsonschema"
 )
 
 const (
@@ -51,6 +53,11 @@ const (
 	PARAM_FROM_VERSION     = "fromVersion"
 	PARAM_TO_VERSION       = "toVersion"
 	PARAM_IS_FORK_WORKFLOW = "isForkWorkflow"
+	PARAM_RESULT_FORMAT    = "resultFormat"
+	PARAM_RESOURCE_TYPE    = "resourceType"
+	PARAM_REFRESH          = "refresh"
+	PARAM_PAGE_SIZE        = "pageSize"
+	PARAM_CONTINUATION     = "continuationToken"
 )
 
 const (
@@ -149,6 +156,7 @@ const (
 	ERROR_FLAG_CREATE_UPLOAD_URL_FAILED      = "ERROR_FLAG_CREATE_UPLOAD_URL_FAILED"
 	ERROR_FLAG_EXECUTE_ACTION_FAILED         = "ERROR_FLAG_EXECUTE_ACTION_FAILED"
 	ERROR_FLAG_GENERATE_SQL_FAILED           = "ERROR_FLAG_GENERATE_SQL_FAILED"
+	ERROR_FLAG_CAN_NOT_GET_ACTION_RESULT     = "ERROR_FLAG_CAN_NOT_GET_ACTION_RESULT"
 
 	// internal failed
 	ERROR_FLAG_BUILD_TEAM_MEMBER_LIST_FAILED = "ERROR_FLAG_BUILD_TEAM_MEMBER_LIST_FAILED"
@@ -167,6 +175,10 @@ const (
 	ERROR_FLAG_CAN_NOT_GET_TOKEN                  = "ERROR_FLAG_CAN_NOT_GET_TOKEN"
 	ERROR_FLAG_CAN_NOT_REFRESH_TOKEN              = "ERROR_FLAG_CAN_NOT_REFRESH_TOKEN"
 
+	// generic oauth2 failed
+	ERROR_FLAG_CAN_NOT_AUTHORIZE_OAUTH2 = "ERROR_FLAG_CAN_NOT_AUTHORIZE_OAUTH2"
+	ERROR_FLAG_CAN_NOT_REFRESH_OAUTH2   = "ERROR_FLAG_CAN_NOT_REFRESH_OAUTH2"
+
 	// flow action
 	ERROR_FLAG_CAN_NOT_GET_FLOW_ACTION      = "ERROR_FLAG_CAN_NOT_GET_FLOW_ACTION"
 	ERROR_FLAG_CAN_NOT_CREATE_FLOW_ACTION   = "ERROR_FLAG_CAN_NOT_CREATE_FLOW_ACTION"
@@ -418,3 +430,39 @@ func (controller *Controller) FeedbackInternalServerError(c *gin.Context, errorF
 	})
 	return
 }
+
+// FeedbackRunActionError feedback the structured connector error in "errorData", the other errors feedback as normal bad request.
+// The result extra (like retry attempts) of the failed run feedback in "extra".
+func (controller *Controller) FeedbackRunActionError(c *gin.Context, errorFlag string, errorMessagePrefix string, errInRunAction error, extra map[string]interface{}) {
+	c.JSON(http.StatusBadRequest, newRunActionErrorFeedback(errorFlag, errorMessagePrefix+errInRunAction.Error(), errInRunAction, extra))
+}
+
+// FeedbackValidateError feedback the failed fields in "errorData" when the options violate the connector json schema.
+func (controller *Controller) FeedbackValidateError(c *gin.Context, errorFlag string, errorMessagePrefix string, errInValidate error) {
+	feedback := gin.H{
+		"errorCode":    400,
+		"errorFlag":    errorFlag,
+		"errorMessage": errorMessagePrefix + errInValidate.Error(),
+	}
+	var validationError *jsonschema.ValidationError
+	if errors.As(errInValidate, &validationError) {
+		feedback["errorData"] = validationError
+	}
+	c.JSON(http.StatusBadRequest, feedback)
+}
+
+func newRunActionErrorFeedback(errorFlag string, errorMessage string, errInRunAction error, extra map[string]interface{}) gin.H {
+	feedback := gin.H{
+		"errorCode":    400,
+		"errorFlag":    errorFlag,
+		"errorMessage": errorMessage,
+	}
+	if connectorError, isConnectorError := common.AsConnectorError(errInRunAction); isConnectorError {
+		feedback["errorMessage"] = connectorError.ExportFeedbackMessage(errorMessage)
+		feedback["errorData"] = connectorError
+	}
+	if len(extra) > 0 {
+		feedback["extra"] = extra
+	}
+	return feedback
+}
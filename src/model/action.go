@@ -163,6 +163,10 @@ func (action *Action) ExportID() int {
 	return action.ID
 }
 
+func (action *Action) ExportTeamID() int {
+	return action.TeamID
+}
+
 func (action *Action) ExportType() int {
 	return action.Type
 }
@@ -177,6 +181,14 @@ func (action *Action) ExportConfig() *ActionConfig {
 	return ac
 }
 
+func (action *Action) ExportRetryPolicy() *RetryPolicy {
+	ac := action.ExportConfig()
+	if ac.AdvancedConfig == nil {
+		return nil
+	}
+	return ac.AdvancedConfig.RetryPolicy
+}
+
 func (action *Action) ExportDisplayName() string {
 	return action.Name
 }
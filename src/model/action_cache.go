@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+)
+
+type CacheConfig struct {
+	Enabled bool `json:"enabled"`
+	TTL     int  `json:"ttl"` // in seconds, use server default ttl when it is 0
+}
+
+func (action *Action) IsCacheEnabled() bool {
+	ac := action.ExportConfig()
+	return ac.CacheConfig != nil && ac.CacheConfig.Enabled
+}
+
+func (action *Action) ExportCacheTTL(defaultTTL time.Duration) time.Duration {
+	ac := action.ExportConfig()
+	if ac.CacheConfig == nil || ac.CacheConfig.TTL <= 0 {
+		return defaultTTL
+	}
+	return time.Duration(ac.CacheConfig.TTL) * time.Second
+}
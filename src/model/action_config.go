@@ -29,17 +29,19 @@ type ActionConfig struct {
 	IsVirtualResource bool            `json:"isVirtualResource"`
 	AdvancedConfig    *AdvancedConfig `json:"advancedConfig"` // 2023_4_20: add advanced config for action
 	MockConfig        *MockConfig     `json:"mockConfig"`
+	CacheConfig       *CacheConfig    `json:"cacheConfig"`
 	TutorialLink      string          `json:"tutorialLink"`
 }
 
 type AdvancedConfig struct {
-	Runtime            string   `json:"runtime"`
-	Pages              []string `json:"pages"`
-	DelayWhenLoaded    string   `json:"delayWhenLoaded"`
-	DisplayLoadingPage bool     `json:"displayLoadingPage"`
-	IsPeriodically     bool     `json:"isPeriodically"`
-	PeriodInterval     string   `json:"periodInterval"`
-	Mock               string   `json:"mock"`
+	Runtime            string       `json:"runtime"`
+	Pages              []string     `json:"pages"`
+	DelayWhenLoaded    string       `json:"delayWhenLoaded"`
+	DisplayLoadingPage bool         `json:"displayLoadingPage"`
+	IsPeriodically     bool         `json:"isPeriodically"`
+	PeriodInterval     string       `json:"periodInterval"`
+	Mock               string       `json:"mock"`
+	RetryPolicy        *RetryPolicy `json:"retryPolicy"`
 }
 
 func NewActionConfig() *ActionConfig {
@@ -58,6 +60,10 @@ func NewActionConfig() *ActionConfig {
 			MockData:             "",
 			EnableForReleasedApp: false,
 		},
+		CacheConfig: &CacheConfig{
+			Enabled: false,
+			TTL:     0,
+		},
 	}
 }
 
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	ACTION_RESUME_TOKEN_KIND_ACTION      = 1
+	ACTION_RESUME_TOKEN_KIND_FLOW_ACTION = 2
+)
+
+// ActionResumeToken persists the position of resumable actions (like the change stream watch) between runs,
+// so the next run continues from where the last run stopped.
+type ActionResumeToken struct {
+	ID          int       `json:"id"          gorm:"column:id;type:bigserial;primary_key"`
+	TeamID      int       `json:"teamID"      gorm:"column:team_id;type:bigint"`
+	ActionKind  int       `json:"actionKind"  gorm:"column:action_kind;type:smallint"`
+	ActionID    int       `json:"actionID"    gorm:"column:action_id;type:bigint"`
+	ResumeToken string    `json:"resumeToken" gorm:"column:resume_token;type:text"`
+	CreatedAt   time.Time `json:"createdAt"   gorm:"column:created_at;type:timestamp"`
+	UpdatedAt   time.Time `json:"updatedAt"   gorm:"column:updated_at;type:timestamp"`
+}
+
+func NewActionResumeToken(teamID int, actionKind int, actionID int, resumeToken string) *ActionResumeToken {
+	now := time.Now().UTC()
+	return &ActionResumeToken{
+		TeamID:      teamID,
+		ActionKind:  actionKind,
+		ActionID:    actionID,
+		ResumeToken: resumeToken,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+func (token *ActionResumeToken) ExportResumeToken() string {
+	return token.ResumeToken
+}
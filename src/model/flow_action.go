@@ -144,6 +144,10 @@ func (action *FlowAction) ExportID() int {
 	return action.ID
 }
 
+func (action *FlowAction) ExportTeamID() int {
+	return action.TeamID
+}
+
 func (action *FlowAction) ExportType() int {
 	return action.Type
 }
@@ -158,6 +162,14 @@ func (action *FlowAction) ExportConfig() *FlowActionConfig {
 	return ac
 }
 
+func (action *FlowAction) ExportRetryPolicy() *RetryPolicy {
+	ac := action.ExportConfig()
+	if ac.FlowAdvancedConfig == nil {
+		return nil
+	}
+	return ac.FlowAdvancedConfig.RetryPolicy
+}
+
 func (action *FlowAction) ExportDisplayName() string {
 	return action.Name
 }
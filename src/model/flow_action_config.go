@@ -25,13 +25,14 @@ type FlowActionConfig struct {
 }
 
 type FlowAdvancedConfig struct {
-	Runtime            string   `json:"runtime"`
-	Pages              []string `json:"pages"`
-	DelayWhenLoaded    string   `json:"delayWhenLoaded"`
-	DisplayLoadingPage bool     `json:"displayLoadingPage"`
-	IsPeriodically     bool     `json:"isPeriodically"`
-	PeriodInterval     string   `json:"periodInterval"`
-	Mock               string   `json:"mock"`
+	Runtime            string       `json:"runtime"`
+	Pages              []string     `json:"pages"`
+	DelayWhenLoaded    string       `json:"delayWhenLoaded"`
+	DisplayLoadingPage bool         `json:"displayLoadingPage"`
+	IsPeriodically     bool         `json:"isPeriodically"`
+	PeriodInterval     string       `json:"periodInterval"`
+	Mock               string       `json:"mock"`
+	RetryPolicy        *RetryPolicy `json:"retryPolicy"`
 }
 
 func NewFlowActionConfig() *FlowActionConfig {
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/illacloud/builder-backend/src/utils/config"
+	"github.com/illacloud/builder-backend/src/utils/oauthgeneric"
+)
+
+// OAuth2Claims is the state of generic oauth2 authorization, the PKCE code verifier is encrypted since the state passed through browser.
+type OAuth2Claims struct {
+	Team     int    `json:"team"`
+	User     int    `json:"user"`
+	Resource int    `json:"resource"`
+	URL      string `json:"url"`
+	Verifier string `json:"verifier"`
+	Access   int    `json:"access,omitempty"`
+	jwt.RegisteredClaims
+}
+
+const (
+	OAUTH2_STATUS_SUCCESS = 1
+	OAUTH2_STATUS_FAILED  = 2
+)
+
+const (
+	OAUTH2_STATE_DEFAULT_EXIPRED_PERIOD = time.Minute * 10
+)
+
+func NewOAuth2Claims() *OAuth2Claims {
+	return &OAuth2Claims{}
+}
+
+func GenerateOAuth2State(teamID int, userID int, resourceID int, redirectURL string, codeVerifier string) (string, error) {
+	encryptedCodeVerifier, errInEncrypt := oauthgeneric.EncryptToken(codeVerifier)
+	if errInEncrypt != nil {
+		return "", errInEncrypt
+	}
+	return signOAuth2State(&OAuth2Claims{
+		Team:     teamID,
+		User:     userID,
+		Resource: resourceID,
+		URL:      redirectURL,
+		Verifier: encryptedCodeVerifier,
+	})
+}
+
+// GenerateGoogleSheetsOAuth2State carries the access type, the state is also used for validating by GoogleSheetsOAuth2Claims.
+func GenerateGoogleSheetsOAuth2State(teamID int, userID int, resourceID int, accessType int, redirectURL string) (string, error) {
+	return signOAuth2State(&OAuth2Claims{
+		Team:     teamID,
+		User:     userID,
+		Resource: resourceID,
+		URL:      redirectURL,
+		Access:   accessType,
+	})
+}
+
+func signOAuth2State(claims *OAuth2Claims) (string, error) {
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		Issuer: "ILLA",
+		ExpiresAt: &jwt.NumericDate{
+			Time: time.Now().Add(OAUTH2_STATE_DEFAULT_EXIPRED_PERIOD),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	conf := config.GetInstance()
+	return token.SignedString([]byte(conf.GetSecretKey()))
+}
+
+func (i *OAuth2Claims) ExtractOAuth2StateInfo(state string) (teamID, userID, resourceID int, url string, codeVerifier string, err error) {
+	token, errInParseClaims := jwt.ParseWithClaims(state, i, func(token *jwt.Token) (interface{}, error) {
+		conf := config.GetInstance()
+		return []byte(conf.GetSecretKey()), nil
+	})
+	if errInParseClaims != nil {
+		return 0, 0, 0, "", "", errInParseClaims
+	}
+
+	claims, assertPass := token.Claims.(*OAuth2Claims)
+	if !(assertPass && token.Valid) {
+		return 0, 0, 0, "", "", errors.New("invalied oauth2 state")
+	}
+
+	codeVerifier, errInDecrypt := oauthgeneric.DecryptToken(claims.Verifier)
+	if errInDecrypt != nil {
+		return 0, 0, 0, "", "", errInDecrypt
+	}
+	return claims.Team, claims.User, claims.Resource, claims.URL, codeVerifier, nil
+}
@@ -64,12 +64,6 @@ func (resource *Resource) UpdateByUpdateResourceRequest(userID int, req *request
 	resource.InitUpdatedAt()
 }
 
-func (resource *Resource) UpdateGoogleSheetOAuth2Options(userID int, options *ResourceOptionGoogleSheets) {
-	resource.Options = options.ExportInString()
-	resource.UpdatedBy = userID
-	resource.InitUpdatedAt()
-}
-
 func (resource *Resource) CleanID() {
 	resource.ID = 0
 }
@@ -86,6 +80,10 @@ func (resource *Resource) InitUpdatedAt() {
 	resource.UpdatedAt = time.Now().UTC()
 }
 
+func (resource *Resource) ExportID() int {
+	return resource.ID
+}
+
 func (resource *Resource) ExportUpdatedAt() time.Time {
 	return resource.UpdatedAt
 }
@@ -107,3 +105,7 @@ func (resource *Resource) ExportOptionsInMap() map[string]interface{} {
 func (resource *Resource) CanCreateOAuthToken() bool {
 	return resourcelist.CanCreateOAuthToken(resource.Type)
 }
+
+func (resource *Resource) CanUseGenericOAuth2() bool {
+	return resourcelist.CanUseGenericOAuth2(resource.Type)
+}
@@ -1,17 +1,30 @@
 package model
 
 import (
-	"encoding/json"
-	"fmt"
+	"time"
 
-	"github.com/illacloud/builder-backend/src/utils/oauthgoogle"
+	"github.com/illacloud/builder-backend/src/utils/config"
+	"github.com/illacloud/builder-backend/src/utils/oauthgeneric"
 	"github.com/mitchellh/mapstructure"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
 )
 
 const (
 	GOOGLE_SHEET_OAUTH_TYPE = "oauth2"
 )
 
+const (
+	GOOGLE_SHEETS_OPTIONS_KEY                = "opts"
+	GOOGLE_SHEETS_ACCESS_TYPE_READ_AND_WRITE = "rw"
+	GOOGLE_SHEETS_ACCESS_TYPE_READ_ONLY      = "r"
+)
+
+var (
+	googleSheetsReadAndWriteScopes = []string{"https://www.googleapis.com/auth/spreadsheets", "https://www.googleapis.com/auth/drive"}
+	googleSheetsReadOnlyScopes     = []string{"https://www.googleapis.com/auth/spreadsheets.readonly", "https://www.googleapis.com/auth/drive.readonly"}
+)
+
 type GoogleSheetsOAuth2Options struct {
 	AccessType   string `json:"accessType"`
 	AccessToken  string `json:"accessToken"`
@@ -19,6 +32,7 @@ type GoogleSheetsOAuth2Options struct {
 	RefreshToken string `json:"refreshToken"`
 	Status       int    `json:"status"`
 	ExpiresIn    int    `json:"expiresIn"`
+	ExpiresAt    string `json:"expiresAt"`
 	Scope        string `json:"scope"`
 }
 
@@ -28,7 +42,6 @@ type ResourceOptionGoogleSheets struct {
 }
 
 func NewResourceOptionGoogleSheetsByResource(resource *Resource) (*ResourceOptionGoogleSheets, error) {
-	fmt.Printf("[DUMP] NewResourceOptionGoogleSheetsByResource().resource.Options: %+v\n", resource.Options)
 	resourceOptionGoogleSheets := &ResourceOptionGoogleSheets{}
 	resourceOptions := resource.ExportOptionsInMap()
 	errInDecode := mapstructure.Decode(resourceOptions, &resourceOptionGoogleSheets)
@@ -36,13 +49,11 @@ func NewResourceOptionGoogleSheetsByResource(resource *Resource) (*ResourceOptio
 		return nil, errInDecode
 	}
 	opts := &GoogleSheetsOAuth2Options{}
-	errInDecodeSub := mapstructure.Decode(resourceOptions["opts"], &opts)
+	errInDecodeSub := mapstructure.Decode(resourceOptions[GOOGLE_SHEETS_OPTIONS_KEY], &opts)
 	if errInDecodeSub != nil {
 		return nil, errInDecodeSub
 	}
 	resourceOptionGoogleSheets.Options = opts
-	jstr, _ := json.Marshal(resourceOptionGoogleSheets)
-	fmt.Printf("[DUMP] NewResourceOptionGoogleSheetsByResource().resourceOptionGoogleSheets: %+v\n", string(jstr))
 	return resourceOptionGoogleSheets, nil
 }
 
@@ -50,23 +61,80 @@ func (i *ResourceOptionGoogleSheets) IsAvaliableAuthenticationMethod() bool {
 	return i.Authentication == GOOGLE_SHEET_OAUTH_TYPE
 }
 
-func (i *ResourceOptionGoogleSheets) SetAccessToken(accessToken string) {
-	i.Options.AccessToken = accessToken
+// ExportProvider returns the google provider configured by illa, the offline access is required for getting refresh token.
+func (i *ResourceOptionGoogleSheets) ExportProvider() (*oauthgeneric.Provider, error) {
+	return NewGoogleSheetsOAuth2Provider(i.Options.AccessType == GOOGLE_SHEETS_ACCESS_TYPE_READ_AND_WRITE), nil
 }
 
-func (i *ResourceOptionGoogleSheets) ExportRefreshToken() string {
-	return i.Options.RefreshToken
+func NewGoogleSheetsOAuth2Provider(readAndWrite bool) *oauthgeneric.Provider {
+	conf := config.GetInstance()
+	scopes := googleSheetsReadOnlyScopes
+	if readAndWrite {
+		scopes = googleSheetsReadAndWriteScopes
+	}
+	return &oauthgeneric.Provider{
+		AuthURL:        google.Endpoint.AuthURL,
+		AccessTokenURL: google.Endpoint.TokenURL,
+		ClientID:       conf.GetIllaGoogleSheetsClientID(),
+		ClientSecret:   conf.GetIllaGoogleSheetsClientSecret(),
+		Scopes:         scopes,
+		RedirectURL:    conf.GetIllaGoogleSheetsRedirectURI(),
+		AuthURLParams: map[string]string{
+			"access_type": "offline",
+			"prompt":      "consent",
+		},
+	}
 }
 
-func (i *ResourceOptionGoogleSheets) UpdateByExchangeTokenResponse(repo *oauthgoogle.ExchangeTokenResponse) {
-	i.Options.AccessToken = repo.AccessToken
-	i.Options.RefreshToken = repo.RefreshToken
-	i.Options.ExpiresIn = repo.Expiry
-	i.Options.Scope = repo.Scope
-	i.Options.TokenType = repo.TokenType
+func (i *ResourceOptionGoogleSheets) ExportRefreshToken() (string, error) {
+	return oauthgeneric.DecryptToken(i.Options.RefreshToken)
+}
+
+// NeedRefresh reports whether the token expires in the given period, the tokens authorized before expiry recorded are only refreshed by request.
+func (i *ResourceOptionGoogleSheets) NeedRefresh(beforeExpiry time.Duration) bool {
+	expiresAt, errInParse := time.Parse(time.RFC3339, i.Options.ExpiresAt)
+	if errInParse != nil || i.Options.RefreshToken == "" {
+		return false
+	}
+	return time.Now().Add(beforeExpiry).After(expiresAt)
 }
 
-func (i *ResourceOptionGoogleSheets) ExportInString() string {
-	byteData, _ := json.Marshal(i)
-	return string(byteData)
+// UpdateByToken stores the encrypted token, the refresh token is kept since google does not return it when refreshing.
+// The legacy plaintext refresh token is encrypted when kept.
+func (i *ResourceOptionGoogleSheets) UpdateByToken(token *oauth2.Token) error {
+	refreshToken, errInEncrypt := oauthgeneric.EncryptToken(i.Options.RefreshToken)
+	if errInEncrypt != nil {
+		return errInEncrypt
+	}
+	authContent := map[string]string{oauthgeneric.AUTH_CONTENT_REFRESH_TOKEN: refreshToken}
+	if errInStore := oauthgeneric.StoreToken(authContent, token); errInStore != nil {
+		return errInStore
+	}
+	i.Options.AccessToken = authContent[oauthgeneric.AUTH_CONTENT_ACCESS_TOKEN]
+	i.Options.RefreshToken = authContent[oauthgeneric.AUTH_CONTENT_REFRESH_TOKEN]
+	i.Options.TokenType = authContent[oauthgeneric.AUTH_CONTENT_TOKEN_TYPE]
+	i.Options.ExpiresAt = authContent[oauthgeneric.AUTH_CONTENT_EXPIRES_AT]
+	i.Options.ExpiresIn = 0
+	if !token.Expiry.IsZero() {
+		i.Options.ExpiresIn = int(time.Until(token.Expiry).Seconds())
+	}
+	if scope, ok := token.Extra("scope").(string); ok {
+		i.Options.Scope = scope
+	}
+	return nil
+}
+
+func (i *ResourceOptionGoogleSheets) ExportTokenContentKey() string {
+	return GOOGLE_SHEETS_OPTIONS_KEY
+}
+
+func (i *ResourceOptionGoogleSheets) ExportTokenContent() map[string]interface{} {
+	return map[string]interface{}{
+		"accessToken":  i.Options.AccessToken,
+		"refreshToken": i.Options.RefreshToken,
+		"tokenType":    i.Options.TokenType,
+		"expiresIn":    i.Options.ExpiresIn,
+		"expiresAt":    i.Options.ExpiresAt,
+		"scope":        i.Options.Scope,
+	}
 }
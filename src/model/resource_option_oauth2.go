@@ -0,0 +1,109 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/illacloud/builder-backend/src/utils/oauthgeneric"
+	"github.com/mitchellh/mapstructure"
+	"golang.org/x/oauth2"
+)
+
+const (
+	RESOURCE_OPTION_OAUTH2_AUTHENTICATION = "oauth2"
+)
+
+const (
+	RESOURCE_OPTION_OAUTH2_AUTH_CONTENT_KEY = "authContent"
+)
+
+// ResourceOAuth2Option is the oauth2 authorization kept in resource options, the generic oauth2 resources and google sheets keep tokens in different layout.
+type ResourceOAuth2Option interface {
+	IsAvaliableAuthenticationMethod() bool
+	ExportProvider() (*oauthgeneric.Provider, error)
+	ExportRefreshToken() (string, error)
+	NeedRefresh(beforeExpiry time.Duration) bool
+	UpdateByToken(token *oauth2.Token) error
+	// ExportTokenContentKey returns the resource options key which holds the token
+	ExportTokenContentKey() string
+	// ExportTokenContent returns the token fields, only these fields are written back to resource options
+	ExportTokenContent() map[string]interface{}
+}
+
+func NewResourceOAuth2OptionByResource(resource *Resource) (ResourceOAuth2Option, error) {
+	var resourceOAuth2Option ResourceOAuth2Option
+	var errInNewResourceOption error
+	switch {
+	case resource.CanCreateOAuthToken():
+		resourceOAuth2Option, errInNewResourceOption = NewResourceOptionGoogleSheetsByResource(resource)
+	case resource.CanUseGenericOAuth2():
+		resourceOAuth2Option, errInNewResourceOption = NewResourceOptionOAuth2ByResource(resource)
+	default:
+		return nil, errors.New("unsupported resource type")
+	}
+	if errInNewResourceOption != nil {
+		return nil, errInNewResourceOption
+	}
+	if !resourceOAuth2Option.IsAvaliableAuthenticationMethod() {
+		return nil, errors.New("unsupported authentication type")
+	}
+	return resourceOAuth2Option, nil
+}
+
+// ResourceOptionOAuth2 is the generic oauth2 resource options, the provider and token are kept in auth content.
+type ResourceOptionOAuth2 struct {
+	Authentication string
+	AuthContent    map[string]string
+}
+
+func NewResourceOptionOAuth2ByResource(resource *Resource) (*ResourceOptionOAuth2, error) {
+	resourceOptions := resource.ExportOptionsInMap()
+	if resourceOptions == nil {
+		resourceOptions = make(map[string]interface{})
+	}
+	resourceOptionOAuth2 := &ResourceOptionOAuth2{
+		AuthContent: make(map[string]string),
+	}
+	if errInDecode := mapstructure.WeakDecode(resourceOptions, &resourceOptionOAuth2); errInDecode != nil {
+		return nil, errInDecode
+	}
+	return resourceOptionOAuth2, nil
+}
+
+func (i *ResourceOptionOAuth2) IsAvaliableAuthenticationMethod() bool {
+	return i.Authentication == RESOURCE_OPTION_OAUTH2_AUTHENTICATION
+}
+
+func (i *ResourceOptionOAuth2) ExportProvider() (*oauthgeneric.Provider, error) {
+	return oauthgeneric.NewProviderByAuthContent(i.AuthContent)
+}
+
+func (i *ResourceOptionOAuth2) ExportRefreshToken() (string, error) {
+	return oauthgeneric.ExportRefreshToken(i.AuthContent)
+}
+
+// NeedRefresh reports whether the token expires in the given period and can be refreshed.
+func (i *ResourceOptionOAuth2) NeedRefresh(beforeExpiry time.Duration) bool {
+	expiresAt := oauthgeneric.ExportExpiresAt(i.AuthContent)
+	if expiresAt.IsZero() || i.AuthContent[oauthgeneric.AUTH_CONTENT_REFRESH_TOKEN] == "" {
+		return false
+	}
+	return time.Now().Add(beforeExpiry).After(expiresAt)
+}
+
+func (i *ResourceOptionOAuth2) UpdateByToken(token *oauth2.Token) error {
+	return oauthgeneric.StoreToken(i.AuthContent, token)
+}
+
+func (i *ResourceOptionOAuth2) ExportTokenContentKey() string {
+	return RESOURCE_OPTION_OAUTH2_AUTH_CONTENT_KEY
+}
+
+func (i *ResourceOptionOAuth2) ExportTokenContent() map[string]interface{} {
+	return map[string]interface{}{
+		oauthgeneric.AUTH_CONTENT_ACCESS_TOKEN:  i.AuthContent[oauthgeneric.AUTH_CONTENT_ACCESS_TOKEN],
+		oauthgeneric.AUTH_CONTENT_REFRESH_TOKEN: i.AuthContent[oauthgeneric.AUTH_CONTENT_REFRESH_TOKEN],
+		oauthgeneric.AUTH_CONTENT_TOKEN_TYPE:    i.AuthContent[oauthgeneric.AUTH_CONTENT_TOKEN_TYPE],
+		oauthgeneric.AUTH_CONTENT_EXPIRES_AT:    i.AuthContent[oauthgeneric.AUTH_CONTENT_EXPIRES_AT],
+	}
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/illacloud/builder-backend/src/utils/oauthgeneric"
+	"github.com/illacloud/builder-backend/src/utils/resourcelist"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+func TestGoogleSheetsOptionUsesGenericOAuth2(t *testing.T) {
+	resource := &Resource{
+		Type:    resourcelist.TYPE_GOOGLESHEETS_ID,
+		Options: `{"authentication":"oauth2","opts":{"accessType":"rw","accessToken":"legacy-access","refreshToken":"legacy-refresh","status":1}}`,
+	}
+	resourceOAuth2Option, err := NewResourceOAuth2OptionByResource(resource)
+	assert.Nil(t, err)
+	assert.Equal(t, GOOGLE_SHEETS_OPTIONS_KEY, resourceOAuth2Option.ExportTokenContentKey())
+
+	// the legacy plaintext refresh token is accepted
+	refreshToken, err := resourceOAuth2Option.ExportRefreshToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "legacy-refresh", refreshToken)
+	assert.False(t, resourceOAuth2Option.NeedRefresh(time.Minute))
+
+	provider, err := resourceOAuth2Option.ExportProvider()
+	assert.Nil(t, err)
+	assert.Equal(t, googleSheetsReadAndWriteScopes, provider.Scopes)
+	assert.Equal(t, "offline", provider.AuthURLParams["access_type"])
+
+	// google does not return refresh token when refreshing
+	assert.Nil(t, resourceOAuth2Option.UpdateByToken(&oauth2.Token{AccessToken: "new-access", TokenType: "Bearer", Expiry: time.Now().Add(time.Hour)}))
+	tokenContent := resourceOAuth2Option.ExportTokenContent()
+	assert.True(t, strings.HasPrefix(tokenContent["accessToken"].(string), oauthgeneric.ENCRYPTED_TOKEN_PREFIX))
+	assert.True(t, strings.HasPrefix(tokenContent["refreshToken"].(string), oauthgeneric.ENCRYPTED_TOKEN_PREFIX))
+	assert.NotContains(t, tokenContent, "accessType")
+	assert.NotContains(t, tokenContent, "status")
+	refreshToken, err = resourceOAuth2Option.ExportRefreshToken()
+	assert.Nil(t, err)
+	assert.Equal(t, "legacy-refresh", refreshToken)
+	assert.True(t, resourceOAuth2Option.NeedRefresh(2*time.Hour))
+	assert.False(t, resourceOAuth2Option.NeedRefresh(time.Minute))
+}
+
+func TestGenericOAuth2OptionExportsOnlyTokenContent(t *testing.T) {
+	resource := &Resource{
+		Type:    resourcelist.GetResourceNameMappedID(resourcelist.TYPE_RESTAPI),
+		Options: `{"authentication":"oauth2","baseURL":"https://api.illa.test","authContent":{"authURL":"https://provider.illa.test/authorize","accessTokenURL":"https://provider.illa.test/token","clientID":"client","clientSecret":"secret"}}`,
+	}
+	resourceOAuth2Option, err := NewResourceOAuth2OptionByResource(resource)
+	assert.Nil(t, err)
+	assert.Nil(t, resourceOAuth2Option.UpdateByToken(&oauth2.Token{AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer"}))
+	tokenContent := resourceOAuth2Option.ExportTokenContent()
+	assert.Equal(t, RESOURCE_OPTION_OAUTH2_AUTH_CONTENT_KEY, resourceOAuth2Option.ExportTokenContentKey())
+	assert.Equal(t, 4, len(tokenContent))
+	assert.NotContains(t, tokenContent, oauthgeneric.AUTH_CONTENT_CLIENT_SECRET)
+
+	resource.Options = `{"authentication":"basic"}`
+	_, err = NewResourceOAuth2OptionByResource(resource)
+	assert.NotNil(t, err)
+}
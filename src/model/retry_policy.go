@@ -0,0 +1,158 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+)
+
+const (
+	RETRYABLE_ERROR_CLASS_ANY       = "any"
+	RETRYABLE_ERROR_CLASS_TIMEOUT   = "timeout"
+	RETRYABLE_ERROR_CLASS_NETWORK   = "network"
+	RETRYABLE_ERROR_CLASS_TRANSIENT = "transient" // the error marked as retryable by connector, like deadlock and serialization failure
+
+	RETRY_POLICY_MAX_ATTEMPTS             = 10
+	RETRY_POLICY_DEFAULT_INITIAL_INTERVAL = 200   // ms
+	RETRY_POLICY_DEFAULT_MAX_INTERVAL     = 10000 // ms
+	RETRY_POLICY_DEFAULT_MULTIPLIER       = 2.0
+	RETRY_POLICY_MAX_TOTAL_BACKOFF        = 30 * time.Second // the request is not held longer by waiting for retries
+
+	RUNTIME_RESULT_FIELD_ATTEMPTS    = "attempts"
+	RUNTIME_RESULT_FIELD_STATUS_CODE = "statusCode"
+)
+
+// RetryPolicy retries the failed action run with exponential backoff and jitter.
+// The action run which returned error in RetryableErrors classes, or returned statusCode in RetryableStatusCodes will be retried.
+type RetryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`     // includes the first attempt, retry disabled when it less than 2
+	InitialInterval      int      `json:"initialInterval"` // ms
+	MaxInterval          int      `json:"maxInterval"`     // ms
+	Multiplier           float64  `json:"multiplier"`
+	RetryableErrors      []string `json:"retryableErrors"`
+	RetryableStatusCodes []int    `json:"retryableStatusCodes"`
+	RetryNonIdempotent   bool     `json:"retryNonIdempotent"` // non-idempotent sql statements will not be retried by default
+}
+
+func (policy *RetryPolicy) IsEnabled() bool {
+	return policy != nil && policy.MaxAttempts > 1
+}
+
+func (policy *RetryPolicy) ExportMaxAttempts() int {
+	if policy.MaxAttempts > RETRY_POLICY_MAX_ATTEMPTS {
+		return RETRY_POLICY_MAX_ATTEMPTS
+	}
+	return policy.MaxAttempts
+}
+
+// ExportBackoff returns the wait duration before the next attempt, attempt starts from 1.
+// The equal jitter strategy is used, so the wait duration is in [backoff/2, backoff).
+func (policy *RetryPolicy) ExportBackoff(attempt int) time.Duration {
+	initialInterval := float64(policy.InitialInterval)
+	if initialInterval <= 0 {
+		initialInterval = RETRY_POLICY_DEFAULT_INITIAL_INTERVAL
+	}
+	maxInterval := float64(policy.MaxInterval)
+	if maxInterval <= 0 {
+		maxInterval = RETRY_POLICY_DEFAULT_MAX_INTERVAL
+	}
+	multiplier := policy.Multiplier
+	if multiplier < 1 {
+		multiplier = RETRY_POLICY_DEFAULT_MULTIPLIER
+	}
+	backoff := math.Min(initialInterval*math.Pow(multiplier, float64(attempt-1)), maxInterval)
+	backoff = backoff/2 + rand.Float64()*backoff/2
+	return time.Duration(backoff) * time.Millisecond
+}
+
+func (policy *RetryPolicy) ShouldRetry(runtimeResult common.RuntimeResult, errInRun error) bool {
+	if errInRun != nil {
+		for _, errorClass := range policy.RetryableErrors {
+			if isErrorInClass(errInRun, errorClass) {
+				return true
+			}
+		}
+	}
+	statusCode, hitStatusCode := runtimeResult.Extra[RUNTIME_RESULT_FIELD_STATUS_CODE].(int)
+	if !hitStatusCode {
+		return false
+	}
+	for _, retryableStatusCode := range policy.RetryableStatusCodes {
+		if statusCode == retryableStatusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// isErrorInClass prefers the category reported by connector, and falls back to detect the timeout and network errors
+func isErrorInClass(err error, errorClass string) bool {
+	if errorClass == RETRYABLE_ERROR_CLASS_ANY {
+		return true
+	}
+	connectorError, isConnectorError := common.AsConnectorError(err)
+	if !isConnectorError {
+		connectorError = common.ClassifyNetworkError(err)
+	}
+	if connectorError == nil {
+		return false
+	}
+	switch errorClass {
+	case RETRYABLE_ERROR_CLASS_TIMEOUT:
+		return connectorError.Category == common.CONNECTOR_ERROR_CATEGORY_TIMEOUT
+	case RETRYABLE_ERROR_CLASS_NETWORK:
+		return connectorError.Category == common.CONNECTOR_ERROR_CATEGORY_CONNECTION
+	case RETRYABLE_ERROR_CLASS_TRANSIENT:
+		return connectorError.Retryable
+	default:
+		return false
+	}
+}
+
+// RunWithRetryPolicy runs the action until it succeeded, the error is not retryable or the attempts exhausted.
+// The retry stops early when the request context is done or the total backoff would exceed RETRY_POLICY_MAX_TOTAL_BACKOFF,
+// then the last attempt is returned.
+// It returns the count of attempts and always reports it in the result extra, the extra is kept when the run failed.
+func RunWithRetryPolicy(ctx context.Context, policy *RetryPolicy, isIdempotent bool, run func() (common.RuntimeResult, error)) (common.RuntimeResult, int, error) {
+	maxAttempts := 1
+	if policy.IsEnabled() && (isIdempotent || policy.RetryNonIdempotent) {
+		maxAttempts = policy.ExportMaxAttempts()
+	}
+	attempts := 0
+	totalBackoff := time.Duration(0)
+	for {
+		attempts++
+		runtimeResult, errInRun := run()
+		if attempts >= maxAttempts || !policy.ShouldRetry(runtimeResult, errInRun) || !waitForRetry(ctx, policy.ExportBackoff(attempts), &totalBackoff) {
+			if runtimeResult.Extra == nil {
+				runtimeResult.Extra = make(map[string]interface{})
+			}
+			runtimeResult.Extra[RUNTIME_RESULT_FIELD_ATTEMPTS] = attempts
+			if errInRun != nil && attempts > 1 {
+				errInRun = fmt.Errorf("%w (after %d attempts)", errInRun, attempts)
+			}
+			return runtimeResult, attempts, errInRun
+		}
+	}
+}
+
+// waitForRetry waits the backoff and adds it to total backoff, it returns false without waiting when the total backoff
+// would exceed the limit, or returns false once the context is done.
+func waitForRetry(ctx context.Context, backoff time.Duration, totalBackoff *time.Duration) bool {
+	if *totalBackoff+backoff > RETRY_POLICY_MAX_TOTAL_BACKOFF {
+		return false
+	}
+	*totalBackoff += backoff
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
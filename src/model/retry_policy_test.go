@@ -0,0 +1,89 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunWithRetryPolicyReportsAttemptsWithoutPolicy(t *testing.T) {
+	runtimeResult, attempts, errInRun := RunWithRetryPolicy(context.Background(), nil, true, func() (common.RuntimeResult, error) {
+		return common.RuntimeResult{Success: true}, nil
+	})
+	assert.Nil(t, errInRun)
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, 1, runtimeResult.Extra[RUNTIME_RESULT_FIELD_ATTEMPTS])
+}
+
+func TestRunWithRetryPolicyKeepsExtraOnFailure(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 3, InitialInterval: 1, MaxInterval: 1, RetryableErrors: []string{RETRYABLE_ERROR_CLASS_ANY}}
+	runs := 0
+	runtimeResult, attempts, errInRun := RunWithRetryPolicy(context.Background(), policy, true, func() (common.RuntimeResult, error) {
+		runs++
+		return common.RuntimeResult{Extra: map[string]interface{}{RUNTIME_RESULT_FIELD_STATUS_CODE: 503}}, errors.New("unavailable")
+	})
+	assert.NotNil(t, errInRun)
+	assert.Contains(t, errInRun.Error(), "after 3 attempts")
+	assert.Equal(t, 3, runs)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, 3, runtimeResult.Extra[RUNTIME_RESULT_FIELD_ATTEMPTS])
+	assert.Equal(t, 503, runtimeResult.Extra[RUNTIME_RESULT_FIELD_STATUS_CODE])
+}
+
+func TestRunWithRetryPolicySkipsNonIdempotent(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 3, InitialInterval: 1, RetryableErrors: []string{RETRYABLE_ERROR_CLASS_ANY}}
+	runs := 0
+	runtimeResult, attempts, errInRun := RunWithRetryPolicy(context.Background(), policy, false, func() (common.RuntimeResult, error) {
+		runs++
+		return common.RuntimeResult{}, errors.New("deadlock")
+	})
+	assert.Equal(t, "deadlock", errInRun.Error())
+	assert.Equal(t, 1, runs)
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, 1, runtimeResult.Extra[RUNTIME_RESULT_FIELD_ATTEMPTS])
+}
+
+func TestRunWithRetryPolicyRetriesStatusCode(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 5, InitialInterval: 1, MaxInterval: 1, RetryableStatusCodes: []int{429}}
+	runs := 0
+	runtimeResult, attempts, errInRun := RunWithRetryPolicy(context.Background(), policy, true, func() (common.RuntimeResult, error) {
+		runs++
+		if runs < 2 {
+			return common.RuntimeResult{Extra: map[string]interface{}{RUNTIME_RESULT_FIELD_STATUS_CODE: 429}}, nil
+		}
+		return common.RuntimeResult{Success: true}, nil
+	})
+	assert.Nil(t, errInRun)
+	assert.True(t, runtimeResult.Success)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, 2, runtimeResult.Extra[RUNTIME_RESULT_FIELD_ATTEMPTS])
+}
+
+func TestRunWithRetryPolicyStopsWhenContextDone(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 10, InitialInterval: 10000, MaxInterval: 10000, RetryableErrors: []string{RETRYABLE_ERROR_CLASS_ANY}}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	runs := 0
+	startedAt := time.Now()
+	runtimeResult, attempts, errInRun := RunWithRetryPolicy(ctx, policy, true, func() (common.RuntimeResult, error) {
+		runs++
+		return common.RuntimeResult{}, errors.New("unavailable")
+	})
+	assert.Less(t, time.Since(startedAt), time.Second)
+	assert.Equal(t, "unavailable", errInRun.Error())
+	assert.Equal(t, 1, runs)
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, 1, runtimeResult.Extra[RUNTIME_RESULT_FIELD_ATTEMPTS])
+}
+
+func TestWaitForRetryCapsTotalBackoff(t *testing.T) {
+	totalBackoff := RETRY_POLICY_MAX_TOTAL_BACKOFF - time.Millisecond
+	assert.False(t, waitForRetry(context.Background(), 2*time.Millisecond, &totalBackoff))
+	assert.Equal(t, RETRY_POLICY_MAX_TOTAL_BACKOFF-time.Millisecond, totalBackoff)
+	assert.True(t, waitForRetry(context.Background(), time.Millisecond, &totalBackoff))
+	assert.Equal(t, RETRY_POLICY_MAX_TOTAL_BACKOFF, totalBackoff)
+}
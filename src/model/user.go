@@ -21,6 +21,11 @@ const (
 	ANONYMOUS_USER_ID = -1
 )
 
+// system user config, the updates made by background jobs are stamped by system user
+const (
+	SYSTEM_USER_ID = 0
+)
+
 type RawUser struct {
 	ID             string    `json:"id" gorm:"column:id;type:bigserial;primary_key;index:users_ukey"`
 	UID            uuid.UUID `json:"uid" gorm:"column:uid;type:uuid;not null;index:users_ukey"`
@@ -0,0 +1,13 @@
+package request
+
+type CreateOAuth2AuthorizeURLRequest struct {
+	RedirectURL string `json:"redirectURL" validate:"required"`
+}
+
+func NewCreateOAuth2AuthorizeURLRequest() *CreateOAuth2AuthorizeURLRequest {
+	return &CreateOAuth2AuthorizeURLRequest{}
+}
+
+func (req *CreateOAuth2AuthorizeURLRequest) ExportRedirectURL() string {
+	return req.RedirectURL
+}
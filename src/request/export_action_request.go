@@ -0,0 +1,91 @@
+package request
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// The export action HTTP request body is the run action request with an "export" field like:
+// ```json
+//
+//	{
+//	    "resourceID": "ILAfx4p1C7dD",
+//	    "actionType": "postgresql",
+//	    "displayName": "postgresql1",
+//	    "content": {
+//	        "mode": "sql",
+//	        "query": "select * from users;"
+//	    },
+//	    "context": {},
+//	    "export": {
+//	        "format": "xlsx",
+//	        "destination": "drive",
+//	        "fileName": "users",
+//	        "path": "/root",
+//	        "overwriteDuplicate": false
+//	    }
+//	}
+//
+// ```
+
+const (
+	EXPORT_ACTION_DESTINATION_BROWSER = "browser"
+	EXPORT_ACTION_DESTINATION_DRIVE   = "drive"
+
+	EXPORT_ACTION_DEFAULT_DRIVE_PATH = "/root"
+)
+
+type ExportActionRequest struct {
+	RunActionRequest
+	Export *ExportActionOption `json:"export" validate:"required"`
+}
+
+type ExportActionOption struct {
+	Format             string `json:"format"             validate:"required,oneof=csv xlsx jsonl parquet"`
+	Destination        string `json:"destination"        validate:"omitempty,oneof=browser drive"`
+	FileName           string `json:"fileName"`
+	Path               string `json:"path"`
+	OverwriteDuplicate bool   `json:"overwriteDuplicate"`
+}
+
+func NewExportActionRequest() *ExportActionRequest {
+	return &ExportActionRequest{}
+}
+
+func (req *ExportActionRequest) ExportRunActionRequest() *RunActionRequest {
+	return &req.RunActionRequest
+}
+
+func (req *ExportActionRequest) ExportFormat() string {
+	return req.Export.Format
+}
+
+func (req *ExportActionRequest) IsExportToDrive() bool {
+	return req.Export.Destination == EXPORT_ACTION_DESTINATION_DRIVE
+}
+
+func (req *ExportActionRequest) ExportDrivePath() string {
+	if req.Export.Path == "" {
+		return EXPORT_ACTION_DEFAULT_DRIVE_PATH
+	}
+	return req.Export.Path
+}
+
+func (req *ExportActionRequest) ExportOverwriteDuplicate() bool {
+	return req.Export.OverwriteDuplicate
+}
+
+// ExportFileName returns the file name with extension, the action display name will be used when file name is empty.
+func (req *ExportActionRequest) ExportFileName(extension string) string {
+	fileName := filepath.Base(req.Export.FileName)
+	if req.Export.FileName == "" || fileName == "." || fileName == "/" {
+		fileName = req.DisplayName
+	}
+	if fileName == "" {
+		fileName = "export"
+	}
+	if !strings.HasSuffix(strings.ToLower(fileName), "."+extension) {
+		fileName += "." + extension
+	}
+	return fileName
+}
@@ -0,0 +1,15 @@
+package response
+
+type CreateOAuth2AuthorizeURLResponse struct {
+	URL string `json:"url"`
+}
+
+func NewCreateOAuth2AuthorizeURLResponse(url string) *CreateOAuth2AuthorizeURLResponse {
+	return &CreateOAuth2AuthorizeURLResponse{
+		URL: url,
+	}
+}
+
+func (resp *CreateOAuth2AuthorizeURLResponse) ExportForFeedback() interface{} {
+	return resp
+}
@@ -1,12 +1,14 @@
 package response
 
 type CreateOAuthTokenResponse struct {
-	AccessToken string `json:"accessToken"`
+	AccessToken  string `json:"accessToken"`
+	AuthorizeURL string `json:"authorizeURL"`
 }
 
-func NewCreateOAuthTokenResponse(token string) *CreateOAuthTokenResponse {
+func NewCreateOAuthTokenResponse(token string, authorizeURL string) *CreateOAuthTokenResponse {
 	return &CreateOAuthTokenResponse{
-		AccessToken: token,
+		AccessToken:  token,
+		AuthorizeURL: authorizeURL,
 	}
 }
 
@@ -0,0 +1,24 @@
+package response
+
+import (
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+	"github.com/illacloud/builder-backend/src/utils/jsonschema"
+)
+
+type GetResourceTypeSchemaResponse struct {
+	ResourceType    string             `json:"resourceType"`
+	ResourceOptions *jsonschema.Schema `json:"resourceOptions"`
+	ActionTemplate  *jsonschema.Schema `json:"actionTemplate"`
+}
+
+func NewGetResourceTypeSchemaResponse(resourceType string, descriptor *common.ConnectorDescriptor) *GetResourceTypeSchemaResponse {
+	return &GetResourceTypeSchemaResponse{
+		ResourceType:    resourceType,
+		ResourceOptions: descriptor.ResourceOptionsSchema,
+		ActionTemplate:  descriptor.ActionTemplateSchema,
+	}
+}
+
+func (resp *GetResourceTypeSchemaResponse) ExportForFeedback() interface{} {
+	return resp
+}
@@ -0,0 +1,19 @@
+package response
+
+import (
+	"github.com/illacloud/builder-backend/src/actionruntime/common"
+)
+
+type GetResourceTypesResponse struct {
+	ResourceTypes []*common.ConnectorDescriptor `json:"resourceTypes"`
+}
+
+func NewGetResourceTypesResponse(descriptors []*common.ConnectorDescriptor) *GetResourceTypesResponse {
+	return &GetResourceTypesResponse{
+		ResourceTypes: descriptors,
+	}
+}
+
+func (resp *GetResourceTypesResponse) ExportForFeedback() interface{} {
+	return resp
+}
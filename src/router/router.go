@@ -82,6 +82,8 @@ func (r *Router) RegisterRouters(engine *gin.Engine) {
 	actionRouter.PATCH("/:actionID/tutorial", r.Controller.SetActionTutorialLink)
 	actionRouter.DELETE("/:actionID", r.Controller.DeleteAction)
 	actionRouter.POST("/:actionID/run", r.Controller.RunAction)
+	actionRouter.POST("/:actionID/export", r.Controller.ExportActionResult)
+	actionRouter.GET("/:actionID/results/:continuationToken", r.Controller.GetActionResultPage)
 
 	// internal action routers
 	internalActionRouter.POST("/generateSQL", r.Controller.GenerateSQL)
@@ -89,6 +91,8 @@ func (r *Router) RegisterRouters(engine *gin.Engine) {
 	// resource routers
 	resourceRouter.GET("", r.Controller.GetAllResources)
 	resourceRouter.POST("", r.Controller.CreateResource)
+	resourceRouter.GET("/types", r.Controller.GetResourceTypes)
+	resourceRouter.GET("/types/:resourceType/schema", r.Controller.GetResourceTypeSchema)
 	resourceRouter.GET("/:resourceID", r.Controller.GetResource)
 	resourceRouter.PUT("/:resourceID", r.Controller.UpdateResource)
 	resourceRouter.DELETE("/:resourceID", r.Controller.DeleteResource)
@@ -97,6 +101,8 @@ func (r *Router) RegisterRouters(engine *gin.Engine) {
 	resourceRouter.POST("/:resourceID/token", r.Controller.CreateGoogleOAuthToken)
 	resourceRouter.GET("/:resourceID/oauth2", r.Controller.GetGoogleSheetsOAuth2Token)
 	resourceRouter.POST("/:resourceID/refresh", r.Controller.RefreshGoogleSheetsOAuth)
+	resourceRouter.POST("/:resourceID/oauth2/authorize", r.Controller.CreateOAuth2AuthorizeURL)
+	resourceRouter.POST("/:resourceID/oauth2/refresh", r.Controller.RefreshOAuth2Token)
 
 	// public app routers
 	publicAppRouter.GET(":appID/versions/:version", r.Controller.GetFullPublicApp)
@@ -107,6 +113,7 @@ func (r *Router) RegisterRouters(engine *gin.Engine) {
 
 	// oauth2 router
 	oauth2Router.GET("/authorize", r.Controller.GoogleOAuth2Exchange)
+	oauth2Router.GET("/callback", r.Controller.OAuth2Exchange)
 
 	// flow action routers
 	flowActionRouter.POST("", r.Controller.CreateFlowAction)
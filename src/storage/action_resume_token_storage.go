@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/illacloud/builder-backend/src/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ActionResumeTokenStorage struct {
+	logger *zap.SugaredLogger
+	db     *gorm.DB
+}
+
+func NewActionResumeTokenStorage(logger *zap.SugaredLogger, db *gorm.DB) *ActionResumeTokenStorage {
+	return &ActionResumeTokenStorage{
+		logger: logger,
+		db:     db,
+	}
+}
+
+// Upsert keeps one resume token for every action, the token of last run overwrites the older one.
+func (impl *ActionResumeTokenStorage) Upsert(actionResumeToken *model.ActionResumeToken) error {
+	return impl.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "team_id"}, {Name: "action_kind"}, {Name: "action_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"resume_token", "updated_at"}),
+	}).Create(actionResumeToken).Error
+}
+
+// RetrieveResumeToken returns empty string when the action has no resume token yet
+func (impl *ActionResumeTokenStorage) RetrieveResumeToken(teamID int, actionKind int, actionID int) (string, error) {
+	var actionResumeToken *model.ActionResumeToken
+	errInRetrieve := impl.db.Where("team_id = ? AND action_kind = ? AND action_id = ?", teamID, actionKind, actionID).First(&actionResumeToken).Error
+	if errors.Is(errInRetrieve, gorm.ErrRecordNotFound) {
+		return "", nil
+	} else if errInRetrieve != nil {
+		return "", errInRetrieve
+	}
+	return actionResumeToken.ExportResumeToken(), nil
+}
+
+func (impl *ActionResumeTokenStorage) DeleteByAction(teamID int, actionKind int, actionID int) error {
+	return impl.db.Where("team_id = ? AND action_kind = ? AND action_id = ?", teamID, actionKind, actionID).Delete(&model.ActionResumeToken{}).Error
+}
+
+// DeleteByApp deletes the resume tokens of the actions in app, it should be called before the actions deleted.
+func (impl *ActionResumeTokenStorage) DeleteByApp(teamID int, appID int) error {
+	actionIDs := impl.db.Model(&model.Action{}).Select("id").Where("team_id = ? AND app_ref_id = ?", teamID, appID)
+	return impl.db.Where("team_id = ? AND action_kind = ? AND action_id IN (?)", teamID, model.ACTION_RESUME_TOKEN_KIND_ACTION, actionIDs).Delete(&model.ActionResumeToken{}).Error
+}
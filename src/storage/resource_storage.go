@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/illacloud/builder-backend/src/model"
@@ -58,6 +59,37 @@ func (impl *ResourceStorage) RetrieveAllByUpdatedTime(teamID int) ([]*model.Reso
 	return resources, nil
 }
 
+// RetrieveOAuth2ResourcesExpiringBefore only retrieves the refreshable tokens, the expiry is kept in RFC3339 UTC format so it can be compared as string.
+func (impl *ResourceStorage) RetrieveOAuth2ResourcesExpiringBefore(expiresBefore time.Time) ([]*model.Resource, error) {
+	var resources []*model.Resource
+	expiresAt := "COALESCE(options->'authContent'->>'expiresAt', options->'opts'->>'expiresAt', '')"
+	refreshToken := "COALESCE(options->'authContent'->>'refreshToken', options->'opts'->>'refreshToken', '')"
+	if err := impl.db.Where(
+		"options->>'authentication' = ? AND "+expiresAt+" <> '' AND "+expiresAt+" <= ? AND "+refreshToken+" <> ''",
+		model.RESOURCE_OPTION_OAUTH2_AUTHENTICATION,
+		expiresBefore.UTC().Format(time.RFC3339),
+	).Find(&resources).Error; err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// UpdateOptionsContent merges content into the object of resource options under key, the other options are kept.
+func (impl *ResourceStorage) UpdateOptionsContent(teamID int, resourceID int, key string, content map[string]interface{}, userID int) error {
+	contentInJSON, errInMarshal := json.Marshal(content)
+	if errInMarshal != nil {
+		return errInMarshal
+	}
+	if err := impl.db.Model(&model.Resource{}).Where("id = ? AND team_id = ?", resourceID, teamID).UpdateColumns(map[string]interface{}{
+		"options":    gorm.Expr("jsonb_set(COALESCE(options, '{}'::jsonb), ARRAY[?]::text[], COALESCE(options->?, '{}'::jsonb) || ?::jsonb)", key, key, string(contentInJSON)),
+		"updated_at": time.Now().UTC(),
+		"updated_by": userID,
+	}).Error; err != nil {
+		return err
+	}
+	return nil
+}
+
 func (impl *ResourceStorage) CountResourceByTeamID(teamID int) (int, error) {
 	var count int64
 	if err := impl.db.Model(&model.Resource{}).Where("team_id = ?", teamID).Count(&count).Error; err != nil {
@@ -6,25 +6,27 @@ import (
 )
 
 type Storage struct {
-	AppStorage         *AppStorage
-	ActionStorage      *ActionStorage
-	FlowActionStorage  *FlowActionStorage
-	AppSnapshotStorage *AppSnapshotStorage
-	KVStateStorage     *KVStateStorage
-	ResourceStorage    *ResourceStorage
-	SetStateStorage    *SetStateStorage
-	TreeStateStorage   *TreeStateStorage
+	AppStorage               *AppStorage
+	ActionStorage            *ActionStorage
+	ActionResumeTokenStorage *ActionResumeTokenStorage
+	FlowActionStorage        *FlowActionStorage
+	AppSnapshotStorage       *AppSnapshotStorage
+	KVStateStorage           *KVStateStorage
+	ResourceStorage          *ResourceStorage
+	SetStateStorage          *SetStateStorage
+	TreeStateStorage         *TreeStateStorage
 }
 
 func NewStorage(postgresDriver *gorm.DB, logger *zap.SugaredLogger) *Storage {
 	return &Storage{
-		AppStorage:         NewAppStorage(logger, postgresDriver),
-		ActionStorage:      NewActionStorage(logger, postgresDriver),
-		FlowActionStorage:  NewFlowActionStorage(logger, postgresDriver),
-		AppSnapshotStorage: NewAppSnapshotStorage(logger, postgresDriver),
-		KVStateStorage:     NewKVStateStorage(logger, postgresDriver),
-		ResourceStorage:    NewResourceStorage(logger, postgresDriver),
-		SetStateStorage:    NewSetStateStorage(logger, postgresDriver),
-		TreeStateStorage:   NewTreeStateStorage(logger, postgresDriver),
+		AppStorage:               NewAppStorage(logger, postgresDriver),
+		ActionStorage:            NewActionStorage(logger, postgresDriver),
+		ActionResumeTokenStorage: NewActionResumeTokenStorage(logger, postgresDriver),
+		FlowActionStorage:        NewFlowActionStorage(logger, postgresDriver),
+		AppSnapshotStorage:       NewAppSnapshotStorage(logger, postgresDriver),
+		KVStateStorage:           NewKVStateStorage(logger, postgresDriver),
+		ResourceStorage:          NewResourceStorage(logger, postgresDriver),
+		SetStateStorage:          NewSetStateStorage(logger, postgresDriver),
+		TreeStateStorage:         NewTreeStateStorage(logger, postgresDriver),
 	}
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -100,6 +101,38 @@ type Config struct {
 	IllaIPZoneDetectorToken string `env:"ILLA_IP_ZONE_DETECTOR_TOKEN" envDefault:""`
 	// illa drive config
 	IllaDriveRestAPI string `env:"ILLA_DRIVE_API" envDefault:"http://illa-drive-backend:8004"`
+	// action result config
+	ActionResultMaxRows    int    `env:"ILLA_ACTION_RESULT_MAX_ROWS" envDefault:"100000"`
+	ActionExportMaxRows    int    `env:"ILLA_ACTION_EXPORT_MAX_ROWS" envDefault:"1000000"`
+	ActionResultPageTTLRaw string `env:"ILLA_ACTION_RESULT_PAGE_TTL" envDefault:"10m"`
+	ActionResultPageTTL    time.Duration
+	// action result cache default ttl, can be overwritten by action cache config
+	ActionResultCacheTTLRaw string `env:"ILLA_ACTION_RESULT_CACHE_TTL" envDefault:"5m"`
+	ActionResultCacheTTL    time.Duration
+	// resource meta info cache ttl, the meta info can be refreshed by request before expired
+	ResourceMetaInfoCacheTTLRaw string `env:"ILLA_RESOURCE_META_INFO_CACHE_TTL" envDefault:"10m"`
+	ResourceMetaInfoCacheTTL    time.Duration
+	// connector plugin config, plugins are disabled when the directory is empty
+	PluginDirectory              string `env:"ILLA_PLUGIN_DIR" envDefault:""`
+	PluginHealthCheckIntervalRaw string `env:"ILLA_PLUGIN_HEALTH_CHECK_INTERVAL" envDefault:"10s"`
+	PluginHealthCheckInterval    time.Duration
+	PluginCallTimeoutRaw         string `env:"ILLA_PLUGIN_CALL_TIMEOUT" envDefault:"60s"`
+	PluginCallTimeout            time.Duration
+	// smtp outbox config, the queued emails are retried until max attempts, and the delivery status kept in retention
+	SMTPOutboxPollIntervalRaw string `env:"ILLA_SMTP_OUTBOX_POLL_INTERVAL" envDefault:"5s"`
+	SMTPOutboxPollInterval    time.Duration
+	SMTPOutboxMaxAttempts     int    `env:"ILLA_SMTP_OUTBOX_MAX_ATTEMPTS" envDefault:"5"`
+	SMTPOutboxRetentionRaw    string `env:"ILLA_SMTP_OUTBOX_RETENTION" envDefault:"168h"`
+	SMTPOutboxRetention       time.Duration
+	// generic oauth2 config, the tokens are encrypted by secret key when encryption key is empty, and refreshed before expiry
+	OAuth2RedirectURI            string `env:"ILLA_OAUTH2_REDIRECT_URI" envDefault:""`
+	OAuth2TokenEncryptionKey     string `env:"ILLA_OAUTH2_TOKEN_ENCRYPTION_KEY" envDefault:""`
+	OAuth2RefreshIntervalRaw     string `env:"ILLA_OAUTH2_REFRESH_INTERVAL" envDefault:"1m"`
+	OAuth2RefreshInterval        time.Duration
+	OAuth2RefreshBeforeExpiryRaw string `env:"ILLA_OAUTH2_REFRESH_BEFORE_EXPIRY" envDefault:"5m"`
+	OAuth2RefreshBeforeExpiry    time.Duration
+	// the origins of frontend split by comma, the oauth2 flows only redirect back to these origins
+	FrontendOrigins string `env:"ILLA_FRONTEND_ORIGINS" envDefault:""`
 }
 
 func getConfig() (*Config, error) {
@@ -112,6 +145,42 @@ func getConfig() (*Config, error) {
 	if errInParseDuration != nil {
 		return nil, errInParseDuration
 	}
+	cfg.ActionResultPageTTL, errInParseDuration = time.ParseDuration(cfg.ActionResultPageTTLRaw)
+	if errInParseDuration != nil {
+		return nil, errInParseDuration
+	}
+	cfg.ActionResultCacheTTL, errInParseDuration = time.ParseDuration(cfg.ActionResultCacheTTLRaw)
+	if errInParseDuration != nil {
+		return nil, errInParseDuration
+	}
+	cfg.ResourceMetaInfoCacheTTL, errInParseDuration = time.ParseDuration(cfg.ResourceMetaInfoCacheTTLRaw)
+	if errInParseDuration != nil {
+		return nil, errInParseDuration
+	}
+	cfg.PluginHealthCheckInterval, errInParseDuration = time.ParseDuration(cfg.PluginHealthCheckIntervalRaw)
+	if errInParseDuration != nil {
+		return nil, errInParseDuration
+	}
+	cfg.PluginCallTimeout, errInParseDuration = time.ParseDuration(cfg.PluginCallTimeoutRaw)
+	if errInParseDuration != nil {
+		return nil, errInParseDuration
+	}
+	cfg.SMTPOutboxPollInterval, errInParseDuration = time.ParseDuration(cfg.SMTPOutboxPollIntervalRaw)
+	if errInParseDuration != nil {
+		return nil, errInParseDuration
+	}
+	cfg.SMTPOutboxRetention, errInParseDuration = time.ParseDuration(cfg.SMTPOutboxRetentionRaw)
+	if errInParseDuration != nil {
+		return nil, errInParseDuration
+	}
+	cfg.OAuth2RefreshInterval, errInParseDuration = time.ParseDuration(cfg.OAuth2RefreshIntervalRaw)
+	if errInParseDuration != nil {
+		return nil, errInParseDuration
+	}
+	cfg.OAuth2RefreshBeforeExpiry, errInParseDuration = time.ParseDuration(cfg.OAuth2RefreshBeforeExpiryRaw)
+	if errInParseDuration != nil {
+		return nil, errInParseDuration
+	}
 	// ok
 	fmt.Printf("----------------\n")
 	fmt.Printf("run by following config: %+v\n", cfg)
@@ -334,3 +403,81 @@ func (c *Config) GetWebScoketServerConnectionAddressCenterEurope() string {
 func (c *Config) GetIllaDriveAPIForSDK() string {
 	return c.IllaDriveRestAPI
 }
+
+func (c *Config) GetActionResultMaxRows() int {
+	return c.ActionResultMaxRows
+}
+
+func (c *Config) GetActionResultPageTTL() time.Duration {
+	return c.ActionResultPageTTL
+}
+
+func (c *Config) GetActionResultCacheTTL() time.Duration {
+	return c.ActionResultCacheTTL
+}
+
+func (c *Config) GetResourceMetaInfoCacheTTL() time.Duration {
+	return c.ResourceMetaInfoCacheTTL
+}
+
+func (c *Config) GetActionExportMaxRows() int {
+	return c.ActionExportMaxRows
+}
+
+func (c *Config) IsPluginEnabled() bool {
+	return c.PluginDirectory != ""
+}
+
+func (c *Config) GetPluginDirectory() string {
+	return c.PluginDirectory
+}
+
+func (c *Config) GetPluginHealthCheckInterval() time.Duration {
+	return c.PluginHealthCheckInterval
+}
+
+func (c *Config) GetPluginCallTimeout() time.Duration {
+	return c.PluginCallTimeout
+}
+
+func (c *Config) GetSMTPOutboxPollInterval() time.Duration {
+	return c.SMTPOutboxPollInterval
+}
+
+func (c *Config) GetSMTPOutboxMaxAttempts() int {
+	return c.SMTPOutboxMaxAttempts
+}
+
+func (c *Config) GetSMTPOutboxRetention() time.Duration {
+	return c.SMTPOutboxRetention
+}
+
+func (c *Config) GetOAuth2RedirectURI() string {
+	return c.OAuth2RedirectURI
+}
+
+func (c *Config) GetOAuth2TokenEncryptionKey() string {
+	if c.OAuth2TokenEncryptionKey == "" {
+		return c.SecretKey
+	}
+	return c.OAuth2TokenEncryptionKey
+}
+
+func (c *Config) GetOAuth2RefreshInterval() time.Duration {
+	return c.OAuth2RefreshInterval
+}
+
+func (c *Config) GetOAuth2RefreshBeforeExpiry() time.Duration {
+	return c.OAuth2RefreshBeforeExpiry
+}
+
+func (c *Config) GetFrontendOrigins() []string {
+	origins := make([]string, 0)
+	for _, origin := range strings.Split(c.FrontendOrigins, ",") {
+		origin = strings.TrimSpace(origin)
+		if origin != "" {
+			origins = append(origins, strings.TrimSuffix(origin, "/"))
+		}
+	}
+	return origins
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
@@ -23,6 +24,8 @@ const (
 	DRIVE_API_RENAME_FILE             = "/api/v1/teams/%s/illaAction/files/%s/name"
 )
 
+const DRIVE_FILE_STATUS_COMPLETE = "complete"
+
 // duplication strategies
 const (
 	DUPLICATION_STRATEGY_COVER  = "cover"
@@ -327,6 +330,69 @@ func (r *IllaDriveRestAPI) UpdateFileStatus(fileID string, status string) (map[s
 	return updateStatusResponse, nil
 }
 
+// UploadFile uploads content to the resumable upload address and marks the file as complete
+func (r *IllaDriveRestAPI) UploadFile(overwriteDuplicate bool, path string, fileName string, fileSize int64, contentType string, content io.Reader) (map[string]interface{}, error) {
+	// self-host need skip this method.
+	if !r.Config.IsCloudMode() {
+		return nil, nil
+	}
+
+	uploadAddress, errInGetUploadAddress := r.GetUploadAddres(overwriteDuplicate, path, fileName, fileSize, contentType)
+	if errInGetUploadAddress != nil {
+		return nil, errInGetUploadAddress
+	}
+	fileID, _ := uploadAddress["id"].(string)
+	uploadURL, _ := uploadAddress["url"].(string)
+	if fileID == "" || uploadURL == "" {
+		return nil, errors.New("invalid upload address response")
+	}
+
+	// the upload address is a resumable upload url, initiate the upload session first, the request like:
+	// ```
+	// [POST] https://storage.googleapis.com/drive_34_.../321_0e94bdab-e489-4ae7-91b2-b2a4bf134ee7.csv?X-Goog-Algorithm=...
+	// x-goog-resumable: start
+	// ```
+	// and the session uri is in the "Location" header of response.
+	client := resty.New()
+	resp, errInPost := client.R().
+		SetHeader("Content-Type", contentType).
+		SetHeader("x-goog-resumable", "start").
+		Post(uploadURL)
+	if errInPost != nil {
+		return nil, errInPost
+	}
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+		r.DeleteFile(fileID)
+		return nil, errors.New(resp.String())
+	}
+	sessionURI := resp.Header().Get("Location")
+	if sessionURI == "" {
+		r.DeleteFile(fileID)
+		return nil, errors.New("missing resumable upload session uri")
+	}
+
+	// upload content
+	uploadRequest, errInNewRequest := http.NewRequest(http.MethodPut, sessionURI, content)
+	if errInNewRequest != nil {
+		return nil, errInNewRequest
+	}
+	uploadRequest.ContentLength = fileSize
+	uploadRequest.Header.Set("Content-Type", contentType)
+	uploadResponse, errInPut := http.DefaultClient.Do(uploadRequest)
+	if errInPut != nil {
+		r.DeleteFile(fileID)
+		return nil, errInPut
+	}
+	defer uploadResponse.Body.Close()
+	if uploadResponse.StatusCode != http.StatusOK && uploadResponse.StatusCode != http.StatusCreated {
+		uploadResponseBody, _ := io.ReadAll(uploadResponse.Body)
+		r.DeleteFile(fileID)
+		return nil, errors.New(string(uploadResponseBody))
+	}
+
+	return r.UpdateFileStatus(fileID, DRIVE_FILE_STATUS_COMPLETE)
+}
+
 func (r *IllaDriveRestAPI) GetMultipleUploadAddress(overwriteDuplicate bool, path string, fileNames []string, fileSizes []int64, contentTypes []string) ([]map[string]interface{}, error) {
 	ret := make([]map[string]interface{}, 0)
 	fmt.Printf("[DUMP] GetMultipleUploadAddress() fileName: %+v, fileSizes: %+v, contentTypes: %+v\n ", fileNames, fileSizes, contentTypes)
@@ -0,0 +1,75 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testHeader struct {
+	Key   string `validate:"required"`
+	Value string
+}
+
+type testOptions struct {
+	BaseURL        string            `validate:"required,url"`
+	Port           int               `validate:"gt=0"`
+	Headers        []testHeader      `validate:"dive"`
+	SelfSignedCert bool              `mapstructure:"selfSignedCert"`
+	Certs          map[string]string `validate:"required_unless=SelfSignedCert false"`
+	Authentication string            `validate:"oneof=none basic bearer"`
+}
+
+func TestReflect(t *testing.T) {
+	schema := Reflect(&testOptions{})
+	assert.Equal(t, TYPE_OBJECT, schema.Type)
+	assert.Equal(t, []string{"baseURL"}, schema.Required)
+	assert.Equal(t, FORMAT_URI, schema.Properties["baseURL"].Format)
+	assert.Equal(t, TYPE_INTEGER, schema.Properties["port"].Type)
+	assert.Equal(t, float64(0), *schema.Properties["port"].ExclusiveMinimum)
+	assert.Equal(t, []string{"key"}, schema.Properties["headers"].Items.Required)
+	assert.Equal(t, []interface{}{"none", "basic", "bearer"}, schema.Properties["authentication"].Enum)
+	assert.Equal(t, 1, len(schema.AllOf))
+	assert.Equal(t, []string{"certs"}, schema.AllOf[0].Else.Required)
+}
+
+func TestValidate(t *testing.T) {
+	schema := Reflect(&testOptions{})
+	var instance map[string]interface{}
+	input := `{"baseUrl": "api.example.com", "port": 0, "headers": [{"key": "a"}, {"value": "b"}], "selfSignedCert": true, "authentication": "oauth"}`
+	json.Unmarshal([]byte(input), &instance)
+
+	err := Validate(schema, instance)
+	assert.NotNil(t, err)
+	paths := make(map[string]string)
+	for _, fieldError := range err.(*ValidationError).Errors {
+		paths[fieldError.Path] = fieldError.Message
+	}
+	assert.Equal(t, "must be a valid URL", paths["baseURL"])
+	assert.Equal(t, "must be greater than 0", paths["port"])
+	assert.Equal(t, "is required", paths["headers[1].key"])
+	assert.Equal(t, "is required", paths["certs"])
+	assert.Equal(t, "must be one of [none, basic, bearer]", paths["authentication"])
+	assert.Equal(t, 5, len(paths))
+
+	valid := map[string]interface{}{"baseURL": "https://api.example.com", "port": 443, "authentication": "none"}
+	assert.Nil(t, Validate(schema, valid))
+}
+
+type testOmitEmptyOptions struct {
+	Mode    string   `validate:"omitempty,oneof=gui sql"`
+	Methods []string `validate:"omitempty,dive,oneof=get post"`
+}
+
+func TestOmitEmpty(t *testing.T) {
+	schema := Reflect(&testOmitEmptyOptions{})
+	assert.Equal(t, []interface{}{"", "gui", "sql"}, schema.Properties["mode"].Enum)
+	// the omitempty before dive does not apply to the elements
+	assert.Equal(t, []interface{}{"get", "post"}, schema.Properties["methods"].Items.Enum)
+
+	assert.Nil(t, Validate(schema, map[string]interface{}{"mode": ""}))
+	assert.Nil(t, Validate(schema, map[string]interface{}{"mode": "sql", "methods": []interface{}{"get"}}))
+	assert.NotNil(t, Validate(schema, map[string]interface{}{"mode": "raw"}))
+	assert.NotNil(t, Validate(schema, map[string]interface{}{"methods": []interface{}{""}}))
+}
@@ -0,0 +1,303 @@
+package jsonschema
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	SCHEMA_DRAFT = "https://json-schema.org/draft/2020-12/schema"
+
+	TYPE_STRING  = "string"
+	TYPE_BOOLEAN = "boolean"
+	TYPE_INTEGER = "integer"
+	TYPE_NUMBER  = "number"
+	TYPE_OBJECT  = "object"
+	TYPE_ARRAY   = "array"
+
+	FORMAT_URI   = "uri"
+	FORMAT_EMAIL = "email"
+)
+
+// Schema is the subset of JSON Schema which can be derived from the `validate` tags used by connectors
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	If                   *Schema            `json:"if,omitempty"`
+	Then                 *Schema            `json:"then,omitempty"`
+	Else                 *Schema            `json:"else,omitempty"`
+}
+
+// Reflect derives the schema from struct, the property names follow the mapstructure decoding,
+// and the `validate` tags are converted into the keywords.
+func Reflect(value interface{}) *Schema {
+	schema := reflectType(reflect.TypeOf(value))
+	schema.Schema = SCHEMA_DRAFT
+	return schema
+}
+
+func reflectType(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: TYPE_STRING}
+	case reflect.Bool:
+		return &Schema{Type: TYPE_BOOLEAN}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: TYPE_INTEGER}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: TYPE_NUMBER}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: TYPE_ARRAY, Items: reflectType(t.Elem())}
+	case reflect.Map:
+		schema := &Schema{Type: TYPE_OBJECT}
+		if t.Elem().Kind() != reflect.Interface {
+			schema.AdditionalProperties = reflectType(t.Elem())
+		}
+		return schema
+	case reflect.Struct:
+		schema := &Schema{Type: TYPE_OBJECT, Properties: make(map[string]*Schema)}
+		reflectStructFields(t, schema)
+		return schema
+	default:
+		// interface{} accepts any value
+		return &Schema{}
+	}
+}
+
+func reflectStructFields(t reflect.Type, schema *Schema) {
+	propertyNames := make(map[string]string) // field name => property name
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, squash := exportPropertyName(field)
+		if name == "-" {
+			continue
+		}
+		if squash && field.Type.Kind() == reflect.Struct {
+			reflectStructFields(field.Type, schema)
+			continue
+		}
+		propertyNames[field.Name] = name
+		schema.Properties[name] = reflectType(field.Type)
+	}
+	// the tags referring other fields need all the property names
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, hit := propertyNames[field.Name]
+		if !hit {
+			continue
+		}
+		applyValidateTag(t, propertyNames, schema, name, field.Tag.Get("validate"))
+	}
+}
+
+// exportPropertyName returns the mapstructure tag name, or the lower camel case field name
+func exportPropertyName(field reflect.StructField) (string, bool) {
+	tagName, tagOptions, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+	squash := field.Anonymous || strings.Contains(tagOptions, "squash")
+	if tagName != "" {
+		return tagName, squash
+	}
+	return lowerCamelCase(field.Name), squash
+}
+
+// lowerCamelCase converts "SSL" to "ssl", "URLParams" to "urlParams" and "DatabaseName" to "databaseName"
+func lowerCamelCase(name string) string {
+	runes := []rune(name)
+	upperCount := 0
+	for upperCount < len(runes) && unicode.IsUpper(runes[upperCount]) {
+		upperCount++
+	}
+	if upperCount > 1 && upperCount < len(runes) {
+		upperCount--
+	}
+	for i := 0; i < upperCount; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+func applyValidateTag(parentType reflect.Type, propertyNames map[string]string, parent *Schema, name string, tag string) {
+	if tag == "" {
+		return
+	}
+	schema := parent.Properties[name]
+	omitEmpty := false
+	for _, rule := range strings.Split(tag, ",") {
+		ruleName, ruleParam, _ := strings.Cut(rule, "=")
+		switch ruleName {
+		case "omitempty":
+			omitEmpty = true
+		case "dive":
+			// the following rules apply to the elements
+			if schema.Items == nil && schema.AdditionalProperties == nil {
+				return
+			}
+			if schema.Items != nil {
+				schema = schema.Items
+			} else {
+				schema = schema.AdditionalProperties
+			}
+			omitEmpty = false
+			continue
+		case "required":
+			if schema == parent.Properties[name] {
+				parent.Required = append(parent.Required, name)
+			}
+			// validator treats empty string as missing
+			if schema.Type == TYPE_STRING {
+				schema.MinLength = intPointer(1)
+			}
+		case "required_unless", "required_if":
+			applyConditionalRequired(parentType, propertyNames, parent, name, ruleName, ruleParam)
+		case "oneof":
+			// validator skips the following rules for empty string when omitempty given
+			if omitEmpty && schema.Type == TYPE_STRING {
+				schema.Enum = append(schema.Enum, "")
+			}
+			for _, option := range strings.Fields(ruleParam) {
+				schema.Enum = append(schema.Enum, parseValue(schema.Type, option))
+			}
+		case "url":
+			schema.Format = FORMAT_URI
+		case "email":
+			schema.Format = FORMAT_EMAIL
+		case "gt", "gte", "min", "lt", "lte", "max", "len":
+			applyBoundary(schema, ruleName, ruleParam)
+		}
+	}
+}
+
+// applyConditionalRequired converts "required_unless=Field value" into if-then-else
+func applyConditionalRequired(parentType reflect.Type, propertyNames map[string]string, parent *Schema, name string, ruleName string, ruleParam string) {
+	fieldName, value, _ := strings.Cut(ruleParam, " ")
+	conditionName, hit := propertyNames[fieldName]
+	if !hit {
+		return
+	}
+	conditionSchema := parent.Properties[conditionName]
+	condition := &Schema{
+		Properties: map[string]*Schema{
+			conditionName: {Enum: []interface{}{parseValue(conditionSchema.Type, value)}},
+		},
+	}
+	// the missing field is decoded as zero value, so it only matches the condition when the value is zero value
+	if field, hit := parentType.FieldByName(fieldName); !hit || !isZeroValueString(field.Type, value) {
+		condition.Required = []string{conditionName}
+	}
+	required := &Schema{Required: []string{name}}
+	conditional := &Schema{If: condition}
+	if ruleName == "required_unless" {
+		conditional.Else = required
+	} else {
+		conditional.Then = required
+	}
+	parent.AllOf = append(parent.AllOf, conditional)
+}
+
+func applyBoundary(schema *Schema, ruleName string, ruleParam string) {
+	boundary, errInParse := strconv.ParseFloat(ruleParam, 64)
+	if errInParse != nil {
+		return
+	}
+	// for strings and arrays, the boundary is the length
+	if schema.Type == TYPE_STRING || schema.Type == TYPE_ARRAY {
+		length := int(boundary)
+		var minLength, maxLength *int
+		switch ruleName {
+		case "gt":
+			minLength = intPointer(length + 1)
+		case "gte", "min":
+			minLength = intPointer(length)
+		case "lt":
+			maxLength = intPointer(length - 1)
+		case "lte", "max":
+			maxLength = intPointer(length)
+		case "len":
+			minLength, maxLength = intPointer(length), intPointer(length)
+		}
+		if schema.Type == TYPE_STRING {
+			if minLength != nil {
+				schema.MinLength = minLength
+			}
+			if maxLength != nil {
+				schema.MaxLength = maxLength
+			}
+		} else {
+			if minLength != nil {
+				schema.MinItems = minLength
+			}
+			if maxLength != nil {
+				schema.MaxItems = maxLength
+			}
+		}
+		return
+	}
+	switch ruleName {
+	case "gt":
+		schema.ExclusiveMinimum = &boundary
+	case "gte", "min":
+		schema.Minimum = &boundary
+	case "lt":
+		schema.ExclusiveMaximum = &boundary
+	case "lte", "max":
+		schema.Maximum = &boundary
+	case "len":
+		schema.Minimum, schema.Maximum = &boundary, &boundary
+	}
+}
+
+func parseValue(schemaType string, value string) interface{} {
+	switch schemaType {
+	case TYPE_BOOLEAN:
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	case TYPE_INTEGER, TYPE_NUMBER:
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return value
+}
+
+func isZeroValueString(t reflect.Type, value string) bool {
+	switch t.Kind() {
+	case reflect.Bool:
+		return value == "false"
+	case reflect.String:
+		return value == ""
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(value, 64)
+		return err == nil && parsed == 0
+	default:
+		return false
+	}
+}
+
+func intPointer(value int) *int {
+	return &value
+}
@@ -0,0 +1,357 @@
+package jsonschema
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// FieldError reports a single violation at the instance path, like "ssl.serverCert" or "headers[0].key"
+type FieldError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+type ValidationError struct {
+	Errors []*FieldError `json:"fields"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fieldError := range e.Errors {
+		if fieldError.Path == "" {
+			messages = append(messages, fieldError.Message)
+			continue
+		}
+		messages = append(messages, fmt.Sprintf("%s: %s", fieldError.Path, fieldError.Message))
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Validate checks the instance against the schema. The instance is usually the map[string]interface{}
+// received from the request, structs are accepted too. Property names are matched case-insensitively
+// like mapstructure does, and null is treated as absent.
+func Validate(schema *Schema, instance interface{}) error {
+	if schema == nil {
+		return nil
+	}
+	validationError := &ValidationError{Errors: make([]*FieldError, 0)}
+	validateValue(schema, reflect.ValueOf(instance), "", validationError)
+	if len(validationError.Errors) > 0 {
+		return validationError
+	}
+	return nil
+}
+
+func (e *ValidationError) add(path string, format string, args ...interface{}) {
+	e.Errors = append(e.Errors, &FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func indirect(value reflect.Value) reflect.Value {
+	for value.IsValid() && (value.Kind() == reflect.Interface || value.Kind() == reflect.Pointer) {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+func validateValue(schema *Schema, value reflect.Value, path string, validationError *ValidationError) {
+	value = indirect(value)
+	if !value.IsValid() {
+		return
+	}
+	if !matchType(schema.Type, value) {
+		validationError.add(path, "expected %s, got %s", schema.Type, describeKind(value))
+		return
+	}
+	if len(schema.Enum) > 0 && !matchEnum(schema.Enum, value) {
+		validationError.add(path, "must be one of %s", describeEnum(schema.Enum))
+	}
+	switch schema.Type {
+	case TYPE_STRING:
+		validateString(schema, value.String(), path, validationError)
+	case TYPE_INTEGER, TYPE_NUMBER:
+		validateNumber(schema, toFloat(value), path, validationError)
+	case TYPE_ARRAY:
+		validateArray(schema, value, path, validationError)
+	case TYPE_OBJECT:
+		validateObject(schema, value, path, validationError)
+	}
+}
+
+func validateString(schema *Schema, value string, path string, validationError *ValidationError) {
+	length := len([]rune(value))
+	if schema.MinLength != nil && length < *schema.MinLength {
+		if *schema.MinLength == 1 {
+			validationError.add(path, "must not be empty")
+		} else {
+			validationError.add(path, "length must be at least %d", *schema.MinLength)
+		}
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		validationError.add(path, "length must be at most %d", *schema.MaxLength)
+	}
+	if value == "" {
+		return
+	}
+	switch schema.Format {
+	case FORMAT_URI:
+		if parsed, err := url.Parse(value); err != nil || parsed.Scheme == "" {
+			validationError.add(path, "must be a valid URL")
+		}
+	case FORMAT_EMAIL:
+		if _, err := mail.ParseAddress(value); err != nil {
+			validationError.add(path, "must be a valid email address")
+		}
+	}
+}
+
+func validateNumber(schema *Schema, value float64, path string, validationError *ValidationError) {
+	if schema.Minimum != nil && value < *schema.Minimum {
+		validationError.add(path, "must be greater than or equal to %v", *schema.Minimum)
+	}
+	if schema.ExclusiveMinimum != nil && value <= *schema.ExclusiveMinimum {
+		validationError.add(path, "must be greater than %v", *schema.ExclusiveMinimum)
+	}
+	if schema.Maximum != nil && value > *schema.Maximum {
+		validationError.add(path, "must be less than or equal to %v", *schema.Maximum)
+	}
+	if schema.ExclusiveMaximum != nil && value >= *schema.ExclusiveMaximum {
+		validationError.add(path, "must be less than %v", *schema.ExclusiveMaximum)
+	}
+}
+
+func validateArray(schema *Schema, value reflect.Value, path string, validationError *ValidationError) {
+	if schema.MinItems != nil && value.Len() < *schema.MinItems {
+		validationError.add(path, "must contain at least %d items", *schema.MinItems)
+	}
+	if schema.MaxItems != nil && value.Len() > *schema.MaxItems {
+		validationError.add(path, "must contain at most %d items", *schema.MaxItems)
+	}
+	if schema.Items == nil {
+		return
+	}
+	for i := 0; i < value.Len(); i++ {
+		validateValue(schema.Items, value.Index(i), fmt.Sprintf("%s[%d]", path, i), validationError)
+	}
+}
+
+func validateObject(schema *Schema, value reflect.Value, path string, validationError *ValidationError) {
+	fields := exportFields(value)
+	for _, name := range schema.Required {
+		if _, hit := lookupField(fields, name); !hit {
+			validationError.add(joinPath(path, name), "is required")
+		}
+	}
+	for name, propertySchema := range schema.Properties {
+		if fieldValue, hit := lookupField(fields, name); hit {
+			validateValue(propertySchema, fieldValue, joinPath(path, name), validationError)
+		}
+	}
+	if schema.AdditionalProperties != nil {
+		for name, fieldValue := range fields {
+			if _, hit := schema.Properties[name]; !hit {
+				validateValue(schema.AdditionalProperties, fieldValue, joinPath(path, name), validationError)
+			}
+		}
+	}
+	for _, subSchema := range schema.AllOf {
+		validateConditional(subSchema, fields, path, validationError)
+	}
+}
+
+// validateConditional only supports the if-then-else generated by Reflect
+func validateConditional(schema *Schema, fields map[string]reflect.Value, path string, validationError *ValidationError) {
+	if schema.If == nil {
+		return
+	}
+	branch := schema.Else
+	if matchCondition(schema.If, fields) {
+		branch = schema.Then
+	}
+	if branch == nil {
+		return
+	}
+	for _, name := range branch.Required {
+		if _, hit := lookupField(fields, name); !hit {
+			validationError.add(joinPath(path, name), "is required")
+		}
+	}
+}
+
+func matchCondition(condition *Schema, fields map[string]reflect.Value) bool {
+	for _, name := range condition.Required {
+		if _, hit := lookupField(fields, name); !hit {
+			return false
+		}
+	}
+	for name, propertySchema := range condition.Properties {
+		fieldValue, hit := lookupField(fields, name)
+		if !hit {
+			continue
+		}
+		if len(propertySchema.Enum) > 0 && !matchEnum(propertySchema.Enum, fieldValue) {
+			return false
+		}
+	}
+	return true
+}
+
+// exportFields returns the non-null members of map or struct
+func exportFields(value reflect.Value) map[string]reflect.Value {
+	fields := make(map[string]reflect.Value)
+	switch value.Kind() {
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			member := indirect(iter.Value())
+			if member.IsValid() {
+				fields[fmt.Sprint(iter.Key().Interface())] = member
+			}
+		}
+	case reflect.Struct:
+		exportStructFields(value, fields)
+	}
+	return fields
+}
+
+func exportStructFields(value reflect.Value, fields map[string]reflect.Value) {
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, squash := exportPropertyName(field)
+		if name == "-" {
+			continue
+		}
+		member := indirect(value.Field(i))
+		if squash && member.Kind() == reflect.Struct {
+			exportStructFields(member, fields)
+			continue
+		}
+		if member.IsValid() {
+			fields[name] = member
+		}
+	}
+}
+
+func lookupField(fields map[string]reflect.Value, name string) (reflect.Value, bool) {
+	if fieldValue, hit := fields[name]; hit {
+		return fieldValue, true
+	}
+	for fieldName, fieldValue := range fields {
+		if strings.EqualFold(fieldName, name) {
+			return fieldValue, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func matchType(schemaType string, value reflect.Value) bool {
+	switch schemaType {
+	case "":
+		return true
+	case TYPE_STRING:
+		return value.Kind() == reflect.String
+	case TYPE_BOOLEAN:
+		return value.Kind() == reflect.Bool
+	case TYPE_INTEGER:
+		if isFloat(value) {
+			number := value.Float()
+			return number == float64(int64(number))
+		}
+		return isInteger(value)
+	case TYPE_NUMBER:
+		return isInteger(value) || isFloat(value)
+	case TYPE_ARRAY:
+		return value.Kind() == reflect.Slice || value.Kind() == reflect.Array
+	case TYPE_OBJECT:
+		return value.Kind() == reflect.Map || value.Kind() == reflect.Struct
+	default:
+		return false
+	}
+}
+
+func matchEnum(enum []interface{}, value reflect.Value) bool {
+	for _, option := range enum {
+		switch typedOption := option.(type) {
+		case float64:
+			if (isInteger(value) || isFloat(value)) && toFloat(value) == typedOption {
+				return true
+			}
+		case bool:
+			if value.Kind() == reflect.Bool && value.Bool() == typedOption {
+				return true
+			}
+		case string:
+			if value.Kind() == reflect.String && value.String() == typedOption {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isInteger(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func isFloat(value reflect.Value) bool {
+	return value.Kind() == reflect.Float32 || value.Kind() == reflect.Float64
+}
+
+func toFloat(value reflect.Value) float64 {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		return value.Float()
+	}
+	return 0
+}
+
+func describeKind(value reflect.Value) string {
+	switch {
+	case value.Kind() == reflect.String:
+		return TYPE_STRING
+	case value.Kind() == reflect.Bool:
+		return TYPE_BOOLEAN
+	case isInteger(value):
+		return TYPE_INTEGER
+	case isFloat(value):
+		return TYPE_NUMBER
+	case value.Kind() == reflect.Slice || value.Kind() == reflect.Array:
+		return TYPE_ARRAY
+	case value.Kind() == reflect.Map || value.Kind() == reflect.Struct:
+		return TYPE_OBJECT
+	}
+	return value.Kind().String()
+}
+
+func describeEnum(enum []interface{}) string {
+	options := make([]string, 0, len(enum))
+	for _, option := range enum {
+		options = append(options, fmt.Sprintf("%v", option))
+	}
+	return "[" + strings.Join(options, ", ") + "]"
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
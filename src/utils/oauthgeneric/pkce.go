@@ -0,0 +1,26 @@
+package oauthgeneric
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+const (
+	CODE_CHALLENGE_METHOD_S256 = "S256"
+	CODE_VERIFIER_LENGTH       = 32
+)
+
+// GenerateCodeVerifier returns a RFC 7636 code verifier with 256 bits entropy.
+func GenerateCodeVerifier() (string, error) {
+	buf := make([]byte, CODE_VERIFIER_LENGTH)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func ExportCodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,51 @@
+package oauthgeneric
+
+import (
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportCodeChallengeByRFC7636Example(t *testing.T) {
+	// the example of RFC 7636 appendix B
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", ExportCodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
+
+func TestGenerateCodeVerifier(t *testing.T) {
+	codeVerifier, err := GenerateCodeVerifier()
+	assert.Nil(t, err)
+	// 43-128 characters of unreserved characters
+	assert.Regexp(t, regexp.MustCompile(`^[A-Za-z0-9\-._~]{43,128}$`), codeVerifier)
+	anotherCodeVerifier, err := GenerateCodeVerifier()
+	assert.Nil(t, err)
+	assert.NotEqual(t, codeVerifier, anotherCodeVerifier)
+}
+
+func TestExportAuthorizeURLWithPKCE(t *testing.T) {
+	provider := &Provider{
+		AuthURL:        "https://provider.illa.test/authorize",
+		AccessTokenURL: "https://provider.illa.test/token",
+		ClientID:       "client",
+		Scopes:         []string{"read", "write"},
+		UsePKCE:        true,
+		RedirectURL:    "https://builder.illa.test/api/v1/oauth2/callback",
+		AuthURLParams:  map[string]string{"access_type": "offline"},
+	}
+	authorizeURL, err := url.Parse(provider.ExportAuthorizeURL("state", "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+	assert.Nil(t, err)
+	query := authorizeURL.Query()
+	assert.Equal(t, "state", query.Get("state"))
+	assert.Equal(t, "client", query.Get("client_id"))
+	assert.Equal(t, "read write", query.Get("scope"))
+	assert.Equal(t, "https://builder.illa.test/api/v1/oauth2/callback", query.Get("redirect_uri"))
+	assert.Equal(t, "offline", query.Get("access_type"))
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", query.Get("code_challenge"))
+	assert.Equal(t, CODE_CHALLENGE_METHOD_S256, query.Get("code_challenge_method"))
+
+	provider.UsePKCE = false
+	authorizeURL, err = url.Parse(provider.ExportAuthorizeURL("state", ""))
+	assert.Nil(t, err)
+	assert.Equal(t, "", authorizeURL.Query().Get("code_challenge"))
+}
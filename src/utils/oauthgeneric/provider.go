@@ -0,0 +1,119 @@
+package oauthgeneric
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/illacloud/builder-backend/src/utils/config"
+	"golang.org/x/oauth2"
+)
+
+// the auth content keys of resource options for declaring oauth2 provider
+const (
+	AUTH_CONTENT_AUTH_URL         = "authURL"
+	AUTH_CONTENT_ACCESS_TOKEN_URL = "accessTokenURL"
+	AUTH_CONTENT_CLIENT_ID        = "clientID"
+	AUTH_CONTENT_CLIENT_SECRET    = "clientSecret"
+	AUTH_CONTENT_SCOPE            = "scope"
+	AUTH_CONTENT_USE_PKCE         = "usePKCE"
+	AUTH_CONTENT_ACCESS_TOKEN     = "accessToken"
+	AUTH_CONTENT_REFRESH_TOKEN    = "refreshToken"
+	AUTH_CONTENT_TOKEN_TYPE       = "tokenType"
+	AUTH_CONTENT_EXPIRES_AT       = "expiresAt"
+)
+
+const (
+	EXCHANGE_TIMEOUT = 30 * time.Second
+)
+
+type Provider struct {
+	AuthURL        string
+	AccessTokenURL string
+	ClientID       string
+	ClientSecret   string
+	Scopes         []string
+	UsePKCE        bool
+	// RedirectURL overrides the default oauth2 redirect uri
+	RedirectURL string
+	// AuthURLParams are the extra params appended to the authorization url
+	AuthURLParams map[string]string
+}
+
+func NewProviderByAuthContent(authContent map[string]string) (*Provider, error) {
+	provider := &Provider{
+		AuthURL:        authContent[AUTH_CONTENT_AUTH_URL],
+		AccessTokenURL: authContent[AUTH_CONTENT_ACCESS_TOKEN_URL],
+		ClientID:       authContent[AUTH_CONTENT_CLIENT_ID],
+		ClientSecret:   authContent[AUTH_CONTENT_CLIENT_SECRET],
+		Scopes:         strings.Fields(authContent[AUTH_CONTENT_SCOPE]),
+	}
+	provider.UsePKCE, _ = strconv.ParseBool(authContent[AUTH_CONTENT_USE_PKCE])
+	if provider.AuthURL == "" {
+		return nil, errors.New("missing oauth2 authorization url")
+	}
+	if provider.AccessTokenURL == "" {
+		return nil, errors.New("missing oauth2 access token url")
+	}
+	if provider.ClientID == "" {
+		return nil, errors.New("missing oauth2 client id")
+	}
+	return provider, nil
+}
+
+func (provider *Provider) exportConfig() *oauth2.Config {
+	conf := config.GetInstance()
+	redirectURL := provider.RedirectURL
+	if redirectURL == "" {
+		redirectURL = conf.GetOAuth2RedirectURI()
+	}
+	return &oauth2.Config{
+		ClientID:     provider.ClientID,
+		ClientSecret: provider.ClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  provider.AuthURL,
+			TokenURL: provider.AccessTokenURL,
+		},
+		RedirectURL: redirectURL,
+		Scopes:      provider.Scopes,
+	}
+}
+
+// ExportAuthorizeURL builds the url for redirecting user to the provider consent page.
+// The codeVerifier is only used when the provider enabled PKCE.
+func (provider *Provider) ExportAuthorizeURL(state string, codeVerifier string) string {
+	opts := []oauth2.AuthCodeOption{}
+	for key, value := range provider.AuthURLParams {
+		opts = append(opts, oauth2.SetAuthURLParam(key, value))
+	}
+	if provider.UsePKCE {
+		opts = append(opts,
+			oauth2.SetAuthURLParam("code_challenge", ExportCodeChallenge(codeVerifier)),
+			oauth2.SetAuthURLParam("code_challenge_method", CODE_CHALLENGE_METHOD_S256),
+		)
+	}
+	return provider.exportConfig().AuthCodeURL(state, opts...)
+}
+
+func (provider *Provider) ExchangeOAuthToken(code string, codeVerifier string) (*oauth2.Token, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), EXCHANGE_TIMEOUT)
+	defer cancel()
+	opts := []oauth2.AuthCodeOption{}
+	if provider.UsePKCE {
+		opts = append(opts, oauth2.SetAuthURLParam("code_verifier", codeVerifier))
+	}
+	return provider.exportConfig().Exchange(ctx, code, opts...)
+}
+
+func (provider *Provider) RefreshOAuthToken(refreshToken string) (*oauth2.Token, error) {
+	if refreshToken == "" {
+		return nil, errors.New("missing oauth2 refresh token")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), EXCHANGE_TIMEOUT)
+	defer cancel()
+	// the expired token forces token source to refresh
+	tokenSource := provider.exportConfig().TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken, Expiry: time.Unix(1, 0)})
+	return tokenSource.Token()
+}
@@ -0,0 +1,31 @@
+package oauthgeneric
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// ValidateRedirectURL accepts the absolute url on allowed origins, the origin of request host is allowed when no origin configured.
+func ValidateRedirectURL(redirectURL string, allowedOrigins []string, requestHost string) error {
+	parsedURL, errInParse := url.Parse(redirectURL)
+	if errInParse != nil {
+		return errInParse
+	}
+	if (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" || parsedURL.User != nil {
+		return errors.New("invalid redirect url")
+	}
+	if len(allowedOrigins) == 0 {
+		if requestHost != "" && strings.EqualFold(parsedURL.Host, requestHost) {
+			return nil
+		}
+		return errors.New("redirect url is not on the origin of request")
+	}
+	origin := parsedURL.Scheme + "://" + parsedURL.Host
+	for _, allowedOrigin := range allowedOrigins {
+		if strings.EqualFold(origin, allowedOrigin) {
+			return nil
+		}
+	}
+	return errors.New("redirect url is not on the allowed origins")
+}
@@ -0,0 +1,26 @@
+package oauthgeneric
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRedirectURLByAllowedOrigins(t *testing.T) {
+	allowedOrigins := []string{"https://builder.illa.test", "http://localhost:3000"}
+	assert.Nil(t, ValidateRedirectURL("https://builder.illa.test/workspace/resources", allowedOrigins, "api.illa.test"))
+	assert.Nil(t, ValidateRedirectURL("http://localhost:3000/oauth2", allowedOrigins, "api.illa.test"))
+	assert.NotNil(t, ValidateRedirectURL("https://evil.test/builder.illa.test", allowedOrigins, "api.illa.test"))
+	assert.NotNil(t, ValidateRedirectURL("https://builder.illa.test.evil.test/", allowedOrigins, "api.illa.test"))
+	assert.NotNil(t, ValidateRedirectURL("http://builder.illa.test/", allowedOrigins, "api.illa.test"))
+	assert.NotNil(t, ValidateRedirectURL("https://builder.illa.test@evil.test/", allowedOrigins, "api.illa.test"))
+	assert.NotNil(t, ValidateRedirectURL("//evil.test/", allowedOrigins, "api.illa.test"))
+	assert.NotNil(t, ValidateRedirectURL("javascript:alert(1)", allowedOrigins, "api.illa.test"))
+	assert.NotNil(t, ValidateRedirectURL("/workspace", allowedOrigins, "api.illa.test"))
+}
+
+func TestValidateRedirectURLByRequestHost(t *testing.T) {
+	assert.Nil(t, ValidateRedirectURL("https://illa.self-host.test/workspace", nil, "illa.self-host.test"))
+	assert.NotNil(t, ValidateRedirectURL("https://evil.test/workspace", nil, "illa.self-host.test"))
+	assert.NotNil(t, ValidateRedirectURL("https://evil.test/workspace", nil, ""))
+}
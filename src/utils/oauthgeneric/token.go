@@ -0,0 +1,115 @@
+package oauthgeneric
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/illacloud/builder-backend/src/utils/config"
+	"golang.org/x/oauth2"
+)
+
+const (
+	ENCRYPTED_TOKEN_PREFIX = "enc:"
+)
+
+func exportCipher() (cipher.AEAD, error) {
+	conf := config.GetInstance()
+	key := sha256.Sum256([]byte(conf.GetOAuth2TokenEncryptionKey()))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptToken seals the token by AES-GCM, the encrypted token will not be encrypted again.
+func EncryptToken(token string) (string, error) {
+	if token == "" || strings.HasPrefix(token, ENCRYPTED_TOKEN_PREFIX) {
+		return token, nil
+	}
+	aead, err := exportCipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(token), nil)
+	return ENCRYPTED_TOKEN_PREFIX + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptToken opens the encrypted token, the legacy plaintext token is returned as it is.
+func DecryptToken(encryptedToken string) (string, error) {
+	if !strings.HasPrefix(encryptedToken, ENCRYPTED_TOKEN_PREFIX) {
+		return encryptedToken, nil
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(encryptedToken, ENCRYPTED_TOKEN_PREFIX))
+	if err != nil {
+		return "", err
+	}
+	aead, err := exportCipher()
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("invalid encrypted oauth2 token")
+	}
+	token, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(token), nil
+}
+
+// StoreToken writes the encrypted token into auth content, the refresh token is kept when provider did not rotate it.
+func StoreToken(authContent map[string]string, token *oauth2.Token) error {
+	accessToken, err := EncryptToken(token.AccessToken)
+	if err != nil {
+		return err
+	}
+	authContent[AUTH_CONTENT_ACCESS_TOKEN] = accessToken
+	if token.RefreshToken != "" {
+		refreshToken, err := EncryptToken(token.RefreshToken)
+		if err != nil {
+			return err
+		}
+		authContent[AUTH_CONTENT_REFRESH_TOKEN] = refreshToken
+	}
+	authContent[AUTH_CONTENT_TOKEN_TYPE] = token.Type()
+	authContent[AUTH_CONTENT_EXPIRES_AT] = ""
+	if !token.Expiry.IsZero() {
+		authContent[AUTH_CONTENT_EXPIRES_AT] = token.Expiry.UTC().Format(time.RFC3339)
+	}
+	return nil
+}
+
+func ExportAccessToken(authContent map[string]string) (string, error) {
+	accessToken, err := DecryptToken(authContent[AUTH_CONTENT_ACCESS_TOKEN])
+	if err != nil {
+		return "", err
+	}
+	if accessToken == "" {
+		return "", errors.New("oauth2 authorization is required")
+	}
+	return accessToken, nil
+}
+
+func ExportRefreshToken(authContent map[string]string) (string, error) {
+	return DecryptToken(authContent[AUTH_CONTENT_REFRESH_TOKEN])
+}
+
+// ExportExpiresAt returns zero time when the token never expires.
+func ExportExpiresAt(authContent map[string]string) time.Time {
+	expiresAt, err := time.Parse(time.RFC3339, authContent[AUTH_CONTENT_EXPIRES_AT])
+	if err != nil {
+		return time.Time{}
+	}
+	return expiresAt
+}
@@ -0,0 +1,76 @@
+package oauthgeneric
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+func TestEncryptAndDecryptToken(t *testing.T) {
+	encryptedToken, err := EncryptToken("access-token")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(encryptedToken, ENCRYPTED_TOKEN_PREFIX))
+	assert.NotContains(t, encryptedToken, "access-token")
+
+	// the encrypted token is not encrypted again
+	encryptedAgain, err := EncryptToken(encryptedToken)
+	assert.Nil(t, err)
+	assert.Equal(t, encryptedToken, encryptedAgain)
+
+	token, err := DecryptToken(encryptedToken)
+	assert.Nil(t, err)
+	assert.Equal(t, "access-token", token)
+
+	// nonce is random
+	anotherEncryptedToken, err := EncryptToken("access-token")
+	assert.Nil(t, err)
+	assert.NotEqual(t, encryptedToken, anotherEncryptedToken)
+}
+
+func TestDecryptLegacyPlaintextToken(t *testing.T) {
+	token, err := DecryptToken("ya29.legacy-token")
+	assert.Nil(t, err)
+	assert.Equal(t, "ya29.legacy-token", token)
+
+	token, err = DecryptToken("")
+	assert.Nil(t, err)
+	assert.Equal(t, "", token)
+}
+
+func TestDecryptTamperedToken(t *testing.T) {
+	encryptedToken, err := EncryptToken("access-token")
+	assert.Nil(t, err)
+	tampered := []byte(encryptedToken)
+	if tampered[len(tampered)-1] == 'A' {
+		tampered[len(tampered)-1] = 'B'
+	} else {
+		tampered[len(tampered)-1] = 'A'
+	}
+	_, err = DecryptToken(string(tampered))
+	assert.NotNil(t, err)
+
+	_, err = DecryptToken(ENCRYPTED_TOKEN_PREFIX + "AAAA")
+	assert.NotNil(t, err)
+}
+
+func TestStoreTokenKeepsRefreshToken(t *testing.T) {
+	expiry := time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)
+	authContent := map[string]string{}
+	assert.Nil(t, StoreToken(authContent, &oauth2.Token{AccessToken: "access-1", RefreshToken: "refresh-1", TokenType: "Bearer", Expiry: expiry}))
+	assert.Equal(t, "2026-10-18T08:00:00Z", authContent[AUTH_CONTENT_EXPIRES_AT])
+	assert.Equal(t, expiry, ExportExpiresAt(authContent))
+
+	// the provider did not rotate refresh token
+	assert.Nil(t, StoreToken(authContent, &oauth2.Token{AccessToken: "access-2", TokenType: "Bearer"}))
+	accessToken, err := ExportAccessToken(authContent)
+	assert.Nil(t, err)
+	assert.Equal(t, "access-2", accessToken)
+	refreshToken, err := ExportRefreshToken(authContent)
+	assert.Nil(t, err)
+	assert.Equal(t, "refresh-1", refreshToken)
+	assert.Equal(t, "", authContent[AUTH_CONTENT_EXPIRES_AT])
+	assert.True(t, ExportExpiresAt(authContent).IsZero())
+}
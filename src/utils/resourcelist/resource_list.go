@@ -1,5 +1,13 @@
 package resourcelist
 
+import (
+	"errors"
+	"fmt"
+)
+
+// the resource type id of out-of-process connector plugins should not be less than this
+const PLUGIN_RESOURCE_TYPE_ID_MIN = 1000
+
 var (
 	TYPE_TRANSFORMER             = "transformer"
 	TYPE_RESTAPI                 = "restapi"
@@ -35,6 +43,7 @@ var (
 	TYPE_TRIGGER                 = "trigger"
 	TYPE_SERVER_SIDE_TRANSFORMER = "serversidetransformer"
 	TYPE_CONDITION               = "condition"
+	TYPE_KAFKA                   = "kafka"
 )
 
 var (
@@ -72,6 +81,7 @@ var (
 	TYPE_TRIGGER_ID                 = 31
 	TYPE_SERVER_SIDE_TRANSFORMER_ID = 32
 	TYPE_CONDITION_ID               = 33
+	TYPE_KAFKA_ID                   = 34
 )
 
 var type_array = []string{
@@ -109,43 +119,19 @@ var type_array = []string{
 	31: TYPE_TRIGGER,
 	32: TYPE_SERVER_SIDE_TRANSFORMER,
 	33: TYPE_CONDITION,
+	34: TYPE_KAFKA,
 }
 
-var type_map = map[string]int{
-	TYPE_TRANSFORMER:             TYPE_TRANSFORMER_ID,
-	TYPE_RESTAPI:                 TYPE_RESTAPI_ID,
-	TYPE_GRAPHQL:                 TYPE_GRAPHQL_ID,
-	TYPE_REDIS:                   TYPE_REDIS_ID,
-	TYPE_MYSQL:                   TYPE_MYSQL_ID,
-	TYPE_MARIADB:                 TYPE_MARIADB_ID,
-	TYPE_POSTGRESQL:              TYPE_POSTGRESQL_ID,
-	TYPE_MONGODB:                 TYPE_MONGODB_ID,
-	TYPE_TIDB:                    TYPE_TIDB_ID,
-	TYPE_ELASTICSEARCH:           TYPE_ELASTICSEARCH_ID,
-	TYPE_S3:                      TYPE_S3_ID,
-	TYPE_SMTP:                    TYPE_SMTP_ID,
-	TYPE_SUPABASEDB:              TYPE_SUPABASEDB_ID,
-	TYPE_FIREBASE:                TYPE_FIREBASE_ID,
-	TYPE_CLICKHOUSE:              TYPE_CLICKHOUSE_ID,
-	TYPE_MSSQL:                   TYPE_MSSQL_ID,
-	TYPE_HUGGINGFACE:             TYPE_HUGGINGFACE_ID,
-	TYPE_DYNAMODB:                TYPE_DYNAMODB_ID,
-	TYPE_SNOWFLAKE:               TYPE_SNOWFLAKE_ID,
-	TYPE_COUCHDB:                 TYPE_COUCHDB_ID,
-	TYPE_HFENDPOINT:              TYPE_HFENDPOINT_ID,
-	TYPE_ORACLE:                  TYPE_ORACLE_ID,
-	TYPE_APPWRITE:                TYPE_APPWRITE_ID,
-	TYPE_GOOGLESHEETS:            TYPE_GOOGLESHEETS_ID,
-	TYPE_NEON:                    TYPE_NEON_ID,
-	TYPE_UPSTASH:                 TYPE_UPSTASH_ID,
-	TYPE_AIRTABLE:                TYPE_AIRTABLE_ID,
-	TYPE_HYDRA:                   TYPE_HYDRA_ID,
-	TYPE_AI_AGENT:                TYPE_AI_AGENT_ID,
-	TYPE_ORACLE_9I:               TYPE_ORACLE_9I_ID,
-	TYPE_ILLA_DRIVE:              TYPE_ILLA_DRIVE_ID,
-	TYPE_TRIGGER:                 TYPE_TRIGGER_ID,
-	TYPE_SERVER_SIDE_TRANSFORMER: TYPE_SERVER_SIDE_TRANSFORMER_ID,
-	TYPE_CONDITION:               TYPE_CONDITION_ID,
+// type_map is built from type_array, so the name and id only need to be maintained in one place
+var type_map = make(map[string]int, len(type_array))
+
+// plugin_type_map keeps the resource types of connector plugins, they are registered at startup
+var plugin_type_map = make(map[int]string)
+
+func init() {
+	for id, name := range type_array {
+		type_map[name] = id
+	}
 }
 
 var virtualResourceList = map[string]bool{
@@ -171,11 +157,19 @@ var canCreateOAuthTokenResourceList = map[string]bool{
 	TYPE_GOOGLESHEETS: true,
 }
 
+var canUseGenericOAuth2ResourceList = map[string]bool{
+	TYPE_RESTAPI: true,
+	TYPE_GRAPHQL: true,
+}
+
 var needFetchResourceInfoFromSourceManagerList = map[string]bool{
 	TYPE_AI_AGENT: true,
 }
 
 func GetResourceIDMappedType(id int) string {
+	if id < 0 || id >= len(type_array) {
+		return plugin_type_map[id]
+	}
 	return type_array[id]
 }
 
@@ -229,7 +223,32 @@ func CanCreateOAuthToken(resourceType int) bool {
 	return canDo && hit
 }
 
+func CanUseGenericOAuth2(resourceType int) bool {
+	resourceTypeString := GetResourceIDMappedType(resourceType)
+	canDo, hit := canUseGenericOAuth2ResourceList[resourceTypeString]
+	return canDo && hit
+}
+
 func NeedFetchResourceInfoFromSourceManager(resourceType string) bool {
 	itIs, hit := needFetchResourceInfoFromSourceManagerList[resourceType]
 	return itIs && hit
 }
+
+// RegisterPluginResourceType should only be called at startup, before the server serving requests.
+func RegisterPluginResourceType(name string, id int) error {
+	if name == "" {
+		return errors.New("missing plugin resource type name")
+	}
+	if id < PLUGIN_RESOURCE_TYPE_ID_MIN {
+		return fmt.Errorf("plugin resource type id %d is reserved, it should not be less than %d", id, PLUGIN_RESOURCE_TYPE_ID_MIN)
+	}
+	if _, hit := type_map[name]; hit {
+		return fmt.Errorf("resource type %s already exists", name)
+	}
+	if registered := GetResourceIDMappedType(id); registered != "" {
+		return fmt.Errorf("resource type id %d already used by %s", id, registered)
+	}
+	plugin_type_map[id] = name
+	type_map[name] = id
+	return nil
+}
@@ -0,0 +1,44 @@
+package resultexporter
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+type CSVExporter struct {
+	writer  *csv.Writer
+	columns []string
+}
+
+func NewCSVExporter(writer io.Writer) *CSVExporter {
+	return &CSVExporter{
+		writer: csv.NewWriter(writer),
+	}
+}
+
+func (exporter *CSVExporter) WriteRow(row map[string]interface{}) error {
+	if exporter.columns == nil {
+		exporter.columns = extractColumns(row)
+		if err := exporter.writer.Write(exporter.columns); err != nil {
+			return err
+		}
+	}
+	record := make([]string, len(exporter.columns))
+	for serial, column := range exporter.columns {
+		record[serial] = stringifyValue(row[column])
+	}
+	return exporter.writer.Write(record)
+}
+
+func (exporter *CSVExporter) Close() error {
+	exporter.writer.Flush()
+	return exporter.writer.Error()
+}
+
+func (exporter *CSVExporter) ExportContentType() string {
+	return "text/csv; charset=utf-8"
+}
+
+func (exporter *CSVExporter) ExportFileExtension() string {
+	return EXPORT_FORMAT_CSV
+}
@@ -0,0 +1,32 @@
+package resultexporter
+
+import (
+	"encoding/json"
+	"io"
+)
+
+type JSONLExporter struct {
+	encoder *json.Encoder
+}
+
+func NewJSONLExporter(writer io.Writer) *JSONLExporter {
+	return &JSONLExporter{
+		encoder: json.NewEncoder(writer),
+	}
+}
+
+func (exporter *JSONLExporter) WriteRow(row map[string]interface{}) error {
+	return exporter.encoder.Encode(row)
+}
+
+func (exporter *JSONLExporter) Close() error {
+	return nil
+}
+
+func (exporter *JSONLExporter) ExportContentType() string {
+	return "application/x-ndjson"
+}
+
+func (exporter *JSONLExporter) ExportFileExtension() string {
+	return EXPORT_FORMAT_JSONL
+}
@@ -0,0 +1,115 @@
+package resultexporter
+
+import (
+	"io"
+
+	"github.com/apache/arrow/go/v12/parquet"
+	"github.com/apache/arrow/go/v12/parquet/compress"
+	"github.com/apache/arrow/go/v12/parquet/file"
+	"github.com/apache/arrow/go/v12/parquet/schema"
+)
+
+const PARQUET_ROW_GROUP_SIZE = 10000
+
+// ParquetExporter writes every column as optional UTF8 string, rows are buffered until a row group filled.
+type ParquetExporter struct {
+	writer       io.Writer
+	fileWriter   *file.Writer
+	columns      []string
+	values       [][]parquet.ByteArray
+	definitions  [][]int16
+	bufferedRows int
+}
+
+func NewParquetExporter(writer io.Writer) *ParquetExporter {
+	return &ParquetExporter{
+		writer: writer,
+	}
+}
+
+func (exporter *ParquetExporter) start(columns []string) error {
+	fields := make(schema.FieldList, 0, len(columns))
+	for _, column := range columns {
+		node, errInNewNode := schema.NewPrimitiveNodeLogical(column, parquet.Repetitions.Optional, schema.StringLogicalType{}, parquet.Types.ByteArray, 0, -1)
+		if errInNewNode != nil {
+			return errInNewNode
+		}
+		fields = append(fields, node)
+	}
+	root, errInNewRoot := schema.NewGroupNode("schema", parquet.Repetitions.Required, fields, -1)
+	if errInNewRoot != nil {
+		return errInNewRoot
+	}
+	properties := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy))
+	exporter.fileWriter = file.NewParquetWriter(exporter.writer, root, file.WithWriterProps(properties))
+	exporter.columns = columns
+	exporter.values = make([][]parquet.ByteArray, len(columns))
+	exporter.definitions = make([][]int16, len(columns))
+	return nil
+}
+
+func (exporter *ParquetExporter) WriteRow(row map[string]interface{}) error {
+	if exporter.fileWriter == nil {
+		if err := exporter.start(extractColumns(row)); err != nil {
+			return err
+		}
+	}
+	for serial, column := range exporter.columns {
+		value := row[column]
+		if value == nil {
+			exporter.definitions[serial] = append(exporter.definitions[serial], 0)
+			continue
+		}
+		exporter.definitions[serial] = append(exporter.definitions[serial], 1)
+		exporter.values[serial] = append(exporter.values[serial], parquet.ByteArray(stringifyValue(value)))
+	}
+	exporter.bufferedRows++
+	if exporter.bufferedRows >= PARQUET_ROW_GROUP_SIZE {
+		return exporter.flushRowGroup()
+	}
+	return nil
+}
+
+func (exporter *ParquetExporter) flushRowGroup() error {
+	if exporter.bufferedRows == 0 {
+		return nil
+	}
+	rowGroupWriter := exporter.fileWriter.AppendRowGroup()
+	for serial := range exporter.columns {
+		columnWriter, errInNextColumn := rowGroupWriter.NextColumn()
+		if errInNextColumn != nil {
+			return errInNextColumn
+		}
+		byteArrayWriter := columnWriter.(*file.ByteArrayColumnChunkWriter)
+		if _, errInWrite := byteArrayWriter.WriteBatch(exporter.values[serial], exporter.definitions[serial], nil); errInWrite != nil {
+			return errInWrite
+		}
+		if errInClose := columnWriter.Close(); errInClose != nil {
+			return errInClose
+		}
+		exporter.values[serial] = exporter.values[serial][:0]
+		exporter.definitions[serial] = exporter.definitions[serial][:0]
+	}
+	exporter.bufferedRows = 0
+	return rowGroupWriter.Close()
+}
+
+func (exporter *ParquetExporter) Close() error {
+	if exporter.fileWriter == nil {
+		if err := exporter.start([]string{}); err != nil {
+			return err
+		}
+	}
+	if err := exporter.flushRowGroup(); err != nil {
+		return err
+	}
+	return exporter.fileWriter.Close()
+}
+
+func (exporter *ParquetExporter) ExportContentType() string {
+	return "application/vnd.apache.parquet"
+}
+
+func (exporter *ParquetExporter) ExportFileExtension() string {
+	return EXPORT_FORMAT_PARQUET
+}
@@ -0,0 +1,82 @@
+package resultexporter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	EXPORT_FORMAT_CSV     = "csv"
+	EXPORT_FORMAT_XLSX    = "xlsx"
+	EXPORT_FORMAT_JSONL   = "jsonl"
+	EXPORT_FORMAT_PARQUET = "parquet"
+)
+
+// ResultExporter encodes action result rows into a file format and writes them to the underlying writer.
+// The columns are decided by the first row, fields which not appeared in first row will be ignored (except JSON Lines).
+type ResultExporter interface {
+	WriteRow(row map[string]interface{}) error
+	Close() error
+	ExportContentType() string
+	ExportFileExtension() string
+}
+
+func NewResultExporter(format string, writer io.Writer) (ResultExporter, error) {
+	switch format {
+	case EXPORT_FORMAT_CSV:
+		return NewCSVExporter(writer), nil
+	case EXPORT_FORMAT_XLSX:
+		return NewXLSXExporter(writer), nil
+	case EXPORT_FORMAT_JSONL:
+		return NewJSONLExporter(writer), nil
+	case EXPORT_FORMAT_PARQUET:
+		return NewParquetExporter(writer), nil
+	default:
+		return nil, errors.New("unsupported export format: " + format)
+	}
+}
+
+func IsSupportedFormat(format string) bool {
+	switch format {
+	case EXPORT_FORMAT_CSV, EXPORT_FORMAT_XLSX, EXPORT_FORMAT_JSONL, EXPORT_FORMAT_PARQUET:
+		return true
+	}
+	return false
+}
+
+func extractColumns(row map[string]interface{}) []string {
+	columns := make([]string, 0, len(row))
+	for column := range row {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+func stringifyValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, json.Number:
+		return fmt.Sprint(v)
+	default:
+		valueInJSON, errInMarshal := json.Marshal(v)
+		if errInMarshal != nil {
+			return fmt.Sprint(v)
+		}
+		return string(valueInJSON)
+	}
+}
@@ -0,0 +1,234 @@
+package resultexporter
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow/go/v12/parquet"
+	"github.com/apache/arrow/go/v12/parquet/file"
+	"github.com/stretchr/testify/assert"
+)
+
+var testExportRows = []map[string]interface{}{
+	{"id": 1, "name": "alice", "active": true, "tags": []string{"a", "b"}},
+	{"id": 2.5, "name": "<bob & co>", "active": false, "tags": nil},
+	{"id": nil, "name": "carol", "active": nil, "tags": map[string]interface{}{"k": "v"}, "extra": "ignored"},
+}
+
+func exportTestRows(t *testing.T, format string) []byte {
+	buffer := bytes.NewBuffer(nil)
+	exporter, errInNew := NewResultExporter(format, buffer)
+	assert.Nil(t, errInNew)
+	for _, row := range testExportRows {
+		assert.Nil(t, exporter.WriteRow(row))
+	}
+	assert.Nil(t, exporter.Close())
+	return buffer.Bytes()
+}
+
+type xlsxTestSheet struct {
+	Rows []struct {
+		Reference string `xml:"r,attr"`
+		Cells     []struct {
+			Reference  string `xml:"r,attr"`
+			Type       string `xml:"t,attr"`
+			Value      string `xml:"v"`
+			InlineText string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func TestXLSXExporterRoundTrip(t *testing.T) {
+	content := exportTestRows(t, EXPORT_FORMAT_XLSX)
+
+	archive, errInOpen := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	assert.Nil(t, errInOpen)
+	parts := make(map[string][]byte)
+	for _, part := range archive.File {
+		partReader, errInOpenPart := part.Open()
+		assert.Nil(t, errInOpenPart)
+		partContent, errInRead := io.ReadAll(partReader)
+		assert.Nil(t, errInRead)
+		partReader.Close()
+		parts[part.Name] = partContent
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		assert.Contains(t, parts, name)
+	}
+
+	sheet := xlsxTestSheet{}
+	assert.Nil(t, xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &sheet))
+	assert.Equal(t, 4, len(sheet.Rows))
+
+	// header, columns sorted by name
+	header := sheet.Rows[0]
+	assert.Equal(t, "1", header.Reference)
+	headerNames := make([]string, 0, len(header.Cells))
+	for _, cell := range header.Cells {
+		assert.Equal(t, "inlineStr", cell.Type)
+		headerNames = append(headerNames, cell.InlineText)
+	}
+	assert.Equal(t, []string{"active", "id", "name", "tags"}, headerNames)
+	assert.Equal(t, "D1", header.Cells[3].Reference)
+
+	// typed cells
+	first := sheet.Rows[1]
+	assert.Equal(t, 4, len(first.Cells))
+	assert.Equal(t, "b", first.Cells[0].Type)
+	assert.Equal(t, "1", first.Cells[0].Value)
+	assert.Equal(t, "", first.Cells[1].Type)
+	assert.Equal(t, "1", first.Cells[1].Value)
+	assert.Equal(t, "alice", first.Cells[2].InlineText)
+	assert.Equal(t, `["a","b"]`, first.Cells[3].InlineText)
+
+	// escaped text, nil cell skipped
+	second := sheet.Rows[2]
+	assert.Equal(t, 3, len(second.Cells))
+	assert.Equal(t, "0", second.Cells[0].Value)
+	assert.Equal(t, "2.5", second.Cells[1].Value)
+	assert.Equal(t, "<bob & co>", second.Cells[2].InlineText)
+
+	// columns not in first row are ignored
+	third := sheet.Rows[3]
+	assert.Equal(t, 2, len(third.Cells))
+	assert.Equal(t, "C4", third.Cells[0].Reference)
+	assert.Equal(t, "carol", third.Cells[0].InlineText)
+	assert.Equal(t, "D4", third.Cells[1].Reference)
+	assert.Equal(t, `{"k":"v"}`, third.Cells[1].InlineText)
+}
+
+func TestXLSXExporterEmpty(t *testing.T) {
+	buffer := bytes.NewBuffer(nil)
+	exporter := NewXLSXExporter(buffer)
+	assert.Nil(t, exporter.Close())
+
+	archive, errInOpen := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	assert.Nil(t, errInOpen)
+	assert.Equal(t, 5, len(archive.File))
+}
+
+func TestColumnNameByIndex(t *testing.T) {
+	assert.Equal(t, "A", columnNameByIndex(0))
+	assert.Equal(t, "Z", columnNameByIndex(25))
+	assert.Equal(t, "AA", columnNameByIndex(26))
+	assert.Equal(t, "AB", columnNameByIndex(27))
+	assert.Equal(t, "ZZ", columnNameByIndex(701))
+	assert.Equal(t, "AAA", columnNameByIndex(702))
+}
+
+func readParquetColumn(t *testing.T, reader *file.Reader, column int) []interface{} {
+	values := make([]interface{}, 0)
+	for rowGroup := 0; rowGroup < reader.NumRowGroups(); rowGroup++ {
+		rowGroupReader := reader.RowGroup(rowGroup)
+		rowCount := rowGroupReader.NumRows()
+		columnReader, errInColumn := rowGroupReader.Column(column)
+		assert.Nil(t, errInColumn)
+		byteArrayReader := columnReader.(*file.ByteArrayColumnChunkReader)
+		batchValues := make([]parquet.ByteArray, rowCount)
+		definitions := make([]int16, rowCount)
+		total, _, errInRead := byteArrayReader.ReadBatch(rowCount, batchValues, definitions, nil)
+		assert.Nil(t, errInRead)
+		assert.Equal(t, rowCount, total)
+		valueIndex := 0
+		for _, definition := range definitions[:total] {
+			if definition == 0 {
+				values = append(values, nil)
+				continue
+			}
+			values = append(values, batchValues[valueIndex].String())
+			valueIndex++
+		}
+	}
+	return values
+}
+
+func TestParquetExporterRoundTrip(t *testing.T) {
+	content := exportTestRows(t, EXPORT_FORMAT_PARQUET)
+
+	reader, errInOpen := file.NewParquetReader(bytes.NewReader(content))
+	assert.Nil(t, errInOpen)
+	defer reader.Close()
+
+	assert.Equal(t, int64(3), reader.NumRows())
+	fileSchema := reader.MetaData().Schema
+	assert.Equal(t, 4, fileSchema.NumColumns())
+	columnNames := make([]string, 0, fileSchema.NumColumns())
+	for serial := 0; serial < fileSchema.NumColumns(); serial++ {
+		columnNames = append(columnNames, fileSchema.Column(serial).Name())
+		assert.Equal(t, parquet.Types.ByteArray, fileSchema.Column(serial).PhysicalType())
+	}
+	assert.Equal(t, []string{"active", "id", "name", "tags"}, columnNames)
+
+	assert.Equal(t, []interface{}{"true", "false", nil}, readParquetColumn(t, reader, 0))
+	assert.Equal(t, []interface{}{"1", "2.5", nil}, readParquetColumn(t, reader, 1))
+	assert.Equal(t, []interface{}{"alice", "<bob & co>", "carol"}, readParquetColumn(t, reader, 2))
+	assert.Equal(t, []interface{}{`["a","b"]`, nil, `{"k":"v"}`}, readParquetColumn(t, reader, 3))
+}
+
+func TestParquetExporterMultipleRowGroups(t *testing.T) {
+	buffer := bytes.NewBuffer(nil)
+	exporter := NewParquetExporter(buffer)
+	rowCount := PARQUET_ROW_GROUP_SIZE + 5
+	for serial := 0; serial < rowCount; serial++ {
+		assert.Nil(t, exporter.WriteRow(map[string]interface{}{"n": serial}))
+	}
+	assert.Nil(t, exporter.Close())
+
+	reader, errInOpen := file.NewParquetReader(bytes.NewReader(buffer.Bytes()))
+	assert.Nil(t, errInOpen)
+	defer reader.Close()
+	assert.Equal(t, 2, reader.NumRowGroups())
+	assert.Equal(t, int64(rowCount), reader.NumRows())
+	values := readParquetColumn(t, reader, 0)
+	assert.Equal(t, "0", values[0])
+	assert.Equal(t, "10004", values[rowCount-1])
+}
+
+func TestParquetExporterEmpty(t *testing.T) {
+	buffer := bytes.NewBuffer(nil)
+	exporter := NewParquetExporter(buffer)
+	assert.Nil(t, exporter.Close())
+
+	reader, errInOpen := file.NewParquetReader(bytes.NewReader(buffer.Bytes()))
+	assert.Nil(t, errInOpen)
+	defer reader.Close()
+	assert.Equal(t, int64(0), reader.NumRows())
+	assert.Equal(t, 0, reader.MetaData().Schema.NumColumns())
+}
+
+func TestCSVExporterRoundTrip(t *testing.T) {
+	content := exportTestRows(t, EXPORT_FORMAT_CSV)
+
+	records, errInRead := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	assert.Nil(t, errInRead)
+	assert.Equal(t, [][]string{
+		{"active", "id", "name", "tags"},
+		{"true", "1", "alice", `["a","b"]`},
+		{"false", "2.5", "<bob & co>", ""},
+		{"", "", "carol", `{"k":"v"}`},
+	}, records)
+}
+
+func TestJSONLExporterRoundTrip(t *testing.T) {
+	content := exportTestRows(t, EXPORT_FORMAT_JSONL)
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Equal(t, 3, len(lines))
+	row := make(map[string]interface{})
+	assert.Nil(t, json.Unmarshal([]byte(lines[2]), &row))
+	assert.Equal(t, "carol", row["name"])
+	assert.Equal(t, "ignored", row["extra"])
+}
+
+func TestNewResultExporterUnsupportedFormat(t *testing.T) {
+	_, errInNew := NewResultExporter("pdf", bytes.NewBuffer(nil))
+	assert.NotNil(t, errInNew)
+	assert.False(t, IsSupportedFormat("pdf"))
+	assert.True(t, IsSupportedFormat(EXPORT_FORMAT_PARQUET))
+}
@@ -0,0 +1,161 @@
+package resultexporter
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+const (
+	XLSX_SHEET_NAME = "Sheet1"
+	XLSX_XML_HEADER = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+	XLSX_CONTENT_TYPES = XLSX_XML_HEADER + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`
+	XLSX_ROOT_RELS = XLSX_XML_HEADER + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+	XLSX_WORKBOOK = XLSX_XML_HEADER + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="` + XLSX_SHEET_NAME + `" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`
+	XLSX_WORKBOOK_RELS = XLSX_XML_HEADER + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`
+	XLSX_SHEET_BEGIN = XLSX_XML_HEADER + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	XLSX_SHEET_END   = `</sheetData></worksheet>`
+)
+
+// XLSXExporter writes a single sheet workbook, the sheet xml is streamed into the zip archive row by row.
+type XLSXExporter struct {
+	archive  *zip.Writer
+	sheet    *bufio.Writer
+	columns  []string
+	rowCount int
+	started  bool
+}
+
+func NewXLSXExporter(writer io.Writer) *XLSXExporter {
+	return &XLSXExporter{
+		archive: zip.NewWriter(writer),
+	}
+}
+
+func (exporter *XLSXExporter) start() error {
+	staticParts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", XLSX_CONTENT_TYPES},
+		{"_rels/.rels", XLSX_ROOT_RELS},
+		{"xl/workbook.xml", XLSX_WORKBOOK},
+		{"xl/_rels/workbook.xml.rels", XLSX_WORKBOOK_RELS},
+	}
+	for _, part := range staticParts {
+		partWriter, errInCreate := exporter.archive.Create(part.name)
+		if errInCreate != nil {
+			return errInCreate
+		}
+		if _, errInWrite := io.WriteString(partWriter, part.content); errInWrite != nil {
+			return errInWrite
+		}
+	}
+	sheetWriter, errInCreate := exporter.archive.Create("xl/worksheets/sheet1.xml")
+	if errInCreate != nil {
+		return errInCreate
+	}
+	exporter.sheet = bufio.NewWriter(sheetWriter)
+	exporter.started = true
+	_, errInWrite := exporter.sheet.WriteString(XLSX_SHEET_BEGIN)
+	return errInWrite
+}
+
+func (exporter *XLSXExporter) WriteRow(row map[string]interface{}) error {
+	if !exporter.started {
+		if err := exporter.start(); err != nil {
+			return err
+		}
+	}
+	if exporter.columns == nil {
+		exporter.columns = extractColumns(row)
+		header := make([]interface{}, len(exporter.columns))
+		for serial, column := range exporter.columns {
+			header[serial] = column
+		}
+		if err := exporter.writeCells(header); err != nil {
+			return err
+		}
+	}
+	cells := make([]interface{}, len(exporter.columns))
+	for serial, column := range exporter.columns {
+		cells[serial] = row[column]
+	}
+	return exporter.writeCells(cells)
+}
+
+func (exporter *XLSXExporter) writeCells(cells []interface{}) error {
+	exporter.rowCount++
+	rowNumber := strconv.Itoa(exporter.rowCount)
+	exporter.sheet.WriteString(`<row r="` + rowNumber + `">`)
+	for serial, cell := range cells {
+		reference := columnNameByIndex(serial) + rowNumber
+		switch v := cell.(type) {
+		case nil:
+			continue
+		case bool:
+			exporter.sheet.WriteString(`<c r="` + reference + `" t="b"><v>`)
+			if v {
+				exporter.sheet.WriteString("1")
+			} else {
+				exporter.sheet.WriteString("0")
+			}
+			exporter.sheet.WriteString(`</v></c>`)
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			exporter.sheet.WriteString(`<c r="` + reference + `"><v>` + stringifyValue(v) + `</v></c>`)
+		default:
+			exporter.sheet.WriteString(`<c r="` + reference + `" t="inlineStr"><is><t xml:space="preserve">`)
+			if err := xml.EscapeText(exporter.sheet, []byte(stringifyValue(v))); err != nil {
+				return err
+			}
+			exporter.sheet.WriteString(`</t></is></c>`)
+		}
+	}
+	_, errInWrite := exporter.sheet.WriteString(`</row>`)
+	return errInWrite
+}
+
+func (exporter *XLSXExporter) Close() error {
+	if !exporter.started {
+		if err := exporter.start(); err != nil {
+			return err
+		}
+	}
+	exporter.sheet.WriteString(XLSX_SHEET_END)
+	if err := exporter.sheet.Flush(); err != nil {
+		return err
+	}
+	return exporter.archive.Close()
+}
+
+func (exporter *XLSXExporter) ExportContentType() string {
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+func (exporter *XLSXExporter) ExportFileExtension() string {
+	return EXPORT_FORMAT_XLSX
+}
+
+// columnNameByIndex converts 0 based column index to excel column name, like 0 => "A", 27 => "AB"
+func columnNameByIndex(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}